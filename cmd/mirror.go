@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	cfgParser "github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
+	"github.com/runatlantis/atlantis/server/core/runtime/policy"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/spf13/cobra"
+)
+
+// MirrorCmd pre-populates the binary mirror with terraform and conftest versions
+// so they can be loaded by servers without outbound network access.
+type MirrorCmd struct {
+	MirrorDir           string
+	RepoConfig          string
+	DataDir             string
+	TerraformVersions   []string
+	ConftestVersions    []string
+	TFDownloadURL       string
+	BinaryChecksumsFile string
+	TFSigningKeyFile    string
+}
+
+// Init returns the runnable cobra command.
+func (m *MirrorCmd) Init() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "mirror",
+		Short: "Pre-populate the binary mirror",
+		Long: "Downloads and verifies the given terraform and conftest releases and copies their archives, checksums and signatures into the binary mirror." +
+			" The mirror is either --" + BinaryMirrorDirFlag + " or the store configured by binary_mirror_prefix in --" + RepoConfigFlag + ".",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return m.run()
+		},
+	}
+
+	c.Flags().StringVar(&m.MirrorDir, BinaryMirrorDirFlag, "", "Directory to populate.")
+	c.Flags().StringVar(&m.RepoConfig, RepoConfigFlag, "", "Path to a repo config file which configures a binary_mirror_prefix.")
+	c.Flags().StringVar(&m.DataDir, DataDirFlag, DefaultDataDir, "Path to the data dir used by the default local store.")
+	c.Flags().StringSliceVar(&m.TerraformVersions, "terraform-versions", nil, "Comma separated terraform versions to mirror.")
+	c.Flags().StringSliceVar(&m.ConftestVersions, "conftest-versions", nil, "Comma separated conftest versions to mirror.")
+	c.Flags().StringVar(&m.TFDownloadURL, TFDownloadURLFlag, DefaultTFDownloadURL, "Base URL to download Terraform versions from.")
+	c.Flags().StringVar(&m.BinaryChecksumsFile, BinaryChecksumsFileFlag, "", "File of pinned SHA256 checksums for the downloaded archives.")
+	c.Flags().StringVar(&m.TFSigningKeyFile, TFSigningKeyFileFlag, "", "File containing the armored PGP key which signs terraform release checksums.")
+	return c
+}
+
+func (m *MirrorCmd) run() error {
+	binaryMirror, err := m.mirror()
+	if err != nil {
+		return err
+	}
+
+	tfVersions, err := parseVersions(m.TerraformVersions)
+	if err != nil {
+		return errors.Wrap(err, "parsing terraform versions")
+	}
+	conftestVersions, err := parseVersions(m.ConftestVersions)
+	if err != nil {
+		return errors.Wrap(err, "parsing conftest versions")
+	}
+
+	downloader := &terraform.DefaultDownloader{}
+	getFile := func(dst, src string) error {
+		return downloader.GetFile(dst, src)
+	}
+
+	tfVerifier, err := checksum.NewVerifierWithDefaultKey(m.BinaryChecksumsFile, m.TFSigningKeyFile, checksum.HashiCorpKey, getFile)
+	if err != nil {
+		return errors.Wrap(err, "initializing terraform verifier")
+	}
+	conftestVerifier, err := checksum.NewVerifier(m.BinaryChecksumsFile, "", getFile)
+	if err != nil {
+		return errors.Wrap(err, "initializing conftest verifier")
+	}
+
+	tfLoader := terraform.NewVersionLoader(downloader, m.TFDownloadURL, tfVerifier)
+	if err := mirror.Populate(binaryMirror, "terraform", tfVersions, tfLoader, getFile); err != nil {
+		return err
+	}
+
+	if err := mirror.Populate(binaryMirror, "conftest", conftestVersions, policy.NewConfTestVersionDownloader(downloader, conftestVerifier), getFile); err != nil {
+		return err
+	}
+
+	fmt.Printf("mirrored %d terraform and %d conftest versions\n", len(tfVersions), len(conftestVersions))
+	return nil
+}
+
+func (m *MirrorCmd) mirror() (mirror.Mirror, error) {
+	var store *valid.StoreConfig
+	if m.MirrorDir == "" && m.RepoConfig != "" {
+		dataDir, err := homedir.Expand(m.DataDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "determining home directory")
+		}
+
+		globalCfg, err := (&cfgParser.ParserValidator{}).ParseGlobalCfg(m.RepoConfig, valid.NewGlobalCfg(dataDir))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s file", m.RepoConfig)
+		}
+		store = globalCfg.PersistenceConfig.BinaryMirror
+	}
+
+	binaryMirror, err := mirror.New(m.MirrorDir, store)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing binary mirror")
+	}
+	if binaryMirror == nil {
+		return nil, fmt.Errorf("--%s or a --%s with a binary_mirror_prefix must be set", BinaryMirrorDirFlag, RepoConfigFlag)
+	}
+	return binaryMirror, nil
+}
+
+func parseVersions(versions []string) ([]*version.Version, error) {
+	var parsed []*version.Version
+	for _, v := range versions {
+		p, err := version.NewVersion(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing version %s", v)
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorCmd_RequiresMirror(t *testing.T) {
+	err := (&MirrorCmd{DataDir: t.TempDir()}).run()
+	assert.ErrorContains(t, err, "--"+BinaryMirrorDirFlag)
+}
+
+func TestMirrorCmd_InvalidVersion(t *testing.T) {
+	m := &MirrorCmd{
+		MirrorDir:         t.TempDir(),
+		TerraformVersions: []string{"not-a-version"},
+	}
+	assert.ErrorContains(t, m.run(), "parsing terraform versions")
+}
+
+func TestMirrorCmd_SkipsMirroredVersions(t *testing.T) {
+	dir := t.TempDir()
+	v, err := version.NewVersion("1.2.0")
+	require.NoError(t, err)
+
+	// an already mirrored version is not downloaded again
+	release := terraform.EngineRelease(valid.TerraformEngine, "http://127.0.0.1:0", v)
+	src := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(src, []byte("archive"), 0600))
+	require.NoError(t, (&mirror.Dir{Path: dir}).Put(mirror.Key("terraform", v, release.ArchiveURL), src))
+
+	m := &MirrorCmd{
+		MirrorDir:         dir,
+		TerraformVersions: []string{"1.2.0"},
+		TFDownloadURL:     "http://127.0.0.1:0",
+	}
+	require.NoError(t, m.run())
+
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(mirror.Key("terraform", v, release.ArchiveURL))))
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(b))
+}
+
+func TestMirrorCmd_Init(t *testing.T) {
+	m := &MirrorCmd{}
+	c := m.Init()
+	require.NoError(t, c.ParseFlags([]string{
+		"--" + BinaryMirrorDirFlag, "/mirror",
+		"--terraform-versions", "1.2.0,1.3.0",
+		"--conftest-versions", "0.25.0",
+	}))
+
+	assert.Equal(t, "/mirror", m.MirrorDir)
+	assert.Equal(t, []string{"1.2.0", "1.3.0"}, m.TerraformVersions)
+	assert.Equal(t, []string{"0.25.0"}, m.ConftestVersions)
+	assert.Equal(t, DefaultTFDownloadURL, m.TFDownloadURL)
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/runatlantis/atlantis/server"
 	cfgParser "github.com/runatlantis/atlantis/server/core/config"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -49,6 +51,8 @@ const (
 	ADUserFlag                 = "azuredevops-user"
 	AtlantisURLFlag            = "atlantis-url"
 	AutoplanFileListFlag       = "autoplan-file-list"
+	BinaryChecksumsFileFlag    = "binary-checksums-file"
+	BinaryMirrorDirFlag        = "binary-mirror-dir"
 	BitbucketBaseURLFlag       = "bitbucket-base-url"
 	BitbucketTokenFlag         = "bitbucket-token"
 	BitbucketUserFlag          = "bitbucket-user"
@@ -57,12 +61,14 @@ const (
 	CheckoutStrategyFlag       = "checkout-strategy"
 	DataDirFlag                = "data-dir"
 	DefaultTFVersionFlag       = "default-tf-version"
+	DefaultTofuVersionFlag     = "default-tofu-version"
 	DisableApplyAllFlag        = "disable-apply-all"
 	DisableApplyFlag           = "disable-apply"
 	DisableAutoplanFlag        = "disable-autoplan"
 	DisableMarkdownFoldingFlag = "disable-markdown-folding"
 	EnableRegExpCmdFlag        = "enable-regexp-cmd"
 	EnableDiffMarkdownFormat   = "enable-diff-markdown-format"
+	EnableTofuFlag             = "enable-tofu"
 	FFOwnerFlag                = "ff-owner"
 	FFRepoFlag                 = "ff-repo"
 	FFBranchFlag               = "ff-branch"
@@ -81,9 +87,17 @@ const (
 	GitlabUserFlag             = "gitlab-user"
 	GitlabWebhookSecretFlag    = "gitlab-webhook-secret" // nolint: gosec
 	HidePrevPlanComments       = "hide-prev-plan-comments"
+	LockAbandonedDaysFlag      = "lock-abandoned-days"
+	LockTTLHoursFlag           = "lock-ttl-hours"
+	LockingDBTypeFlag          = "locking-db-type"
+	LockingDBSQLDriverFlag     = "locking-db-sql-driver"
+	LockingDBSQLDSNFlag        = "locking-db-sql-dsn"
 	LogLevelFlag               = "log-level"
 	ParallelPoolSize           = "parallel-pool-size"
 	MaxProjectsPerPR           = "max-projects-per-pr"
+	PlanEncryptionKeyFileFlag  = "plan-encryption-key-file"
+	PluginCacheMaxSizeMBFlag   = "plugin-cache-max-size-mb"
+	PluginCacheWarmUpFlag      = "plugin-cache-warm-up"
 	StatsNamespace             = "stats-namespace"
 	AllowDraftPRs              = "allow-draft-prs"
 	PortFlag                   = "port"
@@ -96,6 +110,9 @@ const (
 	SSLCertFileFlag              = "ssl-cert-file"
 	SSLKeyFileFlag               = "ssl-key-file"
 	TFDownloadURLFlag            = "tf-download-url"
+	TFSigningKeyFileFlag         = "tf-signing-key-file"
+	TofuDownloadURLFlag          = "tofu-download-url"
+	TofuSigningKeyFileFlag       = "tofu-signing-key-file"
 	VCSStatusName                = "vcs-status-name"
 	WriteGitFileFlag             = "write-git-creds"
 	LyftAuditJobsSnsTopicArnFlag = "lyft-audit-jobs-sns-topic-arn"
@@ -113,11 +130,13 @@ const (
 	DefaultDataDir                = "~/.atlantis"
 	DefaultGHHostname             = "github.com"
 	DefaultGitlabHostname         = "gitlab.com"
+	DefaultLockingDBType          = "boltdb"
 	DefaultLogLevel               = "info"
 	DefaultParallelPoolSize       = 15
 	DefaultStatsNamespace         = "atlantis"
 	DefaultPort                   = 4141
 	DefaultTFDownloadURL          = "https://releases.hashicorp.com"
+	DefaultTofuDownloadURL        = "https://github.com/opentofu/opentofu/releases/download"
 	DefaultVCSStatusName          = "atlantis"
 )
 
@@ -150,6 +169,17 @@ var stringFlags = map[string]stringFlag{
 			" A custom Workflow that uses autoplan 'when_modified' will ignore this value.",
 		defaultValue: DefaultAutoplanFileList,
 	},
+	BinaryChecksumsFileFlag: {
+		description: "File of pinned SHA256 checksums for downloaded terraform, OpenTofu and conftest archives in the SHA256SUMS format." +
+			" When set, archives without a pinned checksum are rejected unless their release checksums are signed by a trusted key." +
+			" Conftest releases aren't signed so every conftest version must be pinned.",
+	},
+	BinaryMirrorDirFlag: {
+		description: "Directory of pre-seeded terraform and conftest releases which is checked before downloading a version." +
+			" Release archives, checksums and signatures are stored as <binary>/<version>/<file name>, are verified like downloads" +
+			" and can be populated with the mirror command." +
+			" Takes precedence over the binary_mirror_prefix persistence config.",
+	},
 	BitbucketUserFlag: {
 		description: "Bitbucket username of API user.",
 	},
@@ -247,6 +277,16 @@ var stringFlags = map[string]stringFlag{
 			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
 			"Should be specified via the ATLANTIS_GITLAB_WEBHOOK_SECRET environment variable.",
 	},
+	LockingDBTypeFlag: {
+		description:  "Database which stores locks and pull request statuses. Either boltdb, which is stored in the data dir and limited to a single server, or sql, which can be shared by multiple servers.",
+		defaultValue: DefaultLockingDBType,
+	},
+	LockingDBSQLDriverFlag: {
+		description: "SQL driver used when --" + LockingDBTypeFlag + " is sql. Either postgres or sqlite.",
+	},
+	LockingDBSQLDSNFlag: {
+		description: "Connection string of the SQL database used when --" + LockingDBTypeFlag + " is sql. Can also be specified via the ATLANTIS_LOCKING_DB_SQL_DSN environment variable.",
+	},
 	LogLevelFlag: {
 		description:  "Log level. Either debug, info, warn, or error.",
 		defaultValue: DefaultLogLevel,
@@ -281,9 +321,34 @@ var stringFlags = map[string]stringFlag{
 		description: fmt.Sprintf("File containing x509 private key matching --%s.", SSLCertFileFlag),
 	},
 	TFDownloadURLFlag: {
-		description:  "Base URL to download Terraform versions from.",
+		description: "Base URL to download Terraform versions from." +
+			" Since HashiCorp's release key is trusted by default when it's installed, as it is in the docker image, mirrors must serve" +
+			" the <SHA256SUMS>.sig signature alongside the checksums of each version unless its checksum is pinned with --" + BinaryChecksumsFileFlag + ".",
 		defaultValue: DefaultTFDownloadURL,
 	},
+	PlanEncryptionKeyFileFlag: {
+		description: "File containing the hex encoded 32 byte key used to encrypt plan artifacts at rest." +
+			" Required when plan_store_prefix is set in --" + RepoConfigFlag + "." +
+			" Artifacts are deleted once their apply finishes, configure a lifecycle rule expiring objects under the prefix" +
+			" to remove artifacts of deployments which were terminated before then.",
+	},
+	TFSigningKeyFileFlag: {
+		description: "File containing the armored PGP public key used to verify the signature of terraform release checksums." +
+			" Versions without a pinned checksum are only downloaded if their checksums are signed by this key." +
+			" Defaults to HashiCorp's release key " + checksum.HashiCorpKeyFingerprint + ", which is embedded in atlantis.",
+	},
+	TofuDownloadURLFlag: {
+		description:  "Base URL to download OpenTofu versions from.",
+		defaultValue: DefaultTofuDownloadURL,
+	},
+	TofuSigningKeyFileFlag: {
+		description: "File containing the armored PGP public key used to verify the signature of OpenTofu release checksums." +
+			" Versions without a pinned checksum are only downloaded if their checksums are signed by this key.",
+	},
+	DefaultTofuVersionFlag: {
+		description: "OpenTofu version to default to for roots using the opentofu engine (ex. v1.6.0). Will download if not yet on disk." +
+			" If not set, opentofu roots must set a terraform_version.",
+	},
 	DefaultTFVersionFlag: {
 		description: "Terraform version to default to (ex. v0.12.0). Will download if not yet on disk." +
 			" If not set, Atlantis uses the terraform binary in its PATH.",
@@ -335,6 +400,10 @@ var boolFlags = map[string]boolFlag{
 		description:  "Enable Atlantis to format Terraform plan output into a markdown-diff friendly format for color-coding purposes.",
 		defaultValue: false,
 	},
+	EnableTofuFlag: {
+		description:  "Enable OpenTofu as an engine for roots which set engine: opentofu. Versions are downloaded from --" + TofuDownloadURLFlag + ".",
+		defaultValue: false,
+	},
 	AllowDraftPRs: {
 		description:  "Enable autoplan for Github Draft Pull Requests",
 		defaultValue: false,
@@ -348,6 +417,10 @@ var boolFlags = map[string]boolFlag{
 		description:  "Toggle off folding in markdown output.",
 		defaultValue: false,
 	},
+	PluginCacheWarmUpFlag: {
+		description:  "Install the providers of previously initialized roots into the shared plugin cache on startup.",
+		defaultValue: false,
+	},
 	WriteGitFileFlag: {
 		description: "Write out a .git-credentials file with the provider user and token to allow cloning private modules over HTTPS or SSH." +
 			" This writes secrets to disk and should only be enabled in a secure environment.",
@@ -363,6 +436,18 @@ var intFlags = map[string]intFlag{
 		description:  "Max number of projects to operate on in a given pull request.",
 		defaultValue: events.InfiniteProjectsPerPR,
 	},
+	LockAbandonedDaysFlag: {
+		description:  "Days an open pull request can go without being updated before its locks are released. 0 disables releasing locks of abandoned pull requests.",
+		defaultValue: 0,
+	},
+	LockTTLHoursFlag: {
+		description:  "Hours after which project locks expire and are released. 0 means locks don't expire.",
+		defaultValue: 0,
+	},
+	PluginCacheMaxSizeMBFlag: {
+		description:  "Size in MB the shared plugin cache is evicted down to, least recently used providers are evicted first. 0 disables eviction.",
+		defaultValue: 0,
+	},
 	PortFlag: {
 		description:  "Port to bind to.",
 		defaultValue: DefaultPort,
@@ -531,12 +616,34 @@ func (t *TemporalWorker) NewServer(userConfig server.UserConfig, config server.C
 			DefaultVersion: userConfig.DefaultTFVersion,
 			DownloadURL:    userConfig.TFDownloadURL,
 			LogFilters:     globalCfg.TerraformLogFilter,
+			ChecksumsFile:  userConfig.BinaryChecksumsFile,
+			SigningKeyFile: userConfig.TFSigningKeyFile,
+			BinaryMirror: neptune.BinaryMirrorConfig{
+				Dir:   userConfig.BinaryMirrorDir,
+				Store: globalCfg.PersistenceConfig.BinaryMirror,
+			},
+			Tofu: neptune.TofuConfig{
+				Enabled:        userConfig.EnableTofu,
+				DefaultVersion: userConfig.DefaultTofuVersion,
+				DownloadURL:    userConfig.TofuDownloadURL,
+				SigningKeyFile: userConfig.TofuSigningKeyFile,
+			},
+			PluginCache: neptune.PluginCacheConfig{
+				MaxSizeMB: userConfig.PluginCacheMaxSizeMB,
+				WarmUp:    userConfig.PluginCacheWarmUp,
+			},
+			PlanArtifacts: neptune.PlanArtifactsConfig{
+				Store:             globalCfg.PersistenceConfig.Plans,
+				EncryptionKeyFile: userConfig.PlanEncryptionKeyFile,
+			},
 		},
 		ValidationConfig: neptune.ValidationConfig{
 			DefaultVersion: globalCfg.PolicySets.Version,
 			Policies:       globalCfg.PolicySets,
+			ChecksumsFile:  userConfig.BinaryChecksumsFile,
 		},
 		JobConfig:                globalCfg.PersistenceConfig.Jobs,
+		JobRetention:             globalCfg.PersistenceConfig.JobRetention,
 		DeploymentConfig:         globalCfg.PersistenceConfig.Deployments,
 		DataDir:                  userConfig.DataDir,
 		TemporalCfg:              globalCfg.Temporal,
@@ -546,6 +653,7 @@ func (t *TemporalWorker) NewServer(userConfig server.UserConfig, config server.C
 		Metrics:                  globalCfg.Metrics,
 		LyftAuditJobsSnsTopicArn: userConfig.LyftAuditJobsSnsTopicArn,
 		RevisionSetter:           globalCfg.RevisionSetter,
+		FreezeWindows:            globalCfg.FreezeWindows,
 	}
 	return temporalworker.NewServer(cfg)
 }
@@ -737,6 +845,9 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig) {
 	if c.BitbucketBaseURL == "" {
 		c.BitbucketBaseURL = DefaultBitbucketBaseURL
 	}
+	if c.LockingDBType == "" {
+		c.LockingDBType = DefaultLockingDBType
+	}
 	if c.LogLevel == "" {
 		c.LogLevel = DefaultLogLevel
 	}
@@ -752,6 +863,9 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig) {
 	if c.TFDownloadURL == "" {
 		c.TFDownloadURL = DefaultTFDownloadURL
 	}
+	if c.TofuDownloadURL == "" {
+		c.TofuDownloadURL = DefaultTofuDownloadURL
+	}
 	if c.VCSStatusName == "" {
 		c.VCSStatusName = DefaultVCSStatusName
 	}
@@ -771,6 +885,23 @@ func (s *ServerCmd) validate(userConfig server.UserConfig, logger logging.Logger
 		return errors.New("invalid checkout strategy: not one of branch or merge")
 	}
 
+	if userConfig.LockTTLHours < 0 || userConfig.LockAbandonedDays < 0 {
+		return fmt.Errorf("--%s and --%s can't be negative", LockTTLHoursFlag, LockAbandonedDaysFlag)
+	}
+
+	switch userConfig.LockingDBType {
+	case "boltdb":
+	case "sql":
+		if userConfig.LockingDBSQLDriver != db.PostgresDriver && userConfig.LockingDBSQLDriver != db.SQLiteDriver {
+			return fmt.Errorf("invalid --%s: not one of %s or %s", LockingDBSQLDriverFlag, db.PostgresDriver, db.SQLiteDriver)
+		}
+		if userConfig.LockingDBSQLDSN == "" {
+			return fmt.Errorf("--%s must be set when --%s is sql", LockingDBSQLDSNFlag, LockingDBTypeFlag)
+		}
+	default:
+		return fmt.Errorf("invalid --%s: not one of boltdb or sql", LockingDBTypeFlag)
+	}
+
 	if (userConfig.SSLKeyFile == "") != (userConfig.SSLCertFile == "") {
 		return fmt.Errorf("--%s and --%s are both required for ssl", SSLKeyFileFlag, SSLCertFileFlag)
 	}
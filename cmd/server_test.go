@@ -57,6 +57,8 @@ var testFlags = map[string]interface{}{
 	ADWebhookUserFlag:            "ad-wh-user",
 	AtlantisURLFlag:              "url",
 	AutoplanFileListFlag:         "**/*.tf,**/*.yml",
+	BinaryChecksumsFileFlag:      "/path/to/SHA256SUMS",
+	BinaryMirrorDirFlag:          "/path/to/mirror",
 	BitbucketBaseURLFlag:         "https://bitbucket-base-url.com",
 	BitbucketTokenFlag:           "bitbucket-token",
 	BitbucketUserFlag:            "bitbucket-user",
@@ -64,6 +66,7 @@ var testFlags = map[string]interface{}{
 	CheckoutStrategyFlag:         "merge",
 	DataDirFlag:                  "/path",
 	DefaultTFVersionFlag:         "v0.11.0",
+	DefaultTofuVersionFlag:       "v1.6.0",
 	DisableApplyAllFlag:          true,
 	DisableApplyFlag:             true,
 	DisableMarkdownFoldingFlag:   true,
@@ -79,16 +82,27 @@ var testFlags = map[string]interface{}{
 	GitlabTokenFlag:              "gitlab-token",
 	GitlabUserFlag:               "gitlab-user",
 	GitlabWebhookSecretFlag:      "gitlab-secret",
+	LockAbandonedDaysFlag:        7,
+	LockTTLHoursFlag:             72,
+	LockingDBTypeFlag:            "sql",
+	LockingDBSQLDriverFlag:       "sqlite",
+	LockingDBSQLDSNFlag:          "atlantis.db",
 	LogLevelFlag:                 "debug",
 	StatsNamespace:               "atlantis",
 	AllowDraftPRs:                true,
 	PortFlag:                     8181,
 	ParallelPoolSize:             100,
+	PlanEncryptionKeyFileFlag:    "/path/to/plan.key",
+	PluginCacheMaxSizeMBFlag:     2048,
+	PluginCacheWarmUpFlag:        true,
 	RepoAllowlistFlag:            "github.com/runatlantis/atlantis",
 	SlackTokenFlag:               "slack-token",
 	SSLCertFileFlag:              "cert-file",
 	SSLKeyFileFlag:               "key-file",
 	TFDownloadURLFlag:            "https://my-hostname.com",
+	TFSigningKeyFileFlag:         "/path/to/key.asc",
+	TofuDownloadURLFlag:          "https://my-tofu-hostname.com",
+	TofuSigningKeyFileFlag:       "/path/to/tofu-key.asc",
 	VCSStatusName:                "my-status",
 	WriteGitFileFlag:             true,
 	LyftAuditJobsSnsTopicArnFlag: "",
@@ -98,6 +112,7 @@ var testFlags = map[string]interface{}{
 	DisableAutoplanFlag:          true,
 	EnableRegExpCmdFlag:          false,
 	EnableDiffMarkdownFormat:     false,
+	EnableTofuFlag:               true,
 }
 
 func TestExecute_Defaults(t *testing.T) {
@@ -306,6 +321,59 @@ func TestExecute_ValidateCheckoutStrategy(t *testing.T) {
 	ErrEquals(t, "invalid checkout strategy: not one of branch or merge", err)
 }
 
+func TestExecute_ValidateLockingDB(t *testing.T) {
+	cases := []struct {
+		description string
+		flags       map[string]interface{}
+		expErr      string
+	}{
+		{
+			"invalid type",
+			map[string]interface{}{
+				LockingDBTypeFlag: "redis",
+			},
+			"invalid --locking-db-type: not one of boltdb or sql",
+		},
+		{
+			"invalid driver",
+			map[string]interface{}{
+				LockingDBTypeFlag:      "sql",
+				LockingDBSQLDriverFlag: "mysql",
+				LockingDBSQLDSNFlag:    "dsn",
+			},
+			"invalid --locking-db-sql-driver: not one of postgres or sqlite",
+		},
+		{
+			"missing dsn",
+			map[string]interface{}{
+				LockingDBTypeFlag:      "sql",
+				LockingDBSQLDriverFlag: "postgres",
+			},
+			"--locking-db-sql-dsn must be set when --locking-db-type is sql",
+		},
+		{
+			"valid sql",
+			map[string]interface{}{
+				LockingDBTypeFlag:      "sql",
+				LockingDBSQLDriverFlag: "postgres",
+				LockingDBSQLDSNFlag:    "postgres://localhost/atlantis",
+			},
+			"",
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.description, func(t *testing.T) {
+			c := setupWithDefaults(testCase.flags, t)
+			err := c.Execute()
+			if testCase.expErr != "" {
+				ErrEquals(t, testCase.expErr, err)
+			} else {
+				Ok(t, err)
+			}
+		})
+	}
+}
+
 func TestExecute_ValidateSSLConfig(t *testing.T) {
 	expErr := "--ssl-key-file and --ssl-cert-file are both required for ssl"
 	cases := []struct {
@@ -30,9 +30,11 @@ func main() {
 	// (as recommended by cobra) because it makes testing easier.
 	server := cmd.NewServerCmd(v, atlantisVersion)
 	version := &cmd.VersionCmd{AtlantisVersion: atlantisVersion}
+	mirror := &cmd.MirrorCmd{}
 
 	cmd.RootCmd.AddCommand(server.Init())
 	cmd.RootCmd.AddCommand(version.Init())
+	cmd.RootCmd.AddCommand(mirror.Init())
 
 	cmd.Execute()
 }
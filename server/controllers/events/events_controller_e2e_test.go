@@ -824,7 +824,7 @@ func setupE2E(t *testing.T, repoFixtureDir string, userConfig *server.UserConfig
 	conftestVersion, err := version.NewVersion(ConftestVersion)
 	Ok(t, err)
 
-	conftextExec := policy.NewConfTestVersionEnsurer(ctxLogger, binDir, downloader)
+	conftextExec := policy.NewConfTestVersionEnsurer(ctxLogger, binDir, downloader, nil, nil)
 
 	// swapping out version cache to something that always returns local contest
 	// binary
@@ -31,7 +31,7 @@ type JobsController struct {
 	Logger                   logging.Logger
 	ProjectJobsTemplate      templates.TemplateWriter
 	ProjectJobsErrorTemplate templates.TemplateWriter
-	Db                       *db.BoltDB
+	Db                       db.Database
 	WsMux                    websocket.Multiplexor
 	StatsScope               tally.Scope
 	KeyGenerator             JobIDKeyGenerator
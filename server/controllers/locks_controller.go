@@ -27,7 +27,7 @@ type LocksController struct {
 	LockDetailTemplate templates.TemplateWriter
 	WorkingDir         events.WorkingDir
 	WorkingDirLocker   events.WorkingDirLocker
-	DB                 *db.BoltDB
+	DB                 db.Database
 	DeleteLockCommand  events.DeleteLockCommand
 }
 
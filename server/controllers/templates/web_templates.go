@@ -626,3 +626,118 @@ var GithubAppSetupTemplate = template.Must(template.New("github-app.html.tmpl").
 </body>
 </html>
 `))
+
+// DeploymentData holds the fields needed to display a single deployment of a root.
+type DeploymentData struct {
+	ID       string `json:"id"`
+	Revision string `json:"revision"`
+	Branch   string `json:"branch"`
+	User     string `json:"user,omitempty"`
+	Trigger  string `json:"trigger"`
+	// PlanJobURL and ApplyJobURL are empty until the job is started
+	PlanJobURL  string `json:"plan_job_url,omitempty"`
+	ApplyJobURL string `json:"apply_job_url,omitempty"`
+}
+
+// DeployRootData holds the deploy state of a root.
+type DeployRootData struct {
+	Repo              string           `json:"repo"`
+	Root              string           `json:"root"`
+	LastDeployment    *DeploymentData  `json:"last_deployment,omitempty"`
+	Locked            bool             `json:"locked"`
+	LockedRevision    string           `json:"locked_revision,omitempty"`
+	CurrentDeployment *DeploymentData  `json:"current_deployment,omitempty"`
+	Queue             []DeploymentData `json:"queue"`
+	// Error is set when the root's deploy workflow couldn't be queried
+	Error string `json:"error,omitempty"`
+}
+
+// DeploymentsIndexData holds the data for rendering the deployments page
+type DeploymentsIndexData struct {
+	Roots           []DeployRootData `json:"roots"`
+	AtlantisVersion string           `json:"-"`
+	// CleanedBasePath is the path Atlantis is accessible at externally. If
+	// not using a path-based proxy, this will be an empty string. Never ends
+	// in a '/' (hence "cleaned").
+	CleanedBasePath string `json:"-"`
+}
+
+var DeploymentsIndexTemplate = template.Must(template.New("deployments.html.tmpl").Parse(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>atlantis</title>
+  <meta name="description" content="">
+  <meta name="author" content="">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/normalize.css">
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/skeleton.css">
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/custom.css">
+  <link rel="icon" type="image/png" href="{{ .CleanedBasePath }}/static/images/atlantis-icon.png">
+  <style>
+    .deploy-table td, .deploy-table th {
+      vertical-align: top;
+    }
+  </style>
+</head>
+<body>
+<div class="container">
+  <section class="header">
+    <a title="atlantis" href="{{ .CleanedBasePath }}/"><img class="hero" src="{{ .CleanedBasePath }}/static/images/atlantis-icon_512.png"/></a>
+    <p class="title-heading">atlantis</p>
+  </section>
+  <section>
+    <p class="title-heading small"><strong>Deployments</strong></p>
+    {{ if .Roots }}
+    <table class="u-full-width deploy-table">
+      <thead>
+        <tr>
+          <th>Repo</th>
+          <th>Root</th>
+          <th>Last Deployment</th>
+          <th>Lock</th>
+          <th>In Progress</th>
+          <th>Queue</th>
+        </tr>
+      </thead>
+      <tbody>
+      {{ range .Roots }}
+        <tr>
+          <td>{{ .Repo }}</td>
+          <td><code>{{ .Root }}</code></td>
+          <td>
+            {{ with .LastDeployment }}{{ template "deployment" . }}{{ else }}-{{ end }}
+          </td>
+          {{ if .Error }}
+          <td colspan="3"><span class="heading-font-size">{{ .Error }}</span></td>
+          {{ else }}
+          <td>{{ if .Locked }}<code>Locked</code> {{ .LockedRevision }}{{ else }}Unlocked{{ end }}</td>
+          <td>
+            {{ with .CurrentDeployment }}{{ template "deployment" . }}{{ else }}-{{ end }}
+          </td>
+          <td>
+            {{ range .Queue }}{{ template "deployment" . }}<br>{{ else }}Empty{{ end }}
+          </td>
+          {{ end }}
+        </tr>
+      {{ end }}
+      </tbody>
+    </table>
+    {{ else }}
+    <p class="placeholder">No deployments found.</p>
+    {{ end }}
+  </section>
+</div>
+<footer>
+v{{ .AtlantisVersion }}
+</footer>
+</body>
+</html>
+{{ define "deployment" }}
+<code>{{ .Revision }}</code> on {{ .Branch }}<br>
+<span class="heading-font-size">{{ .Trigger }}{{ if .User }} by {{ .User }}{{ end }}</span>
+{{ if .PlanJobURL }}<br><a href="{{ .PlanJobURL }}">plan</a>{{ end }}
+{{ if .ApplyJobURL }} <a href="{{ .ApplyJobURL }}">apply</a>{{ end }}
+{{ end }}
+`))
@@ -77,6 +77,9 @@ func (p *ParserValidator) ParseRepoCfgData(repoCfgData []byte, globalCfg valid.G
 	if err := p.validateProjectNames(validConfig); err != nil {
 		return valid.RepoCfg{}, err
 	}
+	if err := p.validateProjectDependencies(validConfig); err != nil {
+		return valid.RepoCfg{}, err
+	}
 	if validConfig.Version == 2 {
 		// The only difference between v2 and v3 is how we parse custom run
 		// commands.
@@ -172,6 +175,76 @@ func (p *ParserValidator) validateProjectNames(config valid.RepoCfg) error {
 	return nil
 }
 
+// validateProjectDependencies ensures that projects only depend on other named
+// projects and that there are no cycles between them.
+func (p *ParserValidator) validateProjectDependencies(config valid.RepoCfg) error {
+	dependencies := make(map[string][]string)
+	for _, project := range config.Projects {
+		if project.Name != nil {
+			dependencies[*project.Name] = project.DependsOn
+		}
+	}
+
+	for _, project := range config.Projects {
+		if len(project.DependsOn) == 0 {
+			continue
+		}
+		if project.Name == nil {
+			return fmt.Errorf("project with dir: %q workspace: %q sets depends_on but isn't named; it must have a 'name' key so other projects can be ordered against it", project.Dir, project.Workspace)
+		}
+
+		name := *project.Name
+		for _, dependency := range project.DependsOn {
+			if dependency == name {
+				return fmt.Errorf("project %q cannot depend on itself", name)
+			}
+			if _, ok := dependencies[dependency]; !ok {
+				return fmt.Errorf("project %q depends on %q which is not a project name", name, dependency)
+			}
+		}
+	}
+
+	// Depth first search for a path which leads back to a project we're visiting.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(path []string) error
+	visit = func(path []string) error {
+		name := path[len(path)-1]
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return fmt.Errorf("found a dependency cycle between projects: %s", strings.Join(path[i:], " -> "))
+				}
+			}
+		}
+
+		state[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if err := visit(append(path, dependency)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for _, project := range config.Projects {
+		if project.Name == nil {
+			continue
+		}
+		if err := visit([]string{*project.Name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // applyLegacyShellParsing changes any custom run commands in cfg to use the old
 // parsing method with shlex.Split().
 func (p *ParserValidator) applyLegacyShellParsing(cfg *valid.RepoCfg) error {
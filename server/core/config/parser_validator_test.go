@@ -781,6 +781,89 @@ projects:
 				Workflows: map[string]valid.Workflow{},
 			},
 		},
+		{
+			description: "project depends on another project",
+			input: `
+version: 3
+projects:
+- name: network
+  dir: network
+- name: service
+  dir: service
+  depends_on: [network]`,
+			exp: valid.RepoCfg{
+				Version: 3,
+				Projects: []valid.Project{
+					{
+						Name:      String("network"),
+						Dir:       "network",
+						Workspace: "default",
+						Autoplan: valid.Autoplan{
+							WhenModified: []string{"**/*.tf*", "**/terragrunt.hcl"},
+							Enabled:      true,
+						},
+					},
+					{
+						Name:      String("service"),
+						Dir:       "service",
+						Workspace: "default",
+						Autoplan: valid.Autoplan{
+							WhenModified: []string{"**/*.tf*", "**/terragrunt.hcl"},
+							Enabled:      true,
+						},
+						DependsOn: []string{"network"},
+					},
+				},
+				Workflows: map[string]valid.Workflow{},
+			},
+		},
+		{
+			description: "unnamed project depends on another project",
+			input: `
+version: 3
+projects:
+- name: network
+  dir: network
+- dir: service
+  depends_on: [network]`,
+			expErr: "project with dir: \"service\" workspace: \"default\" sets depends_on but isn't named; it must have a 'name' key so other projects can be ordered against it",
+		},
+		{
+			description: "project depends on itself",
+			input: `
+version: 3
+projects:
+- name: network
+  dir: network
+  depends_on: [network]`,
+			expErr: "project \"network\" cannot depend on itself",
+		},
+		{
+			description: "project depends on unknown project",
+			input: `
+version: 3
+projects:
+- name: service
+  dir: service
+  depends_on: [network]`,
+			expErr: "project \"service\" depends on \"network\" which is not a project name",
+		},
+		{
+			description: "project dependency cycle",
+			input: `
+version: 3
+projects:
+- name: network
+  dir: network
+  depends_on: [dns]
+- name: dns
+  dir: dns
+  depends_on: [service]
+- name: service
+  dir: service
+  depends_on: [network]`,
+			expErr: "found a dependency cycle between projects: network -> dns -> service -> network",
+		},
 		{
 			description: "if steps are set then we parse them properly",
 			input: `
@@ -0,0 +1,95 @@
+package raw
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// ApprovalPolicy configures which deployment plans are applied automatically
+// and which require manual approval.  Each resource change is assigned the approval
+// of the first rule which matches it, if any change requires manual approval the
+// entire plan does.
+type ApprovalPolicy struct {
+	// Default is used for resource changes which don't match any rule and defaults to auto
+	Default    string         `yaml:"default,omitempty" json:"default,omitempty"`
+	MaxChanges int            `yaml:"max_changes,omitempty" json:"max_changes,omitempty"`
+	Rules      []ApprovalRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+func (p ApprovalPolicy) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Default, validation.In(string(valid.AutoApprovalType), string(valid.ManualApprovalType))),
+		validation.Field(&p.MaxChanges, validation.Min(0)),
+		validation.Field(&p.Rules),
+	)
+}
+
+func (p ApprovalPolicy) ToValid() *valid.ApprovalPolicy {
+	defaultApproval := valid.AutoApprovalType
+	if p.Default != "" {
+		defaultApproval = valid.ApprovalType(p.Default)
+	}
+
+	var rules []valid.ApprovalRule
+	for _, r := range p.Rules {
+		rules = append(rules, r.ToValid())
+	}
+
+	return &valid.ApprovalPolicy{
+		Default:    defaultApproval,
+		MaxChanges: p.MaxChanges,
+		Rules:      rules,
+	}
+}
+
+// ApprovalRule matches resource changes by action, resource type and address glob
+// where `*` matches any sequence of characters.  Omitted criteria match everything.
+type ApprovalRule struct {
+	Actions       []string `yaml:"actions,omitempty" json:"actions,omitempty"`
+	ResourceTypes []string `yaml:"resource_types,omitempty" json:"resource_types,omitempty"`
+	Addresses     []string `yaml:"addresses,omitempty" json:"addresses,omitempty"`
+	Approval      string   `yaml:"approval" json:"approval"`
+}
+
+func (r ApprovalRule) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Actions, validation.By(func(value interface{}) error {
+			actions, _ := value.([]string)
+			for _, a := range actions {
+				if err := validation.Validate(a, validation.In(valid.CreateAction, valid.UpdateAction, valid.DeleteAction, valid.ReplaceAction)); err != nil {
+					return errors.Wrapf(err, "action %q", a)
+				}
+			}
+			return nil
+		})),
+		validation.Field(&r.ResourceTypes, validation.By(nonEmptyStrings)),
+		validation.Field(&r.Addresses, validation.By(nonEmptyStrings)),
+		validation.Field(&r.Approval, validation.Required, validation.In(string(valid.AutoApprovalType), string(valid.ManualApprovalType))),
+	)
+}
+
+func nonEmptyStrings(value interface{}) error {
+	values, _ := value.([]string)
+	for _, v := range values {
+		if v == "" {
+			return errors.New("cannot be empty")
+		}
+	}
+	return nil
+}
+
+func (r ApprovalRule) ToValid() valid.ApprovalRule {
+	// patterns are compiled once here rather than for each resource change they're matched against
+	var addresses []valid.AddressPattern
+	for _, a := range r.Addresses {
+		addresses = append(addresses, valid.NewAddressPattern(a))
+	}
+
+	return valid.ApprovalRule{
+		Actions:       r.Actions,
+		ResourceTypes: r.ResourceTypes,
+		Addresses:     addresses,
+		Approval:      valid.ApprovalType(r.Approval),
+	}
+}
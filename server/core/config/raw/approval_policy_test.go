@@ -0,0 +1,120 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestApprovalPolicy_Unmarshal(t *testing.T) {
+	rawYaml := `
+approval_policy:
+  default: manual
+  max_changes: 20
+  rules:
+    - actions: [create]
+      approval: auto
+    - actions: [delete, replace]
+      resource_types: [aws_db_instance]
+      addresses: ["module.prod.*"]
+      approval: manual
+`
+
+	var result raw.DeploymentWorkflow
+
+	err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Validate())
+	assert.Equal(t, &valid.ApprovalPolicy{
+		Default:    valid.ManualApprovalType,
+		MaxChanges: 20,
+		Rules: []valid.ApprovalRule{
+			{
+				Actions:  []string{"create"},
+				Approval: valid.AutoApprovalType,
+			},
+			{
+				Actions:       []string{"delete", "replace"},
+				ResourceTypes: []string{"aws_db_instance"},
+				Addresses:     []valid.AddressPattern{valid.NewAddressPattern("module.prod.*")},
+				Approval:      valid.ManualApprovalType,
+			},
+		},
+	}, result.ToValid("default").ApprovalPolicy)
+}
+
+func TestApprovalPolicy_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		subject     raw.ApprovalPolicy
+		expectErr   bool
+	}{
+		{
+			description: "empty",
+			subject:     raw.ApprovalPolicy{},
+		},
+		{
+			description: "invalid default",
+			subject:     raw.ApprovalPolicy{Default: "sometimes"},
+			expectErr:   true,
+		},
+		{
+			description: "negative max changes",
+			subject:     raw.ApprovalPolicy{MaxChanges: -1},
+			expectErr:   true,
+		},
+		{
+			description: "invalid action",
+			subject: raw.ApprovalPolicy{
+				Rules: []raw.ApprovalRule{
+					{Actions: []string{"destroy"}, Approval: "manual"},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			description: "missing rule approval",
+			subject: raw.ApprovalPolicy{
+				Rules: []raw.ApprovalRule{
+					{Actions: []string{"delete"}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			description: "empty address",
+			subject: raw.ApprovalPolicy{
+				Rules: []raw.ApprovalRule{
+					{Addresses: []string{""}, Approval: "manual"},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.subject.Validate()
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestApprovalPolicy_ToValid(t *testing.T) {
+	t.Run("default approval", func(t *testing.T) {
+		assert.Equal(t, &valid.ApprovalPolicy{
+			Default: valid.AutoApprovalType,
+		}, raw.ApprovalPolicy{}.ToValid())
+	})
+
+	t.Run("omitted", func(t *testing.T) {
+		assert.Nil(t, raw.DeploymentWorkflow{}.ToValid("default").ApprovalPolicy)
+	})
+}
@@ -26,12 +26,17 @@ func (w DeploymentWorkflows) ToValid(defaultCfg valid.GlobalCfg) map[string]vali
 type DeploymentWorkflow struct {
 	Apply *Stage `yaml:"apply,omitempty" json:"apply,omitempty"`
 	Plan  *Stage `yaml:"plan,omitempty" json:"plan,omitempty"`
+
+	ApprovalPolicy *ApprovalPolicy `yaml:"approval_policy,omitempty" json:"approval_policy,omitempty"`
+	Promotion      Promotion       `yaml:"promotion,omitempty" json:"promotion,omitempty"`
 }
 
 func (w DeploymentWorkflow) Validate() error {
 	return validation.ValidateStruct(&w,
 		validation.Field(&w.Apply),
 		validation.Field(&w.Plan),
+		validation.Field(&w.ApprovalPolicy),
+		validation.Field(&w.Promotion),
 	)
 }
 
@@ -51,5 +56,11 @@ func (w DeploymentWorkflow) ToValid(name string) valid.Workflow {
 	v.Apply = w.toValidStage(w.Apply, valid.DefaultApplyStage)
 	v.Plan = w.toValidStage(w.Plan, valid.DefaultPlanStage)
 
+	if w.ApprovalPolicy != nil {
+		v.ApprovalPolicy = w.ApprovalPolicy.ToValid()
+	}
+
+	v.Promotion = w.Promotion.ToValid()
+
 	return v
 }
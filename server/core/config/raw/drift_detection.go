@@ -0,0 +1,58 @@
+package raw
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+const DefaultDriftDetectionInterval = 24 * time.Hour
+
+// DriftDetection configures periodically planning deployed roots in order to detect changes
+// made outside of atlantis.
+type DriftDetection struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Interval is a duration string (ie. 12h) which defaults to DefaultDriftDetectionInterval
+	Interval string `yaml:"interval" json:"interval"`
+}
+
+func (d DriftDetection) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Interval, validation.By(func(value interface{}) error {
+			interval, _ := value.(string)
+			if interval == "" {
+				return nil
+			}
+
+			duration, err := time.ParseDuration(interval)
+			if err != nil {
+				return errors.Wrap(err, "parsing interval")
+			}
+
+			if duration <= 0 {
+				return errors.New("interval must be positive")
+			}
+			return nil
+		})),
+	)
+}
+
+func (d DriftDetection) ToValid() valid.DriftDetection {
+	if !d.Enabled {
+		return valid.DriftDetection{}
+	}
+
+	interval := DefaultDriftDetectionInterval
+	if d.Interval != "" {
+		// validated prior
+		interval, _ = time.ParseDuration(d.Interval)
+	}
+
+	return valid.DriftDetection{
+		Enabled:  d.Enabled,
+		Interval: interval,
+	}
+}
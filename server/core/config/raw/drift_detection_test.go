@@ -0,0 +1,75 @@
+package raw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDriftDetection_Unmarshal(t *testing.T) {
+	rawYaml := `
+enabled: true
+interval: 12h
+`
+
+	var result raw.DriftDetection
+
+	err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Validate())
+	assert.Equal(t, valid.DriftDetection{
+		Enabled:  true,
+		Interval: 12 * time.Hour,
+	}, result.ToValid())
+}
+
+func TestDriftDetection_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		subject     raw.DriftDetection
+		expectErr   bool
+	}{
+		{
+			description: "default interval",
+			subject:     raw.DriftDetection{Enabled: true},
+		},
+		{
+			description: "invalid interval",
+			subject:     raw.DriftDetection{Enabled: true, Interval: "daily"},
+			expectErr:   true,
+		},
+		{
+			description: "negative interval",
+			subject:     raw.DriftDetection{Enabled: true, Interval: "-1h"},
+			expectErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.subject.Validate()
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDriftDetection_ToValid(t *testing.T) {
+	t.Run("default interval", func(t *testing.T) {
+		assert.Equal(t, valid.DriftDetection{
+			Enabled:  true,
+			Interval: raw.DefaultDriftDetectionInterval,
+		}, raw.DriftDetection{Enabled: true}.ToValid())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, valid.DriftDetection{}, raw.DriftDetection{Interval: "1h"}.ToValid())
+	})
+}
@@ -0,0 +1,159 @@
+package raw
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// FreezeWindows are periods during which deploys of matching roots are blocked.
+type FreezeWindows []FreezeWindow
+
+func (f FreezeWindows) Validate() error {
+	seen := make(map[string]bool)
+	for i, w := range f {
+		if err := w.Validate(); err != nil {
+			return errors.Wrapf(err, "freeze window %d", i)
+		}
+
+		if seen[w.Name] {
+			return fmt.Errorf("freeze window %q is defined more than once", w.Name)
+		}
+		seen[w.Name] = true
+	}
+	return nil
+}
+
+func (f FreezeWindows) ToValid() []valid.FreezeWindow {
+	var windows []valid.FreezeWindow
+	for _, w := range f {
+		windows = append(windows, w.ToValid())
+	}
+	return windows
+}
+
+// FreezeWindow is either a one-off window between start and end or a recurring window
+// which starts every time schedule fires and lasts for duration.
+type FreezeWindow struct {
+	Name   string `yaml:"name" json:"name"`
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+
+	// Repos and Roots are globs, the window applies to every repo or root if they're empty
+	Repos []string `yaml:"repos,omitempty" json:"repos,omitempty"`
+	Roots []string `yaml:"roots,omitempty" json:"roots,omitempty"`
+
+	// Start and End are RFC3339 timestamps (ie. 2022-12-24T00:00:00Z)
+	Start string `yaml:"start,omitempty" json:"start,omitempty"`
+	End   string `yaml:"end,omitempty" json:"end,omitempty"`
+
+	// Schedule is a standard cron expression (ie. 0 17 * * 5) and Duration is a duration string (ie. 64h)
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	Duration string `yaml:"duration,omitempty" json:"duration,omitempty"`
+
+	// Timezone is the IANA time zone the schedule is evaluated in, defaults to UTC
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+func (w FreezeWindow) Validate() error {
+	recurring := w.Schedule != ""
+	oneOff := w.Start != "" || w.End != ""
+
+	if recurring == oneOff {
+		return fmt.Errorf("freeze window %q must set either start and end or schedule and duration", w.Name)
+	}
+
+	err := validation.ValidateStruct(&w,
+		validation.Field(&w.Name, validation.Required),
+		validation.Field(&w.Repos, validation.By(validGlobs)),
+		validation.Field(&w.Roots, validation.By(validGlobs)),
+		validation.Field(&w.Start, timestampRules(oneOff)...),
+		validation.Field(&w.End, timestampRules(oneOff)...),
+		validation.Field(&w.Schedule, validation.By(func(value interface{}) error {
+			schedule, _ := value.(string)
+			if schedule == "" {
+				return nil
+			}
+			_, err := cron.ParseStandard(schedule)
+			return errors.Wrap(err, "parsing schedule")
+		})),
+		validation.Field(&w.Duration, validation.By(func(value interface{}) error {
+			duration, _ := value.(string)
+			if duration == "" {
+				if recurring {
+					return errors.New("is required for recurring windows")
+				}
+				return nil
+			}
+
+			d, err := time.ParseDuration(duration)
+			if err != nil {
+				return errors.Wrap(err, "parsing duration")
+			}
+
+			if d <= 0 {
+				return errors.New("duration must be positive")
+			}
+			return nil
+		})),
+		validation.Field(&w.Timezone, validation.By(func(value interface{}) error {
+			timezone, _ := value.(string)
+			_, err := time.LoadLocation(timezone)
+			return errors.Wrap(err, "loading timezone")
+		})),
+	)
+	if err != nil {
+		return err
+	}
+
+	if oneOff {
+		// validated above
+		start, _ := time.Parse(time.RFC3339, w.Start)
+		end, _ := time.Parse(time.RFC3339, w.End)
+		if !end.After(start) {
+			return fmt.Errorf("freeze window %q must end after it starts", w.Name)
+		}
+	}
+	return nil
+}
+
+func (w FreezeWindow) ToValid() valid.FreezeWindow {
+	// validated prior
+	start, _ := time.Parse(time.RFC3339, w.Start)
+	end, _ := time.Parse(time.RFC3339, w.End)
+	duration, _ := time.ParseDuration(w.Duration)
+
+	return valid.FreezeWindow{
+		Name:     w.Name,
+		Reason:   w.Reason,
+		Repos:    w.Repos,
+		Roots:    w.Roots,
+		Start:    start,
+		End:      end,
+		Schedule: w.Schedule,
+		Duration: duration,
+		Timezone: w.Timezone,
+	}
+}
+
+func timestampRules(required bool) []validation.Rule {
+	var rules []validation.Rule
+	if required {
+		rules = append(rules, validation.Required)
+	}
+	return append(rules, validation.Date(time.RFC3339).Error("must be an RFC3339 timestamp"))
+}
+
+func validGlobs(value interface{}) error {
+	globs, _ := value.([]string)
+	for _, g := range globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return errors.Wrapf(err, "parsing %q", g)
+		}
+	}
+	return nil
+}
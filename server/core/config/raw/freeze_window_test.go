@@ -0,0 +1,143 @@
+package raw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestFreezeWindows_Unmarshal(t *testing.T) {
+	rawYaml := `
+- name: holidays
+  reason: change freeze over the holidays
+  repos: [github.com/owner/*]
+  roots: [prod-*]
+  start: 2022-12-20T00:00:00Z
+  end: 2023-01-03T00:00:00Z
+- name: weekends
+  schedule: 0 17 * * 5
+  duration: 64h
+  timezone: America/Los_Angeles
+`
+
+	var result raw.FreezeWindows
+
+	err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Validate())
+	assert.Equal(t, []valid.FreezeWindow{
+		{
+			Name:   "holidays",
+			Reason: "change freeze over the holidays",
+			Repos:  []string{"github.com/owner/*"},
+			Roots:  []string{"prod-*"},
+			Start:  time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC),
+			End:    time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "weekends",
+			Schedule: "0 17 * * 5",
+			Duration: 64 * time.Hour,
+			Timezone: "America/Los_Angeles",
+		},
+	}, result.ToValid())
+}
+
+func TestFreezeWindows_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		subject     raw.FreezeWindows
+		expectErr   bool
+	}{
+		{
+			description: "empty",
+			subject:     raw.FreezeWindows{},
+		},
+		{
+			description: "one-off",
+			subject:     raw.FreezeWindows{{Name: "incident", Start: "2022-12-20T00:00:00Z", End: "2022-12-21T00:00:00Z"}},
+		},
+		{
+			description: "recurring",
+			subject:     raw.FreezeWindows{{Name: "weekends", Schedule: "0 17 * * 5", Duration: "64h"}},
+		},
+		{
+			description: "missing name",
+			subject:     raw.FreezeWindows{{Start: "2022-12-20T00:00:00Z", End: "2022-12-21T00:00:00Z"}},
+			expectErr:   true,
+		},
+		{
+			description: "duplicate name",
+			subject: raw.FreezeWindows{
+				{Name: "incident", Start: "2022-12-20T00:00:00Z", End: "2022-12-21T00:00:00Z"},
+				{Name: "incident", Schedule: "0 17 * * 5", Duration: "64h"},
+			},
+			expectErr: true,
+		},
+		{
+			description: "neither one-off nor recurring",
+			subject:     raw.FreezeWindows{{Name: "incident"}},
+			expectErr:   true,
+		},
+		{
+			description: "both one-off and recurring",
+			subject:     raw.FreezeWindows{{Name: "incident", Start: "2022-12-20T00:00:00Z", End: "2022-12-21T00:00:00Z", Schedule: "0 17 * * 5", Duration: "64h"}},
+			expectErr:   true,
+		},
+		{
+			description: "missing end",
+			subject:     raw.FreezeWindows{{Name: "incident", Start: "2022-12-20T00:00:00Z"}},
+			expectErr:   true,
+		},
+		{
+			description: "invalid start",
+			subject:     raw.FreezeWindows{{Name: "incident", Start: "2022-12-20", End: "2022-12-21T00:00:00Z"}},
+			expectErr:   true,
+		},
+		{
+			description: "ends before it starts",
+			subject:     raw.FreezeWindows{{Name: "incident", Start: "2022-12-21T00:00:00Z", End: "2022-12-20T00:00:00Z"}},
+			expectErr:   true,
+		},
+		{
+			description: "invalid schedule",
+			subject:     raw.FreezeWindows{{Name: "weekends", Schedule: "fridays", Duration: "64h"}},
+			expectErr:   true,
+		},
+		{
+			description: "missing duration",
+			subject:     raw.FreezeWindows{{Name: "weekends", Schedule: "0 17 * * 5"}},
+			expectErr:   true,
+		},
+		{
+			description: "negative duration",
+			subject:     raw.FreezeWindows{{Name: "weekends", Schedule: "0 17 * * 5", Duration: "-1h"}},
+			expectErr:   true,
+		},
+		{
+			description: "invalid timezone",
+			subject:     raw.FreezeWindows{{Name: "weekends", Schedule: "0 17 * * 5", Duration: "64h", Timezone: "Mars/Olympus_Mons"}},
+			expectErr:   true,
+		},
+		{
+			description: "invalid glob",
+			subject:     raw.FreezeWindows{{Name: "incident", Roots: []string{"prod-["}, Start: "2022-12-20T00:00:00Z", End: "2022-12-21T00:00:00Z"}},
+			expectErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.subject.Validate()
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
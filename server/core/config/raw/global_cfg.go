@@ -25,6 +25,8 @@ type GlobalCfg struct {
 	Persistence          Persistence          `yaml:"persistence" json:"persistence"`
 	RevisionSetter       RevisionSetter       `yaml:"revision_setter" json:"revision_setter"`
 	Admin                Admin                `yaml:"admin" json:"admin"`
+	DriftDetection       DriftDetection       `yaml:"drift_detection" json:"drift_detection"`
+	FreezeWindows        FreezeWindows        `yaml:"freeze_windows" json:"freeze_windows"`
 }
 
 type GithubTeam struct {
@@ -77,6 +79,7 @@ type Repo struct {
 	TemplateOverrides           map[string]string `yaml:"template_overrides,omitempty" json:"template_overrides,omitempty"`
 	CheckoutStrategy            string            `yaml:"checkout_strategy,omitempty" json:"checkout_strategy,omitempty"`
 	ApplySettings               ApplySettings     `yaml:"apply_settings" json:"apply_settings"`
+	Engine                      *string           `yaml:"engine,omitempty" json:"engine,omitempty"`
 }
 
 func (g GlobalCfg) GetWorkflowNames() []string {
@@ -112,6 +115,8 @@ func (g GlobalCfg) Validate() error {
 		validation.Field(&g.Metrics),
 		validation.Field(&g.TerraformLogFilters),
 		validation.Field(&g.Persistence),
+		validation.Field(&g.DriftDetection),
+		validation.Field(&g.FreezeWindows),
 	)
 	if err != nil {
 		return err
@@ -199,6 +204,8 @@ func (g GlobalCfg) ToValid(defaultCfg valid.GlobalCfg) valid.GlobalCfg {
 		Temporal:             g.Temporal.ToValid(),
 		Admin:                g.Admin.ToValid(),
 		RevisionSetter:       g.RevisionSetter.ToValid(),
+		DriftDetection:       g.DriftDetection.ToValid(),
+		FreezeWindows:        g.FreezeWindows.ToValid(),
 	}
 }
 
@@ -261,6 +268,7 @@ func (r Repo) Validate() error {
 		validation.Field(&r.PullRequestWorkflow, validation.By(workflowExists)),
 		validation.Field(&r.DeploymentWorkflow, validation.By(workflowExists)),
 		validation.Field(&r.ApplySettings),
+		validation.Field(&r.Engine, validation.In(string(valid.TerraformEngine), string(valid.OpenTofuEngine))),
 	)
 }
 
@@ -340,6 +348,12 @@ OUTER:
 		checkoutStrategy = r.CheckoutStrategy
 	}
 
+	var engine *valid.Engine
+	if r.Engine != nil {
+		e := valid.Engine(*r.Engine)
+		engine = &e
+	}
+
 	return valid.Repo{
 		ID:                          id,
 		IDRegex:                     idRegex,
@@ -357,6 +371,7 @@ OUTER:
 		TemplateOverrides:           r.TemplateOverrides,
 		CheckoutStrategy:            checkoutStrategy,
 		ApplySettings:               r.ApplySettings.ToValid(),
+		Engine:                      engine,
 	}
 }
 
@@ -0,0 +1,99 @@
+package raw
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+const DefaultJobRetentionInterval = 1 * time.Hour
+
+// JobRetention expires job logs in the job store once they're older than max age.
+type JobRetention struct {
+	// MaxAge is a duration string (ie. 720h), logs of repos without an override are kept forever if it's empty
+	MaxAge string `yaml:"max_age" json:"max_age"`
+
+	// Interval is a duration string (ie. 1h) which defaults to DefaultJobRetentionInterval
+	Interval string `yaml:"interval" json:"interval"`
+
+	// Repos override the max age of matching repos, the first match wins
+	Repos []RepoJobRetention `yaml:"repos" json:"repos"`
+}
+
+// RepoJobRetention overrides the max age of job logs for repos matching name
+type RepoJobRetention struct {
+	// Name is a glob matched against the repo's full name (ie. owner/*)
+	Name   string `yaml:"name" json:"name"`
+	MaxAge string `yaml:"max_age" json:"max_age"`
+}
+
+func (r JobRetention) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.MaxAge, validation.By(positiveDuration)),
+		validation.Field(&r.Interval, validation.By(positiveDuration)),
+		validation.Field(&r.Repos),
+	)
+}
+
+func (r JobRetention) ToValid() valid.JobRetention {
+	// validated prior
+	maxAge, _ := time.ParseDuration(r.MaxAge)
+
+	interval := DefaultJobRetentionInterval
+	if r.Interval != "" {
+		interval, _ = time.ParseDuration(r.Interval)
+	}
+
+	var repos []valid.RepoJobRetention
+	for _, repo := range r.Repos {
+		repos = append(repos, repo.ToValid())
+	}
+
+	return valid.JobRetention{
+		MaxAge:   maxAge,
+		Interval: interval,
+		Repos:    repos,
+	}
+}
+
+func (r RepoJobRetention) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Name, validation.Required, validation.By(func(value interface{}) error {
+			name, _ := value.(string)
+			_, err := path.Match(name, "")
+			return errors.Wrapf(err, "parsing %q", name)
+		})),
+		validation.Field(&r.MaxAge, validation.Required, validation.By(positiveDuration)),
+	)
+}
+
+func (r RepoJobRetention) ToValid() valid.RepoJobRetention {
+	// validated prior
+	maxAge, _ := time.ParseDuration(r.MaxAge)
+
+	return valid.RepoJobRetention{
+		Name:   r.Name,
+		MaxAge: maxAge,
+	}
+}
+
+func positiveDuration(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Wrap(err, "parsing duration")
+	}
+
+	if d <= 0 {
+		return fmt.Errorf("must be positive")
+	}
+	return nil
+}
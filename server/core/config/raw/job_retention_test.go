@@ -0,0 +1,91 @@
+package raw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestJobRetention_Unmarshal(t *testing.T) {
+	rawYaml := `
+max_age: 720h
+interval: 30m
+repos:
+  - name: owner/compliance-*
+    max_age: 8760h
+`
+
+	var result raw.JobRetention
+
+	err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Validate())
+	assert.Equal(t, valid.JobRetention{
+		MaxAge:   720 * time.Hour,
+		Interval: 30 * time.Minute,
+		Repos: []valid.RepoJobRetention{
+			{Name: "owner/compliance-*", MaxAge: 8760 * time.Hour},
+		},
+	}, result.ToValid())
+}
+
+func TestJobRetention_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		subject     raw.JobRetention
+		expectErr   bool
+	}{
+		{
+			description: "repo overrides only",
+			subject: raw.JobRetention{
+				Repos: []raw.RepoJobRetention{{Name: "owner/*", MaxAge: "24h"}},
+			},
+		},
+		{
+			description: "invalid max age",
+			subject:     raw.JobRetention{MaxAge: "forever"},
+			expectErr:   true,
+		},
+		{
+			description: "negative interval",
+			subject:     raw.JobRetention{MaxAge: "24h", Interval: "-1h"},
+			expectErr:   true,
+		},
+		{
+			description: "repo without max age",
+			subject: raw.JobRetention{
+				Repos: []raw.RepoJobRetention{{Name: "owner/*"}},
+			},
+			expectErr: true,
+		},
+		{
+			description: "invalid repo glob",
+			subject: raw.JobRetention{
+				Repos: []raw.RepoJobRetention{{Name: "owner/[", MaxAge: "24h"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.subject.Validate()
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJobRetention_ToValid(t *testing.T) {
+	assert.Equal(t, valid.JobRetention{
+		MaxAge:   24 * time.Hour,
+		Interval: raw.DefaultJobRetentionInterval,
+	}, raw.JobRetention{MaxAge: "24h"}.ToValid())
+}
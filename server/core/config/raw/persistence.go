@@ -12,11 +12,23 @@ type Persistence struct {
 
 	DeploymentStorePrefix string `yaml:"deployment_store_prefix" json:"deployment_store_prefix"`
 	JobStorePrefix        string `yaml:"job_store_prefix" json:"job_store_prefix"`
+
+	// BinaryMirrorPrefix enables a mirror of terraform and conftest binaries in the default store
+	BinaryMirrorPrefix string `yaml:"binary_mirror_prefix" json:"binary_mirror_prefix"`
+
+	// PlanStorePrefix enables persisting plan artifacts in the default store. Artifacts are deleted once their
+	// apply finishes, a lifecycle rule expiring the prefix is still recommended since workflows which are
+	// terminated never clean up.
+	PlanStorePrefix string `yaml:"plan_store_prefix" json:"plan_store_prefix"`
+
+	// JobRetention expires logs in the job store, they're kept forever if it isn't set
+	JobRetention *JobRetention `yaml:"job_retention" json:"job_retention"`
 }
 
 func (p Persistence) Validate() error {
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.DefaultStore),
+		validation.Field(&p.JobRetention),
 	)
 }
 
@@ -24,12 +36,32 @@ func (p Persistence) ToValid(defaultCfg valid.GlobalCfg) valid.PersistenceConfig
 	deployments := buildValidStore(p.DefaultStore, p.DeploymentStorePrefix, defaultCfg.PersistenceConfig.Deployments)
 	jobs := buildValidStore(p.DefaultStore, p.JobStorePrefix, defaultCfg.PersistenceConfig.Jobs)
 
+	var jobRetention valid.JobRetention
+	if p.JobRetention != nil {
+		jobRetention = p.JobRetention.ToValid()
+	}
+
 	return valid.PersistenceConfig{
-		Deployments: deployments,
-		Jobs:        jobs,
+		Deployments:  deployments,
+		Jobs:         jobs,
+		BinaryMirror: p.buildOptionalStore(p.BinaryMirrorPrefix, defaultCfg),
+		Plans:        p.buildOptionalStore(p.PlanStorePrefix, defaultCfg),
+		JobRetention: jobRetention,
 	}
 }
 
+// buildOptionalStore returns nil if the prefix enabling the store isn't set
+func (p Persistence) buildOptionalStore(prefix string, defaultCfg valid.GlobalCfg) *valid.StoreConfig {
+	if prefix == "" {
+		return nil
+	}
+
+	defaultStore := defaultCfg.PersistenceConfig.Jobs
+	defaultStore.Prefix = prefix
+	store := buildValidStore(p.DefaultStore, prefix, defaultStore)
+	return &store
+}
+
 func buildValidStore(dataStore DataStore, prefix string, defaultCfg valid.StoreConfig) valid.StoreConfig {
 	// Serially checks for non-nil supported backends
 	switch {
@@ -3,8 +3,12 @@ package raw_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/graymeta/stow"
+	stow_s3 "github.com/graymeta/stow/s3"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -14,6 +18,8 @@ func TestPersistence_Unmarshal(t *testing.T) {
 		rawYaml := `
 job_store_prefix: jobs
 deployment_store_prefix: deployments
+binary_mirror_prefix: binaries
+plan_store_prefix: plans
 default_store:
   s3:
     bucket-name: atlantis-test
@@ -69,3 +75,72 @@ func TestPersistence_ValidateError(t *testing.T) {
 		}.Validate())
 	})
 }
+
+func TestPersistence_ToValid_BinaryMirror(t *testing.T) {
+	defaultCfg := valid.NewGlobalCfg("/data")
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.Nil(t, raw.Persistence{}.ToValid(defaultCfg).BinaryMirror)
+	})
+
+	t.Run("default store", func(t *testing.T) {
+		expected := defaultCfg.PersistenceConfig.Jobs
+		expected.Prefix = "binaries"
+
+		assert.Equal(t, &expected, raw.Persistence{
+			BinaryMirrorPrefix: "binaries",
+		}.ToValid(defaultCfg).BinaryMirror)
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		assert.Equal(t, &valid.StoreConfig{
+			ContainerName: "test-bucket",
+			Prefix:        "binaries",
+			BackendType:   valid.S3Backend,
+			Config: stow.ConfigMap{
+				stow_s3.ConfigAuthType: "iam",
+			},
+		}, raw.Persistence{
+			BinaryMirrorPrefix: "binaries",
+			DefaultStore: raw.DataStore{
+				S3: &raw.S3{
+					BucketName: "test-bucket",
+				},
+			},
+		}.ToValid(defaultCfg).BinaryMirror)
+	})
+}
+
+func TestPersistence_ToValid_Plans(t *testing.T) {
+	defaultCfg := valid.NewGlobalCfg("/data")
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.Nil(t, raw.Persistence{}.ToValid(defaultCfg).Plans)
+	})
+
+	t.Run("default store", func(t *testing.T) {
+		expected := defaultCfg.PersistenceConfig.Jobs
+		expected.Prefix = "plans"
+
+		assert.Equal(t, &expected, raw.Persistence{
+			PlanStorePrefix: "plans",
+		}.ToValid(defaultCfg).Plans)
+	})
+}
+
+func TestPersistence_ToValid_JobRetention(t *testing.T) {
+	defaultCfg := valid.NewGlobalCfg("/data")
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.False(t, raw.Persistence{}.ToValid(defaultCfg).JobRetention.Enabled())
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		assert.Equal(t, valid.JobRetention{
+			MaxAge:   720 * time.Hour,
+			Interval: raw.DefaultJobRetentionInterval,
+		}, raw.Persistence{
+			JobRetention: &raw.JobRetention{MaxAge: "720h"},
+		}.ToValid(defaultCfg).JobRetention)
+	})
+}
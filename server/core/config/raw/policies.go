@@ -1,6 +1,8 @@
 package raw
 
 import (
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -39,9 +41,10 @@ func (p PolicySets) ToValid() valid.PolicySets {
 }
 
 type PolicySet struct {
-	Name  string   `yaml:"name" json:"name"`
-	Owner string   `yaml:"owner,omitempty" json:"owner,omitempty"`
-	Paths []string `yaml:"paths" json:"paths"`
+	Name       string            `yaml:"name" json:"name"`
+	Owner      string            `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Paths      []string          `yaml:"paths" json:"paths"`
+	Exemptions []PolicyExemption `yaml:"exemptions,omitempty" json:"exemptions,omitempty"`
 }
 
 func (p PolicySet) Validate() error {
@@ -49,6 +52,7 @@ func (p PolicySet) Validate() error {
 		validation.Field(&p.Name, validation.Required.Error("is required")),
 		validation.Field(&p.Owner, validation.Required.Error("is required")),
 		validation.Field(&p.Paths, validation.Required.Error("is required")),
+		validation.Field(&p.Exemptions),
 	)
 }
 
@@ -59,5 +63,39 @@ func (p PolicySet) ToValid() valid.PolicySet {
 	policySet.Paths = p.Paths
 	policySet.Owner = p.Owner
 
+	for _, e := range p.Exemptions {
+		policySet.Exemptions = append(policySet.Exemptions, e.ToValid())
+	}
+
 	return policySet
 }
+
+// PolicyExemption allows a repo, or specific roots within it, to bypass a failing
+// policy set until the exemption expires.
+type PolicyExemption struct {
+	Repo          string   `yaml:"repo" json:"repo"`
+	Roots         []string `yaml:"roots,omitempty" json:"roots,omitempty"`
+	Expires       string   `yaml:"expires" json:"expires"`
+	Justification string   `yaml:"justification" json:"justification"`
+}
+
+func (e PolicyExemption) Validate() error {
+	return validation.ValidateStruct(&e,
+		validation.Field(&e.Repo, validation.Required.Error("is required")),
+		validation.Field(&e.Roots, validation.By(nonEmptyStrings)),
+		validation.Field(&e.Expires, validation.Required.Error("is required"), validation.Date(valid.ExemptionExpiryFormat).Error("must be formatted as YYYY-MM-DD")),
+		validation.Field(&e.Justification, validation.Required.Error("is required")),
+	)
+}
+
+func (e PolicyExemption) ToValid() valid.PolicyExemption {
+	// validated beforehand
+	expiry, _ := time.Parse(valid.ExemptionExpiryFormat, e.Expires)
+
+	return valid.PolicyExemption{
+		Repo:          e.Repo,
+		Roots:         e.Roots,
+		Expiry:        expiry,
+		Justification: e.Justification,
+	}
+}
@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -138,6 +139,25 @@ func TestPolicySets_Validate(t *testing.T) {
 			},
 			expErr: "conftest_version: version \"version123\" could not be parsed: Malformed version: version123.",
 		},
+		{
+			description: "invalid exemption",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:  "policy-name-1",
+						Owner: "owner1",
+						Paths: []string{"rel/path/to/source"},
+						Exemptions: []raw.PolicyExemption{
+							{
+								Repo:    "owner/repo",
+								Expires: "06/01/2022",
+							},
+						},
+					},
+				},
+			},
+			expErr: "policy_sets: (0: (exemptions: (0: (expires: must be formatted as YYYY-MM-DD; justification: is required.).).).).",
+		},
 	}
 
 	for _, c := range cases {
@@ -182,6 +202,45 @@ func TestPolicySets_ToValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "valid policies with exemptions",
+			input: raw.PolicySets{
+				Organization: "org",
+				Version:      String("v1.0.0"),
+				PolicySets: []raw.PolicySet{
+					{
+						Name:  "good-policy",
+						Paths: []string{"rel/path/to/source"},
+						Exemptions: []raw.PolicyExemption{
+							{
+								Repo:          "owner/repo",
+								Roots:         []string{"root"},
+								Expires:       "2022-06-01",
+								Justification: "migrating",
+							},
+						},
+					},
+				},
+			},
+			exp: valid.PolicySets{
+				Organization: "org",
+				Version:      version,
+				PolicySets: []valid.PolicySet{
+					{
+						Name:  "good-policy",
+						Paths: []string{"rel/path/to/source"},
+						Exemptions: []valid.PolicyExemption{
+							{
+								Repo:          "owner/repo",
+								Roots:         []string{"root"},
+								Expiry:        time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+								Justification: "migrating",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			description: "valid policies with multiple paths",
 			input: raw.PolicySets{
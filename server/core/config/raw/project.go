@@ -38,10 +38,12 @@ type Project struct {
 	PullRequestWorkflowName *string           `yaml:"pull_request_workflow,omitempty"`
 	DeploymentWorkflowName  *string           `yaml:"deployment_workflow,omitempty"`
 	TerraformVersion        *string           `yaml:"terraform_version,omitempty"`
+	Engine                  *string           `yaml:"engine,omitempty"`
 	Autoplan                *Autoplan         `yaml:"autoplan,omitempty"`
 	ApplyRequirements       []string          `yaml:"apply_requirements,omitempty"`
 	Tags                    map[string]string `yaml:"tags,omitempty"`
 	WorkflowModeType        *string           `yaml:"workflow_mode_type,omitempty"`
+	DependsOn               []string          `yaml:"depends_on,omitempty"`
 }
 
 func (p Project) Validate() error {
@@ -69,6 +71,7 @@ func (p Project) Validate() error {
 		validation.Field(&p.Dir, validation.Required, validation.By(hasDotDot)),
 		validation.Field(&p.ApplyRequirements, validation.By(validApplyReq)),
 		validation.Field(&p.TerraformVersion, validation.By(VersionValidator)),
+		validation.Field(&p.Engine, validation.In(string(valid.TerraformEngine), string(valid.OpenTofuEngine))),
 		validation.Field(&p.Name, validation.By(validName)),
 	)
 }
@@ -97,6 +100,9 @@ func (p Project) ToValid(defaultWorkflowModeType valid.WorkflowModeType) valid.P
 	if p.TerraformVersion != nil {
 		v.TerraformVersion, _ = version.NewVersion(*p.TerraformVersion)
 	}
+	if p.Engine != nil {
+		v.Engine = valid.Engine(*p.Engine)
+	}
 	if p.Autoplan == nil {
 		v.Autoplan = DefaultAutoPlan()
 	} else {
@@ -108,6 +114,7 @@ func (p Project) ToValid(defaultWorkflowModeType valid.WorkflowModeType) valid.P
 
 	v.Tags = p.Tags
 	v.Name = p.Name
+	v.DependsOn = p.DependsOn
 
 	return v
 }
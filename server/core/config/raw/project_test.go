@@ -95,6 +95,22 @@ func TestProject_Validate(t *testing.T) {
 			},
 			expErr: "dir: cannot contain '..'.",
 		},
+		{
+			description: "opentofu engine",
+			input: raw.Project{
+				Dir:    String("."),
+				Engine: String("opentofu"),
+			},
+			expErr: "",
+		},
+		{
+			description: "unsupported engine",
+			input: raw.Project{
+				Dir:    String("."),
+				Engine: String("pulumi"),
+			},
+			expErr: "engine: must be a valid value.",
+		},
 		{
 			description: "apply reqs with unsupported",
 			input: raw.Project{
@@ -0,0 +1,89 @@
+package raw
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// Promotion is an ordered chain of roots, a revision is only deployed to a root
+// once the previous root in the chain has successfully deployed it.
+type Promotion []PromotionStage
+
+func (p Promotion) Validate() error {
+	if len(p) == 1 {
+		return errors.New("must contain at least two stages")
+	}
+
+	seen := make(map[string]bool)
+	for i, stage := range p {
+		if err := stage.Validate(); err != nil {
+			return errors.Wrapf(err, "stage %d", i)
+		}
+
+		if seen[stage.Root] {
+			return fmt.Errorf("root %q is in more than one stage", stage.Root)
+		}
+		seen[stage.Root] = true
+
+		if i == 0 && (stage.Soak != "" || stage.ManualGate) {
+			return fmt.Errorf("root %q is the first stage so it can't set soak or manual_gate", stage.Root)
+		}
+	}
+	return nil
+}
+
+func (p Promotion) ToValid() []valid.PromotionStage {
+	var stages []valid.PromotionStage
+	for _, stage := range p {
+		stages = append(stages, stage.ToValid())
+	}
+	return stages
+}
+
+// PromotionStage gates the deployment of a root on the previous stage of the chain.
+type PromotionStage struct {
+	Root string `yaml:"root" json:"root"`
+
+	// Soak is a duration string (ie. 30m) to wait after the previous stage deploys
+	Soak string `yaml:"soak,omitempty" json:"soak,omitempty"`
+
+	// ManualGate requires a user to promote the revision after the soak
+	ManualGate bool `yaml:"manual_gate,omitempty" json:"manual_gate,omitempty"`
+}
+
+func (s PromotionStage) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Root, validation.Required),
+		validation.Field(&s.Soak, validation.By(func(value interface{}) error {
+			soak, _ := value.(string)
+			if soak == "" {
+				return nil
+			}
+
+			duration, err := time.ParseDuration(soak)
+			if err != nil {
+				return errors.Wrap(err, "parsing soak")
+			}
+
+			if duration < 0 {
+				return errors.New("soak can't be negative")
+			}
+			return nil
+		})),
+	)
+}
+
+func (s PromotionStage) ToValid() valid.PromotionStage {
+	// validated prior
+	soak, _ := time.ParseDuration(s.Soak)
+
+	return valid.PromotionStage{
+		Root:       s.Root,
+		Soak:       soak,
+		ManualGate: s.ManualGate,
+	}
+}
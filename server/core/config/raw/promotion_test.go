@@ -0,0 +1,92 @@
+package raw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPromotion_Unmarshal(t *testing.T) {
+	rawYaml := `
+promotion:
+  - root: staging
+  - root: canary
+    soak: 30m
+  - root: prod
+    soak: 1h
+    manual_gate: true
+`
+
+	var result raw.DeploymentWorkflow
+
+	err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+	assert.NoError(t, err)
+	assert.NoError(t, result.Validate())
+	assert.Equal(t, []valid.PromotionStage{
+		{Root: "staging"},
+		{Root: "canary", Soak: 30 * time.Minute},
+		{Root: "prod", Soak: time.Hour, ManualGate: true},
+	}, result.ToValid("default").Promotion)
+}
+
+func TestPromotion_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		subject     raw.Promotion
+		expectErr   bool
+	}{
+		{
+			description: "empty",
+			subject:     raw.Promotion{},
+		},
+		{
+			description: "single stage",
+			subject:     raw.Promotion{{Root: "staging"}},
+			expectErr:   true,
+		},
+		{
+			description: "missing root",
+			subject:     raw.Promotion{{Root: "staging"}, {Soak: "1h"}},
+			expectErr:   true,
+		},
+		{
+			description: "duplicate root",
+			subject:     raw.Promotion{{Root: "staging"}, {Root: "prod"}, {Root: "staging"}},
+			expectErr:   true,
+		},
+		{
+			description: "invalid soak",
+			subject:     raw.Promotion{{Root: "staging"}, {Root: "prod", Soak: "a while"}},
+			expectErr:   true,
+		},
+		{
+			description: "negative soak",
+			subject:     raw.Promotion{{Root: "staging"}, {Root: "prod", Soak: "-1h"}},
+			expectErr:   true,
+		},
+		{
+			description: "first stage gated",
+			subject:     raw.Promotion{{Root: "staging", ManualGate: true}, {Root: "prod"}},
+			expectErr:   true,
+		},
+		{
+			description: "valid",
+			subject:     raw.Promotion{{Root: "staging"}, {Root: "prod", Soak: "1h", ManualGate: true}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.subject.Validate()
+			if c.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -14,13 +14,17 @@ type Temporal struct {
 	UseSystemCACert    bool   `yaml:"us_system_ca_cert" json:"us_system_ca_cert"`
 	Namespace          string `yaml:"namespace" json:"namespace"`
 	TerraformTaskQueue string `yaml:"terraform_taskqueue" json:"terraform_taskqueue"`
+	// ContinueAsNewThreshold is the number of revisions a long-lived workflow processes
+	// before continuing as new, this bounds the size of the workflow's history.
+	ContinueAsNewThreshold int `yaml:"continue_as_new_threshold" json:"continue_as_new_threshold"`
 }
 
 func (t *Temporal) Validate() error {
 	return validation.ValidateStruct(t,
 		validation.Field(&t.Host, validation.Required),
 		validation.Field(&t.Port, validation.Required),
-		validation.Field(&t.Port, is.Int))
+		validation.Field(&t.Port, is.Int),
+		validation.Field(&t.ContinueAsNewThreshold, validation.Min(0)))
 }
 
 func (t *Temporal) ToValid() valid.Temporal {
@@ -29,10 +33,11 @@ func (t *Temporal) ToValid() valid.Temporal {
 		terraformTaskQueue = t.TerraformTaskQueue
 	}
 	return valid.Temporal{
-		Host:               t.Host,
-		Port:               t.Port,
-		UseSystemCACert:    t.UseSystemCACert,
-		Namespace:          t.Namespace,
-		TerraformTaskQueue: terraformTaskQueue,
+		Host:                   t.Host,
+		Port:                   t.Port,
+		UseSystemCACert:        t.UseSystemCACert,
+		Namespace:              t.Namespace,
+		TerraformTaskQueue:     terraformTaskQueue,
+		ContinueAsNewThreshold: t.ContinueAsNewThreshold,
 	}
 }
@@ -0,0 +1,37 @@
+package valid
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AddressPattern is a resource address glob where `*` matches any sequence of characters.
+// Other characters match literally since addresses can contain brackets which would otherwise
+// be interpreted as character classes.
+type AddressPattern struct {
+	glob   string
+	regexp *regexp.Regexp
+}
+
+func NewAddressPattern(glob string) AddressPattern {
+	parts := strings.Split(glob, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return AddressPattern{
+		glob:   glob,
+		regexp: regexp.MustCompile("^" + strings.Join(parts, ".*") + "$"),
+	}
+}
+
+func (p AddressPattern) String() string {
+	return p.glob
+}
+
+// Match reports whether the address matches the entire pattern
+func (p AddressPattern) Match(address string) bool {
+	if p.regexp == nil {
+		return false
+	}
+	return p.regexp.MatchString(address)
+}
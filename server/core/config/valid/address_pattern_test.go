@@ -0,0 +1,29 @@
+package valid_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressPattern_Match(t *testing.T) {
+	cases := []struct {
+		glob     string
+		address  string
+		expected bool
+	}{
+		{glob: "aws_s3_bucket.*", address: "aws_s3_bucket.logs", expected: true},
+		{glob: "aws_s3_bucket.*", address: "module.a.aws_s3_bucket.logs", expected: false},
+		{glob: "module.*.aws_s3_bucket.logs", address: "module.a.aws_s3_bucket.logs", expected: true},
+		{glob: `aws_instance.a["key"]`, address: `aws_instance.a["key"]`, expected: true},
+		{glob: "aws_instance.a[0]", address: "aws_instance.a0", expected: false},
+		{glob: "aws_instance.a", address: "aws_instance.ab", expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.glob+" "+c.address, func(t *testing.T) {
+			assert.Equal(t, c.expected, valid.NewAddressPattern(c.glob).Match(c.address))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package valid
+
+// Engine is the binary used to execute a root's terraform configuration
+type Engine string
+
+const (
+	TerraformEngine Engine = "terraform"
+	OpenTofuEngine  Engine = "opentofu"
+)
+
+// BinaryName returns the name of the engine's executable, defaults to terraform
+func (e Engine) BinaryName() string {
+	if e == OpenTofuEngine {
+		return "tofu"
+	}
+	return "terraform"
+}
+
+// OrDefault returns the terraform engine if e is unset
+func (e Engine) OrDefault() Engine {
+	if e == "" {
+		return TerraformEngine
+	}
+	return e
+}
@@ -0,0 +1,107 @@
+package valid
+
+import (
+	"path"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// maxFreezeOccurrences bounds how many back to back occurrences of a recurring
+// freeze window are joined together when computing when the freeze ends.
+const maxFreezeOccurrences = 100
+
+// FreezeWindow blocks deploys of matching roots either once, between Start and End,
+// or every time Schedule fires for Duration.
+type FreezeWindow struct {
+	Name   string
+	Reason string
+
+	// Repos and Roots are globs (ie. github.com/owner/*), all repos or roots match if empty
+	Repos []string
+	Roots []string
+
+	// Start and End are set for one-off windows
+	Start time.Time
+	End   time.Time
+
+	// Schedule is a standard cron expression evaluated in Timezone (defaults to UTC)
+	// and is set for recurring windows
+	Schedule string
+	Duration time.Duration
+	Timezone string
+}
+
+func (w FreezeWindow) IsRecurring() bool {
+	return w.Schedule != ""
+}
+
+func (w FreezeWindow) Matches(repoID string, root string) bool {
+	return matchesAnyGlob(w.Repos, repoID) && matchesAnyGlob(w.Roots, root)
+}
+
+// FrozenUntil returns when the window stops blocking deploys if it's active at now.
+func (w FreezeWindow) FrozenUntil(now time.Time) (time.Time, bool) {
+	if !w.IsRecurring() {
+		if now.Before(w.Start) || !now.Before(w.End) {
+			return time.Time{}, false
+		}
+		return w.End, true
+	}
+
+	// validated prior
+	schedule, err := cron.ParseStandard(w.Schedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+	location, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// the earliest occurrence which would still be in effect
+	start := schedule.Next(now.In(location).Add(-w.Duration))
+	if start.After(now) {
+		return time.Time{}, false
+	}
+
+	// occurrences which overlap or directly follow extend the freeze
+	end := start.Add(w.Duration)
+	for i := 0; i < maxFreezeOccurrences; i++ {
+		next := schedule.Next(start)
+		if next.After(end) {
+			break
+		}
+		start = next
+		end = next.Add(w.Duration)
+	}
+	return end.UTC(), true
+}
+
+// ActiveFreezeWindow returns the active window which freezes deploys the longest
+// along with when that freeze ends.
+func ActiveFreezeWindow(windows []FreezeWindow, now time.Time) (FreezeWindow, time.Time, bool) {
+	var active FreezeWindow
+	var until time.Time
+	for _, w := range windows {
+		end, frozen := w.FrozenUntil(now)
+		if frozen && end.After(until) {
+			active = w
+			until = end
+		}
+	}
+	return active, until, !until.IsZero()
+}
+
+func matchesAnyGlob(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, p := range patterns {
+		if matched, _ := path.Match(p, s); matched {
+			return true
+		}
+	}
+	return false
+}
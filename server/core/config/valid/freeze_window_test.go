@@ -0,0 +1,129 @@
+package valid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestFreezeWindow_Matches(t *testing.T) {
+	window := valid.FreezeWindow{
+		Name:  "holidays",
+		Repos: []string{"github.com/owner/*"},
+		Roots: []string{"prod-*", "global"},
+	}
+
+	Assert(t, window.Matches("github.com/owner/repo", "prod-us-east-1"), "expected glob to match")
+	Assert(t, window.Matches("github.com/owner/repo", "global"), "expected exact root to match")
+	Assert(t, !window.Matches("github.com/owner/repo", "staging"), "expected root not to match")
+	Assert(t, !window.Matches("github.com/other/repo", "global"), "expected repo not to match")
+	Assert(t, valid.FreezeWindow{Name: "all"}.Matches("github.com/other/repo", "staging"), "expected an unscoped window to match")
+}
+
+func TestFreezeWindow_FrozenUntil(t *testing.T) {
+	oneOff := valid.FreezeWindow{
+		Name:  "incident",
+		Start: time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 12, 21, 0, 0, 0, 0, time.UTC),
+	}
+
+	// fridays at 17:00 pacific through monday at 09:00
+	weekends := valid.FreezeWindow{
+		Name:     "weekends",
+		Schedule: "0 17 * * 5",
+		Duration: 64 * time.Hour,
+		Timezone: "America/Los_Angeles",
+	}
+
+	// every hour for two hours which joins occurrences together
+	overlapping := valid.FreezeWindow{
+		Name:     "overlapping",
+		Schedule: "0 * * * *",
+		Duration: 2 * time.Hour,
+	}
+
+	weekendEnd := time.Date(2022, 12, 19, 17, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		window      valid.FreezeWindow
+		now         time.Time
+		expFrozen   bool
+		expUntil    time.Time
+	}{
+		{
+			description: "before one-off",
+			window:      oneOff,
+			now:         oneOff.Start.Add(-time.Second),
+		},
+		{
+			description: "during one-off",
+			window:      oneOff,
+			now:         oneOff.Start,
+			expFrozen:   true,
+			expUntil:    oneOff.End,
+		},
+		{
+			description: "after one-off",
+			window:      oneOff,
+			now:         oneOff.End,
+		},
+		{
+			description: "before recurring",
+			window:      weekends,
+			now:         time.Date(2022, 12, 16, 0, 59, 0, 0, time.UTC),
+		},
+		{
+			description: "during recurring",
+			window:      weekends,
+			now:         time.Date(2022, 12, 17, 12, 0, 0, 0, time.UTC),
+			expFrozen:   true,
+			expUntil:    weekendEnd,
+		},
+		{
+			description: "start of recurring",
+			window:      weekends,
+			now:         time.Date(2022, 12, 17, 1, 0, 0, 0, time.UTC),
+			expFrozen:   true,
+			expUntil:    weekendEnd,
+		},
+		{
+			description: "after recurring",
+			window:      weekends,
+			now:         weekendEnd,
+		},
+		{
+			description: "overlapping occurrences",
+			window:      overlapping,
+			now:         time.Date(2022, 12, 17, 12, 30, 0, 0, time.UTC),
+			expFrozen:   true,
+			// a window which never ends is capped after 100 occurrences from the one at 11:00
+			expUntil: time.Date(2022, 12, 21, 17, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			until, frozen := c.window.FrozenUntil(c.now)
+			Equals(t, c.expFrozen, frozen)
+			Equals(t, c.expUntil, until)
+		})
+	}
+}
+
+func TestActiveFreezeWindow(t *testing.T) {
+	now := time.Date(2022, 12, 20, 12, 0, 0, 0, time.UTC)
+	short := valid.FreezeWindow{Name: "short", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+	long := valid.FreezeWindow{Name: "long", Start: now.Add(-time.Hour), End: now.Add(24 * time.Hour)}
+	inactive := valid.FreezeWindow{Name: "inactive", Start: now.Add(time.Hour), End: now.Add(48 * time.Hour)}
+
+	window, until, frozen := valid.ActiveFreezeWindow([]valid.FreezeWindow{short, long, inactive}, now)
+	Assert(t, frozen, "expected to be frozen")
+	Equals(t, long, window)
+	Equals(t, long.End, until)
+
+	_, _, frozen = valid.ActiveFreezeWindow([]valid.FreezeWindow{inactive}, now)
+	Assert(t, !frozen, "expected not to be frozen")
+}
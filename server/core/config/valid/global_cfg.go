@@ -3,6 +3,7 @@ package valid
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/graymeta/stow"
 	"github.com/graymeta/stow/local"
@@ -65,6 +66,8 @@ type GlobalCfg struct {
 	Temporal             Temporal
 	RevisionSetter       RevisionSetter
 	Admin                Admin
+	DriftDetection       DriftDetection
+	FreezeWindows        []FreezeWindow
 }
 
 type GithubTeam struct {
@@ -79,6 +82,14 @@ type Admin struct {
 type PersistenceConfig struct {
 	Deployments StoreConfig
 	Jobs        StoreConfig
+
+	// BinaryMirror is nil unless a mirror of terraform and conftest binaries is configured
+	BinaryMirror *StoreConfig
+
+	// Plans is nil unless plan artifacts are persisted so applies can run on any worker
+	Plans *StoreConfig
+
+	JobRetention JobRetention
 }
 
 type StoreConfig struct {
@@ -144,6 +155,9 @@ type Temporal struct {
 	UseSystemCACert    bool
 	Namespace          string
 	TerraformTaskQueue string
+
+	// ContinueAsNewThreshold is optional, workflows use their own default when this is 0
+	ContinueAsNewThreshold int
 }
 
 type TerraformLogFilters struct {
@@ -159,6 +173,11 @@ type TaskQueue struct {
 	ActivitiesPerSecond float64
 }
 
+type DriftDetection struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
 type RevisionSetter struct {
 	BasicAuth BasicAuth
 	URL       string
@@ -180,10 +199,15 @@ type MergedProjectCfg struct {
 	AutoplanEnabled     bool
 	WhenModified        []string
 	TerraformVersion    *version.Version
+	Engine              Engine
 	RepoCfgVersion      int
 	PolicySets          PolicySets
 	Tags                map[string]string
 	WorkflowMode        WorkflowModeType
+	DependsOn           []string
+
+	// FreezeWindows are the server side freeze windows which match this root
+	FreezeWindows []FreezeWindow
 }
 
 // PreWorkflowHook is a map of custom run commands to run before workflows.
@@ -383,10 +407,13 @@ func (g GlobalCfg) MergeProjectCfg(repoID string, proj Project, rCfg RepoCfg) Me
 		AutoplanEnabled:     proj.Autoplan.Enabled,
 		WhenModified:        proj.Autoplan.WhenModified,
 		TerraformVersion:    proj.TerraformVersion,
+		Engine:              g.engine(repo, proj),
 		RepoCfgVersion:      rCfg.Version,
 		PolicySets:          g.PolicySets,
 		Tags:                proj.Tags,
 		WorkflowMode:        proj.WorkflowModeType,
+		DependsOn:           proj.DependsOn,
+		FreezeWindows:       g.MatchingFreezeWindows(repoID, proj.GetName()),
 	}
 }
 
@@ -403,12 +430,25 @@ func (g GlobalCfg) DefaultProjCfg(log logging.Logger, repoID string, repoRelDir
 		Name:              "",
 		AutoplanEnabled:   DefaultAutoPlanEnabled,
 		TerraformVersion:  nil,
+		Engine:            g.engine(repo, Project{}),
 		PolicySets:        g.PolicySets,
 	}
 
 	return mrgPrj
 }
 
+// engine returns the project's engine if set, otherwise the repo's engine.
+// An empty engine uses terraform.
+func (g GlobalCfg) engine(repo Repo, proj Project) Engine {
+	if proj.Engine != "" {
+		return proj.Engine
+	}
+	if repo.Engine != nil {
+		return *repo.Engine
+	}
+	return ""
+}
+
 // foldMatchingRepos will return a pseudo repo instance that will iterate over
 // the matching repositories and assign relevant fields if they're defined.
 // This means returned object will contain the last matching repo's value as a it's fields
@@ -442,6 +482,9 @@ func (g GlobalCfg) foldMatchingRepos(repoID string) Repo {
 			if repo.AllowCustomWorkflows != nil {
 				foldedRepo.AllowCustomWorkflows = repo.AllowCustomWorkflows
 			}
+			if repo.Engine != nil {
+				foldedRepo.Engine = repo.Engine
+			}
 		}
 	}
 
@@ -485,3 +528,14 @@ func (g GlobalCfg) MatchingRepo(repoID string) *Repo {
 	}
 	return nil
 }
+
+// MatchingFreezeWindows returns the freeze windows which apply to the root of the repo with id repoID.
+func (g GlobalCfg) MatchingFreezeWindows(repoID string, root string) []FreezeWindow {
+	var windows []FreezeWindow
+	for _, w := range g.FreezeWindows {
+		if w.Matches(repoID, root) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
@@ -979,6 +979,75 @@ repos:
 				WorkflowMode:    valid.PlatformWorkflowMode,
 			},
 		},
+		"server side engine": {
+			gCfg: `
+repos:
+- id: /.*/
+  engine: opentofu`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:       "mydir",
+				Workspace: "myworkspace",
+			},
+			exp: valid.MergedProjectCfg{
+				ApplyRequirements: []string{},
+				Workflow: valid.Workflow{
+					Name:        "default",
+					Apply:       valid.DefaultApplyStage,
+					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Plan:        valid.DefaultPlanStage,
+				},
+				PullRequestWorkflow: valid.Workflow{
+					Name:        "default",
+					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Plan:        valid.DefaultLocklessPlanStage,
+				},
+				DeploymentWorkflow: valid.Workflow{
+					Name:  "default",
+					Apply: valid.DefaultApplyStage,
+					Plan:  valid.DefaultPlanStage,
+				},
+				RepoRelDir: "mydir",
+				Workspace:  "myworkspace",
+				PolicySets: emptyPolicySets,
+				Engine:     valid.OpenTofuEngine,
+			},
+		},
+		"project level override of engine": {
+			gCfg: `
+repos:
+- id: /.*/
+  engine: opentofu`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:       "mydir",
+				Workspace: "myworkspace",
+				Engine:    valid.TerraformEngine,
+			},
+			exp: valid.MergedProjectCfg{
+				ApplyRequirements: []string{},
+				Workflow: valid.Workflow{
+					Name:        "default",
+					Apply:       valid.DefaultApplyStage,
+					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Plan:        valid.DefaultPlanStage,
+				},
+				PullRequestWorkflow: valid.Workflow{
+					Name:        "default",
+					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Plan:        valid.DefaultLocklessPlanStage,
+				},
+				DeploymentWorkflow: valid.Workflow{
+					Name:  "default",
+					Apply: valid.DefaultApplyStage,
+					Plan:  valid.DefaultPlanStage,
+				},
+				RepoRelDir: "mydir",
+				Workspace:  "myworkspace",
+				PolicySets: emptyPolicySets,
+				Engine:     valid.TerraformEngine,
+			},
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -24,6 +24,7 @@ type Repo struct {
 	TemplateOverrides           map[string]string
 	CheckoutStrategy            string
 	ApplySettings               ApplySettings
+	Engine                      *Engine
 }
 
 // IDMatches returns true if the repo ID otherID matches this config.
@@ -0,0 +1,40 @@
+package valid
+
+import (
+	"path"
+	"time"
+)
+
+// JobRetention expires job logs in the job store, it's disabled if neither a max age nor repo overrides are set.
+type JobRetention struct {
+	// MaxAge applies to repos without an override, their logs are kept forever if it's 0
+	MaxAge time.Duration
+	// Interval is how often expired logs are swept
+	Interval time.Duration
+	Repos    []RepoJobRetention
+}
+
+// RepoJobRetention overrides the max age of job logs for repos matching Name
+type RepoJobRetention struct {
+	// Name is a glob matched against the repo's full name
+	Name   string
+	MaxAge time.Duration
+}
+
+func (r JobRetention) Enabled() bool {
+	return r.MaxAge > 0 || len(r.Repos) > 0
+}
+
+// MaxAgeFor returns how long logs of jobs for the repo are kept, repo is empty if it's unknown
+func (r JobRetention) MaxAgeFor(repo string) time.Duration {
+	if repo == "" {
+		return r.MaxAge
+	}
+
+	for _, override := range r.Repos {
+		if matched, _ := path.Match(override.Name, repo); matched {
+			return override.MaxAge
+		}
+	}
+	return r.MaxAge
+}
@@ -0,0 +1,33 @@
+package valid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestJobRetention_MaxAgeFor(t *testing.T) {
+	retention := valid.JobRetention{
+		MaxAge: 30 * 24 * time.Hour,
+		Repos: []valid.RepoJobRetention{
+			{Name: "owner/compliance-*", MaxAge: 365 * 24 * time.Hour},
+			{Name: "owner/*", MaxAge: 7 * 24 * time.Hour},
+		},
+	}
+
+	Equals(t, 365*24*time.Hour, retention.MaxAgeFor("owner/compliance-prod"))
+	Equals(t, 7*24*time.Hour, retention.MaxAgeFor("owner/repo"))
+	Equals(t, 30*24*time.Hour, retention.MaxAgeFor("other/repo"))
+	Equals(t, 30*24*time.Hour, retention.MaxAgeFor(""))
+	Equals(t, time.Duration(0), valid.JobRetention{}.MaxAgeFor("owner/repo"))
+}
+
+func TestJobRetention_Enabled(t *testing.T) {
+	Assert(t, !valid.JobRetention{}.Enabled(), "expected retention without max ages to be disabled")
+	Assert(t, valid.JobRetention{MaxAge: time.Hour}.Enabled(), "expected a max age to enable retention")
+	Assert(t, valid.JobRetention{
+		Repos: []valid.RepoJobRetention{{Name: "owner/*", MaxAge: time.Hour}},
+	}.Enabled(), "expected repo overrides to enable retention")
+}
@@ -1,6 +1,9 @@
 package valid
 
 import (
+	"strings"
+	"time"
+
 	"github.com/hashicorp/go-version"
 )
 
@@ -9,6 +12,10 @@ const (
 	GithubPolicySet string = "github"
 )
 
+// ExemptionExpiryFormat is the date format of policy exemption expiries, exemptions
+// are no longer honored from the start of the expiry date in UTC.
+const ExemptionExpiryFormat = "2006-01-02"
+
 // PolicySets defines version of policy checker binary(conftest) and a list of
 // PolicySet objects. PolicySets struct is used by PolicyCheck workflow to build
 // context to enforce policies.
@@ -23,9 +30,54 @@ type PolicyOwners struct {
 }
 
 type PolicySet struct {
-	Name  string
-	Owner string
-	Paths []string
+	Name       string
+	Owner      string
+	Paths      []string
+	Exemptions []PolicyExemption
+}
+
+// PolicyExemption allows failures of a policy set to be ignored for a repo, optionally
+// scoped to a set of roots, until the exemption expires.
+type PolicyExemption struct {
+	// Repo is the full name of the repo ie. owner/repo
+	Repo string
+
+	// Roots limits the exemption to the given roots, all roots in the repo are exempt if empty
+	Roots         []string
+	Expiry        time.Time
+	Justification string
+}
+
+func (e PolicyExemption) IsActive(now time.Time) bool {
+	return now.Before(e.Expiry)
+}
+
+func (e PolicyExemption) Matches(repo string, root string) bool {
+	if !strings.EqualFold(e.Repo, repo) {
+		return false
+	}
+
+	if len(e.Roots) == 0 {
+		return true
+	}
+
+	for _, r := range e.Roots {
+		if r == root {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveExemption returns the first unexpired exemption for the repo and root if one exists
+func (p PolicySet) ActiveExemption(repo string, root string, now time.Time) *PolicyExemption {
+	for _, e := range p.Exemptions {
+		if e.Matches(repo, root) && e.IsActive(now) {
+			exemption := e
+			return &exemption
+		}
+	}
+	return nil
 }
 
 func (p *PolicySets) HasPolicies() bool {
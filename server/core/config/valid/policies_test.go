@@ -0,0 +1,69 @@
+package valid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPolicySet_ActiveExemption(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	rootExemption := valid.PolicyExemption{
+		Repo:          "owner/repo",
+		Roots:         []string{"root1"},
+		Expiry:        now.Add(time.Hour),
+		Justification: "migrating",
+	}
+	repoExemption := valid.PolicyExemption{
+		Repo:          "owner/other",
+		Expiry:        now.Add(time.Hour),
+		Justification: "legacy",
+	}
+	expiredExemption := valid.PolicyExemption{
+		Repo:          "owner/expired",
+		Expiry:        now,
+		Justification: "expired",
+	}
+	policySet := valid.PolicySet{
+		Name:       "policy",
+		Exemptions: []valid.PolicyExemption{rootExemption, repoExemption, expiredExemption},
+	}
+
+	cases := []struct {
+		description string
+		repo        string
+		root        string
+		exp         *valid.PolicyExemption
+	}{
+		{
+			description: "root match",
+			repo:        "owner/repo",
+			root:        "root1",
+			exp:         &rootExemption,
+		},
+		{
+			description: "root mismatch",
+			repo:        "owner/repo",
+			root:        "root2",
+		},
+		{
+			description: "all roots",
+			repo:        "Owner/Other",
+			root:        "root2",
+			exp:         &repoExemption,
+		},
+		{
+			description: "expired",
+			repo:        "owner/expired",
+			root:        "root1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, policySet.ActiveExemption(c.repo, c.root, now))
+		})
+	}
+}
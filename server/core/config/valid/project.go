@@ -23,10 +23,14 @@ type Project struct {
 	PullRequestWorkflowName *string
 	DeploymentWorkflowName  *string
 	TerraformVersion        *version.Version
-	Autoplan                Autoplan
-	ApplyRequirements       []string
-	Tags                    map[string]string
-	WorkflowModeType        WorkflowModeType
+	// Engine is empty unless the project overrides the repo's engine
+	Engine            Engine
+	Autoplan          Autoplan
+	ApplyRequirements []string
+	Tags              map[string]string
+	WorkflowModeType  WorkflowModeType
+	// DependsOn lists the names of projects which must deploy a revision before this one
+	DependsOn []string
 }
 
 // GetName returns the name of the project or an empty string if there is no
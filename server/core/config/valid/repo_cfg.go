@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 const TfLogEnvVar = "TF_LOG"
@@ -217,6 +218,84 @@ type Workflow struct {
 	Apply       Stage
 	Plan        Stage
 	PolicyCheck Stage
+
+	// ApprovalPolicy is only supported for deployment workflows, when nil
+	// plans are approved using the default behavior.
+	ApprovalPolicy *ApprovalPolicy
+
+	// Promotion is only supported for deployment workflows and orders the deployment
+	// of a revision across the roots in it.
+	Promotion []PromotionStage
+}
+
+type ApprovalType string
+
+const (
+	AutoApprovalType   ApprovalType = "auto"
+	ManualApprovalType ApprovalType = "manual"
+)
+
+// Actions of the resource changes approval rules can match
+const (
+	CreateAction  = "create"
+	UpdateAction  = "update"
+	DeleteAction  = "delete"
+	ReplaceAction = "replace"
+)
+
+// ApprovalPolicy determines whether a deployment plan is applied automatically
+// or requires manual approval based on the changes within it.
+type ApprovalPolicy struct {
+	// Default is the approval for resource changes which match no rule
+	Default ApprovalType
+
+	// MaxChanges requires manual approval for plans with more resource changes, 0 is unlimited
+	MaxChanges int
+
+	Rules []ApprovalRule
+}
+
+// ApprovalRule matches resource changes by action, resource type and address.
+// Empty criteria match all resource changes.
+type ApprovalRule struct {
+	Actions       []string
+	ResourceTypes []string
+	Addresses     []AddressPattern
+	Approval      ApprovalType
+}
+
+// PromotionStage is a root within a promotion chain. Soak and ManualGate are
+// applied after the previous stage deploys a revision and before this root does.
+type PromotionStage struct {
+	Root       string
+	Soak       time.Duration
+	ManualGate bool
+}
+
+// PreviousStages returns the roots before root in the promotion chain, nearest first.
+func (w Workflow) PreviousStages(root string) []PromotionStage {
+	for i, stage := range w.Promotion {
+		if stage.Root != root {
+			continue
+		}
+
+		var previous []PromotionStage
+		for j := i - 1; j >= 0; j-- {
+			previous = append(previous, w.Promotion[j])
+		}
+		return previous
+	}
+	return nil
+}
+
+// PromotionStage returns the stage of root within the promotion chain if there is one
+func (w Workflow) PromotionStage(root string) (PromotionStage, bool) {
+	for _, stage := range w.Promotion {
+		if stage.Root == root {
+			return stage, true
+		}
+	}
+	return PromotionStage{}, false
 }
 
 // If logLevel is passed in a comment, we will prepend an env step to export it
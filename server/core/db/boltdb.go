@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,6 +27,7 @@ const (
 	locksBucketName       = "runLocks"
 	pullsBucketName       = "pulls"
 	globalLocksBucketName = "globalLocks"
+	waitListsBucketName   = "lockWaitLists"
 	pullKeySeparator      = "::"
 )
 
@@ -143,6 +143,35 @@ func (b *BoltDB) Unlock(p models.Project, workspace string) (*models.ProjectLock
 	return nil, err
 }
 
+// UnlockExpired deletes the lock of the project and workspace if it expired before now and
+// returns it. If there is no expired lock, it returns a nil pointer.
+func (b *BoltDB) UnlockExpired(p models.Project, workspace string, now time.Time) (*models.ProjectLock, error) {
+	var expired *models.ProjectLock
+	key := b.lockKey(p, workspace)
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.locksBucketName)
+		serialized := bucket.Get([]byte(key))
+		if serialized == nil {
+			return nil
+		}
+
+		var lock models.ProjectLock
+		if err := json.Unmarshal(serialized, &lock); err != nil {
+			return errors.Wrap(err, "failed to deserialize lock")
+		}
+		if !lock.Expired(now) {
+			return nil
+		}
+
+		expired = &lock
+		return bucket.Delete([]byte(key))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	return expired, nil
+}
+
 // List lists all current locks.
 func (b *BoltDB) List() ([]models.ProjectLock, error) {
 	var locks []models.ProjectLock
@@ -327,49 +356,7 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 			return err
 		}
 
-		// If there is no pull OR if the pull we have is out of date, we
-		// just write a new pull.
-		if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
-			var statuses []models.ProjectStatus
-			for _, r := range newResults {
-				statuses = append(statuses, b.projectResultToProject(r))
-			}
-			newStatus = models.PullStatus{
-				Pull:      pull,
-				Projects:  statuses,
-				UpdatedAt: time.Now().Unix(),
-			}
-		} else {
-			// If there's an existing pull at the right commit then we have to
-			// merge our project results with the existing ones. We do a merge
-			// because it's possible a user is just applying a single project
-			// in this command and so we don't want to delete our data about
-			// other projects that aren't affected by this command.
-			newStatus = *currStatus
-			newStatus.UpdatedAt = time.Now().Unix()
-			for _, res := range newResults {
-				// First, check if we should update any existing projects.
-				updatedExisting := false
-				for i := range newStatus.Projects {
-					// NOTE: We're using a reference here because we are
-					// in-place updating its Status field.
-					proj := &newStatus.Projects[i]
-					if res.Workspace == proj.Workspace &&
-						res.RepoRelDir == proj.RepoRelDir &&
-						res.ProjectName == proj.ProjectName {
-						proj.Status = res.PlanStatus()
-						updatedExisting = true
-						break
-					}
-				}
-
-				if !updatedExisting {
-					// If we didn't update an existing project, then we need to
-					// add this because it's a new one.
-					newStatus.Projects = append(newStatus.Projects, b.projectResultToProject(res))
-				}
-			}
-		}
+		newStatus = mergePullResults(currStatus, pull, newResults)
 
 		// Now, we overwrite the key with our new status.
 		return b.writePullToBucket(bucket, key, newStatus)
@@ -425,40 +412,154 @@ func (b *BoltDB) UpdateProjectStatus(pull models.PullRequest, workspace string,
 		currStatus := *currStatusPtr
 
 		// Update the status.
-		for i := range currStatus.Projects {
-			// NOTE: We're using a reference here because we are
-			// in-place updating its Status field.
-			proj := &currStatus.Projects[i]
-			if proj.Workspace == workspace && proj.RepoRelDir == repoRelDir {
-				proj.Status = newStatus
-				break
+		setProjectStatus(currStatus, workspace, repoRelDir, newStatus)
+		return b.writePullToBucket(bucket, key, currStatus)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// EnqueueLock adds the lock's pull to the wait list of its project and workspace and
+// returns its position in line, starting at 1. A pull which is already waiting keeps its position.
+func (b *BoltDB) EnqueueLock(lock models.ProjectLock) (int, error) {
+	var position int
+	err := b.updateWaitList(b.lockKey(lock.Project, lock.Workspace), func(waiting []models.ProjectLock) []models.ProjectLock {
+		for i, l := range waiting {
+			if l.Pull.Num == lock.Pull.Num {
+				position = i + 1
+				return waiting
 			}
 		}
-		return b.writePullToBucket(bucket, key, currStatus)
+		waiting = append(waiting, lock)
+		position = len(waiting)
+		return waiting
+	})
+	return position, errors.Wrap(err, "DB transaction failed")
+}
+
+// NextLock returns the first lock waiting for the project and workspace without removing it.
+// If no pulls are waiting, it returns a nil pointer.
+func (b *BoltDB) NextLock(p models.Project, workspace string) (*models.ProjectLock, error) {
+	var next *models.ProjectLock
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(waitListsBucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		waiting, err := b.getWaitList(bucket, []byte(b.lockKey(p, workspace)))
+		if err != nil {
+			return err
+		}
+		if len(waiting) > 0 {
+			next = &waiting[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	return next, nil
+}
+
+// RemoveFromWaitList removes the pull from the wait list of the project and workspace.
+func (b *BoltDB) RemoveFromWaitList(p models.Project, workspace string, pullNum int) error {
+	err := b.updateWaitList(b.lockKey(p, workspace), func(waiting []models.ProjectLock) []models.ProjectLock {
+		return removeFromWaitList(waiting, pullNum)
 	})
 	return errors.Wrap(err, "DB transaction failed")
 }
 
-func (b *BoltDB) pullKey(pull models.PullRequest) ([]byte, error) {
-	hostname := pull.BaseRepo.VCSHost.Hostname
-	if strings.Contains(hostname, pullKeySeparator) {
-		return nil, fmt.Errorf("vcs hostname %q contains illegal string %q", hostname, pullKeySeparator)
+// RemovePullFromWaitLists removes the pull from every wait list of the repo.
+func (b *BoltDB) RemovePullFromWaitLists(repoFullName string, pullNum int) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(waitListsBucketName))
+		if err != nil {
+			return err
+		}
+
+		// keys are collected first since the bucket can't be modified while it's iterated
+		var keys [][]byte
+		prefix := []byte(repoFullName + "/")
+		c := bucket.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+
+		for _, k := range keys {
+			waiting, err := b.getWaitList(bucket, k)
+			if err != nil {
+				return err
+			}
+			if err := b.writeWaitList(bucket, k, removeFromWaitList(waiting, pullNum)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+func removeFromWaitList(waiting []models.ProjectLock, pullNum int) []models.ProjectLock {
+	var remaining []models.ProjectLock
+	for _, l := range waiting {
+		if l.Pull.Num != pullNum {
+			remaining = append(remaining, l)
+		}
 	}
-	repo := pull.BaseRepo.FullName
-	if strings.Contains(repo, pullKeySeparator) {
-		return nil, fmt.Errorf("repo name %q contains illegal string %q", hostname, pullKeySeparator)
+	return remaining
+}
+
+func (b *BoltDB) updateWaitList(key string, update func(waiting []models.ProjectLock) []models.ProjectLock) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(waitListsBucketName))
+		if err != nil {
+			return err
+		}
+
+		waiting, err := b.getWaitList(bucket, []byte(key))
+		if err != nil {
+			return err
+		}
+		return b.writeWaitList(bucket, []byte(key), update(waiting))
+	})
+}
+
+func (b *BoltDB) getWaitList(bucket *bolt.Bucket, key []byte) ([]models.ProjectLock, error) {
+	serialized := bucket.Get(key)
+	if serialized == nil {
+		return nil, nil
 	}
 
-	return []byte(fmt.Sprintf("%s::%s::%d", hostname, repo, pull.Num)),
-		nil
+	var waiting []models.ProjectLock
+	if err := json.Unmarshal(serialized, &waiting); err != nil {
+		return nil, errors.Wrapf(err, "deserializing wait list at %q", key)
+	}
+	return waiting, nil
+}
+
+func (b *BoltDB) writeWaitList(bucket *bolt.Bucket, key []byte, waiting []models.ProjectLock) error {
+	if len(waiting) == 0 {
+		return bucket.Delete(key)
+	}
+
+	serialized, err := json.Marshal(waiting)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	return bucket.Put(key, serialized)
+}
+
+func (b *BoltDB) pullKey(pull models.PullRequest) ([]byte, error) {
+	key, err := pullKey(pull)
+	return []byte(key), err
 }
 
 func (b *BoltDB) commandLockKey(cmdName command.Name) string {
-	return fmt.Sprintf("%s/lock", cmdName)
+	return commandLockKey(cmdName)
 }
 
 func (b *BoltDB) lockKey(p models.Project, workspace string) string {
-	return fmt.Sprintf("%s/%s/%s", p.RepoFullName, p.Path, workspace)
+	return lockKey(p, workspace)
 }
 
 func (b *BoltDB) getPullFromBucket(bucket *bolt.Bucket, key []byte) (*models.PullStatus, error) {
@@ -481,12 +582,3 @@ func (b *BoltDB) writePullToBucket(bucket *bolt.Bucket, key []byte, pull models.
 	}
 	return bucket.Put(key, serialized)
 }
-
-func (b *BoltDB) projectResultToProject(p command.ProjectResult) models.ProjectStatus {
-	return models.ProjectStatus{
-		Workspace:   p.Workspace,
-		RepoRelDir:  p.RepoRelDir,
-		ProjectName: p.ProjectName,
-		Status:      p.PlanStatus(),
-	}
-}
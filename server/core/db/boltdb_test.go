@@ -278,6 +278,30 @@ func TestUnlocking(t *testing.T) {
 	Equals(t, newLock, currLock)
 }
 
+func TestUnlockExpired(t *testing.T) {
+	db, b := newTestDB()
+	defer cleanupDB(db)
+
+	expiring := lock
+	expiring.ExpiresAt = time.Now().Add(time.Hour)
+	_, _, err := b.TryLock(expiring)
+	Ok(t, err)
+
+	// locks which haven't expired are kept
+	released, err := b.UnlockExpired(project, workspace, time.Now())
+	Ok(t, err)
+	Assert(t, released == nil, "exp lock to not be released")
+
+	released, err = b.UnlockExpired(project, workspace, time.Now().Add(2*time.Hour))
+	Ok(t, err)
+	Assert(t, released != nil, "exp expired lock to be released")
+	Equals(t, expiring.Pull.Num, released.Pull.Num)
+
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
 func TestUnlockingMultiple(t *testing.T) {
 	t.Log("unlocking and locking multiple locks should succeed")
 	db, b := newTestDB()
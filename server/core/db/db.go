@@ -0,0 +1,121 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// Database stores project locks and their wait lists, command locks and pull statuses.
+// BoltDB is limited to a single server while SQL can be shared by many.
+type Database interface {
+	locking.Backend
+	locking.WaitList
+
+	// UpdatePullWithResults updates pull's status with the latest project results.
+	// It returns the new PullStatus object.
+	UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error)
+	// GetPullStatus returns the status for pull.
+	// If there is no status, returns a nil pointer.
+	GetPullStatus(pull models.PullRequest) (*models.PullStatus, error)
+	DeletePullStatus(pull models.PullRequest) error
+	UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error
+}
+
+var _ Database = &BoltDB{}
+var _ Database = &SQL{}
+
+func pullKey(pull models.PullRequest) (string, error) {
+	hostname := pull.BaseRepo.VCSHost.Hostname
+	if strings.Contains(hostname, pullKeySeparator) {
+		return "", fmt.Errorf("vcs hostname %q contains illegal string %q", hostname, pullKeySeparator)
+	}
+	repo := pull.BaseRepo.FullName
+	if strings.Contains(repo, pullKeySeparator) {
+		return "", fmt.Errorf("repo name %q contains illegal string %q", hostname, pullKeySeparator)
+	}
+
+	return fmt.Sprintf("%s::%s::%d", hostname, repo, pull.Num), nil
+}
+
+func commandLockKey(cmdName command.Name) string {
+	return fmt.Sprintf("%s/lock", cmdName)
+}
+
+func lockKey(p models.Project, workspace string) string {
+	return fmt.Sprintf("%s/%s/%s", p.RepoFullName, p.Path, workspace)
+}
+
+// mergePullResults returns pull's status after applying newResults to its current status
+func mergePullResults(currStatus *models.PullStatus, pull models.PullRequest, newResults []command.ProjectResult) models.PullStatus {
+	// If there is no pull OR if the pull we have is out of date, we
+	// just write a new pull.
+	if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
+		var statuses []models.ProjectStatus
+		for _, r := range newResults {
+			statuses = append(statuses, projectResultToProject(r))
+		}
+		return models.PullStatus{
+			Pull:      pull,
+			Projects:  statuses,
+			UpdatedAt: time.Now().Unix(),
+		}
+	}
+
+	// If there's an existing pull at the right commit then we have to
+	// merge our project results with the existing ones. We do a merge
+	// because it's possible a user is just applying a single project
+	// in this command and so we don't want to delete our data about
+	// other projects that aren't affected by this command.
+	newStatus := *currStatus
+	newStatus.UpdatedAt = time.Now().Unix()
+	for _, res := range newResults {
+		// First, check if we should update any existing projects.
+		updatedExisting := false
+		for i := range newStatus.Projects {
+			// NOTE: We're using a reference here because we are
+			// in-place updating its Status field.
+			proj := &newStatus.Projects[i]
+			if res.Workspace == proj.Workspace &&
+				res.RepoRelDir == proj.RepoRelDir &&
+				res.ProjectName == proj.ProjectName {
+				proj.Status = res.PlanStatus()
+				updatedExisting = true
+				break
+			}
+		}
+
+		if !updatedExisting {
+			// If we didn't update an existing project, then we need to
+			// add this because it's a new one.
+			newStatus.Projects = append(newStatus.Projects, projectResultToProject(res))
+		}
+	}
+	return newStatus
+}
+
+// setProjectStatus updates the status of the project at workspace and repoRelDir in place
+func setProjectStatus(currStatus models.PullStatus, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) {
+	for i := range currStatus.Projects {
+		// NOTE: We're using a reference here because we are
+		// in-place updating its Status field.
+		proj := &currStatus.Projects[i]
+		if proj.Workspace == workspace && proj.RepoRelDir == repoRelDir {
+			proj.Status = newStatus
+			break
+		}
+	}
+}
+
+func projectResultToProject(p command.ProjectResult) models.ProjectStatus {
+	return models.ProjectStatus{
+		Workspace:   p.Workspace,
+		RepoRelDir:  p.RepoRelDir,
+		ProjectName: p.ProjectName,
+		Status:      p.PlanStatus(),
+	}
+}
@@ -0,0 +1,93 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestWaitList(t *testing.T) {
+	databases := map[string]func(t *testing.T) db.Database{
+		"boltdb": func(t *testing.T) db.Database {
+			b, cleanup := newTestDB2(t)
+			t.Cleanup(cleanup)
+			return b
+		},
+		"sql": func(t *testing.T) db.Database {
+			return newTestSQL(t)
+		},
+	}
+
+	waiting := func(num int) models.ProjectLock {
+		l := lock
+		l.Pull.Num = num
+		return l
+	}
+
+	for name, newDB := range databases {
+		t.Run(name, func(t *testing.T) {
+			d := newDB(t)
+
+			next, err := d.NextLock(project, workspace)
+			Ok(t, err)
+			Assert(t, next == nil, "exp nil")
+
+			for i, num := range []int{2, 3, 4} {
+				position, err := d.EnqueueLock(waiting(num))
+				Ok(t, err)
+				Equals(t, i+1, position)
+			}
+
+			// a pull which is already waiting keeps its position
+			position, err := d.EnqueueLock(waiting(3))
+			Ok(t, err)
+			Equals(t, 2, position)
+
+			// wait lists are per project and workspace
+			otherWorkspace := waiting(5)
+			otherWorkspace.Workspace = "other"
+			position, err = d.EnqueueLock(otherWorkspace)
+			Ok(t, err)
+			Equals(t, 1, position)
+
+			// another repo with the same prefix isn't affected
+			otherRepo := waiting(2)
+			otherRepo.Project = models.NewProject("owner/repo2", "parent/child")
+			_, err = d.EnqueueLock(otherRepo)
+			Ok(t, err)
+
+			Ok(t, d.RemoveFromWaitList(project, workspace, 3))
+			Ok(t, d.RemovePullFromWaitLists("owner/repo", 5))
+
+			next, err = d.NextLock(project, workspace)
+			Ok(t, err)
+			Equals(t, 2, next.Pull.Num)
+			Equals(t, lock.User, next.User)
+
+			// the next pull keeps its position until it's removed
+			next, err = d.NextLock(project, workspace)
+			Ok(t, err)
+			Equals(t, 2, next.Pull.Num)
+
+			Ok(t, d.RemoveFromWaitList(project, workspace, 2))
+			next, err = d.NextLock(project, workspace)
+			Ok(t, err)
+			Equals(t, 4, next.Pull.Num)
+
+			Ok(t, d.RemoveFromWaitList(project, workspace, 4))
+			next, err = d.NextLock(project, workspace)
+			Ok(t, err)
+			Assert(t, next == nil, "exp nil")
+
+			next, err = d.NextLock(otherWorkspace.Project, "other")
+			Ok(t, err)
+			Assert(t, next == nil, "exp nil")
+
+			next, err = d.NextLock(otherRepo.Project, workspace)
+			Ok(t, err)
+			Equals(t, 2, next.Pull.Num)
+		})
+	}
+}
@@ -0,0 +1,558 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+
+	// register the supported drivers
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Supported SQL drivers
+const (
+	PostgresDriver = "postgres"
+	SQLiteDriver   = "sqlite"
+)
+
+// emptyPullStatus is the data of a pull status row which is created by an update that
+// hasn't been committed yet
+const emptyPullStatus = "null"
+
+// tryLockAttempts bounds retries when a conflicting lock is released before it can be read
+const tryLockAttempts = 3
+
+// migrations are applied in order and recorded in schema_migrations, a migration's version is
+// its position in the list so new migrations must be appended. The statements of the first
+// migration are idempotent since they predate schema_migrations.
+var migrations = [][]string{
+	// 1: initial schema
+	{
+		`CREATE TABLE IF NOT EXISTS project_locks (
+			lock_key TEXT PRIMARY KEY,
+			repo_full_name TEXT NOT NULL,
+			pull_num INTEGER NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS project_locks_pull ON project_locks (repo_full_name, pull_num)`,
+		`CREATE TABLE IF NOT EXISTS command_locks (
+			command_name TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS lock_wait_list (
+			lock_key TEXT NOT NULL,
+			repo_full_name TEXT NOT NULL,
+			pull_num INTEGER NOT NULL,
+			queued_at BIGINT NOT NULL,
+			data TEXT NOT NULL,
+			PRIMARY KEY (lock_key, pull_num)
+		)`,
+		`CREATE TABLE IF NOT EXISTS pull_statuses (
+			pull_key TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+	},
+}
+
+// SQL is a database which can be shared by multiple servers.
+// Locks and pull statuses are stored as JSON, the same as BoltDB.
+type SQL struct {
+	db     *sql.DB
+	driver string
+}
+
+// NewSQL connects to the database and creates its tables if they don't exist
+func NewSQL(driver string, dsn string) (*SQL, error) {
+	if driver != PostgresDriver && driver != SQLiteDriver {
+		return nil, fmt.Errorf("unsupported sql driver %q, supported drivers are %q and %q", driver, PostgresDriver, SQLiteDriver)
+	}
+
+	sqlDB, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s database", driver)
+	}
+
+	// sqlite only supports a single writer, and each connection to an in-memory database is a separate database
+	if driver == SQLiteDriver {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		return nil, errors.Wrapf(err, "connecting to %s database", driver)
+	}
+
+	s := &SQL{
+		db:     sqlDB,
+		driver: driver,
+	}
+	if err := s.migrate(); err != nil {
+		return nil, errors.Wrap(err, "migrating schema")
+	}
+	return s, nil
+}
+
+// migrate applies the migrations which haven't been recorded yet. Each migration is applied in
+// a transaction along with its record so servers which start at the same time apply it once,
+// the record's insert blocks until the transaction which is applying it finishes.
+func (s *SQL) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	for i, stmts := range migrations {
+		version := i + 1
+		err := s.update(func(tx *sql.Tx) error {
+			res, err := tx.Exec(s.rebind(`INSERT INTO schema_migrations (version) VALUES (?) ON CONFLICT (version) DO NOTHING`), version)
+			if err != nil {
+				return err
+			}
+			if applied, err := res.RowsAffected(); err != nil || applied == 0 {
+				return err
+			}
+
+			for _, stmt := range stmts {
+				if _, err := tx.Exec(stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "applying migration %d", version)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying connections
+func (s *SQL) Close() error {
+	return s.db.Close()
+}
+
+// TryLock attempts to create a new lock. If the lock is
+// acquired, it will return true and the lock returned will be newLock.
+// If the lock is not acquired, it will return false and the current
+// lock that is preventing this lock from being acquired.
+func (s *SQL) TryLock(newLock models.ProjectLock) (bool, models.ProjectLock, error) {
+	key := lockKey(newLock.Project, newLock.Workspace)
+	newLockSerialized, err := json.Marshal(newLock)
+	if err != nil {
+		return false, models.ProjectLock{}, errors.Wrap(err, "serializing lock")
+	}
+
+	for i := 0; i < tryLockAttempts; i++ {
+		res, err := s.db.Exec(
+			s.rebind(`INSERT INTO project_locks (lock_key, repo_full_name, pull_num, data) VALUES (?, ?, ?, ?) ON CONFLICT (lock_key) DO NOTHING`),
+			key, newLock.Project.RepoFullName, newLock.Pull.Num, string(newLockSerialized),
+		)
+		if err != nil {
+			return false, models.ProjectLock{}, errors.Wrap(err, "DB transaction failed")
+		}
+
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return false, models.ProjectLock{}, errors.Wrap(err, "DB transaction failed")
+		}
+		if inserted == 1 {
+			return true, newLock, nil
+		}
+
+		// otherwise the lock fails, return to caller the run that's holding the lock
+		currLock, err := s.GetLock(newLock.Project, newLock.Workspace)
+		if err != nil {
+			return false, models.ProjectLock{}, err
+		}
+		if currLock != nil {
+			return false, *currLock, nil
+		}
+	}
+
+	return false, models.ProjectLock{}, fmt.Errorf("lock %q changed while it was being acquired", key)
+}
+
+// Unlock attempts to unlock the project and workspace.
+// If there is no lock, then it will return a nil pointer.
+// If there is a lock, then it will delete it, and then return a pointer
+// to the deleted lock.
+func (s *SQL) Unlock(p models.Project, workspace string) (*models.ProjectLock, error) {
+	locks, err := s.deleteLocks(`DELETE FROM project_locks WHERE lock_key = ? RETURNING data`, lockKey(p, workspace))
+	if err != nil || len(locks) == 0 {
+		return nil, err
+	}
+	return &locks[0], nil
+}
+
+// UnlockExpired deletes the lock of the project and workspace if it expired before now and
+// returns it. If there is no expired lock, it returns a nil pointer.
+func (s *SQL) UnlockExpired(p models.Project, workspace string, now time.Time) (*models.ProjectLock, error) {
+	key := lockKey(p, workspace)
+
+	var serialized string
+	err := s.db.QueryRow(s.rebind(`SELECT data FROM project_locks WHERE lock_key = ?`), key).Scan(&serialized)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting lock data")
+	}
+
+	var lock models.ProjectLock
+	if err := json.Unmarshal([]byte(serialized), &lock); err != nil {
+		return nil, errors.Wrapf(err, "deserializing lock at key %q", key)
+	}
+	if !lock.Expired(now) {
+		return nil, nil
+	}
+
+	// the lock is only deleted if it hasn't been replaced since it was read
+	locks, err := s.deleteLocks(`DELETE FROM project_locks WHERE lock_key = ? AND data = ? RETURNING data`, key, serialized)
+	if err != nil || len(locks) == 0 {
+		return nil, err
+	}
+	return &locks[0], nil
+}
+
+// List lists all current locks.
+func (s *SQL) List() ([]models.ProjectLock, error) {
+	rows, err := s.db.Query(`SELECT data FROM project_locks ORDER BY lock_key`)
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	return scanLocks(rows)
+}
+
+// GetLock returns a pointer to the lock for that project and workspace.
+// If there is no lock, it returns a nil pointer.
+func (s *SQL) GetLock(p models.Project, workspace string) (*models.ProjectLock, error) {
+	key := lockKey(p, workspace)
+
+	var serialized string
+	err := s.db.QueryRow(s.rebind(`SELECT data FROM project_locks WHERE lock_key = ?`), key).Scan(&serialized)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "getting lock data")
+	}
+
+	var lock models.ProjectLock
+	if err := json.Unmarshal([]byte(serialized), &lock); err != nil {
+		return nil, errors.Wrapf(err, "deserializing lock at key %q", key)
+	}
+
+	// need to set it to Local after deserialization due to https://github.com/golang/go/issues/19486
+	lock.Time = lock.Time.Local()
+	return &lock, nil
+}
+
+// UnlockByPull deletes all locks associated with that pull request and returns them.
+func (s *SQL) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
+	return s.deleteLocks(`DELETE FROM project_locks WHERE repo_full_name = ? AND pull_num = ? RETURNING data`, repoFullName, pullNum)
+}
+
+func (s *SQL) deleteLocks(query string, args ...interface{}) ([]models.ProjectLock, error) {
+	rows, err := s.db.Query(s.rebind(query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	return scanLocks(rows)
+}
+
+func scanLocks(rows *sql.Rows) ([]models.ProjectLock, error) {
+	defer rows.Close()
+
+	var locks []models.ProjectLock
+	for rows.Next() {
+		var serialized string
+		if err := rows.Scan(&serialized); err != nil {
+			return locks, errors.Wrap(err, "DB transaction failed")
+		}
+
+		var lock models.ProjectLock
+		if err := json.Unmarshal([]byte(serialized), &lock); err != nil {
+			return locks, errors.Wrap(err, "failed to deserialize lock")
+		}
+		locks = append(locks, lock)
+	}
+	return locks, errors.Wrap(rows.Err(), "DB transaction failed")
+}
+
+// EnqueueLock adds the lock's pull to the wait list of its project and workspace and
+// returns its position in line, starting at 1. A pull which is already waiting keeps its position.
+func (s *SQL) EnqueueLock(lock models.ProjectLock) (int, error) {
+	key := lockKey(lock.Project, lock.Workspace)
+	serialized, err := json.Marshal(lock)
+	if err != nil {
+		return 0, errors.Wrap(err, "serializing lock")
+	}
+
+	if _, err := s.db.Exec(
+		s.rebind(`INSERT INTO lock_wait_list (lock_key, repo_full_name, pull_num, queued_at, data) VALUES (?, ?, ?, ?, ?) ON CONFLICT (lock_key, pull_num) DO NOTHING`),
+		key, lock.Project.RepoFullName, lock.Pull.Num, time.Now().UnixNano(), string(serialized),
+	); err != nil {
+		return 0, errors.Wrap(err, "DB transaction failed")
+	}
+
+	// pulls queued at the same time are ordered by number
+	var position int
+	err = s.db.QueryRow(s.rebind(`
+		SELECT COUNT(*) FROM lock_wait_list w, lock_wait_list p
+		WHERE p.lock_key = ? AND p.pull_num = ? AND w.lock_key = p.lock_key
+		AND (w.queued_at < p.queued_at OR (w.queued_at = p.queued_at AND w.pull_num <= p.pull_num))`),
+		key, lock.Pull.Num,
+	).Scan(&position)
+	return position, errors.Wrap(err, "DB transaction failed")
+}
+
+// NextLock returns the first lock waiting for the project and workspace without removing it.
+// If no pulls are waiting, it returns a nil pointer.
+func (s *SQL) NextLock(p models.Project, workspace string) (*models.ProjectLock, error) {
+	var data string
+	err := s.db.QueryRow(
+		s.rebind(`SELECT data FROM lock_wait_list WHERE lock_key = ? ORDER BY queued_at, pull_num LIMIT 1`),
+		lockKey(p, workspace),
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+
+	var lock models.ProjectLock
+	if err := json.Unmarshal([]byte(data), &lock); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize lock")
+	}
+	return &lock, nil
+}
+
+// RemoveFromWaitList removes the pull from the wait list of the project and workspace.
+func (s *SQL) RemoveFromWaitList(p models.Project, workspace string, pullNum int) error {
+	_, err := s.db.Exec(s.rebind(`DELETE FROM lock_wait_list WHERE lock_key = ? AND pull_num = ?`), lockKey(p, workspace), pullNum)
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// RemovePullFromWaitLists removes the pull from every wait list of the repo.
+func (s *SQL) RemovePullFromWaitLists(repoFullName string, pullNum int) error {
+	_, err := s.db.Exec(s.rebind(`DELETE FROM lock_wait_list WHERE repo_full_name = ? AND pull_num = ?`), repoFullName, pullNum)
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// LockCommand attempts to create a new lock for a CommandName.
+// If the lock doesn't exists, it will create a lock and return a pointer to it.
+// If the lock already exists, it will return an "lock already exists" error
+func (s *SQL) LockCommand(cmdName command.Name, lockTime time.Time) (*command.Lock, error) {
+	lock := command.Lock{
+		CommandName: cmdName,
+		LockMetadata: command.LockMetadata{
+			UnixTime: lockTime.Unix(),
+		},
+	}
+
+	newLockSerialized, _ := json.Marshal(lock)
+	res, err := s.db.Exec(
+		s.rebind(`INSERT INTO command_locks (command_name, data) VALUES (?, ?) ON CONFLICT (command_name) DO NOTHING`),
+		commandLockKey(cmdName), string(newLockSerialized),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+
+	if inserted, err := res.RowsAffected(); err != nil || inserted == 0 {
+		return nil, errors.Wrap(orError(err, "lock already exists"), "db transaction failed")
+	}
+	return &lock, nil
+}
+
+// UnlockCommand removes CommandName lock if present.
+// If there are no lock it returns an error.
+func (s *SQL) UnlockCommand(cmdName command.Name) error {
+	res, err := s.db.Exec(s.rebind(`DELETE FROM command_locks WHERE command_name = ?`), commandLockKey(cmdName))
+	if err != nil {
+		return errors.Wrap(err, "db transaction failed")
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil || deleted == 0 {
+		return errors.Wrap(orError(err, "no lock exists"), "db transaction failed")
+	}
+	return nil
+}
+
+// CheckCommandLock checks if CommandName lock was set.
+// If the lock exists return the pointer to the lock object, otherwise return nil
+func (s *SQL) CheckCommandLock(cmdName command.Name) (*command.Lock, error) {
+	var serialized string
+	err := s.db.QueryRow(s.rebind(`SELECT data FROM command_locks WHERE command_name = ?`), commandLockKey(cmdName)).Scan(&serialized)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+
+	var cmdLock command.Lock
+	if err := json.Unmarshal([]byte(serialized), &cmdLock); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize command lock")
+	}
+	return &cmdLock, nil
+}
+
+// UpdatePullWithResults updates pull's status with the latest project results.
+// It returns the new PullStatus object.
+func (s *SQL) UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error) {
+	key, err := pullKey(pull)
+	if err != nil {
+		return models.PullStatus{}, err
+	}
+
+	var newStatus models.PullStatus
+	err = s.update(func(tx *sql.Tx) error {
+		// the row is created first so the select below always has a row to lock, otherwise the first
+		// results of concurrent updates would both be written without seeing each other's
+		if _, err := tx.Exec(
+			s.rebind(`INSERT INTO pull_statuses (pull_key, data) VALUES (?, ?) ON CONFLICT (pull_key) DO NOTHING`),
+			key, emptyPullStatus,
+		); err != nil {
+			return err
+		}
+
+		currStatus, err := s.getPullStatus(tx, key, true)
+		if err != nil {
+			return err
+		}
+
+		newStatus = mergePullResults(currStatus, pull, newResults)
+		return s.writePullStatus(tx, key, newStatus)
+	})
+	return newStatus, errors.Wrap(err, "DB transaction failed")
+}
+
+// GetPullStatus returns the status for pull.
+// If there is no status, returns a nil pointer.
+func (s *SQL) GetPullStatus(pull models.PullRequest) (*models.PullStatus, error) {
+	key, err := pullKey(pull)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.getPullStatus(s.db, key, false)
+	return status, errors.Wrap(err, "DB transaction failed")
+}
+
+// DeletePullStatus deletes the status for pull.
+func (s *SQL) DeletePullStatus(pull models.PullRequest) error {
+	key, err := pullKey(pull)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(s.rebind(`DELETE FROM pull_statuses WHERE pull_key = ?`), key)
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// UpdateProjectStatus updates project status.
+func (s *SQL) UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error {
+	key, err := pullKey(pull)
+	if err != nil {
+		return err
+	}
+
+	err = s.update(func(tx *sql.Tx) error {
+		currStatus, err := s.getPullStatus(tx, key, true)
+		if err != nil || currStatus == nil {
+			return err
+		}
+
+		setProjectStatus(*currStatus, workspace, repoRelDir, newStatus)
+		return s.writePullStatus(tx, key, *currStatus)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// getPullStatus reads the pull status at key, forUpdate locks its row until tx is committed
+func (s *SQL) getPullStatus(q queryer, key string, forUpdate bool) (*models.PullStatus, error) {
+	query := `SELECT data FROM pull_statuses WHERE pull_key = ?`
+	// sqlite doesn't support row locks, its transactions are serialized instead
+	if forUpdate && s.driver == PostgresDriver {
+		query += ` FOR UPDATE`
+	}
+
+	var serialized string
+	err := q.QueryRow(s.rebind(query), key).Scan(&serialized)
+	if err == sql.ErrNoRows || serialized == emptyPullStatus {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var p models.PullStatus
+	if err := json.Unmarshal([]byte(serialized), &p); err != nil {
+		return nil, errors.Wrapf(err, "deserializing pull at %q with contents %q", key, serialized)
+	}
+	return &p, nil
+}
+
+func (s *SQL) writePullStatus(tx *sql.Tx, key string, pull models.PullStatus) error {
+	serialized, err := json.Marshal(pull)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+
+	_, err = tx.Exec(
+		s.rebind(`INSERT INTO pull_statuses (pull_key, data) VALUES (?, ?) ON CONFLICT (pull_key) DO UPDATE SET data = excluded.data`),
+		key, string(serialized),
+	)
+	return err
+}
+
+// update runs fn in a transaction which is committed if fn succeeds
+func (s *SQL) update(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebind replaces ? placeholders with the driver's placeholder syntax
+func (s *SQL) rebind(query string) string {
+	if s.driver != PostgresDriver {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func orError(err error, msg string) error {
+	if err != nil {
+		return err
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,305 @@
+package db_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func newTestSQL(t *testing.T) *db.SQL {
+	s, err := db.NewSQL(db.SQLiteDriver, filepath.Join(t.TempDir(), "atlantis.db"))
+	Ok(t, err)
+	t.Cleanup(func() {
+		s.Close() // nolint: errcheck
+	})
+	return s
+}
+
+func testPull() models.PullRequest {
+	return models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		BaseRepo: models.Repo{
+			FullName: "runatlantis/atlantis",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+}
+
+func TestNewSQL_UnsupportedDriver(t *testing.T) {
+	_, err := db.NewSQL("mysql", "")
+	ErrContains(t, "unsupported sql driver", err)
+}
+
+func TestNewSQL_ExistingSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atlantis.db")
+	s, err := db.NewSQL(db.SQLiteDriver, path)
+	Ok(t, err)
+	_, _, err = s.TryLock(lock)
+	Ok(t, err)
+	Ok(t, s.Close())
+
+	// locks survive a restart
+	s, err = db.NewSQL(db.SQLiteDriver, path)
+	Ok(t, err)
+	defer s.Close() // nolint: errcheck
+	locks, err := s.List()
+	Ok(t, err)
+	Equals(t, 1, len(locks))
+}
+
+func TestNewSQL_Migrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atlantis.db")
+
+	// databases created before migrations were recorded already have the initial schema
+	legacy, err := sql.Open(db.SQLiteDriver, path)
+	Ok(t, err)
+	_, err = legacy.Exec(`CREATE TABLE project_locks (lock_key TEXT PRIMARY KEY, repo_full_name TEXT NOT NULL, pull_num INTEGER NOT NULL, data TEXT NOT NULL)`)
+	Ok(t, err)
+	Ok(t, legacy.Close())
+
+	for i := 0; i < 2; i++ {
+		s, err := db.NewSQL(db.SQLiteDriver, path)
+		Ok(t, err)
+		_, _, err = s.TryLock(lock)
+		Ok(t, err)
+		Ok(t, s.Close())
+	}
+
+	conn, err := sql.Open(db.SQLiteDriver, path)
+	Ok(t, err)
+	defer conn.Close() // nolint: errcheck
+
+	var applied, version int
+	Ok(t, conn.QueryRow(`SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&applied, &version))
+	Equals(t, 1, applied)
+	Equals(t, 1, version)
+}
+
+func TestSQL_Locking(t *testing.T) {
+	s := newTestSQL(t)
+
+	acquired, currLock, err := s.TryLock(lock)
+	Ok(t, err)
+	Assert(t, acquired, "exp lock to be acquired")
+	Equals(t, lock, currLock)
+
+	// a different pull can't take the lock
+	newLock := lock
+	newLock.Pull.Num = pullNum + 1
+	acquired, currLock, err = s.TryLock(newLock)
+	Ok(t, err)
+	Assert(t, !acquired, "exp lock to not be acquired")
+	Equals(t, pullNum, currLock.Pull.Num)
+
+	l, err := s.GetLock(project, workspace)
+	Ok(t, err)
+	Equals(t, lock.Pull, l.Pull)
+	Assert(t, lock.Time.Equal(l.Time), "exp lock time to be preserved")
+
+	// a different workspace is a different lock
+	otherWorkspace := lock
+	otherWorkspace.Workspace = "other"
+	acquired, _, err = s.TryLock(otherWorkspace)
+	Ok(t, err)
+	Assert(t, acquired, "exp lock to be acquired")
+
+	locks, err := s.List()
+	Ok(t, err)
+	Equals(t, 2, len(locks))
+
+	unlocked, err := s.Unlock(project, workspace)
+	Ok(t, err)
+	Equals(t, lock.Pull, unlocked.Pull)
+
+	unlocked, err = s.Unlock(project, workspace)
+	Ok(t, err)
+	Assert(t, unlocked == nil, "exp nil")
+
+	l, err = s.GetLock(project, workspace)
+	Ok(t, err)
+	Assert(t, l == nil, "exp nil")
+}
+
+func TestSQL_UnlockExpired(t *testing.T) {
+	s := newTestSQL(t)
+
+	expiring := lock
+	expiring.ExpiresAt = time.Now().Add(time.Hour)
+	_, _, err := s.TryLock(expiring)
+	Ok(t, err)
+
+	// locks which haven't expired are kept
+	released, err := s.UnlockExpired(project, workspace, time.Now())
+	Ok(t, err)
+	Assert(t, released == nil, "exp lock to not be released")
+
+	released, err = s.UnlockExpired(project, workspace, time.Now().Add(2*time.Hour))
+	Ok(t, err)
+	Assert(t, released != nil, "exp expired lock to be released")
+	Equals(t, expiring.Pull.Num, released.Pull.Num)
+
+	l, err := s.GetLock(project, workspace)
+	Ok(t, err)
+	Assert(t, l == nil, "exp lock to be deleted")
+}
+
+func TestSQL_UnlockByPull(t *testing.T) {
+	s := newTestSQL(t)
+
+	otherPath := lock
+	otherPath.Project = models.NewProject("owner/repo", "other/path")
+	otherPull := lock
+	otherPull.Project = models.NewProject("owner/repo", "other/pull")
+	otherPull.Pull.Num = pullNum + 1
+	otherRepo := lock
+	otherRepo.Project = models.NewProject("owner/other", "parent/child")
+
+	for _, l := range []models.ProjectLock{lock, otherPath, otherPull, otherRepo} {
+		_, _, err := s.TryLock(l)
+		Ok(t, err)
+	}
+
+	locks, err := s.UnlockByPull("owner/repo", pullNum)
+	Ok(t, err)
+	Equals(t, 2, len(locks))
+
+	locks, err = s.UnlockByPull("owner/repo", pullNum)
+	Ok(t, err)
+	Equals(t, 0, len(locks))
+
+	locks, err = s.List()
+	Ok(t, err)
+	Equals(t, 2, len(locks))
+}
+
+func TestSQL_CommandLock(t *testing.T) {
+	s := newTestSQL(t)
+
+	cmdLock, err := s.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Assert(t, cmdLock == nil, "exp nil")
+
+	timeNow := time.Now()
+	_, err = s.LockCommand(command.Apply, timeNow)
+	Ok(t, err)
+
+	_, err = s.LockCommand(command.Apply, timeNow)
+	ErrEquals(t, "db transaction failed: lock already exists", err)
+
+	cmdLock, err = s.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Equals(t, command.Apply, cmdLock.CommandName)
+	Equals(t, timeNow.Unix(), cmdLock.LockMetadata.UnixTime)
+
+	Ok(t, s.UnlockCommand(command.Apply))
+	ErrEquals(t, "db transaction failed: no lock exists", s.UnlockCommand(command.Apply))
+}
+
+func TestSQL_PullStatus(t *testing.T) {
+	s := newTestSQL(t)
+	pull := testPull()
+
+	status, err := s.GetPullStatus(pull)
+	Ok(t, err)
+	Assert(t, status == nil, "exp nil")
+
+	_, err = s.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: "mergeme",
+			Workspace:  "default",
+			Failure:    "failure",
+		},
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "staythesame",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+
+	// results for the same commit are merged
+	updated, err := s.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:      command.Apply,
+			RepoRelDir:   "mergeme",
+			Workspace:    "default",
+			ApplySuccess: "applied!",
+		},
+		{
+			Command:    command.Apply,
+			RepoRelDir: "newresult",
+			Workspace:  "default",
+			Error:      errors.New("apply error"),
+		},
+	})
+	Ok(t, err)
+
+	Ok(t, s.UpdateProjectStatus(pull, "default", "staythesame", models.DiscardedPlanStatus))
+
+	status, err = s.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, pull, status.Pull)
+	Equals(t, updated.UpdatedAt, status.UpdatedAt)
+	Equals(t, []models.ProjectStatus{
+		{
+			RepoRelDir: "mergeme",
+			Workspace:  "default",
+			Status:     models.AppliedPlanStatus,
+		},
+		{
+			RepoRelDir: "staythesame",
+			Workspace:  "default",
+			Status:     models.DiscardedPlanStatus,
+		},
+		{
+			RepoRelDir: "newresult",
+			Workspace:  "default",
+			Status:     models.ErroredApplyStatus,
+		},
+	}, status.Projects)
+
+	// a new commit replaces the results
+	pull.HeadCommit = "newsha"
+	_, err = s.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "staythesame",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+
+	status, err = s.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, []models.ProjectStatus{
+		{
+			RepoRelDir: "staythesame",
+			Workspace:  "default",
+			Status:     models.PlannedPlanStatus,
+		},
+	}, status.Projects)
+
+	Ok(t, s.DeletePullStatus(pull))
+	status, err = s.GetPullStatus(pull)
+	Ok(t, err)
+	Assert(t, status == nil, "exp nil")
+}
+
+func TestSQL_UpdateProjectStatusNoPull(t *testing.T) {
+	s := newTestSQL(t)
+	Ok(t, s.UpdateProjectStatus(testPull(), "default", ".", models.DiscardedPlanStatus))
+}
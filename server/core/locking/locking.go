@@ -34,6 +34,7 @@ import (
 type Backend interface {
 	TryLock(lock models.ProjectLock) (bool, models.ProjectLock, error)
 	Unlock(project models.Project, workspace string) (*models.ProjectLock, error)
+	UnlockExpired(project models.Project, workspace string, now time.Time) (*models.ProjectLock, error)
 	List() ([]models.ProjectLock, error)
 	GetLock(project models.Project, workspace string) (*models.ProjectLock, error)
 	UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error)
@@ -43,6 +44,20 @@ type Backend interface {
 	CheckCommandLock(cmdName command.Name) (*command.Lock, error)
 }
 
+// WaitList is a FIFO list per project and workspace of the pulls waiting for its lock.
+type WaitList interface {
+	// EnqueueLock adds the lock's pull to the wait list of its project and workspace and
+	// returns its position in line, starting at 1. A pull which is already waiting keeps its position.
+	EnqueueLock(lock models.ProjectLock) (int, error)
+	// NextLock returns the first lock waiting for the project and workspace without removing
+	// it from the wait list. If no pulls are waiting, it returns a nil pointer.
+	NextLock(project models.Project, workspace string) (*models.ProjectLock, error)
+	// RemoveFromWaitList removes the pull from the wait list of the project and workspace.
+	RemoveFromWaitList(project models.Project, workspace string, pullNum int) error
+	// RemovePullFromWaitLists removes the pull from every wait list.
+	RemovePullFromWaitLists(repoFullName string, pullNum int) error
+}
+
 // TryLockResponse results from an attempted lock.
 type TryLockResponse struct {
 	// LockAcquired is true if the lock was acquired from this call.
@@ -51,11 +66,15 @@ type TryLockResponse struct {
 	CurrLock models.ProjectLock
 	// LockKey is an identified by which to lookup and delete this lock.
 	LockKey string
+	// QueuePosition is the pull's position in line for the lock, starting at 1.
+	// It's 0 if the pull isn't waiting for the lock.
+	QueuePosition int
 }
 
 // Client is used to perform locking actions.
 type Client struct {
 	backend Backend
+	ttl     time.Duration
 }
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_locker.go Locker
@@ -63,6 +82,7 @@ type Client struct {
 type Locker interface {
 	TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (TryLockResponse, error)
 	Unlock(key string) (*models.ProjectLock, error)
+	UnlockExpired(key string, now time.Time) (*models.ProjectLock, error)
 	List() (map[string]models.ProjectLock, error)
 	UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error)
 	GetLock(key string) (*models.ProjectLock, error)
@@ -75,10 +95,20 @@ func NewClient(backend Backend) *Client {
 	}
 }
 
+// NewExpiringClient returns a locking client whose locks expire ttl after
+// they're created. Expired locks are released by the lock reaper.
+func NewExpiringClient(backend Backend, ttl time.Duration) *Client {
+	return &Client{
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
 // keyRegex matches and captures {repoFullName}/{path}/{workspace} where path can have multiple /'s in it.
 var keyRegex = regexp.MustCompile(`^(.*?\/.*?)\/(.*)\/(.*)$`)
 
-// TryLock attempts to acquire a lock to a project and workspace.
+// TryLock attempts to acquire a lock to a project and workspace. An expired lock is
+// treated as free, it's replaced by the new lock.
 func (c *Client) TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (TryLockResponse, error) {
 	lock := models.ProjectLock{
 		Workspace: workspace,
@@ -87,11 +117,24 @@ func (c *Client) TryLock(p models.Project, workspace string, pull models.PullReq
 		User:      user,
 		Pull:      pull,
 	}
+	if c.ttl > 0 {
+		lock.ExpiresAt = lock.Time.Add(c.ttl)
+	}
 	lockAcquired, currLock, err := c.backend.TryLock(lock)
+	if err == nil && !lockAcquired && currLock.Expired(lock.Time) {
+		// the expired lock is only released if it hasn't been replaced since
+		if _, err = c.backend.UnlockExpired(p, workspace, lock.Time); err == nil {
+			lockAcquired, currLock, err = c.backend.TryLock(lock)
+		}
+	}
 	if err != nil {
 		return TryLockResponse{}, err
 	}
-	return TryLockResponse{lockAcquired, currLock, c.key(p, workspace)}, nil
+	return TryLockResponse{
+		LockAcquired: lockAcquired,
+		CurrLock:     currLock,
+		LockKey:      c.key(p, workspace),
+	}, nil
 }
 
 // Unlock attempts to unlock a project and workspace. If successful,
@@ -106,6 +149,16 @@ func (c *Client) Unlock(key string) (*models.ProjectLock, error) {
 	return c.backend.Unlock(project, workspace)
 }
 
+// UnlockExpired unlocks the lock stored at key if it expired before now. It returns the
+// released lock, or nil if there is no lock or it hasn't expired.
+func (c *Client) UnlockExpired(key string, now time.Time) (*models.ProjectLock, error) {
+	project, workspace, err := c.lockKeyToProjectWorkspace(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.backend.UnlockExpired(project, workspace, now)
+}
+
 // List returns a map of all locks with their lock key as the map key.
 // The lock key can be used in GetLock() and Unlock().
 func (c *Client) List() (map[string]models.ProjectLock, error) {
@@ -144,6 +197,11 @@ func (c *Client) GetLock(key string) (*models.ProjectLock, error) {
 }
 
 func (c *Client) key(p models.Project, workspace string) string {
+	return Key(p, workspace)
+}
+
+// Key returns the key the lock for the project and workspace is stored at.
+func Key(p models.Project, workspace string) string {
 	return fmt.Sprintf("%s/%s/%s", p.RepoFullName, p.Path, workspace)
 }
 
@@ -165,7 +223,11 @@ func NewNoOpLocker() *NoOpLocker {
 
 // TryLock attempts to acquire a lock to a project and workspace.
 func (c *NoOpLocker) TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (TryLockResponse, error) {
-	return TryLockResponse{true, models.ProjectLock{}, c.key(p, workspace)}, nil
+	return TryLockResponse{
+		LockAcquired: true,
+		CurrLock:     models.ProjectLock{},
+		LockKey:      c.key(p, workspace),
+	}, nil
 }
 
 // Unlock attempts to unlock a project and workspace. If successful,
@@ -176,6 +238,11 @@ func (c *NoOpLocker) Unlock(key string) (*models.ProjectLock, error) {
 	return &models.ProjectLock{}, nil
 }
 
+// UnlockExpired is a no-op since locks are never held.
+func (c *NoOpLocker) UnlockExpired(key string, now time.Time) (*models.ProjectLock, error) {
+	return nil, nil
+}
+
 // List returns a map of all locks with their lock key as the map key.
 // The lock key can be used in GetLock() and Unlock().
 func (c *NoOpLocker) List() (map[string]models.ProjectLock, error) {
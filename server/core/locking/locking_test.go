@@ -58,6 +58,47 @@ func TestTryLock_Success(t *testing.T) {
 	Equals(t, locking.TryLockResponse{LockAcquired: true, CurrLock: currLock, LockKey: "owner/repo/path/workspace"}, r)
 }
 
+func TestTryLock_Expiring(t *testing.T) {
+	RegisterMockTestingT(t)
+	backend := mocks.NewMockBackend()
+	When(backend.TryLock(matchers.AnyModelsProjectLock())).ThenReturn(true, models.ProjectLock{}, nil)
+	l := locking.NewExpiringClient(backend, time.Hour)
+	_, err := l.TryLock(project, workspace, pull, user)
+	Ok(t, err)
+
+	lock := backend.VerifyWasCalledOnce().TryLock(matchers.AnyModelsProjectLock()).GetCapturedArguments()
+	Equals(t, time.Hour, lock.ExpiresAt.Sub(lock.Time))
+	Assert(t, !lock.Expired(lock.Time), "exp lock to not be expired when created")
+	Assert(t, lock.Expired(lock.Time.Add(2*time.Hour)), "exp lock to be expired after its ttl")
+}
+
+func TestTryLock_ReplacesExpiredLock(t *testing.T) {
+	RegisterMockTestingT(t)
+	backend := mocks.NewMockBackend()
+	expired := models.ProjectLock{Pull: models.PullRequest{Num: 2}, ExpiresAt: time.Now().Add(-time.Minute)}
+	When(backend.TryLock(matchers.AnyModelsProjectLock())).
+		ThenReturn(false, expired, nil).
+		ThenReturn(true, pl, nil)
+	When(backend.UnlockExpired(matchers.EqModelsProject(project), EqString(workspace), matchers.AnyTimeTime())).ThenReturn(&expired, nil)
+
+	l := locking.NewClient(backend)
+	r, err := l.TryLock(project, workspace, pull, user)
+	Ok(t, err)
+	Assert(t, r.LockAcquired, "exp expired lock to be replaced")
+	backend.VerifyWasCalled(Times(2)).TryLock(matchers.AnyModelsProjectLock())
+}
+
+func TestUnlockExpired(t *testing.T) {
+	RegisterMockTestingT(t)
+	backend := mocks.NewMockBackend()
+	now := time.Now()
+	When(backend.UnlockExpired(project, workspace, now)).ThenReturn(&pl, nil)
+	l := locking.NewClient(backend)
+	lock, err := l.UnlockExpired("owner/repo/path/workspace", now)
+	Ok(t, err)
+	Equals(t, &pl, lock)
+}
+
 func TestUnlock_InvalidKey(t *testing.T) {
 	RegisterMockTestingT(t)
 	backend := mocks.NewMockBackend()
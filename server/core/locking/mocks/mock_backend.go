@@ -69,6 +69,25 @@ func (mock *MockBackend) Unlock(project models.Project, workspace string) (*mode
 	return ret0, ret1
 }
 
+func (mock *MockBackend) UnlockExpired(project models.Project, workspace string, now time.Time) (*models.ProjectLock, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{project, workspace, now}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UnlockExpired", params, []reflect.Type{reflect.TypeOf((**models.ProjectLock)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *models.ProjectLock
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*models.ProjectLock)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockBackend) List() ([]models.ProjectLock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockBackend().")
@@ -274,6 +293,41 @@ func (c *MockBackend_Unlock_OngoingVerification) GetAllCapturedArguments() (_par
 	return
 }
 
+func (verifier *VerifierMockBackend) UnlockExpired(project models.Project, workspace string, now time.Time) *MockBackend_UnlockExpired_OngoingVerification {
+	params := []pegomock.Param{project, workspace, now}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UnlockExpired", params, verifier.timeout)
+	return &MockBackend_UnlockExpired_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_UnlockExpired_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_UnlockExpired_OngoingVerification) GetCapturedArguments() (models.Project, string, time.Time) {
+	project, workspace, now := c.GetAllCapturedArguments()
+	return project[len(project)-1], workspace[len(workspace)-1], now[len(now)-1]
+}
+
+func (c *MockBackend_UnlockExpired_OngoingVerification) GetAllCapturedArguments() (_param0 []models.Project, _param1 []string, _param2 []time.Time) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]models.Project, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(models.Project)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]time.Time, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(time.Time)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockBackend) List() *MockBackend_List_OngoingVerification {
 	params := []pegomock.Param{}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "List", params, verifier.timeout)
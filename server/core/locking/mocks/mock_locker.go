@@ -64,6 +64,25 @@ func (mock *MockLocker) Unlock(key string) (*models.ProjectLock, error) {
 	return ret0, ret1
 }
 
+func (mock *MockLocker) UnlockExpired(key string, now time.Time) (*models.ProjectLock, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockLocker().")
+	}
+	params := []pegomock.Param{key, now}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UnlockExpired", params, []reflect.Type{reflect.TypeOf((**models.ProjectLock)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *models.ProjectLock
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*models.ProjectLock)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockLocker) List() (map[string]models.ProjectLock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockLocker().")
@@ -224,6 +243,37 @@ func (c *MockLocker_Unlock_OngoingVerification) GetAllCapturedArguments() (_para
 	return
 }
 
+func (verifier *VerifierMockLocker) UnlockExpired(key string, now time.Time) *MockLocker_UnlockExpired_OngoingVerification {
+	params := []pegomock.Param{key, now}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UnlockExpired", params, verifier.timeout)
+	return &MockLocker_UnlockExpired_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockLocker_UnlockExpired_OngoingVerification struct {
+	mock              *MockLocker
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockLocker_UnlockExpired_OngoingVerification) GetCapturedArguments() (string, time.Time) {
+	key, now := c.GetAllCapturedArguments()
+	return key[len(key)-1], now[len(now)-1]
+}
+
+func (c *MockLocker_UnlockExpired_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []time.Time) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]time.Time, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(time.Time)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockLocker) List() *MockLocker_List_OngoingVerification {
 	params := []pegomock.Param{}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "List", params, verifier.timeout)
@@ -0,0 +1,133 @@
+package locking
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// QueuedClient is a Locker where pulls which fail to acquire a lock wait in line for it.
+// When a lock is released, it's handed to the first pull in line.
+type QueuedClient struct {
+	Locker
+	WaitList WaitList
+	Logger   logging.Logger
+
+	// OnHandoff is called once per pull which was handed locks it was waiting for,
+	// with all the locks it was handed.
+	OnHandoff func(locks []models.ProjectLock)
+}
+
+// TryLock attempts to acquire a lock to a project and workspace. If the lock is held
+// by another pull, the pull is added to the lock's wait list.
+func (c *QueuedClient) TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (TryLockResponse, error) {
+	resp, err := c.Locker.TryLock(p, workspace, pull, user)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.LockAcquired || resp.CurrLock.Pull.Num == pull.Num {
+		// the pull may have been waiting for the lock before it was released
+		return resp, c.WaitList.RemoveFromWaitList(p, workspace, pull.Num)
+	}
+
+	position, err := c.WaitList.EnqueueLock(models.ProjectLock{
+		Project:   p,
+		Workspace: workspace,
+		Pull:      pull,
+		User:      user,
+	})
+	if err != nil {
+		return resp, err
+	}
+	resp.QueuePosition = position
+	return resp, nil
+}
+
+// Unlock releases the lock stored at key and hands it to the first pull waiting for it.
+func (c *QueuedClient) Unlock(key string) (*models.ProjectLock, error) {
+	lock, err := c.Locker.Unlock(key)
+	if err != nil || lock == nil {
+		return lock, err
+	}
+
+	c.handoff([]models.ProjectLock{*lock})
+	return lock, nil
+}
+
+// UnlockExpired releases the lock stored at key if it expired before now and hands it to
+// the first pull waiting for it.
+func (c *QueuedClient) UnlockExpired(key string, now time.Time) (*models.ProjectLock, error) {
+	lock, err := c.Locker.UnlockExpired(key, now)
+	if err != nil || lock == nil {
+		return lock, err
+	}
+
+	c.handoff([]models.ProjectLock{*lock})
+	return lock, nil
+}
+
+// UnlockByPull releases all locks associated with that pull request and hands each of them
+// to the first pull waiting for it. The pull is also removed from the wait lists it's in.
+func (c *QueuedClient) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
+	locks, err := c.Locker.UnlockByPull(repoFullName, pullNum)
+	if err != nil {
+		return locks, err
+	}
+
+	if err := c.WaitList.RemovePullFromWaitLists(repoFullName, pullNum); err != nil {
+		return locks, err
+	}
+
+	c.handoff(locks)
+	return locks, nil
+}
+
+// handoff locks each released lock for the first pull waiting for it. Failures are logged
+// since the locks were released successfully.
+func (c *QueuedClient) handoff(released []models.ProjectLock) {
+	handedOff := make(map[string][]models.ProjectLock)
+	var pullKeys []string
+
+	for _, lock := range released {
+		next, err := c.WaitList.NextLock(lock.Project, lock.Workspace)
+		if err != nil {
+			c.Logger.Error(fmt.Sprintf("getting next pull waiting for %s/%s: %s", lock.Project.Path, lock.Workspace, err))
+			continue
+		}
+		if next == nil {
+			continue
+		}
+
+		resp, err := c.Locker.TryLock(next.Project, next.Workspace, next.Pull, next.User)
+		if err != nil {
+			c.Logger.Error(fmt.Sprintf("handing lock for %s/%s to pull %d: %s", next.Project.Path, next.Workspace, next.Pull.Num, err))
+			continue
+		}
+
+		// another pull took the lock before it could be handed off, so the pull keeps
+		// waiting at the front of the line
+		if !resp.LockAcquired && resp.CurrLock.Pull.Num != next.Pull.Num {
+			continue
+		}
+
+		if err := c.WaitList.RemoveFromWaitList(next.Project, next.Workspace, next.Pull.Num); err != nil {
+			c.Logger.Error(fmt.Sprintf("removing pull %d from wait list of %s/%s: %s", next.Pull.Num, next.Project.Path, next.Workspace, err))
+		}
+
+		pullKey := fmt.Sprintf("%s#%d", next.Pull.BaseRepo.FullName, next.Pull.Num)
+		if _, ok := handedOff[pullKey]; !ok {
+			pullKeys = append(pullKeys, pullKey)
+		}
+		handedOff[pullKey] = append(handedOff[pullKey], *next)
+	}
+
+	if c.OnHandoff == nil {
+		return
+	}
+	for _, pullKey := range pullKeys {
+		c.OnHandoff(handedOff[pullKey])
+	}
+}
@@ -0,0 +1,179 @@
+package locking_test
+
+import (
+	"testing"
+
+	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/core/locking/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// waitList is an in memory WaitList
+type waitList struct {
+	waiting map[string][]models.ProjectLock
+}
+
+func key(p models.Project, workspace string) string {
+	return p.RepoFullName + "/" + p.Path + "/" + workspace
+}
+
+func (w *waitList) EnqueueLock(lock models.ProjectLock) (int, error) {
+	k := key(lock.Project, lock.Workspace)
+	for i, l := range w.waiting[k] {
+		if l.Pull.Num == lock.Pull.Num {
+			return i + 1, nil
+		}
+	}
+	w.waiting[k] = append(w.waiting[k], lock)
+	return len(w.waiting[k]), nil
+}
+
+func (w *waitList) NextLock(p models.Project, workspace string) (*models.ProjectLock, error) {
+	k := key(p, workspace)
+	if len(w.waiting[k]) == 0 {
+		return nil, nil
+	}
+	next := w.waiting[k][0]
+	return &next, nil
+}
+
+func (w *waitList) RemoveFromWaitList(p models.Project, workspace string, pullNum int) error {
+	k := key(p, workspace)
+	var remaining []models.ProjectLock
+	for _, l := range w.waiting[k] {
+		if l.Pull.Num != pullNum {
+			remaining = append(remaining, l)
+		}
+	}
+	w.waiting[k] = remaining
+	return nil
+}
+
+func (w *waitList) RemovePullFromWaitLists(repoFullName string, pullNum int) error {
+	for k, waiting := range w.waiting {
+		var remaining []models.ProjectLock
+		for _, l := range waiting {
+			if l.Project.RepoFullName != repoFullName || l.Pull.Num != pullNum {
+				remaining = append(remaining, l)
+			}
+		}
+		w.waiting[k] = remaining
+	}
+	return nil
+}
+
+func newQueuedClient(t *testing.T) (*locking.QueuedClient, *mocks.MockLocker, *waitList, *[][]models.ProjectLock) {
+	RegisterMockTestingT(t)
+	locker := mocks.NewMockLocker()
+	wl := &waitList{waiting: make(map[string][]models.ProjectLock)}
+	var handedOff [][]models.ProjectLock
+	c := &locking.QueuedClient{
+		Locker:   locker,
+		WaitList: wl,
+		Logger:   logging.NewNoopCtxLogger(t),
+		OnHandoff: func(locks []models.ProjectLock) {
+			handedOff = append(handedOff, locks)
+		},
+	}
+	return c, locker, wl, &handedOff
+}
+
+func TestQueuedClient_TryLock(t *testing.T) {
+	c, locker, wl, _ := newQueuedClient(t)
+	holder := models.ProjectLock{Pull: models.PullRequest{Num: 1}}
+
+	for i, num := range []int{2, 3} {
+		waitingPull := models.PullRequest{Num: num}
+		When(locker.TryLock(project, workspace, waitingPull, user)).ThenReturn(locking.TryLockResponse{CurrLock: holder}, nil)
+
+		r, err := c.TryLock(project, workspace, waitingPull, user)
+		Ok(t, err)
+		Assert(t, !r.LockAcquired, "exp lock to not be acquired")
+		Equals(t, i+1, r.QueuePosition)
+	}
+
+	// trying again keeps the pull's position
+	r, err := c.TryLock(project, workspace, models.PullRequest{Num: 2}, user)
+	Ok(t, err)
+	Equals(t, 1, r.QueuePosition)
+
+	// a pull which acquires the lock stops waiting
+	When(locker.TryLock(project, workspace, models.PullRequest{Num: 2}, user)).ThenReturn(locking.TryLockResponse{LockAcquired: true}, nil)
+	r, err = c.TryLock(project, workspace, models.PullRequest{Num: 2}, user)
+	Ok(t, err)
+	Equals(t, 0, r.QueuePosition)
+	Equals(t, 1, len(wl.waiting[key(project, workspace)]))
+	Equals(t, 3, wl.waiting[key(project, workspace)][0].Pull.Num)
+}
+
+func TestQueuedClient_Unlock(t *testing.T) {
+	c, locker, wl, handedOff := newQueuedClient(t)
+	released := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 1}}
+	next := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 2}, User: user}
+	_, _ = wl.EnqueueLock(next)
+
+	When(locker.Unlock("owner/repo/path/workspace")).ThenReturn(&released, nil)
+	When(locker.TryLock(project, workspace, next.Pull, user)).ThenReturn(locking.TryLockResponse{LockAcquired: true}, nil)
+
+	l, err := c.Unlock("owner/repo/path/workspace")
+	Ok(t, err)
+	Equals(t, &released, l)
+
+	locker.VerifyWasCalledOnce().TryLock(project, workspace, next.Pull, user)
+	Equals(t, [][]models.ProjectLock{{next}}, *handedOff)
+	Equals(t, 0, len(wl.waiting[key(project, workspace)]))
+}
+
+func TestQueuedClient_UnlockLockTakenBeforeHandoff(t *testing.T) {
+	c, locker, wl, handedOff := newQueuedClient(t)
+	released := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 1}}
+	next := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 2}, User: user}
+	after := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 4}, User: user}
+	_, _ = wl.EnqueueLock(next)
+	_, _ = wl.EnqueueLock(after)
+
+	When(locker.Unlock("owner/repo/path/workspace")).ThenReturn(&released, nil)
+	When(locker.TryLock(project, workspace, next.Pull, user)).ThenReturn(locking.TryLockResponse{
+		CurrLock: models.ProjectLock{Pull: models.PullRequest{Num: 3}},
+	}, nil)
+
+	_, err := c.Unlock("owner/repo/path/workspace")
+	Ok(t, err)
+
+	// the pull keeps waiting at the front of the line
+	Equals(t, 0, len(*handedOff))
+	Equals(t, []models.ProjectLock{next, after}, wl.waiting[key(project, workspace)])
+}
+
+func TestQueuedClient_UnlockByPull(t *testing.T) {
+	c, locker, wl, handedOff := newQueuedClient(t)
+	otherProject := models.NewProject("owner/repo", "other")
+	released := []models.ProjectLock{
+		{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 1}},
+		{Project: otherProject, Workspace: workspace, Pull: models.PullRequest{Num: 1}},
+	}
+
+	// pull 2 waits for both projects, pull 1 was also waiting for another project
+	next := models.ProjectLock{Project: project, Workspace: workspace, Pull: models.PullRequest{Num: 2}}
+	nextOther := models.ProjectLock{Project: otherProject, Workspace: workspace, Pull: models.PullRequest{Num: 2}}
+	closedWaiting := models.ProjectLock{Project: models.NewProject("owner/repo", "third"), Workspace: workspace, Pull: models.PullRequest{Num: 1}}
+	for _, l := range []models.ProjectLock{next, nextOther, closedWaiting} {
+		_, _ = wl.EnqueueLock(l)
+	}
+
+	When(locker.UnlockByPull("owner/repo", 1)).ThenReturn(released, nil)
+	When(locker.TryLock(matchers.AnyModelsProject(), AnyString(), matchers.AnyModelsPullRequest(), matchers.AnyModelsUser())).ThenReturn(locking.TryLockResponse{LockAcquired: true}, nil)
+
+	locks, err := c.UnlockByPull("owner/repo", 1)
+	Ok(t, err)
+	Equals(t, released, locks)
+
+	locker.VerifyWasCalled(Times(2)).TryLock(matchers.AnyModelsProject(), AnyString(), matchers.AnyModelsPullRequest(), matchers.AnyModelsUser())
+	// the pull is only autoplanned once, with both locks it was handed
+	Equals(t, [][]models.ProjectLock{{next, nextOther}}, *handedOff)
+	Equals(t, 0, len(wl.waiting[key(closedWaiting.Project, workspace)]))
+}
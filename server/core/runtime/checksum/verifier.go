@@ -0,0 +1,259 @@
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	_ "embed" // embeds HashiCorp's release signing key
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // releases are still signed with gpg
+)
+
+// Release contains the locations of a release archive and the files used to verify it
+type Release struct {
+	ArchiveURL   string
+	ChecksumsURL string
+
+	// SignatureURL is the detached signature of the checksums file, empty if releases aren't signed
+	SignatureURL string
+}
+
+// Verifier resolves trusted sha256 checksums for release archives so they can be verified
+// before they're extracted and cached.
+//
+// If neither pinned checksums nor a key ring are configured, the release's checksums file is trusted as is.
+// Otherwise a release must either have a pinned checksum or a checksums file signed by the key ring.
+type Verifier struct {
+	// Pinned maps release archive file names to their expected sha256 checksum
+	Pinned  map[string]string
+	KeyRing openpgp.EntityList
+	GetFile func(dst, src string) error
+}
+
+// NewVerifier loads pinned checksums and an armored signing key from the given files, either can be empty
+func NewVerifier(checksumsFile string, signingKeyFile string, getFile func(dst, src string) error) (*Verifier, error) {
+	verifier := &Verifier{
+		GetFile: getFile,
+	}
+
+	if checksumsFile != "" {
+		b, err := os.ReadFile(checksumsFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading checksums file %s", checksumsFile)
+		}
+		verifier.Pinned, err = ParseChecksums(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing checksums file %s", checksumsFile)
+		}
+	}
+
+	if signingKeyFile != "" {
+		f, err := os.Open(signingKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening signing key %s", signingKeyFile)
+		}
+		defer f.Close()
+
+		verifier.KeyRing, err = openpgp.ReadArmoredKeyRing(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading signing key %s", signingKeyFile)
+		}
+	}
+
+	return verifier, nil
+}
+
+// DefaultKey is a vendor's release signing key which is trusted when no signing key is configured
+type DefaultKey struct {
+	// Armored is the armored key, the key isn't used if it's empty
+	Armored []byte
+
+	// Fingerprint pins the key so a different key isn't trusted
+	Fingerprint string
+}
+
+// KeyRing returns the key ring of the default key, it's nil if the key is empty
+func (k DefaultKey) KeyRing() (openpgp.EntityList, error) {
+	if len(bytes.TrimSpace(k.Armored)) == 0 {
+		return nil, nil
+	}
+
+	keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(k.Armored))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading default signing key %s", k.Fingerprint)
+	}
+
+	for _, entity := range keyRing {
+		if !strings.EqualFold(hex.EncodeToString(entity.PrimaryKey.Fingerprint[:]), k.Fingerprint) {
+			return nil, fmt.Errorf("default signing key contains a key which doesn't match fingerprint %s", k.Fingerprint)
+		}
+	}
+	return keyRing, nil
+}
+
+// HashiCorpKeyFingerprint pins HashiCorp's published release signing key, see https://www.hashicorp.com/security
+const HashiCorpKeyFingerprint = "C874011F0AB405110D02105534365D9472D7468F"
+
+//go:generate curl -sSfL -o hashicorp.asc https://www.hashicorp.com/.well-known/pgp-key.txt
+//go:embed hashicorp.asc
+var hashiCorpArmoredKey []byte
+
+// HashiCorpKey is HashiCorp's release signing key, it's embedded so terraform downloads are verified by default
+var HashiCorpKey = DefaultKey{
+	Armored:     hashiCorpArmoredKey,
+	Fingerprint: HashiCorpKeyFingerprint,
+}
+
+func init() {
+	// a key which doesn't match its pinned fingerprint must never be trusted, so it's caught at startup
+	if _, err := HashiCorpKey.KeyRing(); err != nil {
+		panic(err)
+	}
+}
+
+// NewVerifierWithDefaultKey behaves like NewVerifier but trusts the default key when no signing key
+// file is configured.
+func NewVerifierWithDefaultKey(checksumsFile string, signingKeyFile string, defaultKey DefaultKey, getFile func(dst, src string) error) (*Verifier, error) {
+	if signingKeyFile != "" {
+		return NewVerifier(checksumsFile, signingKeyFile, getFile)
+	}
+
+	verifier, err := NewVerifier(checksumsFile, "", getFile)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier.KeyRing, err = defaultKey.KeyRing()
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// Query returns the go-getter checksum query value used to verify the release archive on download
+func (v *Verifier) Query(release Release) (string, error) {
+	if !v.isStrict() {
+		return fmt.Sprintf("file:%s", release.ChecksumsURL), nil
+	}
+
+	sum, err := v.Checksum(release)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%s", sum), nil
+}
+
+// Checksum returns the trusted sha256 checksum of the release archive
+func (v *Verifier) Checksum(release Release) (string, error) {
+	archive, err := fileName(release.ArchiveURL)
+	if err != nil {
+		return "", err
+	}
+
+	if v != nil {
+		if sum, ok := v.Pinned[archive]; ok {
+			return sum, nil
+		}
+	}
+
+	if v.isStrict() && v.KeyRing == nil {
+		return "", fmt.Errorf("no pinned checksum found for %s", archive)
+	}
+
+	if v.isStrict() && release.SignatureURL == "" {
+		return "", fmt.Errorf("no pinned checksum found for %s and its release is unsigned", archive)
+	}
+
+	dir, err := os.MkdirTemp("", "checksums")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	checksums, err := v.download(dir, "checksums", release.ChecksumsURL)
+	if err != nil {
+		return "", err
+	}
+
+	if v.isStrict() {
+		// mirrors which only serve archives and checksums can't be verified with a signing key
+		signature, err := v.download(dir, "signature", release.SignatureURL)
+		if err != nil {
+			return "", errors.Wrapf(err, "no pinned checksum found for %s and its checksums signature %s is unavailable, serve the signature alongside %s or pin the archive's checksum with --binary-checksums-file", archive, release.SignatureURL, release.ChecksumsURL)
+		}
+
+		if _, err := openpgp.CheckDetachedSignature(v.KeyRing, bytes.NewReader(checksums), bytes.NewReader(signature)); err != nil {
+			return "", errors.Wrapf(err, "verifying signature of %s", release.ChecksumsURL)
+		}
+	}
+
+	sums, err := ParseChecksums(checksums)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", release.ChecksumsURL)
+	}
+
+	sum, ok := sums[archive]
+	if !ok {
+		return "", fmt.Errorf("%s does not contain a checksum for %s", release.ChecksumsURL, archive)
+	}
+	return sum, nil
+}
+
+func (v *Verifier) isStrict() bool {
+	return v != nil && (len(v.Pinned) > 0 || v.KeyRing != nil)
+}
+
+func (v *Verifier) download(dir string, name string, src string) ([]byte, error) {
+	dst := filepath.Join(dir, name)
+	if err := v.GetFile(dst, src); err != nil {
+		return nil, errors.Wrapf(err, "downloading %s", src)
+	}
+
+	b, err := os.ReadFile(dst)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", src)
+	}
+	return b, nil
+}
+
+// ParseChecksums parses the SHA256SUMS format, ie. lines of `<sha256>  <file name>`
+func ParseChecksums(b []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksum line %q", line)
+		}
+
+		sum := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid sha256 checksum %q", fields[0])
+		}
+
+		// binary mode entries prefix the file name with an asterisk
+		sums[strings.TrimPrefix(fields[1], "*")] = sum
+	}
+	return sums, scanner.Err()
+}
+
+func fileName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", rawURL)
+	}
+	return path.Base(u.Path), nil
+}
@@ -0,0 +1,250 @@
+package checksum_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"       //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
+)
+
+const (
+	archiveURL   = "https://releases.hashicorp.com/terraform/1.3.0/terraform_1.3.0_linux_amd64.zip"
+	checksumsURL = "https://releases.hashicorp.com/terraform/1.3.0/terraform_1.3.0_SHA256SUMS"
+	signatureURL = "https://releases.hashicorp.com/terraform/1.3.0/terraform_1.3.0_SHA256SUMS.sig"
+)
+
+var (
+	archiveSum = strings.Repeat("a", 64)
+	otherSum   = strings.Repeat("b", 64)
+	checksums  = fmt.Sprintf("%s  terraform_1.3.0_linux_amd64.zip\n%s  terraform_1.3.0_darwin_amd64.zip\n", archiveSum, otherSum)
+)
+
+type testGetter struct {
+	files map[string][]byte
+}
+
+func (g *testGetter) GetFile(dst, src string) error {
+	b, ok := g.files[src]
+	if !ok {
+		return fmt.Errorf("%s not found", src)
+	}
+	return os.WriteFile(dst, b, 0600)
+}
+
+func sign(t *testing.T, entity *openpgp.Entity, content string) []byte {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, openpgp.DetachSign(buf, entity, strings.NewReader(content), nil))
+	return buf.Bytes()
+}
+
+func newEntity(t *testing.T) *openpgp.Entity {
+	entity, err := openpgp.NewEntity("releases", "", "releases@example.com", nil)
+	assert.NoError(t, err)
+	return entity
+}
+
+var release = checksum.Release{
+	ArchiveURL:   archiveURL,
+	ChecksumsURL: checksumsURL,
+	SignatureURL: signatureURL,
+}
+
+func TestVerifier_Query(t *testing.T) {
+	signer := newEntity(t)
+	imposter := newEntity(t)
+
+	cases := []struct {
+		description   string
+		verifier      *checksum.Verifier
+		files         map[string][]byte
+		expectedQuery string
+		expectedErr   string
+	}{
+		{
+			description:   "unconfigured",
+			expectedQuery: "file:" + checksumsURL,
+		},
+		{
+			description: "pinned",
+			verifier: &checksum.Verifier{
+				Pinned: map[string]string{"terraform_1.3.0_linux_amd64.zip": otherSum},
+			},
+			expectedQuery: "sha256:" + otherSum,
+		},
+		{
+			description: "not pinned",
+			verifier: &checksum.Verifier{
+				Pinned: map[string]string{"terraform_1.2.0_linux_amd64.zip": otherSum},
+			},
+			expectedErr: "no pinned checksum found for terraform_1.3.0_linux_amd64.zip",
+		},
+		{
+			description: "signed",
+			verifier: &checksum.Verifier{
+				KeyRing: openpgp.EntityList{signer},
+			},
+			files: map[string][]byte{
+				checksumsURL: []byte(checksums),
+				signatureURL: sign(t, signer, checksums),
+			},
+			expectedQuery: "sha256:" + archiveSum,
+		},
+		{
+			description: "invalid signature",
+			verifier: &checksum.Verifier{
+				KeyRing: openpgp.EntityList{signer},
+			},
+			files: map[string][]byte{
+				checksumsURL: []byte(checksums),
+				signatureURL: sign(t, imposter, checksums),
+			},
+			expectedErr: "verifying signature of " + checksumsURL,
+		},
+		{
+			description: "tampered checksums",
+			verifier: &checksum.Verifier{
+				KeyRing: openpgp.EntityList{signer},
+			},
+			files: map[string][]byte{
+				checksumsURL: []byte(strings.Replace(checksums, archiveSum, otherSum, 1)),
+				signatureURL: sign(t, signer, checksums),
+			},
+			expectedErr: "verifying signature of " + checksumsURL,
+		},
+		{
+			description: "missing signature",
+			verifier: &checksum.Verifier{
+				KeyRing: openpgp.EntityList{signer},
+			},
+			files: map[string][]byte{
+				checksumsURL: []byte(checksums),
+			},
+			expectedErr: "its checksums signature " + signatureURL + " is unavailable, serve the signature alongside " + checksumsURL + " or pin the archive's checksum with --binary-checksums-file",
+		},
+		{
+			description: "missing archive",
+			verifier: &checksum.Verifier{
+				KeyRing: openpgp.EntityList{signer},
+			},
+			files: map[string][]byte{
+				checksumsURL: []byte(otherSum + "  terraform_1.3.0_darwin_amd64.zip\n"),
+				signatureURL: sign(t, signer, otherSum+"  terraform_1.3.0_darwin_amd64.zip\n"),
+			},
+			expectedErr: "does not contain a checksum for terraform_1.3.0_linux_amd64.zip",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if c.verifier != nil {
+				c.verifier.GetFile = (&testGetter{files: c.files}).GetFile
+			}
+
+			query, err := c.verifier.Query(release)
+			if c.expectedErr != "" {
+				assert.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedQuery, query)
+		})
+	}
+}
+
+func TestVerifier_UnsignedRelease(t *testing.T) {
+	verifier := &checksum.Verifier{
+		KeyRing: openpgp.EntityList{newEntity(t)},
+	}
+	_, err := verifier.Query(checksum.Release{
+		ArchiveURL:   archiveURL,
+		ChecksumsURL: checksumsURL,
+	})
+	assert.ErrorContains(t, err, "release is unsigned")
+}
+
+func armoredKey(t *testing.T, entity *openpgp.Entity) []byte {
+	buf := &bytes.Buffer{}
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func writeKey(t *testing.T, entity *openpgp.Entity, keyFile string) {
+	assert.NoError(t, os.WriteFile(keyFile, armoredKey(t, entity), 0600))
+}
+
+func TestNewVerifier(t *testing.T) {
+	signer := newEntity(t)
+	dir := t.TempDir()
+
+	keyFile := filepath.Join(dir, "key.asc")
+	writeKey(t, signer, keyFile)
+
+	checksumsFile := filepath.Join(dir, "checksums")
+	assert.NoError(t, os.WriteFile(checksumsFile, []byte("# pinned\n"+checksums), 0600))
+
+	getter := &testGetter{}
+	verifier, err := checksum.NewVerifier(checksumsFile, keyFile, getter.GetFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"terraform_1.3.0_linux_amd64.zip":  archiveSum,
+		"terraform_1.3.0_darwin_amd64.zip": otherSum,
+	}, verifier.Pinned)
+	assert.Len(t, verifier.KeyRing, 1)
+}
+
+func TestNewVerifierWithDefaultKey(t *testing.T) {
+	signer := newEntity(t)
+	dir := t.TempDir()
+	defaultKey := checksum.DefaultKey{
+		Armored:     armoredKey(t, signer),
+		Fingerprint: fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint),
+	}
+	getter := &testGetter{}
+
+	t.Run("uses default key", func(t *testing.T) {
+		verifier, err := checksum.NewVerifierWithDefaultKey("", "", defaultKey, getter.GetFile)
+		assert.NoError(t, err)
+		assert.Len(t, verifier.KeyRing, 1)
+	})
+
+	t.Run("configured key takes precedence", func(t *testing.T) {
+		configured := filepath.Join(dir, "configured.asc")
+		writeKey(t, newEntity(t), configured)
+
+		verifier, err := checksum.NewVerifierWithDefaultKey("", configured, checksum.DefaultKey{Armored: defaultKey.Armored, Fingerprint: "mismatch"}, getter.GetFile)
+		assert.NoError(t, err)
+		assert.Len(t, verifier.KeyRing, 1)
+	})
+
+	t.Run("empty default key", func(t *testing.T) {
+		verifier, err := checksum.NewVerifierWithDefaultKey("", "", checksum.DefaultKey{}, getter.GetFile)
+		assert.NoError(t, err)
+		assert.Nil(t, verifier.KeyRing)
+	})
+
+	t.Run("fingerprint mismatch", func(t *testing.T) {
+		_, err := checksum.NewVerifierWithDefaultKey("", "", checksum.DefaultKey{Armored: defaultKey.Armored, Fingerprint: "mismatch"}, getter.GetFile)
+		assert.ErrorContains(t, err, "doesn't match fingerprint")
+	})
+}
+
+func TestHashiCorpKey(t *testing.T) {
+	// the embedded key is checked against its fingerprint when the package is initialized
+	_, err := checksum.HashiCorpKey.KeyRing()
+	assert.NoError(t, err)
+}
+
+func TestParseChecksums_Invalid(t *testing.T) {
+	_, err := checksum.ParseChecksums([]byte("1234  terraform_1.3.0_linux_amd64.zip"))
+	assert.ErrorContains(t, err, "invalid sha256 checksum")
+}
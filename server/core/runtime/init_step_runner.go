@@ -11,10 +11,18 @@ import (
 	"github.com/runatlantis/atlantis/server/events/runtime/common"
 )
 
+// PluginCache guards inits which share a TF_PLUGIN_CACHE_DIR
+type PluginCache interface {
+	Init(rootPath string, installProviders func() error, init func() error) error
+}
+
 // InitStep runs `terraform init`.
 type InitStepRunner struct {
 	TerraformExecutor TerraformExec
 	DefaultTFVersion  *version.Version
+
+	// PluginCache is optional, inits run without coordinating with each other if it's nil
+	PluginCache PluginCache
 }
 
 func (i *InitStepRunner) Run(ctx context.Context, prjCtx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
@@ -57,7 +65,23 @@ func (i *InitStepRunner) Run(ctx context.Context, prjCtx command.ProjectContext,
 
 	terraformInitCmd := append(terraformInitVerb, finalArgs...)
 
-	out, err := i.TerraformExecutor.RunCommandWithVersion(ctx, prjCtx, path, terraformInitCmd, envs, tfVersion, prjCtx.Workspace)
+	var out string
+	runInit := func() error {
+		out, err = i.TerraformExecutor.RunCommandWithVersion(ctx, prjCtx, path, terraformInitCmd, envs, tfVersion, prjCtx.Workspace)
+		return err
+	}
+
+	// `terraform get` doesn't install providers so it doesn't use the cache
+	if i.PluginCache != nil && terraformInitVerb[0] == "init" {
+		installProvidersCmd := append(append([]string{}, terraformInitCmd...), "-backend=false")
+		installProviders := func() error {
+			out, err = i.TerraformExecutor.RunCommandWithVersion(ctx, prjCtx, path, installProvidersCmd, envs, tfVersion, prjCtx.Workspace)
+			return err
+		}
+		err = i.PluginCache.Init(path, installProviders, runInit)
+	} else {
+		err = runInit()
+	}
 	// Only include the init output if there was an error. Otherwise it's
 	// unnecessary and lengthens the comment.
 	if err != nil {
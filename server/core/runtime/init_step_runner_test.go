@@ -106,6 +106,41 @@ func TestRun_ShowInitOutputOnError(t *testing.T) {
 	Equals(t, "output", output)
 }
 
+type testPluginCache struct {
+	rootPath string
+}
+
+func (c *testPluginCache) Init(rootPath string, installProviders func() error, init func() error) error {
+	c.rootPath = rootPath
+	return init()
+}
+
+func TestRun_InitUsesPluginCache(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfClient := mocks.NewMockClient()
+	logger := logging.NewNoopCtxLogger(t)
+	When(tfClient.RunCommandWithVersion(matchers.AnyContextContext(), matchers.AnyModelsProjectCommandContext(), AnyString(), AnyStringSlice(), matchers2.AnyMapOfStringToString(), matchers2.AnyPtrToGoVersionVersion(), AnyString())).
+		ThenReturn("output", errors.New("error"))
+
+	tfVersion, _ := version.NewVersion("0.14.0")
+	pluginCache := &testPluginCache{}
+	iso := runtime.InitStepRunner{
+		TerraformExecutor: tfClient,
+		DefaultTFVersion:  tfVersion,
+		PluginCache:       pluginCache,
+	}
+
+	output, err := iso.Run(context.Background(), command.ProjectContext{
+		Workspace:  "workspace",
+		RepoRelDir: ".",
+		Log:        logger,
+		RequestCtx: context.TODO(),
+	}, nil, "/path", map[string]string(nil))
+	ErrEquals(t, "error", err)
+	Equals(t, "output", output)
+	Equals(t, "/path", pluginCache.rootPath)
+}
+
 func TestRun_InitKeepsUpgradeFlagIfLockFileNotPresent(t *testing.T) {
 	tmpDir, cleanup := TempDir(t)
 	defer cleanup()
@@ -0,0 +1,319 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+)
+
+// Mirror holds pre-seeded release archives so versions can be loaded without outbound network access.
+// Each archive is stored along with the checksums file and signature of its release, keyed by
+// <binary name>/<version>/<file name>.
+//
+// Anyone with write access to a mirror can change its contents, so mirrored archives are verified with
+// the same checksum.Verifier as downloaded ones before they're extracted.
+type Mirror interface {
+	// Fetch copies the mirrored file at key to destPath, ok is false if the file isn't mirrored
+	Fetch(key string, destPath string) (ok bool, err error)
+
+	// Put copies the file at srcPath into the mirror at key
+	Put(key string, srcPath string) error
+}
+
+// Source locates the release of a version and loads it once its archive is verified
+type Source interface {
+	Release(v *version.Version) checksum.Release
+
+	// LoadRelease verifies the release's archive and extracts its binary into destPath
+	LoadRelease(release checksum.Release, v *version.Version, destPath string) (models.FilePath, error)
+}
+
+// Key returns the location of a release file within a mirror
+func Key(binaryName string, v *version.Version, fileURL string) string {
+	return path.Join(binaryName, v.String(), fileName(fileURL))
+}
+
+// New returns a directory mirror if dir is set, otherwise a storage backed mirror if storeCfg is set,
+// nil is returned if neither are configured.
+func New(dir string, storeCfg *valid.StoreConfig) (Mirror, error) {
+	if dir != "" {
+		return &Dir{Path: dir}, nil
+	}
+
+	if storeCfg != nil {
+		client, err := storage.NewClient(*storeCfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing stow client")
+		}
+		return &Store{Client: client}, nil
+	}
+
+	return nil, nil
+}
+
+// Dir is a mirror on the local filesystem, ie. baked into an image or mounted from a shared volume
+type Dir struct {
+	Path string
+}
+
+func (d *Dir) Fetch(key string, destPath string) (bool, error) {
+	src, err := os.Open(filepath.Join(d.Path, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "opening mirrored %s", key)
+	}
+	defer src.Close()
+
+	if err := writeFile(src, destPath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (d *Dir) Put(key string, srcPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", srcPath)
+	}
+	defer src.Close()
+
+	return writeFile(src, filepath.Join(d.Path, filepath.FromSlash(key)))
+}
+
+type objectStore interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Set(ctx context.Context, key string, object []byte) error
+}
+
+// Store is a mirror in the configured storage backend
+type Store struct {
+	Client objectStore
+}
+
+func (s *Store) Fetch(key string, destPath string) (bool, error) {
+	reader, err := s.Client.Get(context.Background(), key)
+	if err != nil {
+		var notFoundErr *storage.ItemNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting mirrored %s", key)
+	}
+	defer reader.Close()
+
+	if err := writeFile(reader, destPath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) Put(key string, srcPath string) error {
+	b, err := os.ReadFile(srcPath)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", srcPath)
+	}
+
+	if err := s.Client.Set(context.Background(), key, b); err != nil {
+		return errors.Wrapf(err, "writing mirrored %s", key)
+	}
+	return nil
+}
+
+// Loader loads versions from the mirror before falling back to downloading them
+type Loader struct {
+	Mirror     Mirror
+	BinaryName string
+	Source     Source
+}
+
+// NewLoader returns a load function which checks the mirror first, the source's releases are downloaded as is if m is nil
+func NewLoader(m Mirror, binaryName string, source Source) func(v *version.Version, destPath string) (models.FilePath, error) {
+	loader := &Loader{
+		Mirror:     m,
+		BinaryName: binaryName,
+		Source:     source,
+	}
+	return loader.LoadVersion
+}
+
+func (l *Loader) LoadVersion(v *version.Version, destPath string) (models.FilePath, error) {
+	release := l.Source.Release(v)
+	if l.Mirror == nil {
+		return l.Source.LoadRelease(release, v, destPath)
+	}
+
+	dir, err := os.MkdirTemp("", fmt.Sprintf("%s-mirror", l.BinaryName))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	localRelease, ok, err := fetchRelease(l.Mirror, l.BinaryName, v, release, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s %s from mirror", l.BinaryName, v.String())
+	}
+
+	if !ok {
+		return l.Source.LoadRelease(release, v, destPath)
+	}
+
+	binPath, err := l.Source.LoadRelease(localRelease, v, destPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading mirrored %s %s", l.BinaryName, v.String())
+	}
+	return binPath, nil
+}
+
+// fetchRelease copies the mirrored files of release into dir and returns a release which points at them,
+// ok is false if the release's archive isn't mirrored.
+func fetchRelease(m Mirror, binaryName string, v *version.Version, release checksum.Release, dir string) (checksum.Release, bool, error) {
+	local := checksum.Release{
+		ArchiveURL:   filepath.Join(dir, fileName(release.ArchiveURL)),
+		ChecksumsURL: filepath.Join(dir, fileName(release.ChecksumsURL)),
+	}
+	if release.SignatureURL != "" {
+		local.SignatureURL = filepath.Join(dir, fileName(release.SignatureURL))
+	}
+
+	ok, err := m.Fetch(Key(binaryName, v, release.ArchiveURL), local.ArchiveURL)
+	if err != nil || !ok {
+		return local, false, err
+	}
+
+	// the checksums file and signature aren't needed if the archive's checksum is pinned, their absence
+	// is left to the verifier
+	if _, err := m.Fetch(Key(binaryName, v, release.ChecksumsURL), local.ChecksumsURL); err != nil {
+		return local, false, err
+	}
+	if local.SignatureURL != "" {
+		if _, err := m.Fetch(Key(binaryName, v, release.SignatureURL), local.SignatureURL); err != nil {
+			return local, false, err
+		}
+	}
+	return local, true, nil
+}
+
+// Populate downloads each version's release files using getFile, a go-getter file download, and copies them into the mirror once the
+// release is verified, versions which are already mirrored are skipped.
+func Populate(m Mirror, binaryName string, versions []*version.Version, source Source, getFile func(dst, src string) error) error {
+	for _, v := range versions {
+		if err := populate(m, binaryName, v, source, getFile); err != nil {
+			return errors.Wrapf(err, "populating %s %s", binaryName, v.String())
+		}
+	}
+	return nil
+}
+
+func populate(m Mirror, binaryName string, v *version.Version, source Source, getFile func(dst, src string) error) error {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("%s-mirror", binaryName))
+	if err != nil {
+		return errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	release := source.Release(v)
+	if ok, err := m.Fetch(Key(binaryName, v, release.ArchiveURL), filepath.Join(dir, "mirrored")); err != nil || ok {
+		return err
+	}
+
+	local := checksum.Release{
+		ArchiveURL:   filepath.Join(dir, fileName(release.ArchiveURL)),
+		ChecksumsURL: filepath.Join(dir, fileName(release.ChecksumsURL)),
+	}
+	if err := getFile(local.ChecksumsURL, release.ChecksumsURL); err != nil {
+		return errors.Wrapf(err, "downloading %s", release.ChecksumsURL)
+	}
+	// go-getter extracts archives unless told otherwise, the archive itself is mirrored so it can be verified
+	if err := getFile(local.ArchiveURL, withQuery(release.ArchiveURL, "archive=false")); err != nil {
+		return errors.Wrapf(err, "downloading %s", release.ArchiveURL)
+	}
+
+	// signatures aren't needed if the archive's checksum is pinned, so mirrors which don't serve them are
+	// supported and the verifier fails if it's missing but required
+	mirrorSignature := false
+	if release.SignatureURL != "" {
+		local.SignatureURL = filepath.Join(dir, fileName(release.SignatureURL))
+		mirrorSignature = getFile(local.SignatureURL, release.SignatureURL) == nil
+	}
+
+	// releases are only mirrored if they'd load
+	if _, err := source.LoadRelease(local, v, filepath.Join(dir, "verify")); err != nil {
+		return errors.Wrap(err, "verifying release")
+	}
+
+	// the archive is put last, until then the release isn't considered mirrored
+	urls := []string{release.ChecksumsURL}
+	if mirrorSignature {
+		urls = append(urls, release.SignatureURL)
+	}
+	urls = append(urls, release.ArchiveURL)
+
+	for _, u := range urls {
+		if err := m.Put(Key(binaryName, v, u), filepath.Join(dir, fileName(u))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile writes to a temp file which is renamed to dstPath so a partially written file is never observed
+func writeFile(src io.Reader, dstPath string) error {
+	dir := filepath.Dir(dstPath)
+	if err := os.MkdirAll(dir, 0755); err != nil { // nolint: gosec
+		return errors.Wrapf(err, "creating %s", dir)
+	}
+
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf(".%s-*", filepath.Base(dstPath)))
+	if err != nil {
+		return errors.Wrapf(err, "creating temp file for %s", dstPath)
+	}
+	// a no-op once the temp file has been renamed
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "writing %s", dstPath)
+	}
+
+	// mirrored files can be shared between users
+	if err := os.Chmod(tmp.Name(), 0644); err != nil { // nolint: gosec
+		return errors.Wrapf(err, "setting permissions of %s", dstPath)
+	}
+
+	if err := os.Rename(tmp.Name(), dstPath); err != nil {
+		return errors.Wrapf(err, "renaming temp file to %s", dstPath)
+	}
+	return nil
+}
+
+// fileName returns the last path element of a url or local path
+func fileName(fileURL string) string {
+	if u, err := url.Parse(fileURL); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+	return path.Base(fileURL)
+}
+
+func withQuery(fileURL string, query string) string {
+	if strings.Contains(fileURL, "?") {
+		return fileURL + "&" + query
+	}
+	return fileURL + "?" + query
+}
@@ -0,0 +1,257 @@
+package mirror_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
+	"github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStore struct {
+	objects map[string][]byte
+}
+
+func (s *testStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := s.objects[key]
+	if !ok {
+		return nil, &storage.ItemNotFoundError{Err: errors.New("not found")}
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *testStore) Set(ctx context.Context, key string, object []byte) error {
+	s.objects[key] = object
+	return nil
+}
+
+// copyFile downloads local files like go-getter, ignoring its query params
+func copyFile(dst, src string) error {
+	b, err := os.ReadFile(strings.Split(src, "?")[0])
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, 0600)
+}
+
+// testSource serves releases from a local directory and "extracts" archives by copying them as the binary
+type testSource struct {
+	dir      string
+	verifier *checksum.Verifier
+	loaded   []checksum.Release
+}
+
+func newTestSource(t *testing.T, pinned map[string]string) *testSource {
+	return &testSource{
+		dir: t.TempDir(),
+		verifier: &checksum.Verifier{
+			Pinned:  pinned,
+			GetFile: copyFile,
+		},
+	}
+}
+
+func (s *testSource) Release(v *version.Version) checksum.Release {
+	prefix := filepath.Join(s.dir, fmt.Sprintf("terraform_%s", v.String()))
+	return checksum.Release{
+		ArchiveURL:   prefix + "_linux_amd64.zip",
+		ChecksumsURL: prefix + "_SHA256SUMS",
+	}
+}
+
+func (s *testSource) LoadRelease(release checksum.Release, v *version.Version, destPath string) (models.FilePath, error) {
+	s.loaded = append(s.loaded, release)
+
+	sum, err := s.verifier.Checksum(release)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(release.ArchiveURL)
+	if err != nil {
+		return nil, err
+	}
+	if sha256Sum(b) != sum {
+		return nil, errors.New("checksum mismatch")
+	}
+
+	if err := os.MkdirAll(destPath, 0700); err != nil {
+		return nil, err
+	}
+	binPath := filepath.Join(destPath, "terraform")
+	return models.LocalFilePath(binPath), os.WriteFile(binPath, b, 0600)
+}
+
+// publish writes a release of v with the given archive contents into the source
+func (s *testSource) publish(t *testing.T, v *version.Version, archive string) {
+	release := s.Release(v)
+	require.NoError(t, os.WriteFile(release.ArchiveURL, []byte(archive), 0600))
+	checksums := fmt.Sprintf("%s  %s\n", sha256Sum([]byte(archive)), filepath.Base(release.ArchiveURL))
+	require.NoError(t, os.WriteFile(release.ChecksumsURL, []byte(checksums), 0600))
+}
+
+func sha256Sum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMirrors(t *testing.T) {
+	mirrors := map[string]mirror.Mirror{
+		"dir":   &mirror.Dir{Path: t.TempDir()},
+		"store": &mirror.Store{Client: &testStore{objects: map[string][]byte{}}},
+	}
+
+	src := filepath.Join(t.TempDir(), "archive.zip")
+	require.NoError(t, os.WriteFile(src, []byte("archive"), 0600))
+
+	for name, m := range mirrors {
+		t.Run(name, func(t *testing.T) {
+			destPath := filepath.Join(t.TempDir(), "archive.zip")
+			ok, err := m.Fetch("terraform/1.2.0/archive.zip", destPath)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, m.Put("terraform/1.2.0/archive.zip", src))
+
+			ok, err = m.Fetch("terraform/1.2.0/archive.zip", destPath)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			b, err := os.ReadFile(destPath)
+			require.NoError(t, err)
+			assert.Equal(t, "archive", string(b))
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	v, err := version.NewVersion("v1.2.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "terraform/1.2.0/terraform_1.2.0_linux_amd64.zip", mirror.Key("terraform", v, "https://releases.hashicorp.com/terraform/1.2.0/terraform_1.2.0_linux_amd64.zip"))
+	assert.Equal(t, "terraform/1.2.0/terraform_1.2.0_SHA256SUMS", mirror.Key("terraform", v, "/tmp/terraform_1.2.0_SHA256SUMS"))
+}
+
+func TestLoader(t *testing.T) {
+	v, err := version.NewVersion("1.2.0")
+	require.NoError(t, err)
+
+	t.Run("not mirrored", func(t *testing.T) {
+		source := newTestSource(t, nil)
+		source.publish(t, v, "downloaded")
+
+		load := mirror.NewLoader(&mirror.Dir{Path: t.TempDir()}, "terraform", source)
+		binPath, err := load(v, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, []checksum.Release{source.Release(v)}, source.loaded)
+
+		b, err := os.ReadFile(binPath.Resolve())
+		require.NoError(t, err)
+		assert.Equal(t, "downloaded", string(b))
+	})
+
+	t.Run("no mirror", func(t *testing.T) {
+		source := newTestSource(t, nil)
+		source.publish(t, v, "downloaded")
+
+		_, err := mirror.NewLoader(nil, "terraform", source)(v, t.TempDir())
+		require.NoError(t, err)
+		assert.Equal(t, []checksum.Release{source.Release(v)}, source.loaded)
+	})
+
+	t.Run("mirrored", func(t *testing.T) {
+		mirrored := newTestSource(t, nil)
+		mirrored.publish(t, v, "mirrored")
+		m := &mirror.Dir{Path: t.TempDir()}
+		require.NoError(t, mirror.Populate(m, "terraform", []*version.Version{v}, mirrored, copyFile))
+
+		// the release isn't downloaded from the source
+		source := newTestSource(t, map[string]string{
+			"terraform_1.2.0_linux_amd64.zip": sha256Sum([]byte("mirrored")),
+		})
+		binPath, err := mirror.NewLoader(m, "terraform", source)(v, t.TempDir())
+		require.NoError(t, err)
+		require.Len(t, source.loaded, 1)
+		assert.NotEqual(t, source.Release(v), source.loaded[0])
+
+		b, err := os.ReadFile(binPath.Resolve())
+		require.NoError(t, err)
+		assert.Equal(t, "mirrored", string(b))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		// the mirrored checksums file matches the planted archive, but the archive isn't pinned
+		planted := newTestSource(t, nil)
+		planted.publish(t, v, "planted")
+		m := &mirror.Dir{Path: t.TempDir()}
+		require.NoError(t, mirror.Populate(m, "terraform", []*version.Version{v}, planted, copyFile))
+
+		source := newTestSource(t, map[string]string{
+			"terraform_1.2.0_linux_amd64.zip": sha256Sum([]byte("release")),
+		})
+		destPath := t.TempDir()
+		_, err := mirror.NewLoader(m, "terraform", source)(v, destPath)
+		assert.ErrorContains(t, err, "checksum mismatch")
+
+		_, err = os.Stat(filepath.Join(destPath, "terraform"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestPopulate(t *testing.T) {
+	v1, err := version.NewVersion("1.2.0")
+	require.NoError(t, err)
+	v2, err := version.NewVersion("1.3.0")
+	require.NoError(t, err)
+
+	source := newTestSource(t, nil)
+	source.publish(t, v1, "v1")
+	source.publish(t, v2, "v2")
+
+	dir := t.TempDir()
+	m := &mirror.Dir{Path: dir}
+	require.NoError(t, mirror.Populate(m, "terraform", []*version.Version{v1}, source, copyFile))
+
+	source.loaded = nil
+	require.NoError(t, mirror.Populate(m, "terraform", []*version.Version{v1, v2}, source, copyFile))
+	require.Len(t, source.loaded, 1, "mirrored versions aren't downloaded again")
+
+	entries, err := os.ReadDir(filepath.Join(dir, "terraform", "1.3.0"))
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"terraform_1.3.0_SHA256SUMS", "terraform_1.3.0_linux_amd64.zip"}, names)
+}
+
+func TestPopulate_VerifiesRelease(t *testing.T) {
+	v, err := version.NewVersion("1.2.0")
+	require.NoError(t, err)
+
+	source := newTestSource(t, map[string]string{
+		"terraform_1.2.0_linux_amd64.zip": sha256Sum([]byte("release")),
+	})
+	source.publish(t, v, "tampered")
+
+	dir := t.TempDir()
+	err = mirror.Populate(&mirror.Dir{Path: dir}, "terraform", []*version.Version{v}, source, copyFile)
+	assert.ErrorContains(t, err, "verifying release")
+
+	_, err = os.Stat(filepath.Join(dir, "terraform"))
+	assert.True(t, os.IsNotExist(err))
+}
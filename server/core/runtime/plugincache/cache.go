@@ -0,0 +1,500 @@
+package plugincache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// providerDirDepth is the depth of a provider's platform dir within the cache,
+// ie. registry.terraform.io/hashicorp/aws/4.0.0/linux_amd64
+const providerDirDepth = 5
+
+// minEvictionAge protects recently used providers from eviction since initialized roots
+// link to the cache and can still be planned or applied.
+const minEvictionAge = 24 * time.Hour
+
+// Cache manages a TF_PLUGIN_CACHE_DIR which is shared by every init on a host.
+//
+// Terraform doesn't support concurrent writes to the cache so inits which may install providers
+// hold an exclusive lock, while inits whose providers are all cached share the lock. The lock is a
+// file lock so it's also respected by other processes sharing the dir.
+type Cache struct {
+	Dir string
+
+	// MaxSize is the size in bytes the cache is evicted down to, eviction is disabled if it's 0.
+	// The least recently used providers are evicted first.
+	MaxSize int64
+
+	// RootsDir records the lock files of initialized roots which are used to warm the cache,
+	// lock files aren't recorded if it's empty.
+	RootsDir string
+
+	Logger logging.Logger
+
+	evicting int32
+}
+
+// New creates the cache's directories
+func New(dir string, maxSize int64, rootsDir string, logger logging.Logger) (*Cache, error) {
+	for _, d := range []string{dir, rootsDir} {
+		if d == "" {
+			continue
+		}
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return nil, errors.Wrapf(err, "creating %s", d)
+		}
+	}
+
+	return &Cache{
+		Dir:      dir,
+		MaxSize:  maxSize,
+		RootsDir: rootsDir,
+		Logger:   logger,
+	}, nil
+}
+
+// Init runs init for the root at rootPath. Providers which aren't cached yet are installed by
+// installProviders, which is expected to run init without a backend, while holding the cache's
+// exclusive lock. The full init then runs without the lock since its providers are cached and
+// marked as used, which protects them from eviction.
+func (c *Cache) Init(rootPath string, installProviders func() error, init func() error) error {
+	lockFilePath := filepath.Join(rootPath, LockFileName)
+
+	cached, err := c.markCached(lockFilePath, false)
+	if err != nil {
+		return err
+	}
+	if !cached {
+		if err := c.install(lockFilePath, installProviders); err != nil {
+			return err
+		}
+	}
+
+	if err := init(); err != nil {
+		return err
+	}
+
+	c.linked(rootPath)
+	c.used(lockFilePath)
+	return nil
+}
+
+// install runs installProviders while holding the exclusive lock unless another init
+// cached the providers in the meantime
+func (c *Cache) install(lockFilePath string, installProviders func() error) error {
+	unlock, err := c.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cached, err := c.markCached(lockFilePath, true)
+	if err != nil || cached {
+		return err
+	}
+
+	if err := installProviders(); err != nil {
+		return err
+	}
+
+	// the lock file is written by the install if the root didn't have one
+	_, err = c.markCached(lockFilePath, true)
+	return err
+}
+
+// markCached marks the providers of the lock file as used if they're all cached, it takes
+// the shared lock unless the caller holds the lock already. An invalid lock file is left
+// to init to report.
+func (c *Cache) markCached(lockFilePath string, locked bool) (bool, error) {
+	if !locked {
+		unlock, err := c.lock(false)
+		if err != nil {
+			return false, err
+		}
+		defer unlock()
+	}
+
+	providers, err := ParseLockFile(lockFilePath)
+	if err != nil || !c.has(providers) {
+		return false, nil
+	}
+
+	c.touch(providers)
+	return true, nil
+}
+
+// WarmUp installs the providers of each recorded lock file which aren't cached yet,
+// run is expected to run init without a backend in the given dir.
+func (c *Cache) WarmUp(run func(dir string) error) error {
+	if c.RootsDir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.RootsDir)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", c.RootsDir)
+	}
+
+	var failures []string
+	for _, e := range entries {
+		if err := c.warm(filepath.Join(c.RootsDir, e.Name()), run); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("warming %d of %d lock files failed: %s", len(failures), len(entries), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (c *Cache) warm(lockFilePath string, run func(dir string) error) error {
+	src, err := os.ReadFile(lockFilePath)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", lockFilePath)
+	}
+
+	providers, err := parseLockFile(lockFilePath, src)
+	if err != nil {
+		return err
+	}
+	if len(providers) == 0 || c.has(providers) {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "plugin-cache-warm-up")
+	if err != nil {
+		return errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, LockFileName), src, 0600); err != nil {
+		return errors.Wrap(err, "writing lock file")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "providers.tf"), []byte(requiredProviders(providers)), 0600); err != nil {
+		return errors.Wrap(err, "writing configuration")
+	}
+
+	return c.install(filepath.Join(dir, LockFileName), func() error {
+		return run(dir)
+	})
+}
+
+// Evict removes the least recently used providers until the cache is within MaxSize
+func (c *Cache) Evict() error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	unlock, err := c.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, size, err := c.providerDirs()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	linked := c.linkedProviders()
+	evicted := map[string]bool{}
+	for _, e := range entries {
+		if size <= c.MaxSize || time.Since(e.modTime) < minEvictionAge {
+			break
+		}
+
+		// initialized roots link to the cache, removing their providers would break them
+		if linked[realPath(e.path)] {
+			continue
+		}
+
+		if err := os.RemoveAll(e.path); err != nil {
+			return errors.Wrapf(err, "removing %s", e.path)
+		}
+		evicted[e.path] = true
+		size -= e.size
+	}
+
+	c.forget(evicted)
+	return nil
+}
+
+// forget removes the recorded lock files which select an evicted provider so they aren't
+// installed again by the next warm up, roots which still use them are recorded again by their next init.
+func (c *Cache) forget(evicted map[string]bool) {
+	if c.RootsDir == "" || len(evicted) == 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(c.RootsDir)
+	if err != nil {
+		c.warn("reading recorded lock files", err)
+		return
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(c.RootsDir, e.Name())
+		providers, err := ParseLockFile(path)
+		if err != nil {
+			c.warn("parsing recorded lock file", err)
+			continue
+		}
+
+		for _, p := range providers {
+			if !evicted[c.providerDir(p)] {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				c.warn("removing recorded lock file", err)
+			}
+			break
+		}
+	}
+}
+
+// linksDir records the paths of initialized roots
+func (c *Cache) linksDir() string {
+	return filepath.Join(c.Dir, ".roots")
+}
+
+// linked records that the root at rootPath links to the cache's providers
+func (c *Cache) linked(rootPath string) {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		c.warn("recording root", err)
+		return
+	}
+
+	if err := os.MkdirAll(c.linksDir(), 0700); err != nil {
+		c.warn("recording root", err)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(abs))
+	if err := os.WriteFile(filepath.Join(c.linksDir(), hex.EncodeToString(sum[:])), []byte(abs), 0600); err != nil {
+		c.warn("recording root", err)
+	}
+}
+
+// linkedProviders returns the provider dirs which initialized roots link to, roots which
+// have been deleted since are forgotten.
+func (c *Cache) linkedProviders() map[string]bool {
+	linked := map[string]bool{}
+
+	entries, err := os.ReadDir(c.linksDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.warn("reading recorded roots", err)
+		}
+		return linked
+	}
+
+	for _, e := range entries {
+		record := filepath.Join(c.linksDir(), e.Name())
+		rootPath, err := os.ReadFile(record)
+		if err != nil {
+			c.warn("reading recorded root", err)
+			continue
+		}
+
+		providersDir := filepath.Join(string(rootPath), ".terraform", "providers")
+		if _, err := os.Stat(providersDir); os.IsNotExist(err) {
+			if err := os.Remove(record); err != nil {
+				c.warn("removing recorded root", err)
+			}
+			continue
+		}
+
+		err = walkProviderDirs(providersDir, func(path string, _ fs.DirEntry) error {
+			// terraform symlinks the providers it finds in the cache
+			if target, err := filepath.EvalSymlinks(path); err == nil {
+				linked[target] = true
+			}
+			return nil
+		})
+		if err != nil {
+			c.warn("reading linked providers", err)
+		}
+	}
+	return linked
+}
+
+// realPath resolves symlinks in path so it can be compared with the targets of provider links
+func realPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+type providerDir struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// providerDirs returns the platform dir of each cached provider along with the cache's total size
+func (c *Cache) providerDirs() ([]providerDir, int64, error) {
+	var dirs []providerDir
+	var total int64
+	err := walkProviderDirs(c.Dir, func(path string, d fs.DirEntry) error {
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			return err
+		}
+
+		dirs = append(dirs, providerDir{
+			path:    path,
+			size:    size,
+			modTime: info.ModTime(),
+		})
+		total += size
+		return nil
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "walking %s", c.Dir)
+	}
+	return dirs, total, nil
+}
+
+// walkProviderDirs calls fn with each provider platform dir within dir, which is laid out like
+// the plugin cache. Platform dirs may be symlinks as they are in a root's .terraform/providers.
+func walkProviderDirs(dir string, fn func(path string, d fs.DirEntry) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		// the cache keeps its own records in hidden dirs
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if len(strings.Split(rel, string(filepath.Separator))) != providerDirDepth {
+			return nil
+		}
+		if err := fn(path, d); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+func (c *Cache) providerDir(p Provider) string {
+	return filepath.Join(c.Dir, filepath.FromSlash(p.Source), p.Version, fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH))
+}
+
+// has returns true if every provider is cached for the current platform
+func (c *Cache) has(providers []Provider) bool {
+	if len(providers) == 0 {
+		return false
+	}
+
+	for _, p := range providers {
+		if _, err := os.Stat(c.providerDir(p)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// used marks the root's providers as recently used and records its lock file,
+// failures are logged since the init itself succeeded.
+func (c *Cache) used(lockFilePath string) {
+	src, err := os.ReadFile(lockFilePath)
+	if err != nil {
+		// roots which don't use any providers don't have a lock file
+		if !os.IsNotExist(err) {
+			c.warn("reading lock file", err)
+		}
+		return
+	}
+
+	providers, err := parseLockFile(lockFilePath, src)
+	if err != nil {
+		c.warn("parsing lock file", err)
+		return
+	}
+
+	c.touch(providers)
+
+	if c.RootsDir != "" && len(providers) > 0 {
+		// lock files are content addressed so roots with the same providers are only recorded once
+		sum := sha256.Sum256(src)
+		if err := os.WriteFile(filepath.Join(c.RootsDir, hex.EncodeToString(sum[:])+".hcl"), src, 0600); err != nil {
+			c.warn("recording lock file", err)
+		}
+	}
+
+	if c.MaxSize > 0 && atomic.CompareAndSwapInt32(&c.evicting, 0, 1) {
+		go func() {
+			defer atomic.StoreInt32(&c.evicting, 0)
+			if err := c.Evict(); err != nil {
+				c.warn("evicting providers", err)
+			}
+		}()
+	}
+}
+
+// touch marks the providers as recently used so they aren't evicted
+func (c *Cache) touch(providers []Provider) {
+	now := time.Now()
+	for _, p := range providers {
+		if err := os.Chtimes(c.providerDir(p), now, now); err != nil && !os.IsNotExist(err) {
+			c.warn("marking provider as used", err)
+		}
+	}
+}
+
+func (c *Cache) warn(msg string, err error) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.Warn(fmt.Sprintf("plugin cache: %s: %s", msg, err))
+}
@@ -0,0 +1,223 @@
+package plugincache_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/runtime/plugincache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lockFile = `
+# This file is maintained automatically by "terraform init".
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "4.0.0"
+  constraints = "~> 4.0"
+  hashes = [
+    "h1:abc",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/null" {
+  version = "3.1.0"
+}
+`
+
+var platform = fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+
+func writeRoot(t *testing.T) string {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, plugincache.LockFileName), []byte(lockFile), 0600))
+	return root
+}
+
+// installProvider writes a provider into the cache like terraform init would
+func installProvider(t *testing.T, dir string, source string, version string, size int, modTime time.Time) string {
+	providerDir := filepath.Join(dir, filepath.FromSlash(source), version, platform)
+	require.NoError(t, os.MkdirAll(providerDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(providerDir, "terraform-provider"), make([]byte, size), 0600))
+	require.NoError(t, os.Chtimes(providerDir, modTime, modTime))
+	return providerDir
+}
+
+func TestParseLockFile(t *testing.T) {
+	providers, err := plugincache.ParseLockFile(filepath.Join(writeRoot(t), plugincache.LockFileName))
+	require.NoError(t, err)
+	assert.Equal(t, []plugincache.Provider{
+		{Source: "registry.terraform.io/hashicorp/aws", Version: "4.0.0"},
+		{Source: "registry.terraform.io/hashicorp/null", Version: "3.1.0"},
+	}, providers)
+
+	t.Run("missing", func(t *testing.T) {
+		providers, err := plugincache.ParseLockFile(filepath.Join(t.TempDir(), plugincache.LockFileName))
+		assert.NoError(t, err)
+		assert.Empty(t, providers)
+	})
+}
+
+func TestCache_Init(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+
+	cache, err := plugincache.New(t.TempDir(), 0, t.TempDir(), nil)
+	require.NoError(t, err)
+	awsDir := installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "4.0.0", 1, old)
+	installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/null", "3.1.0", 1, old)
+
+	var installed, called bool
+	err = cache.Init(writeRoot(t), func() error {
+		installed = true
+		return nil
+	}, func() error {
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.False(t, installed, "cached providers aren't installed")
+
+	// the root's providers are marked as used
+	info, err := os.Stat(awsDir)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(old))
+
+	// and its lock file is recorded for warm up
+	recorded, err := os.ReadDir(cache.RootsDir)
+	require.NoError(t, err)
+	assert.Len(t, recorded, 1)
+
+	t.Run("installs missing providers", func(t *testing.T) {
+		cache, err := plugincache.New(t.TempDir(), 0, "", nil)
+		require.NoError(t, err)
+
+		var calls []string
+		err = cache.Init(writeRoot(t), func() error {
+			calls = append(calls, "install")
+			installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "4.0.0", 1, time.Now())
+			installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/null", "3.1.0", 1, time.Now())
+			return nil
+		}, func() error {
+			calls = append(calls, "init")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"install", "init"}, calls)
+	})
+
+	t.Run("install error", func(t *testing.T) {
+		cache, err := plugincache.New(t.TempDir(), 0, "", nil)
+		require.NoError(t, err)
+
+		err = cache.Init(writeRoot(t), func() error {
+			return assert.AnError
+		}, func() error {
+			t.Fatal("init shouldn't run")
+			return nil
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := cache.Init(writeRoot(t), func() error {
+			return nil
+		}, func() error {
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestCache_Evict(t *testing.T) {
+	old := time.Now().Add(-72 * time.Hour)
+	older := time.Now().Add(-96 * time.Hour)
+
+	cache, err := plugincache.New(t.TempDir(), 150, "", nil)
+	require.NoError(t, err)
+
+	oldest := installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "3.0.0", 100, older)
+	lessOld := installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "4.0.0", 100, old)
+	recent := installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/null", "3.1.0", 100, time.Now())
+
+	require.NoError(t, cache.Evict())
+
+	_, err = os.Stat(oldest)
+	assert.True(t, os.IsNotExist(err), "least recently used provider should be evicted")
+	_, err = os.Stat(lessOld)
+	assert.True(t, os.IsNotExist(err), "providers are evicted until the cache fits")
+	_, err = os.Stat(recent)
+	assert.NoError(t, err, "recently used providers are never evicted")
+}
+
+func TestCache_EvictKeepsLinkedProviders(t *testing.T) {
+	old := time.Now().Add(-72 * time.Hour)
+
+	cache, err := plugincache.New(t.TempDir(), 0, t.TempDir(), nil)
+	require.NoError(t, err)
+
+	var linked string
+	root := writeRoot(t)
+	err = cache.Init(root, func() error {
+		return nil
+	}, func() error {
+		// init links the root's providers to the cache
+		linked = installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "4.0.0", 100, old)
+		link := filepath.Join(root, ".terraform", "providers", "registry.terraform.io", "hashicorp", "aws", "4.0.0", platform)
+		require.NoError(t, os.MkdirAll(filepath.Dir(link), 0700))
+		require.NoError(t, os.Symlink(linked, link))
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(linked, old, old))
+	cache.MaxSize = 1
+
+	// the recorded lock file selects providers which are evicted
+	recorded := filepath.Join(cache.RootsDir, "evicted.hcl")
+	require.NoError(t, os.WriteFile(recorded, []byte(lockFile), 0600))
+	evicted := installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/null", "3.1.0", 100, old)
+
+	require.NoError(t, cache.Evict())
+
+	_, err = os.Stat(linked)
+	assert.NoError(t, err, "providers linked by initialized roots aren't evicted")
+	_, err = os.Stat(evicted)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(recorded)
+	assert.True(t, os.IsNotExist(err), "lock files selecting evicted providers aren't warmed up")
+
+	// providers of deleted roots can be evicted
+	require.NoError(t, os.RemoveAll(root))
+	require.NoError(t, cache.Evict())
+	_, err = os.Stat(linked)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCache_WarmUp(t *testing.T) {
+	cache, err := plugincache.New(t.TempDir(), 0, t.TempDir(), nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cache.RootsDir, "root.hcl"), []byte(lockFile), 0600))
+
+	var runs int
+	run := func(dir string) error {
+		runs++
+
+		config, err := os.ReadFile(filepath.Join(dir, "providers.tf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(config), `source  = "registry.terraform.io/hashicorp/aws"`)
+		assert.FileExists(t, filepath.Join(dir, plugincache.LockFileName))
+
+		installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/aws", "4.0.0", 1, time.Now())
+		installProvider(t, cache.Dir, "registry.terraform.io/hashicorp/null", "3.1.0", 1, time.Now())
+		return nil
+	}
+
+	require.NoError(t, cache.WarmUp(run))
+	assert.Equal(t, 1, runs)
+
+	// cached providers aren't installed again
+	require.NoError(t, cache.WarmUp(run))
+	assert.Equal(t, 1, runs)
+}
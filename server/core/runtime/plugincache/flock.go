@@ -0,0 +1,33 @@
+//go:build unix
+
+package plugincache
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// lock acquires the cache's file lock and returns a function which releases it
+func (c *Cache) lock(exclusive bool) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(c.Dir, ".lock"), os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening plugin cache lock")
+	}
+
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "locking plugin cache")
+	}
+
+	return func() {
+		// closing the file releases the lock
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,71 @@
+package plugincache
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/pkg/errors"
+)
+
+// LockFileName is the dependency lock file written by terraform init
+const LockFileName = ".terraform.lock.hcl"
+
+// Provider is a provider version selected by a dependency lock file
+type Provider struct {
+	// Source is the fully qualified address, ie. registry.terraform.io/hashicorp/aws
+	Source  string
+	Version string
+}
+
+type lockFile struct {
+	Providers []struct {
+		Source  string   `hcl:"source,label"`
+		Version string   `hcl:"version"`
+		Remain  hcl.Body `hcl:",remain"`
+	} `hcl:"provider,block"`
+}
+
+// ParseLockFile returns the providers selected by the lock file at path, nil is returned if it doesn't exist
+func ParseLockFile(path string) ([]Provider, error) {
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	return parseLockFile(path, src)
+}
+
+func parseLockFile(path string, src []byte) ([]Provider, error) {
+	var f lockFile
+	// hclsimple picks the syntax from the file extension so the name is normalized
+	if err := hclsimple.Decode(LockFileName, src, nil, &f); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+
+	var providers []Provider
+	for _, p := range f.Providers {
+		providers = append(providers, Provider{
+			Source:  p.Source,
+			Version: p.Version,
+		})
+	}
+	return providers, nil
+}
+
+// requiredProviders renders a configuration which requires exactly the given providers,
+// it's used to install a lock file's providers without the root's configuration.
+func requiredProviders(providers []Provider) string {
+	var b strings.Builder
+	b.WriteString("terraform {\n  required_providers {\n")
+	for i, p := range providers {
+		fmt.Fprintf(&b, "    p%d = {\n      source  = %q\n      version = %q\n    }\n", i, p.Source, p.Version)
+	}
+	b.WriteString("  }\n}\n")
+	return b.String()
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package plugincache
+
+import "sync"
+
+// processLock guards the cache on platforms without flock, it isn't respected by other processes sharing the dir
+var processLock sync.RWMutex
+
+// lock acquires the cache's process lock and returns a function which releases it
+func (c *Cache) lock(exclusive bool) (func(), error) {
+	if exclusive {
+		processLock.Lock()
+		return processLock.Unlock, nil
+	}
+
+	processLock.RLock()
+	return processLock.RUnlock, nil
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/runtime/cache"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
 	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -64,19 +66,42 @@ func (c ConftestTestCommandArgs) build() []string {
 
 type ConfTestVersionDownloader struct {
 	downloader terraform.Downloader
+	verifier   *checksum.Verifier
 }
 
-func (c ConfTestVersionDownloader) downloadConfTestVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
+// NewConfTestVersionDownloader returns a downloader of conftest releases
+func NewConfTestVersionDownloader(downloader terraform.Downloader, verifier *checksum.Verifier) ConfTestVersionDownloader {
+	return ConfTestVersionDownloader{
+		downloader: downloader,
+		verifier:   verifier,
+	}
+}
+
+func (c ConfTestVersionDownloader) LoadVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	return c.LoadRelease(c.Release(v), v, destPath)
+}
+
+func (c ConfTestVersionDownloader) Release(v *version.Version) checksum.Release {
 	versionURLPrefix := fmt.Sprintf("%s%s", conftestDownloadURLPrefix, v.Original())
 
-	// download binary in addition to checksum file
-	binURL := fmt.Sprintf("%s/conftest_%s_%s_%s.tar.gz", versionURLPrefix, v.Original(), cases.Title(language.English).String(runtime.GOOS), conftestArch)
-	checksumURL := fmt.Sprintf("%s/checksums.txt", versionURLPrefix)
+	// conftest releases aren't signed so these can only be verified with pinned checksums
+	return checksum.Release{
+		ArchiveURL:   fmt.Sprintf("%s/conftest_%s_%s_%s.tar.gz", versionURLPrefix, v.Original(), cases.Title(language.English).String(runtime.GOOS), conftestArch),
+		ChecksumsURL: fmt.Sprintf("%s/checksums.txt", versionURLPrefix),
+	}
+}
+
+// LoadRelease downloads and extracts the release's archive into destPath, the release's files can also be local paths
+func (c ConfTestVersionDownloader) LoadRelease(release checksum.Release, v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	checksumQuery, err := c.verifier.Query(release)
+	if err != nil {
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "verifying conftest version %s", v.String())
+	}
 
 	// underlying implementation uses go-getter so the URL is formatted as such.
 	// i know i know, I'm assuming an interface implementation with my inputs.
 	// realistically though the interface just exists for testing so ¯\_(ツ)_/¯
-	fullSrcURL := fmt.Sprintf("%s?checksum=file:%s", binURL, checksumURL)
+	fullSrcURL := fmt.Sprintf("%s?checksum=%s", release.ArchiveURL, checksumQuery)
 
 	if err := c.downloader.GetAny(destPath, fullSrcURL); err != nil {
 		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading conftest version %s at %q", v.String(), fullSrcURL)
@@ -92,10 +117,7 @@ type ConfTestVersionEnsurer struct {
 	DefaultConftestVersion *version.Version
 }
 
-func NewConfTestVersionEnsurer(log logging.Logger, versionRootDir string, conftestDownloder terraform.Downloader) *ConfTestVersionEnsurer {
-	downloader := ConfTestVersionDownloader{
-		downloader: conftestDownloder,
-	}
+func NewConfTestVersionEnsurer(log logging.Logger, versionRootDir string, conftestDownloder terraform.Downloader, verifier *checksum.Verifier, binaryMirror mirror.Mirror) *ConfTestVersionEnsurer {
 	version, err := getDefaultVersion()
 
 	if err != nil {
@@ -106,7 +128,7 @@ func NewConfTestVersionEnsurer(log logging.Logger, versionRootDir string, confte
 	versionCache := cache.NewExecutionVersionLayeredLoadingCache(
 		conftestBinaryName,
 		versionRootDir,
-		downloader.downloadConfTestVersion,
+		mirror.NewLoader(binaryMirror, conftestBinaryName, NewConfTestVersionDownloader(conftestDownloder, verifier)),
 	)
 
 	return &ConfTestVersionEnsurer{
@@ -31,7 +31,7 @@ func TestConfTestVersionDownloader(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		When(mockDownloader.GetFile(EqString(destPath), EqString(fullURL))).ThenReturn(nil)
-		binPath, err := subject.downloadConfTestVersion(version, destPath)
+		binPath, err := subject.LoadVersion(version, destPath)
 
 		mockDownloader.VerifyWasCalledOnce().GetAny(EqString(destPath), EqString(fullURL))
 
@@ -42,7 +42,7 @@ func TestConfTestVersionDownloader(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		When(mockDownloader.GetAny(EqString(destPath), EqString(fullURL))).ThenReturn(errors.New("err"))
-		_, err := subject.downloadConfTestVersion(version, destPath)
+		_, err := subject.LoadVersion(version, destPath)
 
 		Assert(t, err != nil, "err is expected")
 	})
@@ -12,6 +12,7 @@ import (
 	"github.com/runatlantis/atlantis/server/vcs/provider/github"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -20,7 +21,7 @@ import (
 )
 
 type policyFilter interface {
-	Filter(ctx context.Context, installationToken int64, repo models.Repo, prNum int, trigger command.CommandTrigger, failedPolicies []valid.PolicySet) ([]valid.PolicySet, error)
+	Filter(ctx context.Context, installationToken int64, repo models.Repo, prNum int, projectName string, trigger command.CommandTrigger, failedPolicies []valid.PolicySet) ([]valid.PolicySet, error)
 }
 
 type exec interface {
@@ -38,6 +39,9 @@ const (
 type ConfTestExecutor struct {
 	Exec         exec
 	PolicyFilter policyFilter
+
+	// Clock is used to determine which exemptions are active, it defaults to time.Now
+	Clock func() time.Time
 }
 
 func NewConfTestExecutor(creator githubapp.ClientCreator, policySets valid.PolicySets, allocator feature.Allocator, logger logging.Logger) *ConfTestExecutor {
@@ -54,6 +58,7 @@ func NewConfTestExecutor(creator githubapp.ClientCreator, policySets valid.Polic
 	return &ConfTestExecutor{
 		Exec:         runtime_models.LocalExec{},
 		PolicyFilter: events.NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamMemberFetcher, allocator, policySets.PolicySets, logger),
+		Clock:        time.Now,
 	}
 }
 
@@ -95,14 +100,16 @@ func (c *ConfTestExecutor) Run(_ context.Context, prjCtx command.ProjectContext,
 	}
 
 	title := c.buildTitle(policyNames)
-	output := c.sanitizeOutput(inputFile, title+strings.Join(totalCmdOutput, "\n"))
+	// exemptions are configured for the repo the pull request is opened against
+	exemptions := c.buildExemptions(prjCtx.BaseRepo.FullName, prjCtx.ProjectName, prjCtx.PolicySets.PolicySets)
+	output := c.sanitizeOutput(inputFile, title+strings.Join(totalCmdOutput, "\n")+exemptions)
 	if prjCtx.InstallationToken == 0 {
 		prjCtx.Log.ErrorContext(prjCtx.RequestCtx, "missing installation token")
 		scope.Counter(metrics.ExecutionErrorMetric).Inc(1)
 		return output, errors.New(internalError)
 	}
 
-	failedPolicies, err := c.PolicyFilter.Filter(prjCtx.RequestCtx, prjCtx.InstallationToken, prjCtx.HeadRepo, prjCtx.Pull.Num, prjCtx.Trigger, failedPolicies)
+	failedPolicies, err := c.PolicyFilter.Filter(prjCtx.RequestCtx, prjCtx.InstallationToken, prjCtx.BaseRepo, prjCtx.Pull.Num, prjCtx.ProjectName, prjCtx.Trigger, failedPolicies)
 	if err != nil {
 		prjCtx.Log.ErrorContext(prjCtx.RequestCtx, fmt.Sprintf("error filtering out approved policies: %s", err.Error()))
 		scope.Counter(metrics.ExecutionErrorMetric).Inc(1)
@@ -121,6 +128,28 @@ func (c *ConfTestExecutor) buildTitle(policySetNames []string) string {
 	return fmt.Sprintf("Checking plan against the following policies: \n  %s\n\n", strings.Join(policySetNames, "\n  "))
 }
 
+// buildExemptions lists the policy sets whose failures are ignored for the project
+func (c *ConfTestExecutor) buildExemptions(repoName string, projectName string, policySets []valid.PolicySet) string {
+	var exemptions []string
+	now := c.now()
+	for _, policySet := range policySets {
+		if e := policySet.ActiveExemption(repoName, projectName, now); e != nil {
+			exemptions = append(exemptions, fmt.Sprintf("%s: %s (expires %s)", policySet.Name, e.Justification, e.Expiry.Format(valid.ExemptionExpiryFormat)))
+		}
+	}
+	if len(exemptions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nThe following policies are exempt for this project: \n  %s\n", strings.Join(exemptions, "\n  "))
+}
+
+func (c *ConfTestExecutor) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock()
+}
+
 func (c *ConfTestExecutor) sanitizeOutput(inputFile string, output string) string {
 	return strings.Replace(output, inputFile, "<redacted plan file>", -1)
 }
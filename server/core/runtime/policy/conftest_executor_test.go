@@ -13,6 +13,7 @@ import (
 	"github.com/uber-go/tally/v4"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -123,6 +124,54 @@ func TestConfTestExecutor_PolicyFailure_NotFiltered(t *testing.T) {
 	assert.Contains(t, cmdOutput, output)
 }
 
+func TestConfTestExecutor_ListsExemptions(t *testing.T) {
+	exec := &mockExec{
+		output: output,
+	}
+	now := time.Date(2022, time.October, 4, 12, 0, 0, 0, time.UTC)
+	policySets := []valid.PolicySet{
+		{
+			Name:  policyA,
+			Paths: []string{path},
+			Exemptions: []valid.PolicyExemption{
+				{
+					Repo:          "owner/repo",
+					Expiry:        now.Add(24 * time.Hour),
+					Justification: "migrating",
+				},
+			},
+		},
+		{
+			Name:  policyB,
+			Paths: []string{path2},
+			Exemptions: []valid.PolicyExemption{
+				{
+					Repo:          "owner/repo",
+					Expiry:        now.Add(-24 * time.Hour),
+					Justification: "expired",
+				},
+			},
+		},
+	}
+	policyFilter := &mockPolicyFilter{}
+	executor := policy.ConfTestExecutor{
+		Exec:         exec,
+		PolicyFilter: policyFilter,
+		Clock: func() time.Time {
+			return now
+		},
+	}
+	prjCtx := buildTestProjectCtx(t, policySets)
+	prjCtx.BaseRepo = models.Repo{FullName: "owner/repo"}
+
+	// exemptions are scoped to the base repo so a fork's name doesn't matter
+	prjCtx.HeadRepo = models.Repo{FullName: "fork/repo"}
+	cmdOutput, err := executor.Run(context.Background(), prjCtx, executablePath, map[string]string{}, workDir, []string{})
+	assert.NoError(t, err)
+	assert.Contains(t, cmdOutput, fmt.Sprintf("The following policies are exempt for this project: \n  A: migrating (expires %s)\n", policySets[0].Exemptions[0].Expiry.Format(valid.ExemptionExpiryFormat)))
+	assert.NotContains(t, cmdOutput, "expired")
+}
+
 func TestConfTestExecutor_FilterFailure(t *testing.T) {
 	exec := &mockExec{
 		output: output,
@@ -185,7 +234,7 @@ type mockPolicyFilter struct {
 	error    error
 }
 
-func (r *mockPolicyFilter) Filter(_ context.Context, _ int64, _ models.Repo, _ int, _ command.CommandTrigger, _ []valid.PolicySet) ([]valid.PolicySet, error) {
+func (r *mockPolicyFilter) Filter(_ context.Context, _ int64, _ models.Repo, _ int, _ string, _ command.CommandTrigger, _ []valid.PolicySet) ([]valid.PolicySet, error) {
 	r.isCalled = true
 	return r.policies, r.error
 }
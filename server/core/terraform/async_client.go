@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/terraform/helpers"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/jobs"
@@ -28,6 +29,27 @@ type ClientAsync interface {
 type AsyncClient struct {
 	projectCmdOutputHandler jobs.ProjectCommandOutputHandler
 	commandBuilder          commandBuilder
+
+	// tofuCommandBuilder is nil unless opentofu is configured
+	tofuCommandBuilder commandBuilder
+}
+
+// resolveEngine returns the command builder for the project's engine.  Step runners pass the default
+// terraform version when a project doesn't pin one, so opentofu projects only use a pinned version
+// and otherwise fall back to the default opentofu version.
+func (c *AsyncClient) resolveEngine(prjCtx command.ProjectContext, v *version.Version) (commandBuilder, *version.Version, error) {
+	if prjCtx.Engine != valid.OpenTofuEngine {
+		return c.commandBuilder, v, nil
+	}
+
+	if c.tofuCommandBuilder == nil {
+		return nil, nil, errors.New("opentofu is not configured on this server")
+	}
+
+	if prjCtx.TerraformVersion == nil {
+		v = nil
+	}
+	return c.tofuCommandBuilder, v, nil
 }
 
 // RunCommandAsync runs terraform with args. It immediately returns an
@@ -53,7 +75,14 @@ func (c *AsyncClient) RunCommandAsyncWithInput(ctx context.Context, prjCtx comma
 			close(outCh)
 		}()
 
-		cmd, err := c.commandBuilder.Build(v, workspace, path, args)
+		builder, v, err := c.resolveEngine(prjCtx, v)
+		if err != nil {
+			prjCtx.Log.ErrorContext(prjCtx.RequestCtx, err.Error())
+			outCh <- helpers.Line{Err: err}
+			return
+		}
+
+		cmd, err := builder.Build(v, workspace, path, args)
 		if err != nil {
 			prjCtx.Log.ErrorContext(prjCtx.RequestCtx, err.Error())
 			outCh <- helpers.Line{Err: err}
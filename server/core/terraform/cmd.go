@@ -27,6 +27,10 @@ func (c *CommandBuilder) Build(v *version.Version, workspace string, path string
 		v = c.defaultVersion
 	}
 
+	if v == nil {
+		return nil, errors.New("no version specified and no default version configured")
+	}
+
 	binPath, err := c.versionCache.Get(v)
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting version from cache %s", v.String())
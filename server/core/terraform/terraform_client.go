@@ -29,7 +29,10 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime/cache"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
 	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/terraform/ansi"
@@ -165,20 +168,20 @@ func NewClient(
 	defaultVersionFlagName string,
 	tfDownloadURL string,
 	tfDownloader Downloader,
+	verifier *checksum.Verifier,
+	binaryMirror mirror.Mirror,
+	tofuCfg TofuConfig,
 	usePluginCache bool,
 	projectCmdOutputHandler jobs.ProjectCommandOutputHandler,
 ) (*DefaultClient, error) {
-	loader := VersionLoader{
-		downloader:  tfDownloader,
-		downloadURL: tfDownloadURL,
-	}
+	loader := NewVersionLoader(tfDownloader, tfDownloadURL, verifier)
 
 	versionCache := cache.NewExecutionVersionLayeredLoadingCache(
-		"terraform",
+		valid.TerraformEngine.BinaryName(),
 		binDir,
-		loader.LoadVersion,
+		mirror.NewLoader(binaryMirror, valid.TerraformEngine.BinaryName(), loader),
 	)
-	return NewClientWithVersionCache(
+	client, err := NewClientWithVersionCache(
 		binDir,
 		cacheDir,
 		defaultVersionStr,
@@ -189,6 +192,54 @@ func NewClient(
 		projectCmdOutputHandler,
 		versionCache,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// opentofu is only available if it's enabled
+	if !tofuCfg.Enabled {
+		return client, nil
+	}
+
+	tofuLoader := NewTofuVersionLoader(tfDownloader, tofuCfg.DownloadURL, tofuCfg.Verifier)
+	tofuVersionCache := cache.NewExecutionVersionLayeredLoadingCache(
+		valid.OpenTofuEngine.BinaryName(),
+		binDir,
+		mirror.NewLoader(binaryMirror, valid.OpenTofuEngine.BinaryName(), tofuLoader),
+	)
+
+	var tofuDefaultVersion *version.Version
+	if tofuCfg.DefaultVersion != "" {
+		tofuDefaultVersion, err = version.NewVersion(tofuCfg.DefaultVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing default opentofu version %s", tofuCfg.DefaultVersion)
+		}
+
+		// warm the cache with this version
+		if _, err := tofuVersionCache.Get(tofuDefaultVersion); err != nil {
+			return nil, errors.Wrapf(err, "getting default opentofu version %s", tofuCfg.DefaultVersion)
+		}
+	}
+
+	tofuBuilder := &CommandBuilder{
+		defaultVersion: tofuDefaultVersion,
+		versionCache:   tofuVersionCache,
+	}
+	if usePluginCache {
+		tofuBuilder.terraformPluginCacheDir = cacheDir
+	}
+	client.AsyncClient.tofuCommandBuilder = tofuBuilder
+
+	return client, nil
+}
+
+// TofuConfig configures opentofu as an alternative engine to terraform, projects
+// can only use opentofu if it's Enabled.
+type TofuConfig struct {
+	Enabled        bool
+	DefaultVersion string
+	DownloadURL    string
+	Verifier       *checksum.Verifier
 }
 
 // Version returns the default version of Terraform we use if no other version
@@ -216,6 +267,21 @@ func (c *DefaultClient) EnsureVersion(log logging.Logger, v *version.Version) er
 	return nil
 }
 
+// InitProviders runs init without a backend in path using the default version, it's used
+// to install providers into the plugin cache outside of a project command.
+func (c *DefaultClient) InitProviders(path string) error {
+	cmd, err := c.commandBuilder.Build(nil, "default", path, []string{"init", "-backend=false", "-input=false"})
+	if err != nil {
+		return err
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "running %q in %q: %s", cmd.String(), path, ansi.Strip(string(out)))
+	}
+	return nil
+}
+
 // See Client.RunCommandWithVersion.
 func (c *DefaultClient) RunCommandWithVersion(ctx context.Context, prjCtx command.ProjectContext, path string, args []string, customEnvVars map[string]string, v *version.Version, workspace string) (string, error) {
 	// if the feature is enabled, we use the async workflow else we default to the original sync workflow
@@ -239,7 +305,11 @@ func (c *DefaultClient) RunCommandWithVersion(ctx context.Context, prjCtx comman
 		return fmt.Sprintf("%s\n", output), err
 	}
 
-	cmd, err := c.commandBuilder.Build(v, workspace, path, args)
+	builder, v, err := c.resolveEngine(prjCtx, v)
+	if err != nil {
+		return "", err
+	}
+	cmd, err := builder.Build(v, workspace, path, args)
 	if err != nil {
 		return "", err
 	}
@@ -262,25 +332,74 @@ func (c *DefaultClient) RunCommandWithVersion(ctx context.Context, prjCtx comman
 type VersionLoader struct {
 	downloader  Downloader
 	downloadURL string
+	verifier    *checksum.Verifier
+	engine      valid.Engine
 }
 
-func NewVersionLoader(downloader Downloader, downloadURL string) *VersionLoader {
+func NewVersionLoader(downloader Downloader, downloadURL string, verifier *checksum.Verifier) *VersionLoader {
 	return &VersionLoader{
 		downloader:  downloader,
 		downloadURL: downloadURL,
+		verifier:    verifier,
+		engine:      valid.TerraformEngine,
 	}
 }
 
-func (l *VersionLoader) LoadVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
-	urlPrefix := fmt.Sprintf("%s/terraform/%s/terraform_%s", l.downloadURL, v.String(), v.String())
-	binURL := fmt.Sprintf("%s_%s_%s.zip", urlPrefix, runtime.GOOS, runtime.GOARCH)
+// NewTofuVersionLoader returns a loader for opentofu releases
+func NewTofuVersionLoader(downloader Downloader, downloadURL string, verifier *checksum.Verifier) *VersionLoader {
+	return &VersionLoader{
+		downloader:  downloader,
+		downloadURL: downloadURL,
+		verifier:    verifier,
+		engine:      valid.OpenTofuEngine,
+	}
+}
+
+// EngineRelease locates the release archive of a terraform or opentofu version
+// and the checksums which verify it.
+func EngineRelease(engine valid.Engine, downloadURL string, v *version.Version) checksum.Release {
+	if engine == valid.OpenTofuEngine {
+		// opentofu releases are tagged with a v prefix and the checksums' gpg signature uses a separate extension
+		urlPrefix := fmt.Sprintf("%s/v%s/tofu_%s", downloadURL, v.String(), v.String())
+		checksumURL := fmt.Sprintf("%s_SHA256SUMS", urlPrefix)
+		return checksum.Release{
+			ArchiveURL:   fmt.Sprintf("%s_%s_%s.zip", urlPrefix, runtime.GOOS, runtime.GOARCH),
+			ChecksumsURL: checksumURL,
+			SignatureURL: fmt.Sprintf("%s.gpgsig", checksumURL),
+		}
+	}
+
+	urlPrefix := fmt.Sprintf("%s/terraform/%s/terraform_%s", downloadURL, v.String(), v.String())
 	checksumURL := fmt.Sprintf("%s_SHA256SUMS", urlPrefix)
-	fullSrcURL := fmt.Sprintf("%s?checksum=file:%s", binURL, checksumURL)
+	return checksum.Release{
+		ArchiveURL:   fmt.Sprintf("%s_%s_%s.zip", urlPrefix, runtime.GOOS, runtime.GOARCH),
+		ChecksumsURL: checksumURL,
+		SignatureURL: fmt.Sprintf("%s.sig", checksumURL),
+	}
+}
+
+func (l *VersionLoader) LoadVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	return l.LoadRelease(l.Release(v), v, destPath)
+}
+
+func (l *VersionLoader) Release(v *version.Version) checksum.Release {
+	return EngineRelease(l.engine, l.downloadURL, v)
+}
+
+// LoadRelease downloads and extracts the release's archive into destPath, the release's files can also be local paths
+func (l *VersionLoader) LoadRelease(release checksum.Release, v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	// the archive is only extracted if it matches the verified checksum
+	checksumQuery, err := l.verifier.Query(release)
+	if err != nil {
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "verifying %s version %s", l.engine.OrDefault(), v.String())
+	}
+
+	fullSrcURL := fmt.Sprintf("%s?checksum=%s", release.ArchiveURL, checksumQuery)
 	if err := l.downloader.GetAny(destPath, fullSrcURL); err != nil {
-		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading terraform version %s at %q", v.String(), fullSrcURL)
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading %s version %s at %q", l.engine, v.String(), fullSrcURL)
 	}
 
-	binPath := filepath.Join(destPath, "terraform")
+	binPath := filepath.Join(destPath, l.engine.BinaryName())
 
 	return runtime_models.LocalFilePath(binPath), nil
 }
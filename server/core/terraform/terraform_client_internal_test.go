@@ -10,6 +10,8 @@ import (
 
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
 	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -91,6 +93,75 @@ func TestVersionLoader_buildsURL(t *testing.T) {
 	})
 }
 
+func TestVersionLoader_pinnedChecksum(t *testing.T) {
+	v, _ := version.NewVersion("0.15.0")
+
+	destPath := "some/path"
+	archive := fmt.Sprintf("terraform_0.15.0_%s_%s.zip", runtime.GOOS, runtime.GOARCH)
+	sum := "a5c0e6e6b6a5b2fd4c3c7a5e3f5b6e1a1d4c2b3a4f5e6d7c8b9a0f1e2d3c4b5a"
+
+	RegisterMockTestingT(t)
+
+	mockDownloader := mocks.NewMockDownloader()
+
+	t.Run("pinned", func(t *testing.T) {
+		subject := VersionLoader{
+			downloader:  mockDownloader,
+			downloadURL: "https://releases.hashicorp.com",
+			verifier:    &checksum.Verifier{Pinned: map[string]string{archive: sum}},
+		}
+
+		fullURL := fmt.Sprintf("https://releases.hashicorp.com/terraform/0.15.0/%s?checksum=sha256:%s", archive, sum)
+		When(mockDownloader.GetAny(EqString(destPath), EqString(fullURL))).ThenReturn(nil)
+		_, err := subject.LoadVersion(v, destPath)
+
+		Ok(t, err)
+		mockDownloader.VerifyWasCalledOnce().GetAny(EqString(destPath), EqString(fullURL))
+	})
+
+	t.Run("not pinned", func(t *testing.T) {
+		subject := VersionLoader{
+			downloader:  mockDownloader,
+			downloadURL: "https://releases.hashicorp.com",
+			verifier:    &checksum.Verifier{Pinned: map[string]string{"terraform_1.0.0_linux_amd64.zip": sum}},
+		}
+
+		_, err := subject.LoadVersion(v, destPath)
+		Assert(t, err != nil, "err is expected")
+	})
+}
+
+func TestVersionLoader_tofu(t *testing.T) {
+	v, _ := version.NewVersion("1.6.0")
+
+	destPath := "some/path"
+	fullURL := fmt.Sprintf("https://github.com/opentofu/opentofu/releases/download/v1.6.0/tofu_1.6.0_%s_%s.zip?checksum=file:https://github.com/opentofu/opentofu/releases/download/v1.6.0/tofu_1.6.0_SHA256SUMS", runtime.GOOS, runtime.GOARCH)
+
+	RegisterMockTestingT(t)
+
+	mockDownloader := mocks.NewMockDownloader()
+	subject := NewTofuVersionLoader(mockDownloader, "https://github.com/opentofu/opentofu/releases/download", nil)
+
+	When(mockDownloader.GetAny(EqString(destPath), EqString(fullURL))).ThenReturn(nil)
+	binPath, err := subject.LoadVersion(v, destPath)
+
+	mockDownloader.VerifyWasCalledOnce().GetAny(EqString(destPath), EqString(fullURL))
+	Ok(t, err)
+	Assert(t, binPath.Resolve() == filepath.Join(destPath, "tofu"), "expected binpath")
+}
+
+func TestEngineRelease(t *testing.T) {
+	v, _ := version.NewVersion("1.6.0")
+
+	tofu := EngineRelease(valid.OpenTofuEngine, "https://tofu", v)
+	assert.Equal(t, fmt.Sprintf("https://tofu/v1.6.0/tofu_1.6.0_%s_%s.zip", runtime.GOOS, runtime.GOARCH), tofu.ArchiveURL)
+	assert.Equal(t, "https://tofu/v1.6.0/tofu_1.6.0_SHA256SUMS.gpgsig", tofu.SignatureURL)
+
+	tf := EngineRelease(valid.Engine(""), "https://tf", v)
+	assert.Equal(t, fmt.Sprintf("https://tf/terraform/1.6.0/terraform_1.6.0_%s_%s.zip", runtime.GOOS, runtime.GOARCH), tf.ArchiveURL)
+	assert.Equal(t, "https://tf/terraform/1.6.0/terraform_1.6.0_SHA256SUMS.sig", tf.SignatureURL)
+}
+
 // Test that it returns an error on error.
 func TestDefaultClient_Synchronous_RunCommandWithVersion_Error(t *testing.T) {
 	path := "some/path"
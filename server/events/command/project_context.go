@@ -69,6 +69,7 @@ func NewProjectContext(
 		RepoRelDir:           projCfg.RepoRelDir,
 		RepoConfigVersion:    projCfg.RepoCfgVersion,
 		TerraformVersion:     projCfg.TerraformVersion,
+		Engine:               projCfg.Engine,
 		User:                 ctx.User,
 		ForceApply:           contextFlags.ForceApply,
 		Workspace:            projCfg.Workspace,
@@ -140,6 +141,9 @@ type ProjectContext struct {
 	// commands for this project. This can be set to nil in which case we will
 	// use the default Atlantis terraform version.
 	TerraformVersion *version.Version
+	// Engine is the binary used to execute commands for this project, an empty
+	// engine uses terraform.
+	Engine valid.Engine
 	// Configuration metadata for a given project.
 	Tags map[string]string
 	// User is the user that triggered this command.
@@ -7,7 +7,7 @@ import (
 )
 
 type DBUpdater struct {
-	DB *db.BoltDB
+	DB db.Database
 }
 
 func (c *DBUpdater) updateDB(_ *command.Context, pull models.PullRequest, results []command.ProjectResult) (models.PullStatus, error) {
@@ -2,6 +2,8 @@ package events
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -13,7 +15,8 @@ import (
 // DeleteLockCommand is the first step after a command request has been parsed.
 type DeleteLockCommand interface {
 	DeleteLock(id string) (*models.ProjectLock, error)
-	DeleteLocksByPull(repoFullName string, pullNum int) (int, error)
+	DeleteExpiredLock(id string, now time.Time) (*models.ProjectLock, error)
+	DeleteLocksByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error)
 }
 
 // DefaultDeleteLockCommand deletes a specific lock after a request from the LocksController.
@@ -22,7 +25,7 @@ type DefaultDeleteLockCommand struct {
 	Logger           logging.Logger
 	WorkingDir       WorkingDir
 	WorkingDirLocker WorkingDirLocker
-	DB               *db.BoltDB
+	DB               db.Database
 }
 
 // DeleteLock handles deleting the lock at id
@@ -39,23 +42,33 @@ func (l *DefaultDeleteLockCommand) DeleteLock(id string) (*models.ProjectLock, e
 	return lock, nil
 }
 
-// DeleteLocksByPull handles deleting all locks for the pull request
-func (l *DefaultDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNum int) (int, error) {
-	locks, err := l.Locker.UnlockByPull(repoFullName, pullNum)
-	numLocks := len(locks)
+// DeleteExpiredLock handles deleting the lock at id if it expired before now, it returns
+// nil if there is no lock or it hasn't expired
+func (l *DefaultDeleteLockCommand) DeleteExpiredLock(id string, now time.Time) (*models.ProjectLock, error) {
+	lock, err := l.Locker.UnlockExpired(id, now)
 	if err != nil {
-		return numLocks, err
+		return nil, err
 	}
-	if numLocks == 0 {
-		return numLocks, nil
+	if lock == nil {
+		return nil, nil
+	}
+
+	l.deleteWorkingDir(*lock)
+	return lock, nil
+}
+
+// DeleteLocksByPull handles deleting all locks for the pull request and returns them
+func (l *DefaultDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
+	locks, err := l.Locker.UnlockByPull(repoFullName, pullNum)
+	if err != nil {
+		return locks, err
 	}
 
-	for i := 0; i < numLocks; i++ {
-		lock := locks[i]
+	for _, lock := range locks {
 		l.deleteWorkingDir(lock)
 	}
 
-	return numLocks, nil
+	return locks, nil
 }
 
 func (l *DefaultDeleteLockCommand) deleteWorkingDir(lock models.ProjectLock) {
@@ -3,6 +3,7 @@ package events_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/petergtz/pegomock"
 	"github.com/runatlantis/atlantis/server/core/db"
@@ -40,6 +41,20 @@ func TestDeleteLock_None(t *testing.T) {
 	Assert(t, lock == nil, "lock was not nil")
 }
 
+func TestDeleteExpiredLock_NotExpired(t *testing.T) {
+	RegisterMockTestingT(t)
+	l := lockmocks.NewMockLocker()
+	now := time.Now()
+	When(l.UnlockExpired("id", now)).ThenReturn(nil, nil)
+	dlc := events.DefaultDeleteLockCommand{
+		Locker: l,
+		Logger: logging.NewNoopCtxLogger(t),
+	}
+	lock, err := dlc.DeleteExpiredLock("id", now)
+	Ok(t, err)
+	Assert(t, lock == nil, "lock was not nil")
+}
+
 func TestDeleteLock_OldFormat(t *testing.T) {
 	t.Log("If the lock doesn't have BaseRepo set it is deleted successfully")
 	RegisterMockTestingT(t)
@@ -45,17 +45,36 @@ func (mock *MockDeleteLockCommand) DeleteLock(id string) (*models.ProjectLock, e
 	return ret0, ret1
 }
 
-func (mock *MockDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNum int) (int, error) {
+func (mock *MockDeleteLockCommand) DeleteExpiredLock(id string, now time.Time) (*models.ProjectLock, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockDeleteLockCommand().")
+	}
+	params := []pegomock.Param{id, now}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteExpiredLock", params, []reflect.Type{reflect.TypeOf((**models.ProjectLock)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *models.ProjectLock
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*models.ProjectLock)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockDeleteLockCommand().")
 	}
 	params := []pegomock.Param{repoFullName, pullNum}
-	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteLocksByPull", params, []reflect.Type{reflect.TypeOf((*int)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
-	var ret0 int
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteLocksByPull", params, []reflect.Type{reflect.TypeOf((*[]models.ProjectLock)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []models.ProjectLock
 	var ret1 error
 	if len(result) != 0 {
 		if result[0] != nil {
-			ret0 = result[0].(int)
+			ret0 = result[0].([]models.ProjectLock)
 		}
 		if result[1] != nil {
 			ret1 = result[1].(error)
@@ -128,6 +147,37 @@ func (c *MockDeleteLockCommand_DeleteLock_OngoingVerification) GetAllCapturedArg
 	return
 }
 
+func (verifier *VerifierMockDeleteLockCommand) DeleteExpiredLock(id string, now time.Time) *MockDeleteLockCommand_DeleteExpiredLock_OngoingVerification {
+	params := []pegomock.Param{id, now}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteExpiredLock", params, verifier.timeout)
+	return &MockDeleteLockCommand_DeleteExpiredLock_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockDeleteLockCommand_DeleteExpiredLock_OngoingVerification struct {
+	mock              *MockDeleteLockCommand
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockDeleteLockCommand_DeleteExpiredLock_OngoingVerification) GetCapturedArguments() (string, time.Time) {
+	id, now := c.GetAllCapturedArguments()
+	return id[len(id)-1], now[len(now)-1]
+}
+
+func (c *MockDeleteLockCommand_DeleteExpiredLock_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []time.Time) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]time.Time, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(time.Time)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNum int) *MockDeleteLockCommand_DeleteLocksByPull_OngoingVerification {
 	params := []pegomock.Param{repoFullName, pullNum}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteLocksByPull", params, verifier.timeout)
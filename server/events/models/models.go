@@ -244,6 +244,14 @@ type ProjectLock struct {
 	Workspace string
 	// Time is the time at which the lock was first created.
 	Time time.Time
+	// ExpiresAt is the time after which the lock is released by the lock
+	// reaper. It's zero if the lock doesn't expire.
+	ExpiresAt time.Time
+}
+
+// Expired returns true if the lock has an expiry which is before now.
+func (l ProjectLock) Expired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && l.ExpiresAt.Before(now)
 }
 
 // Project represents a Terraform project. Since there may be multiple
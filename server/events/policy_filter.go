@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"fmt"
 	gh "github.com/google/go-github/v45/github"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -9,6 +10,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/lyft/feature"
+	"time"
 )
 
 type prReviewFetcher interface {
@@ -31,6 +33,7 @@ type ApprovedPolicyFilter struct {
 	allocator         feature.Allocator
 	policies          []valid.PolicySet
 	logger            logging.Logger
+	now               func() time.Time
 }
 
 func NewApprovedPolicyFilter(
@@ -47,11 +50,15 @@ func NewApprovedPolicyFilter(
 		policies:          policySets,
 		allocator:         allocator,
 		logger:            logger,
+		now:               time.Now,
 	}
 }
 
-// Filter will remove failed policies if the underlying PR has been approved by a policy owner
-func (p *ApprovedPolicyFilter) Filter(ctx context.Context, installationToken int64, repo models.Repo, prNum int, trigger command.CommandTrigger, failedPolicies []valid.PolicySet) ([]valid.PolicySet, error) {
+// Filter will remove failed policies which have an active exemption for the project or if the underlying PR
+// has been approved by a policy owner
+func (p *ApprovedPolicyFilter) Filter(ctx context.Context, installationToken int64, repo models.Repo, prNum int, projectName string, trigger command.CommandTrigger, failedPolicies []valid.PolicySet) ([]valid.PolicySet, error) {
+	failedPolicies = p.filterExemptedPolicies(ctx, repo, projectName, failedPolicies)
+
 	// Skip GH API calls if no policies failed
 	if len(failedPolicies) == 0 {
 		return failedPolicies, nil
@@ -86,6 +93,18 @@ func (p *ApprovedPolicyFilter) Filter(ctx context.Context, installationToken int
 	return filteredFailedPolicies, nil
 }
 
+func (p *ApprovedPolicyFilter) filterExemptedPolicies(ctx context.Context, repo models.Repo, projectName string, failedPolicies []valid.PolicySet) []valid.PolicySet {
+	var filteredFailedPolicies []valid.PolicySet
+	for _, failedPolicy := range failedPolicies {
+		if exemption := failedPolicy.ActiveExemption(repo.FullName, projectName, p.now()); exemption != nil {
+			p.logger.InfoContext(ctx, fmt.Sprintf("policy %s is exempt until %s: %s", failedPolicy.Name, exemption.Expiry.Format(time.RFC3339), exemption.Justification))
+			continue
+		}
+		filteredFailedPolicies = append(filteredFailedPolicies, failedPolicy)
+	}
+	return filteredFailedPolicies
+}
+
 func (p *ApprovedPolicyFilter) dismissStalePRReviews(ctx context.Context, installationToken int64, repo models.Repo, prNum int) error {
 	shouldAllocate, err := p.allocator.ShouldAllocate(feature.LegacyDeprecation, feature.FeatureContext{
 		RepoName: repo.FullName,
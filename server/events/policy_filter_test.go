@@ -12,6 +12,7 @@ import (
 	"github.com/runatlantis/atlantis/server/lyft/feature"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 const (
@@ -35,7 +36,7 @@ func TestFilter_Approved(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.PRReviewTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.PRReviewTrigger, failedPolicies)
 	assert.NoError(t, err)
 	assert.True(t, reviewFetcher.listUsernamesIsCalled)
 	assert.False(t, reviewFetcher.listApprovalsIsCalled)
@@ -64,7 +65,7 @@ func TestFilter_NotApproved(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.AutoTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.AutoTrigger, failedPolicies)
 	assert.NoError(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.True(t, reviewFetcher.listApprovalsIsCalled)
@@ -73,6 +74,54 @@ func TestFilter_NotApproved(t *testing.T) {
 	assert.Equal(t, failedPolicies, filteredPolicies)
 }
 
+func TestFilter_Exempted(t *testing.T) {
+	reviewFetcher := &mockReviewFetcher{}
+	reviewDismisser := &mockReviewDismisser{}
+	teamFetcher := &mockTeamMemberFetcher{}
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	exemptPolicy := valid.PolicySet{
+		Name:  policyName,
+		Owner: policyOwner,
+		Exemptions: []valid.PolicyExemption{
+			{
+				Repo:          "owner/repo",
+				Roots:         []string{"root"},
+				Expiry:        now.Add(time.Hour),
+				Justification: "migrating",
+			},
+		},
+	}
+	expiredPolicy := valid.PolicySet{
+		Name:  "expired-policy",
+		Owner: policyOwner,
+		Exemptions: []valid.PolicyExemption{
+			{
+				Repo:          "owner/repo",
+				Expiry:        now,
+				Justification: "migrating",
+			},
+		},
+	}
+	failedPolicies := []valid.PolicySet{exemptPolicy, expiredPolicy}
+
+	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
+	policyFilter.now = func() time.Time { return now }
+
+	t.Run("exempt root", func(t *testing.T) {
+		filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{FullName: "owner/repo"}, 0, "root", command.CommentTrigger, []valid.PolicySet{exemptPolicy})
+		assert.NoError(t, err)
+		assert.Empty(t, filteredPolicies)
+		assert.False(t, reviewFetcher.listApprovalsIsCalled)
+		assert.False(t, teamFetcher.isCalled)
+	})
+
+	t.Run("expired and unscoped", func(t *testing.T) {
+		filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{FullName: "owner/repo"}, 0, "other-root", command.CommentTrigger, failedPolicies)
+		assert.NoError(t, err)
+		assert.Equal(t, failedPolicies, filteredPolicies)
+	})
+}
+
 func TestFilter_DismissalBlockedByFeatureAllocator(t *testing.T) {
 	reviewFetcher := &mockReviewFetcher{
 		reviews: []*github.PullRequestReview{
@@ -90,7 +139,7 @@ func TestFilter_DismissalBlockedByFeatureAllocator(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{Enabled: true}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.AutoTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.AutoTrigger, failedPolicies)
 	assert.NoError(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.False(t, reviewFetcher.listApprovalsIsCalled)
@@ -116,7 +165,7 @@ func TestFilter_NotApproved_Dismissal(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.AutoTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.AutoTrigger, failedPolicies)
 	assert.NoError(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.True(t, reviewFetcher.listApprovalsIsCalled)
@@ -136,7 +185,7 @@ func TestFilter_NoFailedPolicies(t *testing.T) {
 
 	var failedPolicies []valid.PolicySet
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.PRReviewTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.PRReviewTrigger, failedPolicies)
 	assert.NoError(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.False(t, reviewFetcher.listApprovalsIsCalled)
@@ -156,7 +205,7 @@ func TestFilter_FailedListLatestApprovalUsernames(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.PRReviewTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.PRReviewTrigger, failedPolicies)
 	assert.Error(t, err)
 	assert.True(t, reviewFetcher.listUsernamesIsCalled)
 	assert.False(t, reviewFetcher.listApprovalsIsCalled)
@@ -176,7 +225,7 @@ func TestFilter_FailedListApprovalReviews(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.CommentTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.CommentTrigger, failedPolicies)
 	assert.Error(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.True(t, reviewFetcher.listApprovalsIsCalled)
@@ -198,7 +247,7 @@ func TestFilter_FailedTeamMemberFetch(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.PRReviewTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.PRReviewTrigger, failedPolicies)
 	assert.Error(t, err)
 	assert.True(t, reviewFetcher.listUsernamesIsCalled)
 	assert.False(t, reviewFetcher.listApprovalsIsCalled)
@@ -226,7 +275,7 @@ func TestFilter_FailedDismiss(t *testing.T) {
 	}
 
 	policyFilter := NewApprovedPolicyFilter(reviewFetcher, reviewDismisser, teamFetcher, &testFeatureAllocator{}, failedPolicies, logging.NewNoopCtxLogger(t))
-	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, command.AutoTrigger, failedPolicies)
+	filteredPolicies, err := policyFilter.Filter(context.Background(), 0, models.Repo{}, 0, "", command.AutoTrigger, failedPolicies)
 	assert.Error(t, err)
 	assert.False(t, reviewFetcher.listUsernamesIsCalled)
 	assert.True(t, reviewFetcher.listApprovalsIsCalled)
@@ -73,6 +73,12 @@ func (p *DefaultProjectLocker) TryLock(requestCtx context.Context, log logging.L
 			"This project is currently locked by an unapplied plan from pull %s. To continue, delete the lock from %s or apply that plan and merge the pull request.\n\nOnce the lock is released, comment `atlantis plan` here to re-plan.",
 			link,
 			link)
+		if lockAttempt.QueuePosition > 0 {
+			failureMsg = fmt.Sprintf(
+				"This project is currently locked by an unapplied plan from pull %s. This pull request is number %d in line for the lock.\n\nOnce the lock is released, this pull request will be planned automatically.",
+				link,
+				lockAttempt.QueuePosition)
+		}
 		return &TryLockResponse{
 			LockAcquired:      false,
 			LockFailureReason: failureMsg,
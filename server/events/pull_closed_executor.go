@@ -52,7 +52,7 @@ type PullCleaner interface {
 type PullClosedExecutor struct {
 	Locker                   locking.Locker
 	Logger                   logging.Logger
-	DB                       *db.BoltDB
+	DB                       db.Database
 	PullClosedTemplate       PullCleanupTemplate
 	LogStreamResourceCleaner ResourceCleaner
 	VCSClient                vcs.Client
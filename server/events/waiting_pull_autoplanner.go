@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// WaitingPullAutoplanner autoplans pulls which were handed locks they were waiting in line for.
+type WaitingPullAutoplanner struct {
+	GithubPullGetter  vcs.GithubPullRequestGetter
+	EventParser       EventParsing
+	CommandRunner     CommandRunner
+	PullStatusFetcher PullStatusFetcher
+	// Locker releases the locks the autoplan didn't claim so they're handed to the next
+	// pull in line.
+	Locker locking.Locker
+	Logger logging.Logger
+}
+
+// Autoplan plans the pull which was handed locks in the background, since locks are handed
+// off while releasing them.
+func (a *WaitingPullAutoplanner) Autoplan(locks []models.ProjectLock) {
+	if len(locks) == 0 {
+		return
+	}
+
+	go func() {
+		if err := a.autoplan(context.Background(), locks); err != nil {
+			a.Logger.Error(fmt.Sprintf("autoplanning pull %d which was waiting for locks: %s", locks[0].Pull.Num, err))
+		}
+	}()
+}
+
+func (a *WaitingPullAutoplanner) autoplan(ctx context.Context, locks []models.ProjectLock) error {
+	// the pull was stored when it started waiting so it's fetched again for its latest commit
+	ghPull, err := a.GithubPullGetter.GetPullRequest(locks[0].Pull.BaseRepo, locks[0].Pull.Num)
+	if err != nil {
+		return err
+	}
+
+	pull, baseRepo, headRepo, err := a.EventParser.ParseGithubPull(ghPull)
+	if err != nil {
+		return err
+	}
+
+	// the pull closed event may have already been handled when the locks were handed off, so they're
+	// released here for the next pull in line
+	if pull.State != models.OpenPullState {
+		for _, lock := range locks {
+			a.unlock(pull, lock, "was closed")
+		}
+		return nil
+	}
+
+	a.CommandRunner.RunAutoplanCommand(ctx, baseRepo, headRepo, pull, locks[0].User, time.Now(), 0)
+	return a.releaseUnclaimed(pull, locks)
+}
+
+// releaseUnclaimed releases the locks of projects the autoplan didn't plan, e.g. when autoplan
+// is disabled, the project's when_modified doesn't match or the pull was waiting for a project
+// it planned with -d or -p. The pull takes the lock again when it plans the project.
+func (a *WaitingPullAutoplanner) releaseUnclaimed(pull models.PullRequest, locks []models.ProjectLock) error {
+	status, err := a.PullStatusFetcher.GetPullStatus(pull)
+	if err != nil {
+		return err
+	}
+
+	for _, lock := range locks {
+		if isPlanned(status, pull, lock) {
+			continue
+		}
+
+		a.unlock(pull, lock, "didn't plan")
+	}
+	return nil
+}
+
+func (a *WaitingPullAutoplanner) unlock(pull models.PullRequest, lock models.ProjectLock, reason string) {
+	if _, err := a.Locker.Unlock(locking.Key(lock.Project, lock.Workspace)); err != nil {
+		a.Logger.Error(fmt.Sprintf("releasing lock for %s/%s which pull %d %s: %s", lock.Project.Path, lock.Workspace, pull.Num, reason, err))
+	}
+}
+
+func isPlanned(status *models.PullStatus, pull models.PullRequest, lock models.ProjectLock) bool {
+	// statuses of earlier commits don't count
+	if status == nil || status.Pull.HeadCommit != pull.HeadCommit {
+		return false
+	}
+
+	for _, p := range status.Projects {
+		if p.RepoRelDir != lock.Project.Path || p.Workspace != lock.Workspace {
+			continue
+		}
+		switch p.Status {
+		case models.PlannedPlanStatus, models.PassedPolicyCheckStatus, models.ErroredPolicyCheckStatus:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	. "github.com/petergtz/pegomock"
+	lockmocks "github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+type autoplanRecorder struct {
+	events.CommandRunner
+	pulls chan models.PullRequest
+}
+
+func (r *autoplanRecorder) RunAutoplanCommand(_ context.Context, _ models.Repo, _ models.Repo, pull models.PullRequest, _ models.User, _ time.Time, _ int64) {
+	r.pulls <- pull
+}
+
+func TestWaitingPullAutoplanner_Autoplan(t *testing.T) {
+	repo := models.Repo{FullName: "owner/repo"}
+	planned := models.ProjectLock{Project: models.NewProject("owner/repo", "planned"), Workspace: "default", Pull: models.PullRequest{Num: 1, HeadCommit: "old", BaseRepo: repo}}
+	unplanned := models.ProjectLock{Project: models.NewProject("owner/repo", "unplanned"), Workspace: "default", Pull: planned.Pull}
+	errored := models.ProjectLock{Project: models.NewProject("owner/repo", "errored"), Workspace: "default", Pull: planned.Pull}
+
+	cases := []struct {
+		description string
+		state       models.PullRequestState
+		status      *models.PullStatus
+		expAutoplan bool
+		expReleased []models.ProjectLock
+	}{
+		{
+			description: "open",
+			state:       models.OpenPullState,
+			status: &models.PullStatus{
+				Pull: models.PullRequest{HeadCommit: "new"},
+				Projects: []models.ProjectStatus{
+					{RepoRelDir: "planned", Workspace: "default", Status: models.PlannedPlanStatus},
+					{RepoRelDir: "errored", Workspace: "default", Status: models.ErroredPlanStatus},
+				},
+			},
+			expAutoplan: true,
+			expReleased: []models.ProjectLock{unplanned, errored},
+		},
+		{
+			description: "planned at an earlier commit",
+			state:       models.OpenPullState,
+			status: &models.PullStatus{
+				Pull: models.PullRequest{HeadCommit: "old"},
+				Projects: []models.ProjectStatus{
+					{RepoRelDir: "planned", Workspace: "default", Status: models.PlannedPlanStatus},
+				},
+			},
+			expAutoplan: true,
+			expReleased: []models.ProjectLock{planned, unplanned, errored},
+		},
+		{
+			description: "no status",
+			state:       models.OpenPullState,
+			expAutoplan: true,
+			expReleased: []models.ProjectLock{planned, unplanned, errored},
+		},
+		{
+			description: "closed",
+			state:       models.ClosedPullState,
+			expReleased: []models.ProjectLock{planned, unplanned, errored},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			pullGetter := vcsmocks.NewMockGithubPullRequestGetter()
+			eventParser := mocks.NewMockEventParsing()
+			statusFetcher := mocks.NewMockPullStatusFetcher()
+			locker := lockmocks.NewMockLocker()
+			recorder := &autoplanRecorder{pulls: make(chan models.PullRequest, 1)}
+			autoplanner := &events.WaitingPullAutoplanner{
+				GithubPullGetter:  pullGetter,
+				EventParser:       eventParser,
+				CommandRunner:     recorder,
+				PullStatusFetcher: statusFetcher,
+				Locker:            locker,
+				Logger:            logging.NewNoopCtxLogger(t),
+			}
+
+			ghPull := &github.PullRequest{Number: github.Int(1)}
+			latest := models.PullRequest{Num: 1, HeadCommit: "new", BaseRepo: repo, State: c.state}
+			When(pullGetter.GetPullRequest(repo, 1)).ThenReturn(ghPull, nil)
+			When(eventParser.ParseGithubPull(ghPull)).ThenReturn(latest, repo, repo, nil)
+			When(statusFetcher.GetPullStatus(latest)).ThenReturn(c.status, nil)
+
+			autoplanner.Autoplan([]models.ProjectLock{planned, unplanned, errored})
+
+			if !c.expAutoplan {
+				eventParser.VerifyWasCalledEventually(Once(), time.Second).ParseGithubPull(ghPull)
+				select {
+				case <-recorder.pulls:
+					t.Fatal("closed pulls shouldn't be autoplanned")
+				case <-time.After(50 * time.Millisecond):
+				}
+			} else {
+				select {
+				case pull := <-recorder.pulls:
+					// the latest commit is planned
+					Equals(t, latest, pull)
+				case <-time.After(time.Second):
+					t.Fatal("timed out waiting for autoplan")
+				}
+			}
+
+			// locks which weren't planned are released for the next pull in line
+			for _, l := range c.expReleased {
+				locker.VerifyWasCalledEventually(Once(), time.Second).Unlock("owner/repo/" + l.Project.Path + "/default")
+			}
+			locker.VerifyWasCalled(Times(len(c.expReleased))).Unlock(AnyString())
+		})
+	}
+}
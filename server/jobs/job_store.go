@@ -32,8 +32,8 @@ type JobStore interface {
 	AppendOutput(jobID string, output string) error
 
 	// Sets a job status to complete and triggers any associated workflow,
-	// e.g: if the status is complete, the job is flushed to the associated storage backend
-	SetJobCompleteStatus(ctx context.Context, jobID string, status JobStatus) error
+	// e.g: if the status is complete, the job is flushed to the associated storage backend and indexed under the repo
+	SetJobCompleteStatus(ctx context.Context, jobID string, repoFullName string, status JobStatus) error
 
 	// Removes a job from the store
 	RemoveJob(jobID string)
@@ -94,7 +94,7 @@ func (m *InMemoryJobStore) AppendOutput(jobID string, output string) error {
 	return nil
 }
 
-func (m *InMemoryJobStore) SetJobCompleteStatus(ctx context.Context, jobID string, status JobStatus) error {
+func (m *InMemoryJobStore) SetJobCompleteStatus(ctx context.Context, jobID string, repoFullName string, status JobStatus) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -149,8 +149,8 @@ func (s StorageBackendJobStore) AppendOutput(jobID string, output string) error
 	return s.JobStore.AppendOutput(jobID, output)
 }
 
-func (s *StorageBackendJobStore) SetJobCompleteStatus(ctx context.Context, jobID string, status JobStatus) error {
-	if err := s.JobStore.SetJobCompleteStatus(ctx, jobID, status); err != nil {
+func (s *StorageBackendJobStore) SetJobCompleteStatus(ctx context.Context, jobID string, repoFullName string, status JobStatus) error {
+	if err := s.JobStore.SetJobCompleteStatus(ctx, jobID, repoFullName, status); err != nil {
 		return err
 	}
 
@@ -166,8 +166,16 @@ func (s *StorageBackendJobStore) SetJobCompleteStatus(ctx context.Context, jobID
 	}
 
 	// Remove from memory if successfully persisted
-	if ok {
-		s.JobStore.RemoveJob(jobID)
+	if !ok {
+		return nil
+	}
+	s.JobStore.RemoveJob(jobID)
+
+	if repoFullName == "" {
+		return nil
+	}
+	if err := s.storageBackend.Index(ctx, jobID, repoFullName); err != nil {
+		return errors.Wrapf(err, "indexing job: %s", jobID)
 	}
 	return nil
 }
@@ -143,7 +143,7 @@ func TestJobStore_UpdateJobStatus(t *testing.T) {
 		storageBackend := mocks.NewMockStorageBackend()
 		When(storageBackend.Write(matchers.AnyContextContext(), AnyString(), matchers.AnySliceOfString())).ThenReturn(false, storageBackendErr)
 		jobStore := jobs.NewTestJobStore(storageBackend, jobsMap)
-		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, jobs.Complete)
+		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, "owner/repo", jobs.Complete)
 
 		// Assert storage backend error
 		assert.EqualError(t, err, expecterErr.Error())
@@ -168,7 +168,7 @@ func TestJobStore_UpdateJobStatus(t *testing.T) {
 		// Setup storage backend
 		storageBackend := &jobs.NoopStorageBackend{}
 		jobStore := jobs.NewTestJobStore(storageBackend, jobsMap)
-		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, jobs.Complete)
+		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, "owner/repo", jobs.Complete)
 
 		assert.Nil(t, err)
 
@@ -180,6 +180,8 @@ func TestJobStore_UpdateJobStatus(t *testing.T) {
 	})
 
 	t.Run("delete from memory when persist succeeds", func(t *testing.T) {
+		RegisterMockTestingT(t)
+
 		// Create new job and add it to store
 		jobID := "1234"
 		job := &jobs.Job{
@@ -193,8 +195,9 @@ func TestJobStore_UpdateJobStatus(t *testing.T) {
 		storageBackend := mocks.NewMockStorageBackend()
 		When(storageBackend.Write(matchers.AnyContextContext(), AnyString(), matchers.AnySliceOfString())).ThenReturn(true, nil)
 		jobStore := jobs.NewTestJobStore(storageBackend, jobsMap)
-		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, jobs.Complete)
+		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, "owner/repo", jobs.Complete)
 		assert.Nil(t, err)
+		storageBackend.VerifyWasCalledOnce().Index(context.Background(), jobID, "owner/repo")
 
 		When(storageBackend.Read(context.Background(), jobID)).ThenReturn([]string{}, nil)
 		gotJob, err := jobStore.Get(context.Background(), jobID)
@@ -208,7 +211,26 @@ func TestJobStore_UpdateJobStatus(t *testing.T) {
 		jobID := "1234"
 		expectedErrString := fmt.Sprintf("job: %s does not exist", jobID)
 
-		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, jobs.Complete)
+		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, "owner/repo", jobs.Complete)
 		assert.EqualError(t, err, expectedErrString)
 	})
+
+	t.Run("error when indexing fails", func(t *testing.T) {
+		jobID := "1234"
+		jobsMap := map[string]*jobs.Job{
+			jobID: {Output: []string{"a"}, Status: jobs.Processing},
+		}
+
+		storageBackend := mocks.NewMockStorageBackend()
+		When(storageBackend.Write(matchers.AnyContextContext(), AnyString(), matchers.AnySliceOfString())).ThenReturn(true, nil)
+		When(storageBackend.Index(matchers.AnyContextContext(), AnyString(), AnyString())).ThenReturn(fmt.Errorf("random error"))
+		jobStore := jobs.NewTestJobStore(storageBackend, jobsMap)
+
+		err := jobStore.SetJobCompleteStatus(context.Background(), jobID, "owner/repo", jobs.Complete)
+		assert.EqualError(t, err, "indexing job: 1234: random error")
+
+		// logs are persisted so the job is removed from memory regardless
+		_, ok := jobsMap[jobID]
+		assert.False(t, ok)
+	})
 }
@@ -68,11 +68,11 @@ func (mock *MockJobStore) RemoveJob(_param0 string) {
 	pegomock.GetGenericMockFrom(mock).Invoke("RemoveJob", params, []reflect.Type{})
 }
 
-func (mock *MockJobStore) SetJobCompleteStatus(_param0 context.Context, _param1 string, _param2 jobs.JobStatus) error {
+func (mock *MockJobStore) SetJobCompleteStatus(_param0 context.Context, _param1 string, _param2 string, _param3 jobs.JobStatus) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockJobStore().")
 	}
-	params := []pegomock.Param{_param0, _param1, _param2}
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
 	result := pegomock.GetGenericMockFrom(mock).Invoke("SetJobCompleteStatus", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
 	var ret0 error
 	if len(result) != 0 {
@@ -209,8 +209,8 @@ func (c *MockJobStore_RemoveJob_OngoingVerification) GetAllCapturedArguments() (
 	return
 }
 
-func (verifier *VerifierMockJobStore) SetJobCompleteStatus(_param0 context.Context, _param1 string, _param2 jobs.JobStatus) *MockJobStore_SetJobCompleteStatus_OngoingVerification {
-	params := []pegomock.Param{_param0, _param1, _param2}
+func (verifier *VerifierMockJobStore) SetJobCompleteStatus(_param0 context.Context, _param1 string, _param2 string, _param3 jobs.JobStatus) *MockJobStore_SetJobCompleteStatus_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "SetJobCompleteStatus", params, verifier.timeout)
 	return &MockJobStore_SetJobCompleteStatus_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
 }
@@ -220,12 +220,12 @@ type MockJobStore_SetJobCompleteStatus_OngoingVerification struct {
 	methodInvocations []pegomock.MethodInvocation
 }
 
-func (c *MockJobStore_SetJobCompleteStatus_OngoingVerification) GetCapturedArguments() (context.Context, string, jobs.JobStatus) {
-	_param0, _param1, _param2 := c.GetAllCapturedArguments()
-	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+func (c *MockJobStore_SetJobCompleteStatus_OngoingVerification) GetCapturedArguments() (context.Context, string, string, jobs.JobStatus) {
+	_param0, _param1, _param2, _param3 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1], _param3[len(_param3)-1]
 }
 
-func (c *MockJobStore_SetJobCompleteStatus_OngoingVerification) GetAllCapturedArguments() (_param0 []context.Context, _param1 []string, _param2 []jobs.JobStatus) {
+func (c *MockJobStore_SetJobCompleteStatus_OngoingVerification) GetAllCapturedArguments() (_param0 []context.Context, _param1 []string, _param2 []string, _param3 []jobs.JobStatus) {
 	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
 	if len(params) > 0 {
 		_param0 = make([]context.Context, len(c.methodInvocations))
@@ -236,9 +236,13 @@ func (c *MockJobStore_SetJobCompleteStatus_OngoingVerification) GetAllCapturedAr
 		for u, param := range params[1] {
 			_param1[u] = param.(string)
 		}
-		_param2 = make([]jobs.JobStatus, len(c.methodInvocations))
+		_param2 = make([]string, len(c.methodInvocations))
 		for u, param := range params[2] {
-			_param2[u] = param.(jobs.JobStatus)
+			_param2[u] = param.(string)
+		}
+		_param3 = make([]jobs.JobStatus, len(c.methodInvocations))
+		for u, param := range params[3] {
+			_param3[u] = param.(jobs.JobStatus)
 		}
 	}
 	return
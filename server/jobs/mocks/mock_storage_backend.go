@@ -25,6 +25,21 @@ func NewMockStorageBackend(options ...pegomock.Option) *MockStorageBackend {
 func (mock *MockStorageBackend) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
 func (mock *MockStorageBackend) FailHandler() pegomock.FailHandler      { return mock.fail }
 
+func (mock *MockStorageBackend) Index(_param0 context.Context, _param1 string, _param2 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockStorageBackend().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Index", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockStorageBackend) Read(_param0 context.Context, _param1 string) ([]string, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockStorageBackend().")
@@ -100,6 +115,41 @@ type VerifierMockStorageBackend struct {
 	timeout                time.Duration
 }
 
+func (verifier *VerifierMockStorageBackend) Index(_param0 context.Context, _param1 string, _param2 string) *MockStorageBackend_Index_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Index", params, verifier.timeout)
+	return &MockStorageBackend_Index_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockStorageBackend_Index_OngoingVerification struct {
+	mock              *MockStorageBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockStorageBackend_Index_OngoingVerification) GetCapturedArguments() (context.Context, string, string) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *MockStorageBackend_Index_OngoingVerification) GetAllCapturedArguments() (_param0 []context.Context, _param1 []string, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]context.Context, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(context.Context)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockStorageBackend) Read(_param0 context.Context, _param1 string) *MockStorageBackend_Read_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Read", params, verifier.timeout)
@@ -24,6 +24,9 @@ type StorageBackend interface {
 
 	// Write logs to the storage backend
 	Write(ctx context.Context, key string, logs []string) (bool, error)
+
+	// Index records the repo logs written to key belong to so retention can be applied per repo
+	Index(ctx context.Context, key string, repoFullName string) error
 }
 
 func NewStorageBackend(client *storage.Client, logger logging.Logger, featureAllocator feature.Allocator, scope tally.Scope) (StorageBackend, error) {
@@ -72,6 +75,13 @@ func (s *storageBackend) Write(ctx context.Context, key string, logs []string) (
 	return true, nil
 }
 
+func (s *storageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	if err := s.client.Set(ctx, storage.RepoIndexKey(repoFullName, key), []byte{}); err != nil {
+		return errors.Wrapf(err, "indexing object for job: %s", key)
+	}
+	return nil
+}
+
 // Adds instrumentation to storage backend
 type InstrumentedStorageBackend struct {
 	StorageBackend
@@ -106,6 +116,14 @@ func (i *InstrumentedStorageBackend) Write(ctx context.Context, key string, logs
 	return ok, err
 }
 
+func (i *InstrumentedStorageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	err := i.StorageBackend.Index(ctx, key, repoFullName)
+	if err != nil {
+		i.scope.Counter("index_failure").Inc(1)
+	}
+	return err
+}
+
 // Used when log persistence is not configured
 type NoopStorageBackend struct{}
 
@@ -116,3 +134,7 @@ func (s *NoopStorageBackend) Read(ctx context.Context, key string) ([]string, er
 func (s *NoopStorageBackend) Write(ctx context.Context, key string, logs []string) (bool, error) {
 	return false, nil
 }
+
+func (s *NoopStorageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	return nil
+}
@@ -68,7 +68,7 @@ func NewVCSEventsController(
 		WorkerProxy:      pullEventSNSProxy,
 		VCSStatusUpdater: vcsStatusUpdater,
 	}
-	prSignaler := &pr.WorkflowSignaler{TemporalClient: temporalClient, DefaultTFVersion: defaultTFVersion}
+	prSignaler := &pr.WorkflowSignaler{TemporalClient: temporalClient, DefaultTFVersion: defaultTFVersion, ContinueAsNewThreshold: globalCfg.Temporal.ContinueAsNewThreshold}
 	prRequirementChecker := requirement.NewPRAggregate(globalCfg)
 	modifiedPullHandler := gateway_handlers.NewModifiedPullHandler(logger, asyncScheduler, rootConfigBuilder, globalCfg, prRequirementChecker, prSignaler, legacyHandler, featureAllocator)
 
@@ -12,6 +12,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/metrics"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -27,6 +28,7 @@ type ExecutorService struct {
 	garbageCollector      JobDefinition
 	rateLimitPublisher    JobDefinition
 	runtimeStatsPublisher JobDefinition
+	lockReaper            JobDefinition
 }
 
 func NewExecutorService(
@@ -36,6 +38,10 @@ func NewExecutorService(
 	closedPullCleaner events.PullCleaner,
 	openPullCleaner events.PullCleaner,
 	githubClient *vcs.GithubClient,
+	locker locking.Locker,
+	deleteLockCommand events.DeleteLockCommand,
+	vcsClient vcs.Client,
+	lockAbandonedAfter time.Duration,
 ) *ExecutorService {
 	scheduledScope := statsScope.SubScope("scheduled")
 	garbageCollector := &GarbageCollector{
@@ -72,11 +78,27 @@ func NewExecutorService(
 		Period: 10 * time.Second,
 	}
 
+	lockReaper := NewLockReaper(
+		locker,
+		deleteLockCommand,
+		githubClient,
+		vcsClient,
+		lockAbandonedAfter,
+		scheduledScope.SubScope("lockreaper"),
+		log,
+	)
+
+	lockReaperJob := JobDefinition{
+		Job:    lockReaper,
+		Period: 10 * time.Minute,
+	}
+
 	return &ExecutorService{
 		log:                   log,
 		garbageCollector:      garbageCollectorJob,
 		rateLimitPublisher:    rateLimitPublisherJob,
 		runtimeStatsPublisher: runtimeStatsPublisherJob,
+		lockReaper:            lockReaperJob,
 	}
 }
 
@@ -95,6 +117,7 @@ func (s *ExecutorService) Run() {
 	s.runScheduledJob(ctx, &wg, s.garbageCollector)
 	s.runScheduledJob(ctx, &wg, s.rateLimitPublisher)
 	s.runScheduledJob(ctx, &wg, s.runtimeStatsPublisher)
+	s.runScheduledJob(ctx, &wg, s.lockReaper)
 
 	interrupt := make(chan os.Signal, 1)
 
@@ -0,0 +1,192 @@
+package scheduled
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/metrics"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/uber-go/tally/v4"
+)
+
+var lockReapedTemplate = template.Must(template.New("").Parse(
+	"{{ .Reason }}. Atlantis has released the locks for the following projects and workspaces:\n" +
+		"{{ range .Locks }}\n" +
+		"- dir: `{{ .Project.Path }}` workspace: `{{ .Workspace }}`{{ end }}"))
+
+type lockReapedData struct {
+	Reason string
+	Locks  []models.ProjectLock
+}
+
+// LockReaper releases locks which have expired or which are held by pull requests
+// which are closed, merged or abandoned. Released locks are deleted the same way as
+// unlocking them manually, so their plans are discarded.
+//
+// Unlike the GarbageCollector it works off the locks in the database rather than the
+// working dirs on disk, so it also releases locks taken by other servers.
+type LockReaper struct {
+	locker            locking.Locker
+	deleteLockCommand events.DeleteLockCommand
+	githubClient      vcs.GithubPullRequestGetter
+	vcsClient         vcs.Client
+	stats             tally.Scope
+	log               logging.Logger
+
+	// abandonedAfter is how long an open pull request can go without being
+	// updated before its locks are released, it's disabled if it's 0.
+	abandonedAfter time.Duration
+}
+
+func NewLockReaper(
+	locker locking.Locker,
+	deleteLockCommand events.DeleteLockCommand,
+	githubClient vcs.GithubPullRequestGetter,
+	vcsClient vcs.Client,
+	abandonedAfter time.Duration,
+	stats tally.Scope,
+	log logging.Logger,
+) *LockReaper {
+	return &LockReaper{
+		locker:            locker,
+		deleteLockCommand: deleteLockCommand,
+		githubClient:      githubClient,
+		vcsClient:         vcsClient,
+		abandonedAfter:    abandonedAfter,
+		stats:             stats,
+		log:               log,
+	}
+}
+
+func (r *LockReaper) Run() {
+	errCounter := r.stats.Counter(metrics.ExecutionErrorMetric)
+	reapedCounter := r.stats.Counter("locks.reaped")
+
+	locks, err := r.locker.List()
+	if err != nil {
+		r.log.Error(fmt.Sprintf("error listing locks %s", err))
+		errCounter.Inc(1)
+		return
+	}
+
+	now := time.Now()
+	pulls := make(map[string]models.PullRequest)
+	var pullKeys []string
+	var expired []string
+	for key, lock := range locks {
+		if lock.Expired(now) {
+			expired = append(expired, key)
+			continue
+		}
+
+		pullKey := fmt.Sprintf("%s#%d", lock.Pull.BaseRepo.FullName, lock.Pull.Num)
+		if _, ok := pulls[pullKey]; !ok {
+			pulls[pullKey] = lock.Pull
+			pullKeys = append(pullKeys, pullKey)
+		}
+	}
+
+	// sort so locks are released in a deterministic order
+	sort.Strings(expired)
+	sort.Strings(pullKeys)
+
+	for _, key := range expired {
+		// the lock is only released if it's still expired since it may have been released and
+		// taken by another pull since it was listed
+		lock, err := r.deleteLockCommand.DeleteExpiredLock(key, now)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("error releasing expired lock %s: %s", key, err))
+			errCounter.Inc(1)
+			continue
+		}
+		if lock == nil {
+			continue
+		}
+
+		reapedCounter.Inc(1)
+		r.comment(lock.Pull, "The lock expired", []models.ProjectLock{*lock})
+	}
+
+	for _, pullKey := range pullKeys {
+		pull := pulls[pullKey]
+		reason, err := r.reapReason(pull, now)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("error checking %s: %s", pullKey, err))
+			errCounter.Inc(1)
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+
+		released, err := r.deleteLockCommand.DeleteLocksByPull(pull.BaseRepo.FullName, pull.Num)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("error releasing locks for %s: %s", pullKey, err))
+			errCounter.Inc(1)
+			continue
+		}
+
+		// the pull may have been cleaned up since the locks were listed
+		if len(released) == 0 {
+			continue
+		}
+
+		reapedCounter.Inc(int64(len(released)))
+		r.comment(pull, reason, released)
+	}
+}
+
+// reapReason returns why the pull's locks should be released, it's empty if they shouldn't be.
+func (r *LockReaper) reapReason(pull models.PullRequest, now time.Time) (string, error) {
+	// a pull which can't be found is kept since github also returns not found when
+	// the app has lost access to the repo
+	ghPull, err := r.githubClient.GetPullRequestFromName(pull.BaseRepo.Name, pull.BaseRepo.Owner, pull.Num)
+	if err != nil {
+		return "", err
+	}
+
+	if ghPull.GetMerged() {
+		return "Pull request has been merged", nil
+	}
+	if ghPull.GetState() == "closed" {
+		return "Pull request has been closed", nil
+	}
+	if r.abandonedAfter > 0 && ghPull.GetUpdatedAt().Add(r.abandonedAfter).Before(now) {
+		return fmt.Sprintf("Pull request has not been updated for %s", formatAge(r.abandonedAfter)), nil
+	}
+	return "", nil
+}
+
+func (r *LockReaper) comment(pull models.PullRequest, reason string, locks []models.ProjectLock) {
+	var buf bytes.Buffer
+	if err := lockReapedTemplate.Execute(&buf, lockReapedData{Reason: reason, Locks: locks}); err != nil {
+		r.log.Error(fmt.Sprintf("error rendering lock reaped comment %s", err))
+		return
+	}
+
+	// the pull on the lock may be stale, the comment only needs its repo and number
+	if err := r.vcsClient.CreateComment(pull.BaseRepo, pull.Num, buf.String(), ""); err != nil {
+		r.log.Error(fmt.Sprintf("error commenting on %s#%d: %s", pull.BaseRepo.FullName, pull.Num, err))
+		r.stats.Counter(metrics.ExecutionErrorMetric).Inc(1)
+	}
+}
+
+// formatAge renders whole days as days rather than hours
+func formatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	if d%day == 0 {
+		days := int(d / day)
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return d.String()
+}
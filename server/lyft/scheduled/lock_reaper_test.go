@@ -0,0 +1,169 @@
+package scheduled_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	. "github.com/petergtz/pegomock"
+	lockingmocks "github.com/runatlantis/atlantis/server/core/locking/mocks"
+	eventmocks "github.com/runatlantis/atlantis/server/events/mocks"
+	eventmatchers "github.com/runatlantis/atlantis/server/events/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/vcs/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/lyft/scheduled"
+	"github.com/uber-go/tally/v4"
+)
+
+var repo = models.Repo{
+	FullName: "owner/repo",
+	Owner:    "owner",
+	Name:     "repo",
+}
+
+func projectLock(pullNum int, path string, expiresAt time.Time) models.ProjectLock {
+	return models.ProjectLock{
+		Project:   models.NewProject(repo.FullName, path),
+		Workspace: "default",
+		Pull: models.PullRequest{
+			Num:      pullNum,
+			BaseRepo: repo,
+		},
+		Time:      time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func newReaper(t *testing.T, abandonedAfter time.Duration) (*scheduled.LockReaper, *lockingmocks.MockLocker, *eventmocks.MockDeleteLockCommand, *vcsmocks.MockGithubPullRequestGetter, *vcsmocks.MockClient) {
+	RegisterMockTestingT(t)
+	locker := lockingmocks.NewMockLocker()
+	deleteLockCommand := eventmocks.NewMockDeleteLockCommand()
+	githubClient := vcsmocks.NewMockGithubPullRequestGetter()
+	vcsClient := vcsmocks.NewMockClient()
+	reaper := scheduled.NewLockReaper(locker, deleteLockCommand, githubClient, vcsClient, abandonedAfter, tally.NewTestScope("test", map[string]string{}), logging.NewNoopCtxLogger(t))
+	return reaper, locker, deleteLockCommand, githubClient, vcsClient
+}
+
+func TestLockReaper_Expired(t *testing.T) {
+	reaper, locker, deleteLockCommand, githubClient, vcsClient := newReaper(t, 0)
+
+	expired := projectLock(1, "expired", time.Now().Add(-time.Minute))
+	active := projectLock(2, "active", time.Now().Add(time.Hour))
+	When(locker.List()).ThenReturn(map[string]models.ProjectLock{
+		"owner/repo/expired/default": expired,
+		"owner/repo/active/default":  active,
+	}, nil)
+	When(deleteLockCommand.DeleteExpiredLock(EqString("owner/repo/expired/default"), eventmatchers.AnyTimeTime())).ThenReturn(&expired, nil)
+	When(githubClient.GetPullRequestFromName("repo", "owner", 2)).ThenReturn(&github.PullRequest{
+		State:     github.String("open"),
+		UpdatedAt: timePtr(time.Now()),
+	}, nil)
+
+	reaper.Run()
+
+	deleteLockCommand.VerifyWasCalledOnce().DeleteExpiredLock(EqString("owner/repo/expired/default"), eventmatchers.AnyTimeTime())
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteExpiredLock(EqString("owner/repo/active/default"), eventmatchers.AnyTimeTime())
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteLocksByPull(AnyString(), AnyInt())
+	vcsClient.VerifyWasCalledOnce().CreateComment(repo, 1, "The lock expired. Atlantis has released the locks for the following projects and workspaces:\n\n- dir: `expired` workspace: `default`", "")
+}
+
+func TestLockReaper_ExpiredLockTakenSinceListed(t *testing.T) {
+	reaper, locker, deleteLockCommand, _, vcsClient := newReaper(t, 0)
+
+	expired := projectLock(1, "path", time.Now().Add(-time.Minute))
+	When(locker.List()).ThenReturn(map[string]models.ProjectLock{
+		"owner/repo/path/default": expired,
+	}, nil)
+	// the lock was released and taken by another pull so it isn't expired anymore
+	When(deleteLockCommand.DeleteExpiredLock(EqString("owner/repo/path/default"), eventmatchers.AnyTimeTime())).ThenReturn(nil, nil)
+
+	reaper.Run()
+
+	locker.VerifyWasCalled(Never()).Unlock(AnyString())
+	vcsClient.VerifyWasCalled(Never()).CreateComment(matchers.AnyModelsRepo(), AnyInt(), AnyString(), AnyString())
+}
+
+func TestLockReaper_Pulls(t *testing.T) {
+	cases := []struct {
+		description string
+		pull        *github.PullRequest
+		err         error
+		expReason   string
+	}{
+		{
+			description: "merged",
+			pull: &github.PullRequest{
+				State:  github.String("closed"),
+				Merged: github.Bool(true),
+			},
+			expReason: "Pull request has been merged",
+		},
+		{
+			description: "closed",
+			pull: &github.PullRequest{
+				State: github.String("closed"),
+			},
+			expReason: "Pull request has been closed",
+		},
+		{
+			// github also returns not found when the app can't access the repo
+			description: "not found",
+			err:         &vcs.PullRequestNotFound{Err: errors.New("404")},
+		},
+		{
+			description: "abandoned",
+			pull: &github.PullRequest{
+				State:     github.String("open"),
+				UpdatedAt: timePtr(time.Now().Add(-8 * 24 * time.Hour)),
+			},
+			expReason: "Pull request has not been updated for 7 days",
+		},
+		{
+			description: "open",
+			pull: &github.PullRequest{
+				State:     github.String("open"),
+				UpdatedAt: timePtr(time.Now().Add(-6 * 24 * time.Hour)),
+			},
+		},
+		{
+			description: "error",
+			err:         errors.New("error"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			reaper, locker, deleteLockCommand, githubClient, vcsClient := newReaper(t, 7*24*time.Hour)
+
+			first := projectLock(1, "first", time.Time{})
+			second := projectLock(1, "second", time.Time{})
+			When(locker.List()).ThenReturn(map[string]models.ProjectLock{
+				"owner/repo/first/default":  first,
+				"owner/repo/second/default": second,
+			}, nil)
+			When(githubClient.GetPullRequestFromName("repo", "owner", 1)).ThenReturn(c.pull, c.err)
+			When(deleteLockCommand.DeleteLocksByPull("owner/repo", 1)).ThenReturn([]models.ProjectLock{first, second}, nil)
+
+			reaper.Run()
+
+			// the pull is only fetched once for all of its locks
+			githubClient.VerifyWasCalledOnce().GetPullRequestFromName("repo", "owner", 1)
+			if c.expReason == "" {
+				deleteLockCommand.VerifyWasCalled(Never()).DeleteLocksByPull(AnyString(), AnyInt())
+				vcsClient.VerifyWasCalled(Never()).CreateComment(matchers.AnyModelsRepo(), AnyInt(), AnyString(), AnyString())
+				return
+			}
+
+			deleteLockCommand.VerifyWasCalledOnce().DeleteLocksByPull("owner/repo", 1)
+			vcsClient.VerifyWasCalledOnce().CreateComment(repo, 1, c.expReason+". Atlantis has released the locks for the following projects and workspaces:\n\n- dir: `first` workspace: `default`\n- dir: `second` workspace: `default`", "")
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -2,45 +2,50 @@ package api
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/requirement"
 )
 
 // Controller is a simple generic controller that converts a request and hands it off
 // providing a level of consistency across API handling.
-type Controller[Request any] struct {
+type Controller[Request any, Response any] struct {
 	RequestConverter RequestConverter[Request]
-	Handler          Handler[Request]
+	Handler          Handler[Request, Response]
+	Logger           logging.Logger
 }
 
-type Handler[Request any] interface {
-	Handle(ctx context.Context, request Request) error
+type Handler[Request any, Response any] interface {
+	Handle(ctx context.Context, request Request) (Response, error)
 }
 
 type RequestConverter[T any] interface {
 	Convert(from *http.Request) (T, error)
 }
 
-func (c *Controller[Request]) Handle(w http.ResponseWriter, request *http.Request) {
+func (c *Controller[Request, Response]) Handle(w http.ResponseWriter, request *http.Request) {
 	internalRequest, err := c.RequestConverter.Convert(request)
 
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintln(w, errors.Wrap(err, "converting request"))
+		writeError(w, c.Logger, http.StatusBadRequest, errors.Wrap(err, "converting request"))
 		return
 	}
 
-	err = c.Handler.Handle(request.Context(), internalRequest)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintln(w, errors.Wrap(err, "handling request"))
+	resp, err := c.Handler.Handle(request.Context(), internalRequest)
+
+	// ie. the deployed roots are frozen
+	var forbiddenErr requirement.ForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		writeError(w, c.Logger, http.StatusForbidden, errors.Wrap(err, "handling request"))
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if err != nil {
+		writeError(w, c.Logger, http.StatusInternalServerError, errors.Wrap(err, "handling request"))
+		return
+	}
 
-	//TODO: return something a bit more structured, like json
-	fmt.Fprintln(w, "Request Submitted!")
+	writeJSON(w, c.Logger, http.StatusOK, resp)
 }
@@ -2,12 +2,19 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
-	"github.com/runatlantis/atlantis/server/neptune/sync"
 	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	internalGH "github.com/runatlantis/atlantis/server/vcs/provider/github"
+	"go.temporal.io/sdk/converter"
 )
 
 const (
@@ -15,46 +22,152 @@ const (
 	OwnerVarKey    = "owner"
 	UsernameVarKey = "username"
 	RootVarKey     = "root"
+	IDVarKey       = "id"
+
+	statusIDDelim = "||"
 )
 
 type rootDeployer interface {
-	Deploy(ctx context.Context, deployOptions deploy.RootDeployOptions) error
+	DeployRoots(ctx context.Context, deployOptions deploy.RootDeployOptions) ([]deploy.RootDeployment, error)
+}
+
+type Deployment struct {
+	// ID can be used to fetch the status of this deployment
+	ID         string `json:"id"`
+	Root       string `json:"root"`
+	WorkflowID string `json:"workflow_id"`
+	RunID      string `json:"run_id"`
 }
 
-type scheduler interface {
-	Schedule(ctx context.Context, f sync.Executor) error
+type DeployResponse struct {
+	Deployments []Deployment `json:"deployments"`
 }
 
 type DeployHandler struct {
-	Deployer  rootDeployer
-	Scheduler scheduler
-	Logger    logging.Logger
+	Deployer rootDeployer
+	Logger   logging.Logger
 }
 
-func (c *DeployHandler) Handle(ctx context.Context, r request.Deploy) error {
-	c.Logger.Info("scheduling deploy API request")
+func (c *DeployHandler) Handle(ctx context.Context, r request.Deploy) (DeployResponse, error) {
+	c.Logger.InfoContext(ctx, "handling deploy API request")
 
-	return c.Scheduler.Schedule(ctx,
-		func(ctx context.Context) error {
-			return c.Deployer.Deploy(ctx, deploy.RootDeployOptions{
-				Repo:      r.Repo,
-				Branch:    r.Branch,
-				Revision:  r.Revision,
-				RootNames: r.RootNames,
+	rootDeployments, err := c.Deployer.DeployRoots(ctx, deploy.RootDeployOptions{
+		Repo:      r.Repo,
+		Branch:    r.Branch,
+		Revision:  r.Revision,
+		RootNames: r.RootNames,
 
-				// this we won't have, maybe we can add some gh auth to add this
-				Sender: r.User,
+		// this we won't have, maybe we can add some gh auth to add this
+		Sender: r.User,
 
-				InstallationToken: r.InstallationToken,
+		InstallationToken: r.InstallationToken,
 
-				RepoFetcherOptions: &internalGH.RepoFetcherOptions{
-					CloneDepth: 1,
-				},
+		RepoFetcherOptions: &internalGH.RepoFetcherOptions{
+			CloneDepth: 1,
+		},
 
-				TriggerInfo: workflows.DeployTriggerInfo{
-					Type: workflows.ManualTrigger,
-				},
-			})
+		TriggerInfo: workflows.DeployTriggerInfo{
+			Type:  workflows.ManualTrigger,
+			Force: r.Force,
 		},
-	)
+	})
+	if err != nil {
+		return DeployResponse{}, err
+	}
+
+	return toDeployResponse(rootDeployments), nil
+}
+
+func toDeployResponse(rootDeployments []deploy.RootDeployment) DeployResponse {
+	resp := DeployResponse{
+		Deployments: []Deployment{},
+	}
+	for _, d := range rootDeployments {
+		resp.Deployments = append(resp.Deployments, Deployment{
+			ID:         BuildDeploymentStatusID(d.WorkflowID, d.DeploymentID),
+			Root:       d.Root,
+			WorkflowID: d.WorkflowID,
+			RunID:      d.RunID,
+		})
+	}
+	return resp
+}
+
+// BuildDeploymentStatusID builds an opaque id which contains everything we need to locate a deployment
+func BuildDeploymentStatusID(workflowID string, deploymentID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(workflowID + statusIDDelim + deploymentID))
+}
+
+func parseDeploymentStatusID(id string) (string, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return "", "", errors.Wrap(err, "decoding id")
+	}
+
+	// workflow ids contain our delimiter as well so split on the last one
+	i := strings.LastIndex(string(decoded), statusIDDelim)
+	if i < 0 {
+		return "", "", fmt.Errorf("malformed id: %s", id)
+	}
+
+	return string(decoded[:i]), string(decoded[i+len(statusIDDelim):]), nil
+}
+
+type queryClient interface {
+	QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error)
+}
+
+type DeploymentStatus struct {
+	ID       string `json:"id"`
+	Root     string `json:"root"`
+	Repo     string `json:"repo"`
+	Revision string `json:"revision,omitempty"`
+	Status   string `json:"status"`
+}
+
+// DeployStatusController reports the status of deployments triggered through the deploy API
+type DeployStatusController struct {
+	TemporalClient queryClient
+	Logger         logging.Logger
+}
+
+func (c *DeployStatusController) Get(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)[IDVarKey]
+
+	workflowID, deploymentID, err := parseDeploymentStatusID(id)
+	if err != nil {
+		writeError(w, c.Logger, http.StatusBadRequest, err)
+		return
+	}
+
+	value, err := c.TemporalClient.QueryWorkflow(r.Context(), workflowID, "", workflows.DeploymentStatusQueryName, deploymentID)
+	if err != nil {
+		writeTemporalError(w, c.Logger, err, fmt.Sprintf("querying workflow with id: %s", workflowID))
+		return
+	}
+
+	var state workflows.DeploymentState
+	if err := value.Get(&state); err != nil {
+		writeError(w, c.Logger, http.StatusInternalServerError, errors.Wrap(err, "decoding query result"))
+		return
+	}
+
+	if state.Status == workflows.UnknownDeploymentStatus {
+		writeError(w, c.Logger, http.StatusNotFound, fmt.Errorf("deployment %s not found", deploymentID))
+		return
+	}
+
+	repo, root := splitDeployWorkflowID(workflowID)
+	writeJSON(w, c.Logger, http.StatusOK, DeploymentStatus{
+		ID:       id,
+		Repo:     repo,
+		Root:     root,
+		Revision: state.Revision,
+		Status:   string(state.Status),
+	})
+}
+
+func splitDeployWorkflowID(workflowID string) (string, string) {
+	repo, root, _ := strings.Cut(workflowID, statusIDDelim)
+	return repo, root
 }
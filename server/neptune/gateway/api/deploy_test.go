@@ -0,0 +1,202 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/requirement"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/converter"
+)
+
+type testRootDeployer struct {
+	deployments []deploy.RootDeployment
+	err         error
+}
+
+func (d *testRootDeployer) DeployRoots(ctx context.Context, deployOptions deploy.RootDeployOptions) ([]deploy.RootDeployment, error) {
+	return d.deployments, d.err
+}
+
+type testRequestConverter struct{}
+
+func (c *testRequestConverter) Convert(from *http.Request) (request.Deploy, error) {
+	return request.Deploy{}, nil
+}
+
+func TestController_Handle(t *testing.T) {
+	controller := &api.Controller[request.Deploy, api.DeployResponse]{
+		RequestConverter: &testRequestConverter{},
+		Handler: &api.DeployHandler{
+			Deployer: &testRootDeployer{
+				deployments: []deploy.RootDeployment{
+					{Root: "root", WorkflowID: "owner/repo||root", RunID: "1234", DeploymentID: "abcd"},
+				},
+			},
+			Logger: logging.NewNoopCtxLogger(t),
+		},
+		Logger: logging.NewNoopCtxLogger(t),
+	}
+
+	w := httptest.NewRecorder()
+	controller.Handle(w, httptest.NewRequest(http.MethodPost, "/deploy", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var resp api.DeployResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, api.DeployResponse{
+		Deployments: []api.Deployment{
+			{
+				ID:         api.BuildDeploymentStatusID("owner/repo||root", "abcd"),
+				Root:       "root",
+				WorkflowID: "owner/repo||root",
+				RunID:      "1234",
+			},
+		},
+	}, resp)
+}
+
+func TestController_Handle_Error(t *testing.T) {
+	controller := &api.Controller[request.Deploy, api.DeployResponse]{
+		RequestConverter: &testRequestConverter{},
+		Handler: &api.DeployHandler{
+			Deployer: &testRootDeployer{err: assert.AnError},
+			Logger:   logging.NewNoopCtxLogger(t),
+		},
+		Logger: logging.NewNoopCtxLogger(t),
+	}
+
+	w := httptest.NewRecorder()
+	controller.Handle(w, httptest.NewRequest(http.MethodPost, "/deploy", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp api.ErrorResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.NotEmpty(t, resp.Error)
+}
+
+func TestController_Handle_Forbidden(t *testing.T) {
+	controller := &api.Controller[request.Deploy, api.DeployResponse]{
+		RequestConverter: &testRequestConverter{},
+		Handler: &api.DeployHandler{
+			Deployer: &testRootDeployer{err: errors.Wrap(requirement.NewForbiddenError("frozen"), "checking freeze windows")},
+			Logger:   logging.NewNoopCtxLogger(t),
+		},
+		Logger: logging.NewNoopCtxLogger(t),
+	}
+
+	w := httptest.NewRecorder()
+	controller.Handle(w, httptest.NewRequest(http.MethodPost, "/deploy", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+type testDeploymentStateValue struct {
+	state workflows.DeploymentState
+}
+
+func (v testDeploymentStateValue) HasValue() bool {
+	return true
+}
+
+func (v testDeploymentStateValue) Get(valuePtr interface{}) error {
+	*(valuePtr.(*workflows.DeploymentState)) = v.state
+	return nil
+}
+
+type testQueryClient struct {
+	t                    *testing.T
+	expectedWorkflowID   string
+	expectedDeploymentID string
+	state                workflows.DeploymentState
+}
+
+func (c *testQueryClient) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	assert.Equal(c.t, c.expectedWorkflowID, workflowID)
+	assert.Equal(c.t, workflows.DeploymentStatusQueryName, queryType)
+	assert.Equal(c.t, []interface{}{c.expectedDeploymentID}, args)
+	return testDeploymentStateValue{state: c.state}, nil
+}
+
+func serveStatus(controller *api.DeployStatusController, id string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/deploy/{id}", controller.Get).Methods(http.MethodGet)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deploy/"+id, nil))
+	return w
+}
+
+func TestDeployStatusController_Get(t *testing.T) {
+	id := api.BuildDeploymentStatusID("owner/repo||root", "abcd")
+
+	t.Run("running", func(t *testing.T) {
+		controller := &api.DeployStatusController{
+			TemporalClient: &testQueryClient{
+				t:                    t,
+				expectedWorkflowID:   "owner/repo||root",
+				expectedDeploymentID: "abcd",
+				state: workflows.DeploymentState{
+					ID:       "abcd",
+					Revision: "1234",
+					Status:   workflows.RunningDeploymentStatus,
+				},
+			},
+			Logger: logging.NewNoopCtxLogger(t),
+		}
+
+		w := serveStatus(controller, id)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp api.DeploymentStatus
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, api.DeploymentStatus{
+			ID:       id,
+			Repo:     "owner/repo",
+			Root:     "root",
+			Revision: "1234",
+			Status:   "running",
+		}, resp)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		controller := &api.DeployStatusController{
+			TemporalClient: &testQueryClient{
+				t:                    t,
+				expectedWorkflowID:   "owner/repo||root",
+				expectedDeploymentID: "abcd",
+				state: workflows.DeploymentState{
+					ID:     "abcd",
+					Status: workflows.UnknownDeploymentStatus,
+				},
+			},
+			Logger: logging.NewNoopCtxLogger(t),
+		}
+
+		w := serveStatus(controller, id)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("malformed id", func(t *testing.T) {
+		controller := &api.DeployStatusController{
+			TemporalClient: &testQueryClient{t: t},
+			Logger:         logging.NewNoopCtxLogger(t),
+		}
+
+		w := serveStatus(controller, "not-an-id")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+)
+
+const (
+	LimitQueryParam  = "limit"
+	CursorQueryParam = "cursor"
+
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+type historyStore interface {
+	ListDeploymentHistory(ctx context.Context, repoName string, rootName string, cursor string, limit int) ([]*deployment.HistoryEntry, string, error)
+}
+
+// PlanSummary contains the addresses of the resources changed by a deployment, replaced
+// resources are only listed under replacements.
+type PlanSummary struct {
+	Creations    []string `json:"creations,omitempty"`
+	Updates      []string `json:"updates,omitempty"`
+	Deletions    []string `json:"deletions,omitempty"`
+	Replacements []string `json:"replacements,omitempty"`
+	Moves        []string `json:"moves,omitempty"`
+	Imports      []string `json:"imports,omitempty"`
+}
+
+type HistoricalDeployment struct {
+	ID          string      `json:"id"`
+	Revision    string      `json:"revision"`
+	Branch      string      `json:"branch"`
+	User        string      `json:"user"`
+	Trigger     string      `json:"trigger"`
+	Outcome     string      `json:"outcome"`
+	PlanSummary PlanSummary `json:"plan_summary"`
+
+	// PlanReviewer and PlanReviewReason are only set for plans which were manually approved or rejected
+	PlanReviewer     string `json:"plan_reviewer,omitempty"`
+	PlanReviewReason string `json:"plan_review_reason,omitempty"`
+
+	// RolledBackRevision is only set for rollbacks and is the revision which was deployed beforehand
+	RolledBackRevision string `json:"rolled_back_revision,omitempty"`
+
+	// PromotedBy is only set for deployments promoted past a manual promotion gate
+	PromotedBy string `json:"promoted_by,omitempty"`
+
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+type DeploymentHistory struct {
+	Repo        string                 `json:"repo"`
+	Root        string                 `json:"root"`
+	Deployments []HistoricalDeployment `json:"deployments"`
+
+	// NextCursor is passed as the cursor query param to fetch the next page, it's
+	// omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// DeploymentHistoryController pages through every deployment of a root, most recent first.
+type DeploymentHistoryController struct {
+	Store  historyStore
+	Logger logging.Logger
+}
+
+func (c *DeploymentHistoryController) List(w http.ResponseWriter, r *http.Request) {
+	repo, root := parseRepoAndRoot(r)
+
+	limit := DefaultHistoryLimit
+	if l := r.URL.Query().Get(LimitQueryParam); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > MaxHistoryLimit {
+			writeError(w, c.Logger, http.StatusBadRequest, fmt.Errorf("%s must be between 1 and %d", LimitQueryParam, MaxHistoryLimit))
+			return
+		}
+	}
+
+	entries, next, err := c.Store.ListDeploymentHistory(r.Context(), repo, root, r.URL.Query().Get(CursorQueryParam), limit)
+	if err != nil {
+		writeError(w, c.Logger, http.StatusInternalServerError, errors.Wrapf(err, "listing deployment history of %s/%s", repo, root))
+		return
+	}
+
+	resp := DeploymentHistory{
+		Repo:        repo,
+		Root:        root,
+		Deployments: []HistoricalDeployment{},
+		NextCursor:  next,
+	}
+	for _, entry := range entries {
+		resp.Deployments = append(resp.Deployments, toHistoricalDeployment(entry))
+	}
+
+	writeJSON(w, c.Logger, http.StatusOK, resp)
+}
+
+func toHistoricalDeployment(entry *deployment.HistoryEntry) HistoricalDeployment {
+	d := HistoricalDeployment{
+		ID:          entry.ID,
+		Revision:    entry.Revision,
+		Branch:      entry.Branch,
+		User:        entry.User,
+		Trigger:     entry.Root.Trigger,
+		Outcome:     string(entry.Outcome),
+		PlanSummary: toPlanSummary(entry.Changes),
+		StartedAt:   entry.StartedAt,
+		CompletedAt: entry.CompletedAt,
+
+		RolledBackRevision: entry.RolledBackRevision,
+		PromotedBy:         entry.PromotedBy,
+	}
+
+	if entry.PlanReview != nil {
+		d.PlanReviewer = entry.PlanReview.User
+		d.PlanReviewReason = entry.PlanReview.Reason
+	}
+	return d
+}
+
+func toPlanSummary(changes []deployment.ResourceChange) PlanSummary {
+	var summary PlanSummary
+	for _, c := range changes {
+		switch c.Action {
+		case deployment.CreateAction:
+			summary.Creations = append(summary.Creations, c.Address)
+		case deployment.UpdateAction:
+			summary.Updates = append(summary.Updates, c.Address)
+		case deployment.DeleteAction:
+			summary.Deletions = append(summary.Deletions, c.Address)
+		case deployment.ReplaceAction:
+			summary.Replacements = append(summary.Replacements, c.Address)
+		case deployment.MoveAction:
+			summary.Moves = append(summary.Moves, c.Address)
+		case deployment.ImportAction:
+			summary.Imports = append(summary.Imports, c.Address)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,177 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHistoryStore struct {
+	t *testing.T
+
+	expectedRepo   string
+	expectedRoot   string
+	expectedCursor string
+	expectedLimit  int
+
+	entries []*deployment.HistoryEntry
+	next    string
+	err     error
+}
+
+func (s *testHistoryStore) ListDeploymentHistory(ctx context.Context, repoName string, rootName string, cursor string, limit int) ([]*deployment.HistoryEntry, string, error) {
+	assert.Equal(s.t, s.expectedRepo, repoName)
+	assert.Equal(s.t, s.expectedRoot, rootName)
+	assert.Equal(s.t, s.expectedCursor, cursor)
+	assert.Equal(s.t, s.expectedLimit, limit)
+	return s.entries, s.next, s.err
+}
+
+func serveHistory(controller *api.DeploymentHistoryController, path string) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/history", controller.List).Methods(http.MethodGet)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestDeploymentHistoryController_List(t *testing.T) {
+	started := time.Date(2022, time.October, 4, 12, 0, 0, 0, time.UTC)
+	store := &testHistoryStore{
+		t:              t,
+		expectedRepo:   "owner/repo",
+		expectedRoot:   "root",
+		expectedCursor: "abc",
+		expectedLimit:  2,
+		entries: []*deployment.HistoryEntry{
+			{
+				ID:       "2",
+				Revision: "def",
+				Branch:   "main",
+				User:     "nish",
+				Root:     deployment.Root{Name: "root", Trigger: "manual"},
+				Changes: []deployment.ResourceChange{
+					{Address: "a", Action: deployment.UpdateAction},
+					{Address: "b", Action: deployment.DeleteAction},
+					{Address: "c", Action: deployment.DeleteAction},
+					{Address: "d", Action: deployment.ReplaceAction},
+				},
+				PlanReview:  &deployment.PlanReview{User: "someone", Reason: "expected"},
+				Outcome:     deployment.FailureOutcome,
+				StartedAt:   started,
+				CompletedAt: started.Add(time.Minute),
+			},
+			{
+				ID:          "1",
+				Revision:    "abc",
+				Branch:      "main",
+				User:        "nish",
+				Root:        deployment.Root{Name: "root", Trigger: "manual", ManualRollback: true},
+				Outcome:     deployment.SuccessOutcome,
+				StartedAt:   started,
+				CompletedAt: started,
+
+				RolledBackRevision: "ghi",
+			},
+		},
+		next: "1",
+	}
+
+	w := serveHistory(&api.DeploymentHistoryController{Store: store, Logger: logging.NewNoopCtxLogger(t)}, "/deploy/owner/repo/root/history?limit=2&cursor=abc")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.DeploymentHistory
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, api.DeploymentHistory{
+		Repo: "owner/repo",
+		Root: "root",
+		Deployments: []api.HistoricalDeployment{
+			{
+				ID:       "2",
+				Revision: "def",
+				Branch:   "main",
+				User:     "nish",
+				Trigger:  "manual",
+				Outcome:  "failure",
+				PlanSummary: api.PlanSummary{
+					Updates:      []string{"a"},
+					Deletions:    []string{"b", "c"},
+					Replacements: []string{"d"},
+				},
+				PlanReviewer:     "someone",
+				PlanReviewReason: "expected",
+				StartedAt:        started,
+				CompletedAt:      started.Add(time.Minute),
+			},
+			{
+				ID:          "1",
+				Revision:    "abc",
+				Branch:      "main",
+				User:        "nish",
+				Trigger:     "manual",
+				Outcome:     "success",
+				StartedAt:   started,
+				CompletedAt: started,
+
+				RolledBackRevision: "ghi",
+			},
+		},
+		NextCursor: "1",
+	}, resp)
+}
+
+func TestDeploymentHistoryController_List_DefaultLimit(t *testing.T) {
+	store := &testHistoryStore{
+		t:             t,
+		expectedRepo:  "owner/repo",
+		expectedRoot:  "root",
+		expectedLimit: api.DefaultHistoryLimit,
+	}
+
+	w := serveHistory(&api.DeploymentHistoryController{Store: store, Logger: logging.NewNoopCtxLogger(t)}, "/deploy/owner/repo/root/history")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.DeploymentHistory
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, api.DeploymentHistory{
+		Repo:        "owner/repo",
+		Root:        "root",
+		Deployments: []api.HistoricalDeployment{},
+	}, resp)
+}
+
+func TestDeploymentHistoryController_List_InvalidLimit(t *testing.T) {
+	for _, limit := range []string{"0", "101", "abc"} {
+		t.Run(limit, func(t *testing.T) {
+			w := serveHistory(&api.DeploymentHistoryController{Store: &testHistoryStore{t: t}, Logger: logging.NewNoopCtxLogger(t)}, "/deploy/owner/repo/root/history?limit="+limit)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestDeploymentHistoryController_List_StoreError(t *testing.T) {
+	store := &testHistoryStore{
+		t:             t,
+		expectedRepo:  "owner/repo",
+		expectedRoot:  "root",
+		expectedLimit: api.DefaultHistoryLimit,
+		err:           errors.New("error"),
+	}
+
+	w := serveHistory(&api.DeploymentHistoryController{Store: store, Logger: logging.NewNoopCtxLogger(t)}, "/deploy/owner/repo/root/history")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/middleware"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"go.temporal.io/sdk/converter"
+)
+
+type queueTemporalClient interface {
+	QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error)
+	SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error
+}
+
+type QueuedRevision struct {
+	ID       string `json:"id"`
+	Revision string `json:"revision"`
+	Branch   string `json:"branch"`
+	User     string `json:"user"`
+	Trigger  string `json:"trigger"`
+}
+
+type LatestDeployment struct {
+	ID       string `json:"id"`
+	Revision string `json:"revision"`
+	Branch   string `json:"branch"`
+}
+
+type DeployQueue struct {
+	Repo              string            `json:"repo"`
+	Root              string            `json:"root"`
+	Locked            bool              `json:"locked"`
+	LockedRevision    string            `json:"locked_revision,omitempty"`
+	CurrentDeployment *QueuedRevision   `json:"current_deployment,omitempty"`
+	Revisions         []QueuedRevision  `json:"revisions"`
+	LatestDeployment  *LatestDeployment `json:"latest_deployment,omitempty"`
+}
+
+type ReorderDeployQueue struct {
+	// IDs of queued revisions which are moved to the front of the queue in the order provided
+	IDs []string `json:"ids"`
+}
+
+const (
+	ApprovedPlanReview = "approved"
+	RejectedPlanReview = "rejected"
+)
+
+type PlanReview struct {
+	// Status is either approved or rejected
+	Status string `json:"status"`
+
+	// Reason is optional and is shown on the check run and persisted with the deployment
+	Reason string `json:"reason"`
+}
+
+// DeployQueueController exposes the contents of a root's deploy queue and allows admins
+// to manipulate it.  All operations are done against the running deploy workflow.
+type DeployQueueController struct {
+	TemporalClient queueTemporalClient
+	Logger         logging.Logger
+}
+
+func (c *DeployQueueController) List(w http.ResponseWriter, r *http.Request) {
+	repo, root := parseRepoAndRoot(r)
+	workflowID := deploy.BuildDeployWorkflowID(repo, root)
+
+	value, err := c.TemporalClient.QueryWorkflow(r.Context(), workflowID, "", workflows.DeployQueueQueryName)
+	if err != nil {
+		writeTemporalError(w, c.Logger, err, fmt.Sprintf("querying workflow with id: %s", workflowID))
+		return
+	}
+
+	var state workflows.DeployQueueState
+	if err := value.Get(&state); err != nil {
+		writeError(w, c.Logger, http.StatusInternalServerError, errors.Wrap(err, "decoding query result"))
+		return
+	}
+
+	resp := DeployQueue{
+		Repo:           repo,
+		Root:           root,
+		Locked:         state.Lock.Locked,
+		LockedRevision: state.Lock.Revision,
+		Revisions:      []QueuedRevision{},
+	}
+
+	if state.CurrentDeployment != nil {
+		current := toQueuedRevision(*state.CurrentDeployment)
+		resp.CurrentDeployment = &current
+	}
+
+	for _, revision := range state.Revisions {
+		resp.Revisions = append(resp.Revisions, toQueuedRevision(revision))
+	}
+
+	if state.LatestDeployment != nil {
+		resp.LatestDeployment = &LatestDeployment{
+			ID:       state.LatestDeployment.ID,
+			Revision: state.LatestDeployment.Revision,
+			Branch:   state.LatestDeployment.Branch,
+		}
+	}
+
+	writeJSON(w, c.Logger, http.StatusOK, resp)
+}
+
+func (c *DeployQueueController) Remove(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)[IDVarKey]
+
+	c.signal(w, r, workflows.DeployRemoveRevisionSignalName, workflows.DeployRemoveRevisionSignalRequest{
+		DeploymentID: id,
+		User:         username(r),
+	})
+}
+
+func (c *DeployQueueController) Reorder(w http.ResponseWriter, r *http.Request) {
+	var body ReorderDeployQueue
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, c.Logger, http.StatusBadRequest, errors.Wrap(err, "decoding request body"))
+		return
+	}
+
+	if len(body.IDs) == 0 {
+		writeError(w, c.Logger, http.StatusBadRequest, errors.New("ids must be provided"))
+		return
+	}
+
+	c.signal(w, r, workflows.DeployReorderRevisionsSignalName, workflows.DeployReorderRevisionsSignalRequest{
+		DeploymentIDs: body.IDs,
+		User:          username(r),
+	})
+}
+
+func (c *DeployQueueController) Unlock(w http.ResponseWriter, r *http.Request) {
+	c.signal(w, r, workflows.DeployUnlockSignalName, workflows.DeployUnlockSignalRequest{
+		User: username(r),
+	})
+}
+
+// Promote deploys a revision which is waiting at the manual gate of a promotion chain
+func (c *DeployQueueController) Promote(w http.ResponseWriter, r *http.Request) {
+	c.signal(w, r, workflows.DeployPromoteSignalName, workflows.DeployPromoteSignalRequest{
+		DeploymentID: mux.Vars(r)[IDVarKey],
+		User:         username(r),
+	})
+}
+
+// Review approves or rejects the plan of a deployment which is awaiting manual approval
+func (c *DeployQueueController) Review(w http.ResponseWriter, r *http.Request) {
+	var body PlanReview
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, c.Logger, http.StatusBadRequest, errors.Wrap(err, "decoding request body"))
+		return
+	}
+
+	var status workflows.TerraformPlanReviewStatus
+	switch body.Status {
+	case ApprovedPlanReview:
+		status = workflows.ApprovedPlanReviewStatus
+	case RejectedPlanReview:
+		status = workflows.RejectedPlanReviewStatus
+	default:
+		writeError(w, c.Logger, http.StatusBadRequest, fmt.Errorf("status must be one of %s, %s", ApprovedPlanReview, RejectedPlanReview))
+		return
+	}
+
+	// only deployments which are running for this root can be awaiting review, this ensures
+	// the deployment id isn't used to signal a terraform workflow belonging to another root
+	id := mux.Vars(r)[IDVarKey]
+	repo, root := parseRepoAndRoot(r)
+	workflowID := deploy.BuildDeployWorkflowID(repo, root)
+	value, err := c.TemporalClient.QueryWorkflow(r.Context(), workflowID, "", workflows.DeploymentStatusQueryName, id)
+	if err != nil {
+		writeTemporalError(w, c.Logger, err, fmt.Sprintf("querying workflow with id: %s", workflowID))
+		return
+	}
+
+	var state workflows.DeploymentState
+	if err := value.Get(&state); err != nil {
+		writeError(w, c.Logger, http.StatusInternalServerError, errors.Wrap(err, "decoding query result"))
+		return
+	}
+
+	if state.Status != workflows.RunningDeploymentStatus {
+		writeError(w, c.Logger, http.StatusNotFound, fmt.Errorf("deployment %s is not running for %s", id, workflowID))
+		return
+	}
+
+	// the terraform workflow id is the deployment id
+	c.signalWorkflow(w, r, id, workflows.TerraformPlanReviewSignalName, workflows.TerraformPlanReviewSignalRequest{
+		Status: status,
+		User:   username(r),
+		Reason: body.Reason,
+	})
+}
+
+func (c *DeployQueueController) signal(w http.ResponseWriter, r *http.Request, signalName string, arg interface{}) {
+	repo, root := parseRepoAndRoot(r)
+	c.signalWorkflow(w, r, deploy.BuildDeployWorkflowID(repo, root), signalName, arg)
+}
+
+func (c *DeployQueueController) signalWorkflow(w http.ResponseWriter, r *http.Request, workflowID string, signalName string, arg interface{}) {
+	// keeping the run id empty is fine since temporal will find the currently running workflow
+	if err := c.TemporalClient.SignalWorkflow(r.Context(), workflowID, "", signalName, arg); err != nil {
+		writeTemporalError(w, c.Logger, err, fmt.Sprintf("signaling workflow with id: %s", workflowID))
+		return
+	}
+
+	c.Logger.InfoContext(r.Context(), fmt.Sprintf("signaled workflow with id %s with %s", workflowID, signalName))
+	writeJSON(w, c.Logger, http.StatusAccepted, map[string]string{
+		"workflow_id": workflowID,
+		"signal":      signalName,
+	})
+}
+
+// parseRepoAndRoot reads the repo and root from the request's path, roots containing slashes
+// are addressed by escaping them (ie. modules%2Fvpc) since the router matches the encoded path.
+func parseRepoAndRoot(r *http.Request) (string, string) {
+	return fmt.Sprintf("%s/%s", pathVar(r, OwnerVarKey), pathVar(r, RepoVarKey)), pathVar(r, RootVarKey)
+}
+
+func pathVar(r *http.Request, key string) string {
+	value := mux.Vars(r)[key]
+
+	// the path was already parsed with these escapes so this only fails if the router didn't match the encoded path
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func username(r *http.Request) string {
+	user, _ := r.Context().Value(middleware.UsernameContextKey).(string)
+	return user
+}
+
+func toQueuedRevision(revision workflows.DeployQueuedRevision) QueuedRevision {
+	return QueuedRevision{
+		ID:       revision.ID,
+		Revision: revision.Revision,
+		Branch:   revision.Branch,
+		User:     revision.User,
+		Trigger:  revision.Trigger,
+	}
+}
@@ -0,0 +1,284 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/middleware"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/converter"
+)
+
+type testEncodedValue struct {
+	value           workflows.DeployQueueState
+	deploymentState workflows.DeploymentState
+}
+
+func (v testEncodedValue) HasValue() bool {
+	return true
+}
+
+func (v testEncodedValue) Get(valuePtr interface{}) error {
+	switch ptr := valuePtr.(type) {
+	case *workflows.DeployQueueState:
+		*ptr = v.value
+	case *workflows.DeploymentState:
+		*ptr = v.deploymentState
+	}
+	return nil
+}
+
+type testTemporalClient struct {
+	t *testing.T
+
+	expectedWorkflowID string
+	expectedName       string
+	expectedArg        interface{}
+
+	state workflows.DeployQueueState
+	err   error
+
+	// set when a request queries a different workflow than it signals
+	expectedQueryWorkflowID string
+	expectedQueryName       string
+	deploymentState         workflows.DeploymentState
+
+	called bool
+}
+
+func (c *testTemporalClient) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	c.called = true
+	if c.expectedQueryName != "" {
+		assert.Equal(c.t, c.expectedQueryWorkflowID, workflowID)
+		assert.Equal(c.t, c.expectedQueryName, queryType)
+		return testEncodedValue{deploymentState: c.deploymentState}, nil
+	}
+	assert.Equal(c.t, c.expectedWorkflowID, workflowID)
+	assert.Equal(c.t, c.expectedName, queryType)
+	return testEncodedValue{value: c.state}, c.err
+}
+
+func (c *testTemporalClient) SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error {
+	c.called = true
+	assert.Equal(c.t, c.expectedWorkflowID, workflowID)
+	assert.Equal(c.t, c.expectedName, signalName)
+	assert.Equal(c.t, c.expectedArg, arg)
+	return c.err
+}
+
+func serve(controller *api.DeployQueueController, method string, path string, body string) *httptest.ResponseRecorder {
+	router := mux.NewRouter().UseEncodedPath()
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/queue", controller.List).Methods(http.MethodGet)
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/queue", controller.Reorder).Methods(http.MethodPut)
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/queue/{id}", controller.Remove).Methods(http.MethodDelete)
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/unlock", controller.Unlock).Methods(http.MethodPost)
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/review/{id}", controller.Review).Methods(http.MethodPost)
+	router.HandleFunc("/deploy/{owner}/{repo}/{root}/promote/{id}", controller.Promote).Methods(http.MethodPost)
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r = r.WithContext(context.WithValue(r.Context(), middleware.UsernameContextKey, "nish"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestDeployQueueController_List(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||root",
+		expectedName:       workflows.DeployQueueQueryName,
+		state: workflows.DeployQueueState{
+			Lock: workflows.DeployQueueLockState{
+				Locked:   true,
+				Revision: "abc",
+			},
+			Revisions: []workflows.DeployQueuedRevision{
+				{ID: "1", Revision: "def", Branch: "main", User: "someone", Trigger: "merge"},
+			},
+			LatestDeployment: &deployment.Info{ID: "0", Revision: "123", Branch: "main"},
+		},
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodGet, "/deploy/owner/repo/root/queue", "")
+
+	assert.True(t, client.called)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.DeployQueue
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, api.DeployQueue{
+		Repo:           "owner/repo",
+		Root:           "root",
+		Locked:         true,
+		LockedRevision: "abc",
+		Revisions: []api.QueuedRevision{
+			{ID: "1", Revision: "def", Branch: "main", User: "someone", Trigger: "merge"},
+		},
+		LatestDeployment: &api.LatestDeployment{ID: "0", Revision: "123", Branch: "main"},
+	}, resp)
+}
+
+func TestDeployQueueController_List_NestedRoot(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||modules/vpc",
+		expectedName:       workflows.DeployQueueQueryName,
+		state:              workflows.DeployQueueState{},
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodGet, "/deploy/owner/repo/modules%2Fvpc/queue", "")
+
+	assert.True(t, client.called)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.DeployQueue
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "modules/vpc", resp.Root)
+}
+
+func TestDeployQueueController_List_NotFound(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||root",
+		expectedName:       workflows.DeployQueueQueryName,
+		err:                serviceerror.NewNotFound("not found"),
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodGet, "/deploy/owner/repo/root/queue", "")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeployQueueController_Remove(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||root",
+		expectedName:       workflows.DeployRemoveRevisionSignalName,
+		expectedArg: workflows.DeployRemoveRevisionSignalRequest{
+			DeploymentID: "1234",
+			User:         "nish",
+		},
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodDelete, "/deploy/owner/repo/root/queue/1234", "")
+
+	assert.True(t, client.called)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestDeployQueueController_Reorder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := &testTemporalClient{
+			t:                  t,
+			expectedWorkflowID: "owner/repo||root",
+			expectedName:       workflows.DeployReorderRevisionsSignalName,
+			expectedArg: workflows.DeployReorderRevisionsSignalRequest{
+				DeploymentIDs: []string{"2", "1"},
+				User:          "nish",
+			},
+		}
+
+		w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPut, "/deploy/owner/repo/root/queue", `{"ids": ["2", "1"]}`)
+
+		assert.True(t, client.called)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("missing ids", func(t *testing.T) {
+		client := &testTemporalClient{t: t}
+
+		w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPut, "/deploy/owner/repo/root/queue", `{}`)
+
+		assert.False(t, client.called)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeployQueueController_Unlock(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||root",
+		expectedName:       workflows.DeployUnlockSignalName,
+		expectedArg: workflows.DeployUnlockSignalRequest{
+			User: "nish",
+		},
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPost, "/deploy/owner/repo/root/unlock", "")
+
+	assert.True(t, client.called)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestDeployQueueController_Promote(t *testing.T) {
+	client := &testTemporalClient{
+		t:                  t,
+		expectedWorkflowID: "owner/repo||root",
+		expectedName:       workflows.DeployPromoteSignalName,
+		expectedArg: workflows.DeployPromoteSignalRequest{
+			DeploymentID: "1234",
+			User:         "nish",
+		},
+	}
+
+	w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPost, "/deploy/owner/repo/root/promote/1234", "")
+
+	assert.True(t, client.called)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestDeployQueueController_Review(t *testing.T) {
+	t.Run("approve", func(t *testing.T) {
+		client := &testTemporalClient{
+			t:                  t,
+			expectedWorkflowID: "1234",
+			expectedName:       workflows.TerraformPlanReviewSignalName,
+			expectedArg: workflows.TerraformPlanReviewSignalRequest{
+				Status: workflows.ApprovedPlanReviewStatus,
+				User:   "nish",
+				Reason: "expected deletion",
+			},
+			expectedQueryWorkflowID: "owner/repo||root",
+			expectedQueryName:       workflows.DeploymentStatusQueryName,
+			deploymentState:         workflows.DeploymentState{ID: "1234", Status: workflows.RunningDeploymentStatus},
+		}
+
+		w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPost, "/deploy/owner/repo/root/review/1234", `{"status": "approved", "reason": "expected deletion"}`)
+
+		assert.True(t, client.called)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("deployment not running for root", func(t *testing.T) {
+		client := &testTemporalClient{
+			t:                       t,
+			expectedQueryWorkflowID: "owner/repo||root",
+			expectedQueryName:       workflows.DeploymentStatusQueryName,
+			deploymentState:         workflows.DeploymentState{ID: "1234", Status: workflows.UnknownDeploymentStatus},
+		}
+
+		w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPost, "/deploy/owner/repo/root/review/1234", `{"status": "approved"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		client := &testTemporalClient{t: t}
+
+		w := serve(&api.DeployQueueController{TemporalClient: client, Logger: logging.NewNoopCtxLogger(t)}, http.MethodPost, "/deploy/owner/repo/root/review/1234", `{"status": "maybe"}`)
+
+		assert.False(t, client.called)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
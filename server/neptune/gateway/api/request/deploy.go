@@ -46,6 +46,9 @@ type Deploy struct {
 	Revision          string
 	InstallationToken int64
 	User              models.User
+
+	// Force overrides active freeze windows
+	Force bool
 }
 
 type DeployConverter struct {
@@ -81,6 +84,7 @@ func (c *DeployConverter) Convert(ctx context.Context, r external.DeployRequest)
 		User: models.User{
 			Username: username.(string),
 		},
+		Force: r.Force,
 	}, nil
 }
 
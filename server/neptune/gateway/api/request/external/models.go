@@ -1,6 +1,13 @@
 package external
 
-import validation "github.com/go-ozzo/ozzo-validation"
+import (
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// rollbacks are audited so we require the full sha instead of an abbreviated one
+var shaRegex = regexp.MustCompile("^[0-9a-f]{40}$")
 
 type Repo struct {
 	Owner string
@@ -17,6 +24,9 @@ func (r Repo) Validate() error {
 type DeployRequest struct {
 	Roots []string
 	Repo  Repo
+
+	// Force overrides active freeze windows
+	Force bool
 }
 
 func (r DeployRequest) Validate() error {
@@ -25,3 +35,20 @@ func (r DeployRequest) Validate() error {
 		validation.Field(&r.Repo, validation.Required),
 	)
 }
+
+type RollbackRequest struct {
+	Root     string
+	Repo     Repo
+	Revision string
+
+	// Force overrides active freeze windows
+	Force bool
+}
+
+func (r RollbackRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Root, validation.Required),
+		validation.Field(&r.Repo, validation.Required),
+		validation.Field(&r.Revision, validation.Required, validation.Match(shaRegex).Error("must be a full commit sha")),
+	)
+}
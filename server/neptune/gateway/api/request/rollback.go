@@ -0,0 +1,93 @@
+package request
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/middleware"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request/external"
+	internal "github.com/runatlantis/atlantis/server/vcs/provider/github"
+)
+
+func NewRollbackConverter(
+	repoRetriever *internal.RepoRetriever,
+	installationRetriever *internal.InstallationRetriever,
+	commitComparer *internal.CommitComparer,
+) *JSONRequestValidationProxy[external.RollbackRequest, Rollback] {
+	return &JSONRequestValidationProxy[external.RollbackRequest, Rollback]{
+		Delegate: &RollbackConverter{
+			InstallationRetriever: installationRetriever,
+			RepoRetriever:         repoRetriever,
+			CommitComparer:        commitComparer,
+		},
+	}
+}
+
+type commitComparer interface {
+	IsAncestor(ctx context.Context, installationToken int64, owner, repo, revision, branch string) (bool, error)
+}
+
+// Rollback contains everything needed to redeploy an earlier revision of a root.
+type Rollback struct {
+	RootName          string
+	Repo              models.Repo
+	Branch            string
+	Revision          string
+	InstallationToken int64
+	User              models.User
+
+	// Force overrides active freeze windows
+	Force bool
+}
+
+type RollbackConverter struct {
+	RepoRetriever         repoRetriever
+	InstallationRetriever installationRetriever
+	CommitComparer        commitComparer
+}
+
+func (c *RollbackConverter) Convert(ctx context.Context, r external.RollbackRequest) (Rollback, error) {
+	// this should be set in our auth middleware
+	username := ctx.Value(middleware.UsernameContextKey)
+	if username == nil {
+		return Rollback{}, fmt.Errorf("user not provided")
+	}
+
+	installation, err := c.InstallationRetriever.FindOrganizationInstallation(ctx, r.Repo.Owner)
+	if err != nil {
+		return Rollback{}, errors.Wrap(err, "finding installation")
+	}
+
+	repository, err := c.RepoRetriever.Get(ctx, installation.Token, r.Repo.Owner, r.Repo.Name)
+	if err != nil {
+		return Rollback{}, errors.Wrap(err, "getting repo")
+	}
+
+	if len(repository.DefaultBranch) == 0 {
+		return Rollback{}, fmt.Errorf("default branch was nil, this is a bug on github's side")
+	}
+
+	// rollbacks are only supported for revisions of the default branch
+	isAncestor, err := c.CommitComparer.IsAncestor(ctx, installation.Token, r.Repo.Owner, r.Repo.Name, r.Revision, repository.DefaultBranch)
+	if err != nil {
+		return Rollback{}, errors.Wrap(err, "comparing revision to the default branch")
+	}
+	if !isAncestor {
+		return Rollback{}, fmt.Errorf("revision %s is not on the default branch %s", r.Revision, repository.DefaultBranch)
+	}
+
+	return Rollback{
+		RootName: r.Root,
+		Repo:     repository,
+
+		Branch:            repository.DefaultBranch,
+		Revision:          r.Revision,
+		InstallationToken: installation.Token,
+		User: models.User{
+			Username: username.(string),
+		},
+		Force: r.Force,
+	}, nil
+}
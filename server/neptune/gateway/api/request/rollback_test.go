@@ -0,0 +1,155 @@
+package request_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/middleware"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request/external"
+	"github.com/runatlantis/atlantis/server/vcs/provider/github"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSha = "0123456789abcdef0123456789abcdef01234567"
+
+type commitComparer struct {
+	expectedT        *testing.T
+	expectedToken    int64
+	expectedRevision string
+	expectedBranch   string
+
+	isAncestor bool
+	err        error
+}
+
+func (c *commitComparer) IsAncestor(ctx context.Context, installationToken int64, owner, repo, revision, branch string) (bool, error) {
+	assert.Equal(c.expectedT, c.expectedToken, installationToken)
+	assert.Equal(c.expectedT, "nish", owner)
+	assert.Equal(c.expectedT, "repo", repo)
+	assert.Equal(c.expectedT, c.expectedRevision, revision)
+	assert.Equal(c.expectedT, c.expectedBranch, branch)
+
+	return c.isAncestor, c.err
+}
+
+func newRollbackConverter(t *testing.T, comparer *commitComparer) *request.RollbackConverter {
+	return &request.RollbackConverter{
+		InstallationRetriever: &installationRetriever{
+			expectedT:          t,
+			expectedOrg:        "nish",
+			resultInstallation: github.Installation{Token: 1},
+		},
+		RepoRetriever: &repoRetriever{
+			expectedT:     t,
+			expectedToken: 1,
+			expectedOwner: "nish",
+			expectedRepo:  "repo",
+			resultRepo: models.Repo{
+				Name:          "repo",
+				Owner:         "nish",
+				DefaultBranch: "main",
+			},
+		},
+		CommitComparer: comparer,
+	}
+}
+
+func TestRollbackConverter_Success(t *testing.T) {
+	var token int64 = 1
+	expectedRepo := models.Repo{
+		Name:          "repo",
+		Owner:         "nish",
+		DefaultBranch: "main",
+	}
+
+	subject := newRollbackConverter(t, &commitComparer{
+		expectedT:        t,
+		expectedToken:    token,
+		expectedRevision: testSha,
+		expectedBranch:   "main",
+		isAncestor:       true,
+	})
+
+	result, err := subject.Convert(context.WithValue(context.Background(), middleware.UsernameContextKey, "user"), external.RollbackRequest{
+		Root:     "root1",
+		Repo:     external.Repo{Owner: "nish", Name: "repo"},
+		Revision: testSha,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, request.Rollback{
+		RootName:          "root1",
+		Repo:              expectedRepo,
+		Branch:            "main",
+		Revision:          testSha,
+		InstallationToken: token,
+		User:              models.User{Username: "user"},
+	}, result)
+}
+
+func TestRollbackConverter_RevisionNotOnDefaultBranch(t *testing.T) {
+	subject := newRollbackConverter(t, &commitComparer{
+		expectedT:        t,
+		expectedToken:    1,
+		expectedRevision: testSha,
+		expectedBranch:   "main",
+	})
+
+	_, err := subject.Convert(context.WithValue(context.Background(), middleware.UsernameContextKey, "user"), external.RollbackRequest{
+		Root:     "root1",
+		Repo:     external.Repo{Owner: "nish", Name: "repo"},
+		Revision: testSha,
+	})
+	assert.ErrorContains(t, err, "not on the default branch")
+}
+
+func TestRollbackConverter_CompareError(t *testing.T) {
+	subject := newRollbackConverter(t, &commitComparer{
+		expectedT:        t,
+		expectedToken:    1,
+		expectedRevision: testSha,
+		expectedBranch:   "main",
+		err:              errors.New("error"),
+	})
+
+	_, err := subject.Convert(context.WithValue(context.Background(), middleware.UsernameContextKey, "user"), external.RollbackRequest{
+		Root:     "root1",
+		Repo:     external.Repo{Owner: "nish", Name: "repo"},
+		Revision: testSha,
+	})
+	assert.Error(t, err)
+}
+
+func TestRollbackConverter_UsernameMissing(t *testing.T) {
+	subject := &request.RollbackConverter{
+		InstallationRetriever: &installationRetriever{},
+		RepoRetriever:         &repoRetriever{},
+	}
+
+	_, err := subject.Convert(context.Background(), external.RollbackRequest{
+		Root:     "root1",
+		Repo:     external.Repo{Owner: "nish", Name: "repo"},
+		Revision: testSha,
+	})
+	assert.Error(t, err)
+}
+
+func TestRollbackRequest_Validate(t *testing.T) {
+	valid := external.RollbackRequest{
+		Root:     "root1",
+		Repo:     external.Repo{Owner: "nish", Name: "repo"},
+		Revision: testSha,
+	}
+	assert.NoError(t, valid.Validate())
+
+	abbreviated := valid
+	abbreviated.Revision = "0123456"
+	assert.Error(t, abbreviated.Validate())
+
+	missingRoot := valid
+	missingRoot.Root = ""
+	assert.Error(t, missingRoot.Validate())
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"go.temporal.io/api/serviceerror"
+)
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, logger logging.Logger, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Error(fmt.Sprintf("writing response: %s", err))
+	}
+}
+
+func writeError(w http.ResponseWriter, logger logging.Logger, status int, err error) {
+	writeJSON(w, logger, status, ErrorResponse{
+		Error: err.Error(),
+	})
+}
+
+// writeTemporalError maps workflows which can't be found to a 404
+func writeTemporalError(w http.ResponseWriter, logger logging.Logger, err error, msg string) {
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		writeError(w, logger, http.StatusNotFound, errors.Wrap(err, msg))
+		return
+	}
+	writeError(w, logger, http.StatusInternalServerError, errors.Wrap(err, msg))
+}
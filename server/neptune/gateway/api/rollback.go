@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+)
+
+// RollbackHandler deploys an earlier revision of a root, bypassing the check which rejects
+// revisions behind the latest deployed one.  The plan always has to be confirmed.
+type RollbackHandler struct {
+	Deployer rootDeployer
+	Logger   logging.Logger
+}
+
+func (c *RollbackHandler) Handle(ctx context.Context, r request.Rollback) (DeployResponse, error) {
+	c.Logger.InfoContext(ctx, "handling rollback API request", map[string]interface{}{
+		"root":     r.RootName,
+		"revision": r.Revision,
+		"user":     r.User.Username,
+	})
+
+	rootDeployments, err := c.Deployer.DeployRoots(ctx, deploy.RootDeployOptions{
+		Repo:              r.Repo,
+		Branch:            r.Branch,
+		Revision:          r.Revision,
+		RootNames:         []string{r.RootName},
+		Sender:            r.User,
+		InstallationToken: r.InstallationToken,
+		TriggerInfo: workflows.DeployTriggerInfo{
+			Type:     workflows.ManualTrigger,
+			Rollback: true,
+			Force:    r.Force,
+		},
+	})
+	if err != nil {
+		return DeployResponse{}, err
+	}
+
+	return toDeployResponse(rootDeployments), nil
+}
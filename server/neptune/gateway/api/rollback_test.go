@@ -0,0 +1,84 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/api/request"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingRootDeployer struct {
+	options     deploy.RootDeployOptions
+	deployments []deploy.RootDeployment
+	err         error
+}
+
+func (d *recordingRootDeployer) DeployRoots(ctx context.Context, deployOptions deploy.RootDeployOptions) ([]deploy.RootDeployment, error) {
+	d.options = deployOptions
+	return d.deployments, d.err
+}
+
+func TestRollbackHandler_Handle(t *testing.T) {
+	repo := models.Repo{Owner: "owner", Name: "repo", DefaultBranch: "main"}
+	user := models.User{Username: "nish"}
+	deployer := &recordingRootDeployer{
+		deployments: []deploy.RootDeployment{
+			{Root: "root", WorkflowID: "owner/repo||root", RunID: "1234", DeploymentID: "abcd"},
+		},
+	}
+
+	handler := &api.RollbackHandler{
+		Deployer: deployer,
+		Logger:   logging.NewNoopCtxLogger(t),
+	}
+
+	resp, err := handler.Handle(context.Background(), request.Rollback{
+		RootName:          "root",
+		Repo:              repo,
+		Branch:            "main",
+		Revision:          "abc",
+		InstallationToken: 1,
+		User:              user,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, deploy.RootDeployOptions{
+		Repo:              repo,
+		RootNames:         []string{"root"},
+		Branch:            "main",
+		Revision:          "abc",
+		Sender:            user,
+		InstallationToken: 1,
+		TriggerInfo: workflows.DeployTriggerInfo{
+			Type:     workflows.ManualTrigger,
+			Rollback: true,
+		},
+	}, deployer.options)
+
+	assert.Equal(t, api.DeployResponse{
+		Deployments: []api.Deployment{
+			{
+				ID:         api.BuildDeploymentStatusID("owner/repo||root", "abcd"),
+				Root:       "root",
+				WorkflowID: "owner/repo||root",
+				RunID:      "1234",
+			},
+		},
+	}, resp)
+}
+
+func TestRollbackHandler_Handle_Error(t *testing.T) {
+	handler := &api.RollbackHandler{
+		Deployer: &recordingRootDeployer{err: assert.AnError},
+		Logger:   logging.NewNoopCtxLogger(t),
+	}
+
+	_, err := handler.Handle(context.Background(), request.Rollback{RootName: "root"})
+	assert.Error(t, err)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/config"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
 	contextInternal "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/requirement"
 	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"github.com/runatlantis/atlantis/server/vcs/provider/github"
 	"go.temporal.io/sdk/client"
@@ -23,10 +25,18 @@ type rootConfigBuilder interface {
 	Build(ctx context.Context, commit *config.RepoCommit, installationToken int64, opts ...config.BuilderOptions) ([]*valid.MergedProjectCfg, error)
 }
 
+type requirementChecker interface {
+	Check(ctx context.Context, criteria requirement.Criteria) error
+}
+
 type RootDeployer struct {
 	Logger            logging.Logger
 	RootConfigBuilder rootConfigBuilder
 	DeploySignaler    deploySignaler
+
+	// FreezeRequirement is optional and rejects manual deploys of frozen roots unless an admin forces them,
+	// merged revisions are held in the queue by the deploy workflow instead
+	FreezeRequirement requirementChecker
 }
 
 // RootDeployOptions is basically a modeled request for RootDeployer, options isn't really the right word here
@@ -51,9 +61,35 @@ type RootDeployOptions struct {
 	// instead we should just inject implementations of RepoFetcher to handle different scenarios
 	RepoFetcherOptions *github.RepoFetcherOptions
 	TriggerInfo        workflows.DeployTriggerInfo
+
+	// DeploymentID is set for each root by RootDeployer so the deployment can be tracked
+	DeploymentID string
+
+	// DependsOn and Dependents are set for each root by RootDeployer and only contain
+	// other roots which are deployed alongside it
+	DependsOn  []string
+	Dependents []string
+
+	// PromotionGate is set by RootDeployer for roots promoted to from an earlier stage of a promotion chain
+	PromotionGate *workflows.PromotionGate
+}
+
+// RootDeployment identifies the deployment of a single root within a deploy workflow
+type RootDeployment struct {
+	Root         string
+	WorkflowID   string
+	RunID        string
+	DeploymentID string
 }
 
 func (d *RootDeployer) Deploy(ctx context.Context, deployOptions RootDeployOptions) error {
+	_, err := d.DeployRoots(ctx, deployOptions)
+	return err
+}
+
+// DeployRoots signals a deploy workflow for each resolved root and returns
+// information which can be used to track each deployment.
+func (d *RootDeployer) DeployRoots(ctx context.Context, deployOptions RootDeployOptions) ([]RootDeployment, error) {
 	commit := &config.RepoCommit{
 		Repo:          deployOptions.Repo,
 		Branch:        deployOptions.Branch,
@@ -68,22 +104,137 @@ func (d *RootDeployer) Deploy(ctx context.Context, deployOptions RootDeployOptio
 
 	rootCfgs, err := d.RootConfigBuilder.Build(ctx, commit, deployOptions.InstallationToken, opts)
 	if err != nil {
-		return errors.Wrap(err, "generating roots")
+		return nil, errors.Wrap(err, "generating roots")
 	}
+
+	var platformRootCfgs []*valid.MergedProjectCfg
 	for _, rootCfg := range rootCfgs {
-		c := context.WithValue(ctx, contextInternal.ProjectKey, rootCfg.Name)
 		if rootCfg.WorkflowMode != valid.PlatformWorkflowMode {
+			c := context.WithValue(ctx, contextInternal.ProjectKey, rootCfg.Name)
 			d.Logger.WarnContext(c, "root is not configured for platform mode, skipping...")
 			continue
 		}
-		run, err := d.DeploySignaler.SignalWithStartWorkflow(c, rootCfg, deployOptions)
+		platformRootCfgs = append(platformRootCfgs, rootCfg)
+	}
+
+	if deployOptions.TriggerInfo.Type == workflows.ManualTrigger && d.FreezeRequirement != nil {
+		err := d.FreezeRequirement.Check(ctx, requirement.Criteria{
+			User:              deployOptions.Sender,
+			Branch:            deployOptions.Branch,
+			Repo:              deployOptions.Repo,
+			InstallationToken: deployOptions.InstallationToken,
+			TriggerInfo:       deployOptions.TriggerInfo,
+			Roots:             platformRootCfgs,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "checking freeze windows")
+		}
+	}
+
+	dependsOn, dependents, promotionGates := buildDependencies(platformRootCfgs)
+
+	var deployments []RootDeployment
+	for _, rootCfg := range orderByDependents(platformRootCfgs, dependents) {
+		c := context.WithValue(ctx, contextInternal.ProjectKey, rootCfg.Name)
+
+		rootDeployOptions := deployOptions
+		rootDeployOptions.DeploymentID = uuid.NewString()
+		rootDeployOptions.DependsOn = dependsOn[rootCfg.Name]
+		rootDeployOptions.Dependents = dependents[rootCfg.Name]
+		rootDeployOptions.PromotionGate = promotionGates[rootCfg.Name]
+
+		run, err := d.DeploySignaler.SignalWithStartWorkflow(c, rootCfg, rootDeployOptions)
 		if err != nil {
-			return errors.Wrap(err, "signalling workflow")
+			return deployments, errors.Wrap(err, "signalling workflow")
 		}
 
 		d.Logger.InfoContext(c, "Signaled workflow.", map[string]interface{}{
-			"workflow-id": run.GetID(), "run-id": run.GetRunID(),
+			"workflow-id": run.GetID(), "run-id": run.GetRunID(), "deployment-id": rootDeployOptions.DeploymentID,
 		})
+
+		deployments = append(deployments, RootDeployment{
+			Root:         rootCfg.Name,
+			WorkflowID:   run.GetID(),
+			RunID:        run.GetRunID(),
+			DeploymentID: rootDeployOptions.DeploymentID,
+		})
+	}
+	return deployments, nil
+}
+
+// buildDependencies maps each root to the roots it depends on and the roots which depend on it,
+// roots which aren't being deployed are ignored since there is nothing to wait on.  A root within
+// a promotion chain depends on the nearest previous stage which is being deployed.
+func buildDependencies(rootCfgs []*valid.MergedProjectCfg) (map[string][]string, map[string][]string, map[string]*workflows.PromotionGate) {
+	deployed := make(map[string]bool)
+	for _, rootCfg := range rootCfgs {
+		deployed[rootCfg.Name] = true
+	}
+
+	dependsOn := make(map[string][]string)
+	dependents := make(map[string][]string)
+	addDependency := func(root string, upstream string) {
+		for _, r := range dependsOn[root] {
+			if r == upstream {
+				return
+			}
+		}
+		dependsOn[root] = append(dependsOn[root], upstream)
+		dependents[upstream] = append(dependents[upstream], root)
+	}
+
+	promotionGates := make(map[string]*workflows.PromotionGate)
+	for _, rootCfg := range rootCfgs {
+		for _, upstream := range rootCfg.DependsOn {
+			if deployed[upstream] {
+				addDependency(rootCfg.Name, upstream)
+			}
+		}
+
+		stage, ok := rootCfg.DeploymentWorkflow.PromotionStage(rootCfg.Name)
+		if !ok {
+			continue
+		}
+		for _, previous := range rootCfg.DeploymentWorkflow.PreviousStages(rootCfg.Name) {
+			if !deployed[previous.Root] {
+				continue
+			}
+			addDependency(rootCfg.Name, previous.Root)
+			promotionGates[rootCfg.Name] = &workflows.PromotionGate{
+				Soak:   stage.Soak,
+				Manual: stage.ManualGate,
+			}
+			break
+		}
+	}
+	return dependsOn, dependents, promotionGates
+}
+
+// orderByDependents orders roots such that each root comes after the roots which depend on it.
+// Dependents are signaled first so that their workflows are running by the time an upstream root
+// signals them with the result of its deployment.
+func orderByDependents(rootCfgs []*valid.MergedProjectCfg, dependents map[string][]string) []*valid.MergedProjectCfg {
+	byName := make(map[string]*valid.MergedProjectCfg)
+	for _, rootCfg := range rootCfgs {
+		byName[rootCfg.Name] = rootCfg
+	}
+
+	var ordered []*valid.MergedProjectCfg
+	visited := make(map[string]bool)
+	var visit func(rootCfg *valid.MergedProjectCfg)
+	visit = func(rootCfg *valid.MergedProjectCfg) {
+		if visited[rootCfg.Name] {
+			return
+		}
+		visited[rootCfg.Name] = true
+		for _, dependent := range dependents[rootCfg.Name] {
+			visit(byName[dependent])
+		}
+		ordered = append(ordered, rootCfg)
+	}
+
+	for _, rootCfg := range rootCfgs {
+		visit(rootCfg)
 	}
-	return nil
+	return ordered
 }
@@ -3,6 +3,7 @@ package deploy_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -10,6 +11,8 @@ import (
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/config"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/requirement"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"github.com/runatlantis/atlantis/server/vcs/provider/github"
 	"github.com/stretchr/testify/assert"
 	"go.temporal.io/sdk/client"
@@ -137,12 +140,168 @@ func TestDeploy(t *testing.T) {
 			},
 		}
 
-		err := deployer.Deploy(ctx, deployOptions)
+		deployments, err := deployer.DeployRoots(ctx, deployOptions)
 		assert.NoError(t, err)
 		assert.True(t, signaler.called)
+
+		assert.Len(t, deployments, 1)
+		assert.Equal(t, testRoot, deployments[0].Root)
+		assert.Equal(t, "123", deployments[0].WorkflowID)
+		assert.Equal(t, "456", deployments[0].RunID)
+		assert.NotEmpty(t, deployments[0].DeploymentID)
+		assert.Equal(t, deployments[0].DeploymentID, signaler.capturedOptions.DeploymentID)
+	})
+
+	t.Run("dependent roots", func(t *testing.T) {
+		ctx := context.Background()
+		signaler := &mockDeploySignaler{run: testRun{}}
+		rootCfgs := []*valid.MergedProjectCfg{
+			{Name: "network", WorkflowMode: valid.PlatformWorkflowMode},
+			{Name: "service", WorkflowMode: valid.PlatformWorkflowMode, DependsOn: []string{"network", "dns"}},
+			{Name: "dns", WorkflowMode: valid.PlatformWorkflowMode, DependsOn: []string{"network"}},
+			// roots which aren't deployed alongside their upstream roots don't wait on them
+			{Name: "monitoring", WorkflowMode: valid.PlatformWorkflowMode, DependsOn: []string{"unmodified"}},
+		}
+		deployer := deploy.RootDeployer{
+			DeploySignaler: signaler,
+			Logger:         logger,
+			RootConfigBuilder: &mockRootConfigBuilder{
+				expectedT:      t,
+				expectedCommit: commit,
+				expectedToken:  deployOptions.InstallationToken,
+				expectedOptions: []config.BuilderOptions{
+					{
+						RootNames:          deployOptions.RootNames,
+						RepoFetcherOptions: deployOptions.RepoFetcherOptions,
+					},
+				},
+				rootConfigs: rootCfgs,
+			},
+		}
+
+		deployments, err := deployer.DeployRoots(ctx, deployOptions)
+		assert.NoError(t, err)
+		assert.Len(t, deployments, 4)
+
+		// dependents are signaled before the roots they depend on
+		assert.Equal(t, []string{"service", "dns", "network", "monitoring"}, signaler.signaledRoots)
+
+		var dependsOn, dependents [][]string
+		for _, opts := range signaler.signaledOptions {
+			dependsOn = append(dependsOn, opts.DependsOn)
+			dependents = append(dependents, opts.Dependents)
+		}
+		assert.Equal(t, [][]string{{"network", "dns"}, {"network"}, nil, nil}, dependsOn)
+		assert.Equal(t, [][]string{nil, {"service"}, {"service", "dns"}, nil}, dependents)
+	})
+
+	t.Run("promotion chain", func(t *testing.T) {
+		ctx := context.Background()
+		signaler := &mockDeploySignaler{run: testRun{}}
+		promoted := valid.Workflow{
+			Promotion: []valid.PromotionStage{
+				{Root: "staging"},
+				{Root: "canary", Soak: 30 * time.Minute},
+				{Root: "prod", Soak: time.Hour, ManualGate: true},
+			},
+		}
+		// canary isn't being deployed so prod is promoted to from staging
+		rootCfgs := []*valid.MergedProjectCfg{
+			{Name: "staging", WorkflowMode: valid.PlatformWorkflowMode, DeploymentWorkflow: promoted},
+			{Name: "prod", WorkflowMode: valid.PlatformWorkflowMode, DeploymentWorkflow: promoted},
+		}
+		deployer := deploy.RootDeployer{
+			DeploySignaler: signaler,
+			Logger:         logger,
+			RootConfigBuilder: &mockRootConfigBuilder{
+				expectedT:      t,
+				expectedCommit: commit,
+				expectedToken:  deployOptions.InstallationToken,
+				expectedOptions: []config.BuilderOptions{
+					{
+						RootNames:          deployOptions.RootNames,
+						RepoFetcherOptions: deployOptions.RepoFetcherOptions,
+					},
+				},
+				rootConfigs: rootCfgs,
+			},
+		}
+
+		_, err := deployer.DeployRoots(ctx, deployOptions)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"prod", "staging"}, signaler.signaledRoots)
+
+		prod := signaler.signaledOptions[0]
+		assert.Equal(t, []string{"staging"}, prod.DependsOn)
+		assert.Equal(t, &workflows.PromotionGate{Soak: time.Hour, Manual: true}, prod.PromotionGate)
+
+		staging := signaler.signaledOptions[1]
+		assert.Equal(t, []string{"prod"}, staging.Dependents)
+		assert.Nil(t, staging.PromotionGate)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		rootCfgs := []*valid.MergedProjectCfg{
+			{Name: testRoot, WorkflowMode: valid.PlatformWorkflowMode},
+		}
+
+		cases := []struct {
+			description string
+			trigger     workflows.Trigger
+			checked     bool
+		}{
+			{description: "manual deploys are rejected", trigger: workflows.ManualTrigger, checked: true},
+			{description: "merged revisions are held by the workflow", trigger: workflows.MergeTrigger},
+		}
+
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				signaler := &mockDeploySignaler{run: testRun{}}
+				freeze := &mockRequirement{err: assert.AnError}
+				deployer := deploy.RootDeployer{
+					DeploySignaler: signaler,
+					Logger:         logger,
+					RootConfigBuilder: &mockRootConfigBuilder{
+						expectedT:      t,
+						expectedCommit: commit,
+						expectedToken:  deployOptions.InstallationToken,
+						expectedOptions: []config.BuilderOptions{
+							{
+								RootNames:          deployOptions.RootNames,
+								RepoFetcherOptions: deployOptions.RepoFetcherOptions,
+							},
+						},
+						rootConfigs: rootCfgs,
+					},
+					FreezeRequirement: freeze,
+				}
+
+				opts := deployOptions
+				opts.TriggerInfo = workflows.DeployTriggerInfo{Type: c.trigger}
+
+				_, err := deployer.DeployRoots(context.Background(), opts)
+				assert.Equal(t, c.checked, err != nil)
+				assert.Equal(t, !c.checked, signaler.called)
+				if c.checked {
+					assert.Equal(t, rootCfgs, freeze.criteria.Roots)
+					assert.Equal(t, opts.TriggerInfo, freeze.criteria.TriggerInfo)
+				}
+			})
+		}
 	})
 }
 
+type mockRequirement struct {
+	criteria requirement.Criteria
+	err      error
+}
+
+func (r *mockRequirement) Check(_ context.Context, criteria requirement.Criteria) error {
+	r.criteria = criteria
+	return r.err
+}
+
 type mockRootConfigBuilder struct {
 	expectedCommit  *config.RepoCommit
 	expectedToken   int64
@@ -163,6 +322,10 @@ type mockDeploySignaler struct {
 	run    client.WorkflowRun
 	error  error
 	called bool
+
+	capturedOptions deploy.RootDeployOptions
+	signaledRoots   []string
+	signaledOptions []deploy.RootDeployOptions
 }
 
 func (d *mockDeploySignaler) SignalWorkflow(_ context.Context, _ string, _ string, _ string, _ interface{}) error {
@@ -170,7 +333,10 @@ func (d *mockDeploySignaler) SignalWorkflow(_ context.Context, _ string, _ strin
 	return d.error
 }
 
-func (d *mockDeploySignaler) SignalWithStartWorkflow(_ context.Context, _ *valid.MergedProjectCfg, _ deploy.RootDeployOptions) (client.WorkflowRun, error) {
+func (d *mockDeploySignaler) SignalWithStartWorkflow(_ context.Context, rootCfg *valid.MergedProjectCfg, opts deploy.RootDeployOptions) (client.WorkflowRun, error) {
 	d.called = true
+	d.capturedOptions = opts
+	d.signaledRoots = append(d.signaledRoots, rootCfg.Name)
+	d.signaledOptions = append(d.signaledOptions, opts)
 	return d.run, d.error
 }
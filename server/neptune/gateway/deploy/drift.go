@@ -0,0 +1,91 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	contextInternal "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/config"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"go.temporal.io/sdk/client"
+)
+
+type workflowStarter interface {
+	ExecuteWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) (client.WorkflowRun, error)
+}
+
+// RootDriftOptions identifies the deployed revision of a repo's roots we want to check for drift
+type RootDriftOptions struct {
+	Repo              models.Repo
+	RootNames         []string
+	Branch            string
+	Revision          string
+	InstallationToken int64
+}
+
+// RootDriftDetector starts a drift workflow for each root which plans
+// against the provided revision without applying.
+type RootDriftDetector struct {
+	Logger            logging.Logger
+	RootConfigBuilder rootConfigBuilder
+	TemporalClient    workflowStarter
+}
+
+func (d *RootDriftDetector) Detect(ctx context.Context, opts RootDriftOptions) error {
+	commit := &config.RepoCommit{
+		Repo:   opts.Repo,
+		Branch: opts.Branch,
+		Sha:    opts.Revision,
+	}
+
+	rootCfgs, err := d.RootConfigBuilder.Build(ctx, commit, opts.InstallationToken, config.BuilderOptions{
+		RootNames: opts.RootNames,
+	})
+	if err != nil {
+		return errors.Wrap(err, "generating roots")
+	}
+
+	for _, rootCfg := range rootCfgs {
+		c := context.WithValue(ctx, contextInternal.ProjectKey, rootCfg.Name)
+		if rootCfg.WorkflowMode != valid.PlatformWorkflowMode {
+			d.Logger.WarnContext(c, "root is not configured for platform mode, skipping...")
+			continue
+		}
+
+		// an existing run for this root is returned if one is still in progress
+		run, err := d.TemporalClient.ExecuteWorkflow(
+			c,
+			client.StartWorkflowOptions{
+				ID:        BuildDriftWorkflowID(opts.Repo.FullName, rootCfg.Name),
+				TaskQueue: workflows.DeployTaskQueue,
+				SearchAttributes: map[string]interface{}{
+					"atlantis_repository": opts.Repo.FullName,
+					"atlantis_root":       rootCfg.Name,
+				},
+			},
+			workflows.Drift,
+			workflows.DriftRequest{
+				Repo:     buildRepo(opts.Repo, opts.InstallationToken),
+				Root:     buildRoot(rootCfg, workflows.DeployTriggerInfo{}),
+				Revision: opts.Revision,
+				Branch:   opts.Branch,
+			},
+		)
+		if err != nil {
+			return errors.Wrap(err, "starting drift workflow")
+		}
+
+		d.Logger.InfoContext(c, "Started drift workflow.", map[string]interface{}{
+			"workflow-id": run.GetID(), "run-id": run.GetRunID(),
+		})
+	}
+	return nil
+}
+
+func BuildDriftWorkflowID(repoName string, rootName string) string {
+	return fmt.Sprintf("drift||%s||%s", repoName, rootName)
+}
@@ -0,0 +1,99 @@
+package deploy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/config"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/client"
+)
+
+type testWorkflowStarter struct {
+	options []client.StartWorkflowOptions
+	args    []interface{}
+	err     error
+}
+
+func (s *testWorkflowStarter) ExecuteWorkflow(_ context.Context, options client.StartWorkflowOptions, _ interface{}, args ...interface{}) (client.WorkflowRun, error) {
+	s.options = append(s.options, options)
+	s.args = append(s.args, args...)
+	return testRun{}, s.err
+}
+
+func TestRootDriftDetector_Detect(t *testing.T) {
+	logger := logging.NewNoopCtxLogger(t)
+	opts := deploy.RootDriftOptions{
+		Repo: models.Repo{
+			FullName:      "owner/repo",
+			Owner:         "owner",
+			Name:          "repo",
+			DefaultBranch: "main",
+		},
+		RootNames:         []string{testRoot},
+		Branch:            "main",
+		Revision:          "abc",
+		InstallationToken: 2,
+	}
+	commit := &config.RepoCommit{
+		Repo:   opts.Repo,
+		Branch: opts.Branch,
+		Sha:    opts.Revision,
+	}
+
+	t.Run("starts workflow for platform mode roots", func(t *testing.T) {
+		starter := &testWorkflowStarter{}
+		detector := deploy.RootDriftDetector{
+			Logger:         logger,
+			TemporalClient: starter,
+			RootConfigBuilder: &mockRootConfigBuilder{
+				expectedT:       t,
+				expectedCommit:  commit,
+				expectedToken:   opts.InstallationToken,
+				expectedOptions: []config.BuilderOptions{{RootNames: opts.RootNames}},
+				rootConfigs: []*valid.MergedProjectCfg{
+					{Name: testRoot, WorkflowMode: valid.PlatformWorkflowMode},
+					{Name: "legacy", WorkflowMode: valid.DefaultWorkflowMode},
+				},
+			},
+		}
+
+		err := detector.Detect(context.Background(), opts)
+		assert.NoError(t, err)
+		assert.Len(t, starter.options, 1)
+		assert.Equal(t, deploy.BuildDriftWorkflowID("owner/repo", testRoot), starter.options[0].ID)
+		assert.Equal(t, workflows.DeployTaskQueue, starter.options[0].TaskQueue)
+
+		request := starter.args[0].(workflows.DriftRequest)
+		assert.Equal(t, testRoot, request.Root.Name)
+		assert.Equal(t, "owner/repo", request.Repo.FullName)
+		assert.Equal(t, int64(2), request.Repo.Credentials.InstallationToken)
+		assert.Equal(t, "abc", request.Revision)
+		assert.Equal(t, "main", request.Branch)
+	})
+
+	t.Run("start error", func(t *testing.T) {
+		starter := &testWorkflowStarter{err: assert.AnError}
+		detector := deploy.RootDriftDetector{
+			Logger:         logger,
+			TemporalClient: starter,
+			RootConfigBuilder: &mockRootConfigBuilder{
+				expectedT:       t,
+				expectedCommit:  commit,
+				expectedToken:   opts.InstallationToken,
+				expectedOptions: []config.BuilderOptions{{RootNames: opts.RootNames}},
+				rootConfigs: []*valid.MergedProjectCfg{
+					{Name: testRoot, WorkflowMode: valid.PlatformWorkflowMode},
+				},
+			},
+		}
+
+		err := detector.Detect(context.Background(), opts)
+		assert.Error(t, err)
+	})
+}
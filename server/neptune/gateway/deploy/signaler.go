@@ -2,9 +2,9 @@ package deploy
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"go.temporal.io/sdk/client"
 )
@@ -21,7 +21,8 @@ const (
 )
 
 type WorkflowSignaler struct {
-	TemporalClient signaler
+	TemporalClient         signaler
+	ContinueAsNewThreshold int
 }
 
 func (d *WorkflowSignaler) SignalWithStartWorkflow(ctx context.Context, rootCfg *valid.MergedProjectCfg, rootDeployOptions RootDeployOptions) (client.WorkflowRun, error) {
@@ -33,12 +34,12 @@ func (d *WorkflowSignaler) SignalWithStartWorkflow(ctx context.Context, rootCfg
 		},
 	}
 
-	repo := rootDeployOptions.Repo
-	var tfVersion string
-	if rootCfg.TerraformVersion != nil {
-		tfVersion = rootCfg.TerraformVersion.String()
-	}
+	root := buildRoot(rootCfg, rootDeployOptions.TriggerInfo)
+	root.DependsOn = rootDeployOptions.DependsOn
+	root.Dependents = rootDeployOptions.Dependents
+	root.PromotionGate = rootDeployOptions.PromotionGate
 
+	repo := rootDeployOptions.Repo
 	run, err := d.TemporalClient.SignalWithStartWorkflow(
 		ctx,
 		BuildDeployWorkflowID(repo.FullName, rootCfg.Name),
@@ -49,32 +50,10 @@ func (d *WorkflowSignaler) SignalWithStartWorkflow(ctx context.Context, rootCfg
 			InitiatingUser: workflows.User{
 				Name: rootDeployOptions.Sender.Username,
 			},
-			Root: workflows.Root{
-				Name: rootCfg.Name,
-				Plan: workflows.Job{
-					Steps: d.generateSteps(rootCfg.DeploymentWorkflow.Plan.Steps),
-				},
-				Apply: workflows.Job{
-					Steps: d.generateSteps(rootCfg.DeploymentWorkflow.Apply.Steps),
-				},
-				RepoRelPath:  rootCfg.RepoRelDir,
-				TrackedFiles: rootCfg.WhenModified,
-				TfVersion:    tfVersion,
-				PlanMode:     d.generatePlanMode(rootCfg),
-				TriggerInfo:  rootDeployOptions.TriggerInfo,
-			},
-			Repo: workflows.Repo{
-				URL:      repo.CloneURL,
-				FullName: repo.FullName,
-				Name:     repo.Name,
-				Owner:    repo.Owner,
-				Credentials: workflows.AppCredentials{
-					InstallationToken: rootDeployOptions.InstallationToken,
-				},
-				RebaseEnabled: true,
-				DefaultBranch: repo.DefaultBranch,
-			},
-			Tags: rootCfg.Tags,
+			Root:         root,
+			Repo:         buildRepo(repo, rootDeployOptions.InstallationToken),
+			Tags:         rootCfg.Tags,
+			DeploymentID: rootDeployOptions.DeploymentID,
 		},
 		options,
 		workflows.Deploy,
@@ -85,16 +64,91 @@ func (d *WorkflowSignaler) SignalWithStartWorkflow(ctx context.Context, rootCfg
 			Root: workflows.DeployRequestRoot{
 				Name: rootCfg.Name,
 			},
+			ContinueAsNewThreshold: d.ContinueAsNewThreshold,
 		},
 	)
 	return run, err
 }
 
 func BuildDeployWorkflowID(repoName string, rootName string) string {
-	return fmt.Sprintf("%s||%s", repoName, rootName)
+	return workflows.BuildDeployWorkflowID(repoName, rootName)
+}
+
+func buildRoot(rootCfg *valid.MergedProjectCfg, triggerInfo workflows.DeployTriggerInfo) workflows.Root {
+	var tfVersion string
+	if rootCfg.TerraformVersion != nil {
+		tfVersion = rootCfg.TerraformVersion.String()
+	}
+
+	return workflows.Root{
+		Name: rootCfg.Name,
+		Plan: workflows.Job{
+			Steps: generateSteps(rootCfg.DeploymentWorkflow.Plan.Steps),
+		},
+		Apply: workflows.Job{
+			Steps: generateSteps(rootCfg.DeploymentWorkflow.Apply.Steps),
+		},
+		RepoRelPath:  rootCfg.RepoRelDir,
+		TrackedFiles: rootCfg.WhenModified,
+		TfVersion:    tfVersion,
+		Engine:       string(rootCfg.Engine),
+		PlanMode:     generatePlanMode(rootCfg),
+		TriggerInfo:  triggerInfo,
+
+		ApprovalPolicy: generateApprovalPolicy(rootCfg.DeploymentWorkflow.ApprovalPolicy),
+	}
+}
+
+func generateApprovalPolicy(policy *valid.ApprovalPolicy) *workflows.PlanApprovalPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	var rules []workflows.PlanApprovalRule
+	for _, r := range policy.Rules {
+		// patterns are sent as globs and compiled by the workflow
+		var addresses []string
+		for _, a := range r.Addresses {
+			addresses = append(addresses, a.String())
+		}
+
+		rules = append(rules, workflows.PlanApprovalRule{
+			Actions:       r.Actions,
+			ResourceTypes: r.ResourceTypes,
+			Addresses:     addresses,
+			Approval:      generateApprovalType(r.Approval),
+		})
+	}
+
+	return &workflows.PlanApprovalPolicy{
+		Default:    generateApprovalType(policy.Default),
+		MaxChanges: policy.MaxChanges,
+		Rules:      rules,
+	}
+}
+
+func generateApprovalType(t valid.ApprovalType) workflows.PlanApprovalType {
+	if t == valid.ManualApprovalType {
+		return workflows.ManualApproval
+	}
+	return workflows.AutoApproval
+}
+
+func buildRepo(repo models.Repo, installationToken int64) workflows.Repo {
+	return workflows.Repo{
+		URL:      repo.CloneURL,
+		FullName: repo.FullName,
+		Name:     repo.Name,
+		Owner:    repo.Owner,
+		Credentials: workflows.AppCredentials{
+			InstallationToken: installationToken,
+		},
+		RebaseEnabled: true,
+		DefaultBranch: repo.DefaultBranch,
+	}
 }
 
-func (d *WorkflowSignaler) generateSteps(steps []valid.Step) []workflows.Step {
+func generateSteps(steps []valid.Step) []workflows.Step {
 	// NOTE: for deployment workflows, we won't support command level user requests for log level output verbosity
 	var workflowSteps []workflows.Step
 	for _, step := range steps {
@@ -109,7 +163,7 @@ func (d *WorkflowSignaler) generateSteps(steps []valid.Step) []workflows.Step {
 	return workflowSteps
 }
 
-func (d *WorkflowSignaler) generatePlanMode(cfg *valid.MergedProjectCfg) workflows.PlanMode {
+func generatePlanMode(cfg *valid.MergedProjectCfg) workflows.PlanMode {
 	t, ok := cfg.Tags[Deprecated]
 	if ok && t == Destroy {
 		return workflows.DestroyPlanMode
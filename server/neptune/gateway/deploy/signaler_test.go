@@ -171,6 +171,184 @@ func TestSignalWithStartWorkflow_Success(t *testing.T) {
 		assert.Equal(t, testRun{}, run)
 	})
 
+	t.Run("success w/dependencies", func(t *testing.T) {
+		rootCfg := valid.MergedProjectCfg{
+			Name: testRoot,
+			DeploymentWorkflow: valid.Workflow{
+				Plan:  valid.DefaultPlanStage,
+				Apply: valid.DefaultApplyStage,
+			},
+			TerraformVersion: version,
+		}
+
+		testSignaler := &testSignaler{
+			t:                  t,
+			expectedWorkflowID: fmt.Sprintf("%s||%s", repoFullName, testRoot),
+			expectedSignalName: workflows.DeployNewRevisionSignalID,
+			expectedSignalArg: workflows.DeployNewRevisionSignalRequest{
+				Revision: sha,
+				Branch:   branch,
+				Root: workflows.Root{
+					Name: testRoot,
+					Plan: workflows.Job{
+						Steps: convertTestSteps(valid.DefaultPlanStage.Steps),
+					},
+					Apply: workflows.Job{
+						Steps: convertTestSteps(valid.DefaultApplyStage.Steps),
+					},
+					TfVersion: version.String(),
+					PlanMode:  workflows.NormalPlanMode,
+					TriggerInfo: workflows.DeployTriggerInfo{
+						Type: workflows.MergeTrigger,
+					},
+					DependsOn:  []string{"network"},
+					Dependents: []string{"service"},
+				},
+				InitiatingUser: workflows.User{
+					Name: user.Username,
+				},
+				Repo: workflows.Repo{
+					FullName:      repoFullName,
+					Name:          repoName,
+					Owner:         repoOwner,
+					URL:           repoURL,
+					RebaseEnabled: true,
+				},
+			},
+			expectedWorkflow: workflows.Deploy,
+			expectedOptions: client.StartWorkflowOptions{
+				TaskQueue: workflows.DeployTaskQueue,
+				SearchAttributes: map[string]interface{}{
+					"atlantis_repository": repo.FullName,
+					"atlantis_root":       rootCfg.Name,
+				},
+			},
+			expectedWorkflowArgs: workflows.DeployRequest{
+				Repo: workflows.DeployRequestRepo{
+					FullName: repoFullName,
+				},
+				Root: workflows.DeployRequestRoot{
+					Name: rootCfg.Name,
+				},
+			},
+		}
+		deploySignaler := deploy.WorkflowSignaler{
+			TemporalClient: testSignaler,
+		}
+		rootDeployOptions := deploy.RootDeployOptions{
+			Repo:     repo,
+			Revision: sha,
+			Branch:   branch,
+			Sender:   user,
+			TriggerInfo: workflows.DeployTriggerInfo{
+				Type: workflows.MergeTrigger,
+			},
+			DependsOn:  []string{"network"},
+			Dependents: []string{"service"},
+		}
+		run, err := deploySignaler.SignalWithStartWorkflow(context.Background(), &rootCfg, rootDeployOptions)
+		assert.NoError(t, err)
+		assert.Equal(t, testRun{}, run)
+	})
+
+	t.Run("success w/approval policy", func(t *testing.T) {
+		rootCfg := valid.MergedProjectCfg{
+			Name: testRoot,
+			DeploymentWorkflow: valid.Workflow{
+				Plan:  valid.DefaultPlanStage,
+				Apply: valid.DefaultApplyStage,
+				ApprovalPolicy: &valid.ApprovalPolicy{
+					Default:    valid.AutoApprovalType,
+					MaxChanges: 10,
+					Rules: []valid.ApprovalRule{
+						{
+							Actions:       []string{"delete"},
+							ResourceTypes: []string{"aws_db_instance"},
+							Approval:      valid.ManualApprovalType,
+						},
+					},
+				},
+			},
+			TerraformVersion: version,
+		}
+
+		testSignaler := &testSignaler{
+			t:                  t,
+			expectedWorkflowID: fmt.Sprintf("%s||%s", repoFullName, testRoot),
+			expectedSignalName: workflows.DeployNewRevisionSignalID,
+			expectedSignalArg: workflows.DeployNewRevisionSignalRequest{
+				Revision: sha,
+				Branch:   branch,
+				Root: workflows.Root{
+					Name: testRoot,
+					Plan: workflows.Job{
+						Steps: convertTestSteps(valid.DefaultPlanStage.Steps),
+					},
+					Apply: workflows.Job{
+						Steps: convertTestSteps(valid.DefaultApplyStage.Steps),
+					},
+					TfVersion: version.String(),
+					PlanMode:  workflows.NormalPlanMode,
+					TriggerInfo: workflows.DeployTriggerInfo{
+						Type: workflows.MergeTrigger,
+					},
+					ApprovalPolicy: &workflows.PlanApprovalPolicy{
+						Default:    workflows.AutoApproval,
+						MaxChanges: 10,
+						Rules: []workflows.PlanApprovalRule{
+							{
+								Actions:       []string{"delete"},
+								ResourceTypes: []string{"aws_db_instance"},
+								Approval:      workflows.ManualApproval,
+							},
+						},
+					},
+				},
+				InitiatingUser: workflows.User{
+					Name: user.Username,
+				},
+				Repo: workflows.Repo{
+					FullName:      repoFullName,
+					Name:          repoName,
+					Owner:         repoOwner,
+					URL:           repoURL,
+					RebaseEnabled: true,
+				},
+			},
+			expectedWorkflow: workflows.Deploy,
+			expectedOptions: client.StartWorkflowOptions{
+				TaskQueue: workflows.DeployTaskQueue,
+				SearchAttributes: map[string]interface{}{
+					"atlantis_repository": repo.FullName,
+					"atlantis_root":       rootCfg.Name,
+				},
+			},
+			expectedWorkflowArgs: workflows.DeployRequest{
+				Repo: workflows.DeployRequestRepo{
+					FullName: repoFullName,
+				},
+				Root: workflows.DeployRequestRoot{
+					Name: rootCfg.Name,
+				},
+			},
+		}
+		deploySignaler := deploy.WorkflowSignaler{
+			TemporalClient: testSignaler,
+		}
+		rootDeployOptions := deploy.RootDeployOptions{
+			Repo:     repo,
+			Revision: sha,
+			Branch:   branch,
+			Sender:   user,
+			TriggerInfo: workflows.DeployTriggerInfo{
+				Type: workflows.MergeTrigger,
+			},
+		}
+		run, err := deploySignaler.SignalWithStartWorkflow(context.Background(), &rootCfg, rootDeployOptions)
+		assert.NoError(t, err)
+		assert.Equal(t, testRun{}, run)
+	})
+
 	t.Run("success w/destroy", func(t *testing.T) {
 		rootCfg := valid.MergedProjectCfg{
 			Name: testRoot,
@@ -117,6 +117,13 @@ func (h *CheckRunHandler) handleRequestedAction(ctx context.Context, event Check
 		return h.signalPlanReviewWorkflowChannel(ctx, event, workflows.ApprovedPlanReviewStatus)
 	case "Reject":
 		return h.signalPlanReviewWorkflowChannel(ctx, event, workflows.RejectedPlanReviewStatus)
+	case "Promote":
+		return h.signalPromoteWorkflowChannel(ctx, event, rootName)
+	case "Rollback":
+		// building the root clones the repo so there's no need to block on it
+		return h.AsyncScheduler.Schedule(ctx, func(ctx context.Context) error {
+			return h.rollbackRoot(ctx, event, rootName)
+		})
 	}
 	return fmt.Errorf("unknown action id %s", action.Identifier)
 }
@@ -159,6 +166,49 @@ func (h *CheckRunHandler) signalUnlockWorkflowChannel(ctx context.Context, event
 	return nil
 }
 
+func (h *CheckRunHandler) signalPromoteWorkflowChannel(ctx context.Context, event CheckRun, rootName string) error {
+	workflowID := deploy.BuildDeployWorkflowID(event.Repo.FullName, rootName)
+	err := h.DeploySignaler.SignalWorkflow(
+		ctx,
+		workflowID,
+		// keeping this empty is fine since temporal will find the currently running workflow
+		"",
+		workflows.DeployPromoteSignalName,
+		workflows.DeployPromoteSignalRequest{
+			// the check run external id is the deployment id
+			DeploymentID: event.ExternalID,
+			User:         event.User.Username,
+		})
+	if err != nil {
+		return errors.Wrapf(err, "signaling workflow with id: %s", workflowID)
+	}
+	h.Logger.InfoContext(ctx, fmt.Sprintf("Signaled workflow with id %s to promote deployment %s", workflowID, event.ExternalID))
+	return nil
+}
+
+// rollbackRoot redeploys the check run's revision even though it's behind the latest deployed revision
+func (h *CheckRunHandler) rollbackRoot(ctx context.Context, event CheckRun, rootName string) error {
+	// only revisions of the default branch can be rolled back to, similar to reruns
+	if event.Branch != event.Repo.DefaultBranch {
+		h.Logger.WarnContext(ctx, "dropping rollback for revision which isn't on the default branch")
+		return nil
+	}
+
+	deployOptions := deploy.RootDeployOptions{
+		Repo:              event.Repo,
+		Branch:            event.Branch,
+		RootNames:         []string{rootName},
+		Revision:          event.HeadSha,
+		Sender:            event.User,
+		InstallationToken: event.InstallationToken,
+		TriggerInfo: workflows.DeployTriggerInfo{
+			Type:     workflows.ManualTrigger,
+			Rollback: true,
+		},
+	}
+	return errors.Wrap(h.RootDeployer.Deploy(ctx, deployOptions), "deploying workflow")
+}
+
 func (h *CheckRunHandler) buildRoot(ctx context.Context, event CheckRun, rootName string) error {
 	deployOptions := deploy.RootDeployOptions{
 		Repo:              event.Repo,
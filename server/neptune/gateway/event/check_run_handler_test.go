@@ -12,6 +12,7 @@ import (
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/event"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -217,6 +218,103 @@ func TestCheckRunHandler(t *testing.T) {
 		assert.True(t, signaler.called)
 	})
 
+	t.Run("promote signal success", func(t *testing.T) {
+		user := models.User{Username: "nish"}
+		signaler := &mockDeploySignaler{}
+		logger := logging.NewNoopCtxLogger(t)
+		subject := event.CheckRunHandler{
+			Logger:       logging.NewNoopCtxLogger(t),
+			RootDeployer: &testRootDeployer{},
+			// both are synchronous to keep our tests predictable
+			SyncScheduler:  &sync.SynchronousScheduler{Logger: logger},
+			AsyncScheduler: &sync.SynchronousScheduler{Logger: logger},
+			DeploySignaler: signaler,
+		}
+		e := event.CheckRun{
+			Action: event.RequestedActionChecksAction{
+				Identifier: "Promote",
+			},
+			ExternalID: "1234",
+			User:       user,
+			Repo:       models.Repo{FullName: "testrepo"},
+			Name:       "atlantis/deploy: testroot",
+		}
+		err := subject.Handle(context.Background(), e)
+		assert.NoError(t, err)
+		assert.Equal(t, "testrepo||testroot", signaler.workflowID)
+		assert.Equal(t, workflows.DeployPromoteSignalName, signaler.signalName)
+		assert.Equal(t, workflows.DeployPromoteSignalRequest{DeploymentID: "1234", User: "nish"}, signaler.signalArg)
+	})
+
+	t.Run("rollback success", func(t *testing.T) {
+		repo := models.Repo{DefaultBranch: "main"}
+		user := models.User{Username: "nish"}
+		sha := "12345"
+		rootDeployer := &testRootDeployer{
+			expectedT: t,
+			expectedOptions: deploy.RootDeployOptions{
+				RootNames:         []string{testRoot},
+				Repo:              repo,
+				Branch:            "main",
+				Sender:            user,
+				Revision:          sha,
+				InstallationToken: 2,
+				TriggerInfo: workflows.DeployTriggerInfo{
+					Type:     workflows.ManualTrigger,
+					Rollback: true,
+				},
+			},
+		}
+		logger := logging.NewNoopCtxLogger(t)
+		subject := event.CheckRunHandler{
+			Logger:       logging.NewNoopCtxLogger(t),
+			RootDeployer: rootDeployer,
+			// both are synchronous to keep our tests predictable
+			SyncScheduler:  &sync.SynchronousScheduler{Logger: logger},
+			AsyncScheduler: &sync.SynchronousScheduler{Logger: logger},
+			DeploySignaler: &mockDeploySignaler{},
+		}
+		e := event.CheckRun{
+			Action: event.RequestedActionChecksAction{
+				Identifier: "Rollback",
+			},
+			Name:              "atlantis/deploy: testroot",
+			Repo:              repo,
+			HeadSha:           sha,
+			Branch:            "main",
+			User:              user,
+			InstallationToken: 2,
+		}
+		err := subject.Handle(context.Background(), e)
+		assert.NoError(t, err)
+		assert.True(t, rootDeployer.isCalled)
+	})
+
+	t.Run("rollback non-default branch", func(t *testing.T) {
+		rootDeployer := &testRootDeployer{expectedT: t}
+		logger := logging.NewNoopCtxLogger(t)
+		subject := event.CheckRunHandler{
+			Logger:       logging.NewNoopCtxLogger(t),
+			RootDeployer: rootDeployer,
+			// both are synchronous to keep our tests predictable
+			SyncScheduler:  &sync.SynchronousScheduler{Logger: logger},
+			AsyncScheduler: &sync.SynchronousScheduler{Logger: logger},
+			DeploySignaler: &mockDeploySignaler{},
+		}
+		e := event.CheckRun{
+			Action: event.RequestedActionChecksAction{
+				Identifier: "Rollback",
+			},
+			Name:    "atlantis/deploy: testroot",
+			Repo:    models.Repo{DefaultBranch: "main"},
+			HeadSha: "12345",
+			Branch:  "something",
+		}
+		err := subject.Handle(context.Background(), e)
+		assert.NoError(t, err)
+		assert.False(t, rootDeployer.isCalled)
+	})
+
 	t.Run("non-deploy atlantis check run", func(t *testing.T) {
 		user := models.User{Username: "nish"}
 		workflowID := "testrepo||testroot"
@@ -280,10 +378,17 @@ type mockDeploySignaler struct {
 	run    client.WorkflowRun
 	error  error
 	called bool
+
+	workflowID string
+	signalName string
+	signalArg  interface{}
 }
 
-func (d *mockDeploySignaler) SignalWorkflow(_ context.Context, _ string, _ string, _ string, _ interface{}) error {
+func (d *mockDeploySignaler) SignalWorkflow(_ context.Context, workflowID string, _ string, signalName string, arg interface{}) error {
 	d.called = true
+	d.workflowID = workflowID
+	d.signalName = signalName
+	d.signalArg = arg
 	return d.error
 }
 
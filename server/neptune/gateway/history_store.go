@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+)
+
+// lazyHistoryStore connects to the deployment storage on the first history request, so the
+// gateway starts without deployment storage when the history isn't used.  Failed connections
+// are retried on the next request.
+type lazyHistoryStore struct {
+	config valid.StoreConfig
+	logger logging.Logger
+
+	mutex sync.Mutex
+	store *deployment.Store
+}
+
+func (s *lazyHistoryStore) ListDeploymentHistory(ctx context.Context, repoName string, rootName string, cursor string, limit int) ([]*deployment.HistoryEntry, string, error) {
+	store, err := s.getStore()
+	if err != nil {
+		return nil, "", err
+	}
+	return store.ListDeploymentHistory(ctx, repoName, rootName, cursor, limit)
+}
+
+func (s *lazyHistoryStore) getStore() (*deployment.Store, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.store != nil {
+		return s.store, nil
+	}
+
+	storageClient, err := storage.NewClient(s.config)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing deployment storage client")
+	}
+
+	store, err := deployment.NewStore(storageClient, s.logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing deployment store")
+	}
+	s.store = store
+	return store, nil
+}
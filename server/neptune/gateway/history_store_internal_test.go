@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/local"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyHistoryStore(t *testing.T) {
+	t.Run("storage unavailable", func(t *testing.T) {
+		store := &lazyHistoryStore{
+			config: valid.StoreConfig{BackendType: "unknown"},
+			logger: logging.NewNoopCtxLogger(t),
+		}
+
+		_, _, err := store.ListDeploymentHistory(context.Background(), "owner/repo", "root", "", 10)
+		assert.ErrorContains(t, err, "initializing deployment storage client")
+		assert.Nil(t, store.store)
+	})
+
+	t.Run("connects once", func(t *testing.T) {
+		store := &lazyHistoryStore{
+			config: valid.StoreConfig{
+				ContainerName: "deployments",
+				BackendType:   valid.LocalBackend,
+				Config: stow.ConfigMap{
+					local.ConfigKeyPath: t.TempDir(),
+				},
+			},
+			logger: logging.NewNoopCtxLogger(t),
+		}
+
+		entries, next, err := store.ListDeploymentHistory(context.Background(), "owner/repo", "root", "", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.Empty(t, next)
+
+		connected := store.store
+		_, _, err = store.ListDeploymentHistory(context.Background(), "owner/repo", "root", "", 10)
+		assert.NoError(t, err)
+		assert.Same(t, connected, store.store)
+	})
+}
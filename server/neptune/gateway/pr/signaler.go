@@ -32,8 +32,9 @@ type ValidateEnvs struct {
 }
 
 type WorkflowSignaler struct {
-	TemporalClient   signaler
-	DefaultTFVersion string
+	TemporalClient         signaler
+	DefaultTFVersion       string
+	ContinueAsNewThreshold int
 }
 
 type Request struct {
@@ -73,9 +74,10 @@ func (s *WorkflowSignaler) SignalWithStartWorkflow(ctx context.Context, rootCfgs
 		options,
 		workflows.PR,
 		workflows.PRRequest{
-			RepoFullName: request.Repo.FullName,
-			PRNum:        request.Number,
-			Organization: rootCfgs[0].PolicySets.Organization,
+			RepoFullName:           request.Repo.FullName,
+			PRNum:                  request.Number,
+			Organization:           rootCfgs[0].PolicySets.Organization,
+			ContinueAsNewThreshold: s.ContinueAsNewThreshold,
 		},
 	)
 	return run, err
@@ -96,6 +98,7 @@ func (s *WorkflowSignaler) buildRoots(rootCfgs []*valid.MergedProjectCfg, valida
 			Name:        rootCfg.Name,
 			RepoRelPath: rootCfg.RepoRelDir,
 			TfVersion:   tfVersion,
+			Engine:      string(rootCfg.Engine),
 			PlanMode:    generatePlanMode(rootCfg),
 			Plan:        workflows.PRJob{Steps: generateSteps(rootCfg.PullRequestWorkflow.Plan.Steps)},
 			Validate:    workflows.PRJob{Steps: s.prependValidateEnvSteps(rootCfg, validateEnvOpts...)},
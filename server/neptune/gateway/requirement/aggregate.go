@@ -2,6 +2,7 @@ package requirement
 
 import (
 	"context"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -67,10 +68,27 @@ func NewDeployAggregate(cfg valid.GlobalCfg, teamFetcher *github.TeamMemberFetch
 		// non-overrideable
 		[]Requirement{
 			pull{},
+
+			// admins override freezes by forcing the deploy
+			NewFreeze(cfg, teamFetcher, logger),
 		},
 	)
 }
 
+// NewFreeze returns a requirement which forbids deploying roots during one of their freeze windows
+// unless an admin forces the deploy.
+func NewFreeze(cfg valid.GlobalCfg, teamFetcher *github.TeamMemberFetcher, logger logging.Logger) Requirement {
+	return &freeze{
+		cfg:     cfg,
+		fetcher: teamFetcher,
+		clock:   time.Now,
+		errorGenerator: errorGenerator[template.DeployFrozenData]{
+			logger: logger,
+			loader: template.Loader[template.DeployFrozenData]{GlobalCfg: cfg},
+		},
+	}
+}
+
 func (a *DeployAggregate) Check(ctx context.Context, criteria Criteria) error {
 	for _, d := range a.nonOverrideableRequirements {
 		if err := d.Check(ctx, criteria); err != nil {
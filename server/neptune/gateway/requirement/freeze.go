@@ -0,0 +1,60 @@
+package requirement
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/template"
+)
+
+// freeze forbids deploying roots during one of their freeze windows unless an admin
+// overrides it by forcing the deploy.
+type freeze struct {
+	cfg            valid.GlobalCfg
+	fetcher        fetcher
+	clock          func() time.Time
+	errorGenerator errGenerator[template.DeployFrozenData]
+}
+
+func (r *freeze) Check(ctx context.Context, criteria Criteria) error {
+	var windows []valid.FreezeWindow
+	for _, root := range criteria.Roots {
+		windows = append(windows, root.FreezeWindows...)
+	}
+
+	window, until, frozen := valid.ActiveFreezeWindow(windows, r.clock())
+	if !frozen {
+		return nil
+	}
+
+	admins := r.cfg.Admin.GithubTeam
+	if criteria.TriggerInfo.Force && admins.Name != "" {
+		members, err := r.fetcher.ListTeamMembers(ctx, criteria.InstallationToken, admins.Name)
+		if err != nil {
+			return errors.Wrap(err, "fetching admin team members")
+		}
+
+		for _, m := range members {
+			if criteria.User.Username == m {
+				return nil
+			}
+		}
+	}
+
+	frozenUntil := until.UTC().Format(time.RFC1123)
+	return r.errorGenerator.GenerateForbiddenError(
+		ctx,
+		template.DeployFrozen, criteria.Repo,
+		template.DeployFrozenData{
+			User:   criteria.User.Username,
+			Window: window.Name,
+			Reason: window.Reason,
+			Until:  frozenUntil,
+			Team:   admins.Name,
+			Org:    admins.Org,
+		},
+		"Deploys are frozen until %s by freeze window: %s", frozenUntil, window.Name,
+	)
+}
@@ -0,0 +1,124 @@
+package requirement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/neptune/template"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	now := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	globalCfg := valid.NewGlobalCfg("")
+	globalCfg.Admin = valid.Admin{GithubTeam: valid.GithubTeam{Name: "admins", Org: "lyft"}}
+
+	frozenRoots := []*valid.MergedProjectCfg{
+		{Name: "staging"},
+		{
+			Name: "prod",
+			FreezeWindows: []valid.FreezeWindow{
+				{
+					Name:   "holidays",
+					Reason: "change freeze",
+					Start:  now.Add(-time.Hour),
+					End:    now.Add(time.Hour),
+				},
+			},
+		},
+	}
+
+	t.Run("not frozen", func(t *testing.T) {
+		subject := freeze{
+			cfg:     globalCfg,
+			fetcher: testFetcher{},
+			clock:   clock,
+		}
+
+		err := subject.Check(context.Background(), Criteria{
+			Repo:  models.Repo{Name: "hi"},
+			User:  models.User{Username: "nish"},
+			Roots: []*valid.MergedProjectCfg{{Name: "staging"}},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin override", func(t *testing.T) {
+		subject := freeze{
+			cfg:     globalCfg,
+			fetcher: testFetcher{users: []string{"nish"}},
+			clock:   clock,
+		}
+
+		err := subject.Check(context.Background(), Criteria{
+			Repo:        models.Repo{Name: "hi"},
+			User:        models.User{Username: "nish"},
+			TriggerInfo: workflows.DeployTriggerInfo{Type: workflows.ManualTrigger, Force: true},
+			Roots:       frozenRoots,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin without force", func(t *testing.T) {
+		expectedErr := ForbiddenError{details: "frozen"}
+		subject := freeze{
+			cfg:     globalCfg,
+			fetcher: testFetcher{users: []string{"nish"}},
+			clock:   clock,
+			errorGenerator: testErrGenerator[template.DeployFrozenData]{
+				err: expectedErr,
+			},
+		}
+
+		err := subject.Check(context.Background(), Criteria{
+			Repo:        models.Repo{Name: "hi"},
+			User:        models.User{Username: "nish"},
+			TriggerInfo: workflows.DeployTriggerInfo{Type: workflows.ManualTrigger},
+			Roots:       frozenRoots,
+		})
+		assert.EqualError(t, err, expectedErr.details)
+	})
+
+	t.Run("forced by non admin", func(t *testing.T) {
+		expectedErr := ForbiddenError{details: "frozen"}
+		subject := freeze{
+			cfg:     globalCfg,
+			fetcher: testFetcher{users: []string{"samra"}},
+			clock:   clock,
+			errorGenerator: testErrGenerator[template.DeployFrozenData]{
+				err: expectedErr,
+			},
+		}
+
+		err := subject.Check(context.Background(), Criteria{
+			Repo:        models.Repo{Name: "hi"},
+			User:        models.User{Username: "nish"},
+			TriggerInfo: workflows.DeployTriggerInfo{Type: workflows.ManualTrigger, Force: true},
+			Roots:       frozenRoots,
+		})
+		assert.EqualError(t, err, expectedErr.details)
+	})
+
+	t.Run("fetcher error", func(t *testing.T) {
+		subject := freeze{
+			cfg:     globalCfg,
+			fetcher: testFetcher{err: errors.New("error")},
+			clock:   clock,
+		}
+
+		err := subject.Check(context.Background(), Criteria{
+			Repo:        models.Repo{Name: "hi"},
+			User:        models.User{Username: "nish"},
+			TriggerInfo: workflows.DeployTriggerInfo{Type: workflows.ManualTrigger, Force: true},
+			Roots:       frozenRoots,
+		})
+		assert.Error(t, err)
+	})
+}
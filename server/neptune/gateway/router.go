@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/pprof"
 
@@ -19,7 +20,11 @@ func newRouter(
 	logger logging.Logger,
 	eventsController *lyft_gateway.VCSEventsController,
 	statusController *controllers.StatusController,
-	deployController *api.Controller[request.Deploy],
+	deployController *api.Controller[request.Deploy, api.DeployResponse],
+	rollbackController *api.Controller[request.Rollback, api.DeployResponse],
+	deployStatusController *api.DeployStatusController,
+	deployQueueController *api.DeployQueueController,
+	deploymentHistoryController *api.DeploymentHistoryController,
 	globalCfg valid.GlobalCfg,
 ) *mux.Router {
 	recovery := &commonMiddleware.Recovery{
@@ -44,6 +49,19 @@ func newRouter(
 
 	apiSubrouter.Use(auth.Middleware)
 	apiSubrouter.HandleFunc("/deploy", deployController.Handle).Methods(http.MethodPost)
+	apiSubrouter.HandleFunc("/rollback", rollbackController.Handle).Methods(http.MethodPost)
+	apiSubrouter.HandleFunc(fmt.Sprintf("/deploy/{%s}", api.IDVarKey), deployStatusController.Get).Methods(http.MethodGet)
+
+	// queue path vars are matched encoded so roots containing slashes can be addressed, see parseRepoAndRoot
+	queueSubrouter := apiSubrouter.NewRoute().Subrouter().UseEncodedPath()
+	queuePath := fmt.Sprintf("/deploy/{%s}/{%s}/{%s}", api.OwnerVarKey, api.RepoVarKey, api.RootVarKey)
+	queueSubrouter.HandleFunc(queuePath+"/queue", deployQueueController.List).Methods(http.MethodGet)
+	queueSubrouter.HandleFunc(queuePath+"/queue", deployQueueController.Reorder).Methods(http.MethodPut)
+	queueSubrouter.HandleFunc(fmt.Sprintf("%s/queue/{%s}", queuePath, api.IDVarKey), deployQueueController.Remove).Methods(http.MethodDelete)
+	queueSubrouter.HandleFunc(queuePath+"/unlock", deployQueueController.Unlock).Methods(http.MethodPost)
+	queueSubrouter.HandleFunc(fmt.Sprintf("%s/review/{%s}", queuePath, api.IDVarKey), deployQueueController.Review).Methods(http.MethodPost)
+	queueSubrouter.HandleFunc(fmt.Sprintf("%s/promote/{%s}", queuePath, api.IDVarKey), deployQueueController.Promote).Methods(http.MethodPost)
+	queueSubrouter.HandleFunc(queuePath+"/history", deploymentHistoryController.List).Methods(http.MethodGet)
 
 	return router
 }
@@ -31,11 +31,14 @@ import (
 	root_config "github.com/runatlantis/atlantis/server/neptune/gateway/config"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
 	"github.com/runatlantis/atlantis/server/neptune/gateway/event/preworkflow"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/requirement"
 	httpInternal "github.com/runatlantis/atlantis/server/neptune/http"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
 	"github.com/runatlantis/atlantis/server/neptune/sync"
 	internalSync "github.com/runatlantis/atlantis/server/neptune/sync"
 	"github.com/runatlantis/atlantis/server/neptune/sync/crons"
 	"github.com/runatlantis/atlantis/server/neptune/temporal"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	ghClient "github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 	"github.com/runatlantis/atlantis/server/vcs/provider/github"
 	github_converter "github.com/runatlantis/atlantis/server/vcs/provider/github/converter"
@@ -239,12 +242,18 @@ func NewServer(config Config) (*Server, error) {
 	}
 
 	deploySignaler := &deploy.WorkflowSignaler{
-		TemporalClient: temporalClient,
+		TemporalClient:         temporalClient,
+		ContinueAsNewThreshold: globalCfg.Temporal.ContinueAsNewThreshold,
+	}
+	teamMemberFetcher := &github.TeamMemberFetcher{
+		ClientCreator: clientCreator,
+		Org:           globalCfg.PolicySets.Organization,
 	}
 	rootDeployer := &deploy.RootDeployer{
 		Logger:            ctxLogger,
 		RootConfigBuilder: rootConfigBuilder,
 		DeploySignaler:    deploySignaler,
+		FreezeRequirement: requirement.NewFreeze(globalCfg, teamMemberFetcher, ctxLogger),
 	}
 
 	checkRunFetcher := &github.CheckRunsFetcher{
@@ -292,19 +301,89 @@ func NewServer(config Config) (*Server, error) {
 		ClientCreator: clientCreator,
 	}
 
+	commitComparer := &github.CommitComparer{
+		ClientCreator: clientCreator,
+	}
+
 	installationRetriever := &github.InstallationRetriever{
 		ClientCreator: clientCreator,
 	}
 
-	deployController := &api.Controller[request.Deploy]{
+	deployController := &api.Controller[request.Deploy, api.DeployResponse]{
 		RequestConverter: request.NewDeployConverter(
 			repoRetriever, branchRetriever, installationRetriever,
 		),
 		Handler: &api.DeployHandler{
-			Deployer:  rootDeployer,
-			Scheduler: asyncScheduler,
-			Logger:    ctxLogger,
+			Deployer: rootDeployer,
+			Logger:   ctxLogger,
+		},
+		Logger: ctxLogger,
+	}
+
+	rollbackController := &api.Controller[request.Rollback, api.DeployResponse]{
+		RequestConverter: request.NewRollbackConverter(
+			repoRetriever, installationRetriever, commitComparer,
+		),
+		Handler: &api.RollbackHandler{
+			Deployer: rootDeployer,
+			Logger:   ctxLogger,
+		},
+		Logger: ctxLogger,
+	}
+
+	deployStatusController := &api.DeployStatusController{
+		TemporalClient: temporalClient,
+		Logger:         ctxLogger,
+	}
+
+	deployQueueController := &api.DeployQueueController{
+		TemporalClient: temporalClient,
+		Logger:         ctxLogger,
+	}
+
+	serverCrons := []*internalSync.Cron{
+		{
+			Executor:  crons.NewRuntimeStats(statsScope).Run,
+			Frequency: 1 * time.Minute,
+		},
+	}
+
+	deploymentHistoryController := &api.DeploymentHistoryController{
+		Store: &lazyHistoryStore{
+			config: globalCfg.PersistenceConfig.Deployments,
+			logger: ctxLogger,
 		},
+		Logger: ctxLogger,
+	}
+
+	if globalCfg.DriftDetection.Enabled {
+		storageClient, err := storage.NewClient(globalCfg.PersistenceConfig.Deployments)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing deployment storage client")
+		}
+
+		deploymentStore, err := deployment.NewStore(storageClient, ctxLogger)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing deployment store")
+		}
+
+		driftDetection := &crons.DriftDetection{
+			DeploymentStore:       deploymentStore,
+			InstallationRetriever: installationRetriever,
+			RepoRetriever:         repoRetriever,
+			DriftDetector: &deploy.RootDriftDetector{
+				Logger:            ctxLogger,
+				RootConfigBuilder: rootConfigBuilder,
+				TemporalClient:    temporalClient,
+			},
+			Logger: ctxLogger,
+			Scope:  statsScope,
+		}
+
+		serverCrons = append(serverCrons, &internalSync.Cron{
+			Executor:  driftDetection.Run,
+			Frequency: globalCfg.DriftDetection.Interval,
+		})
 	}
 
 	router := newRouter(
@@ -312,6 +391,10 @@ func NewServer(config Config) (*Server, error) {
 		gatewayEventsController,
 		statusController,
 		deployController,
+		rollbackController,
+		deployStatusController,
+		deployQueueController,
+		deploymentHistoryController,
 		globalCfg,
 	)
 
@@ -328,12 +411,7 @@ func NewServer(config Config) (*Server, error) {
 	cronScheduler := internalSync.NewCronScheduler(ctxLogger)
 
 	return &Server{
-		Crons: []*internalSync.Cron{
-			{
-				Executor:  crons.NewRuntimeStats(statsScope).Run,
-				Frequency: 1 * time.Minute,
-			},
-		},
+		Crons:          serverCrons,
 		StatsCloser:    closer,
 		Scheduler:      asyncScheduler,
 		Logger:         ctxLogger,
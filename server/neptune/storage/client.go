@@ -5,12 +5,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/graymeta/stow"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
+const listPageSize = 100
+
 type ContainerNotFoundError struct {
 	Err error
 }
@@ -101,6 +105,85 @@ func (c *Client) Set(ctx context.Context, key string, object []byte) error {
 	return nil
 }
 
+// Item is an object in the container along with when it was last written
+type Item struct {
+	Key          string
+	LastModified time.Time
+}
+
+// List returns the keys of all items which start with the given prefix, keys are returned
+// relative to the client's configured prefix.
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	items, err := c.ListItems(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	return keys, nil
+}
+
+// ListItems is like List but includes when each item was last modified
+func (c *Client) ListItems(ctx context.Context, prefix string) ([]Item, error) {
+	var items []Item
+	err := c.WalkItems(ctx, prefix, func(item Item) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// WalkItems calls fn with each item which starts with the given prefix, items are listed a page at a
+// time so they're never all held in memory. Walking stops at the first error returned by fn.
+func (c *Client) WalkItems(ctx context.Context, prefix string, fn func(Item) error) error {
+	err := stow.Walk(c.Container, c.addPrefix(prefix), listPageSize, func(item stow.Item, err error) error {
+		if err != nil {
+			return err
+		}
+
+		lastModified, err := item.LastMod()
+		if err != nil {
+			return errors.Wrapf(err, "getting last modified time of %s", item.Name())
+		}
+
+		return fn(Item{
+			Key:          strings.TrimPrefix(item.Name(), c.addPrefix("")),
+			LastModified: lastModified,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, stow.ErrNotFound) {
+			return &ContainerNotFoundError{
+				Err: err,
+			}
+		}
+		return errors.Wrap(err, "listing items")
+	}
+	return nil
+}
+
+// Delete removes the item with the given key, it's a noop if the item doesn't exist
+func (c *Client) Delete(ctx context.Context, key string) error {
+	item, err := c.Container.Item(c.addPrefix(key))
+	if err != nil {
+		if errors.Is(err, stow.ErrNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "getting item")
+	}
+
+	if err := c.Container.RemoveItem(item.ID()); err != nil {
+		return errors.Wrap(err, "removing item")
+	}
+	return nil
+}
+
 func (c *Client) addPrefix(key string) string {
 	return fmt.Sprintf("%s/%s", c.Prefix, key)
 }
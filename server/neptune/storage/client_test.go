@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/local"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/storage"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,3 +78,72 @@ func TestClient_Get(t *testing.T) {
 		}, err)
 	})
 }
+
+func TestClient_List(t *testing.T) {
+	client, err := storage.NewClient(valid.StoreConfig{
+		ContainerName: "container",
+		Prefix:        "prefix",
+		BackendType:   valid.LocalBackend,
+		Config: stow.ConfigMap{
+			local.ConfigKeyPath: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, client.Set(ctx, "owner/repo/root/deployment.json", []byte("{}")))
+	assert.NoError(t, client.Set(ctx, "owner/repo/root/queue.json", []byte("{}")))
+	assert.NoError(t, client.Set(ctx, "other/repo/root/deployment.json", []byte("{}")))
+
+	keys, err := client.List(ctx, "owner/")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"owner/repo/root/deployment.json",
+		"owner/repo/root/queue.json",
+	}, keys)
+}
+
+func TestClient_ListItems(t *testing.T) {
+	client, err := storage.NewClient(valid.StoreConfig{
+		ContainerName: "container",
+		Prefix:        "prefix",
+		BackendType:   valid.LocalBackend,
+		Config: stow.ConfigMap{
+			local.ConfigKeyPath: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute)
+	assert.NoError(t, client.Set(ctx, "1234", []byte("logs")))
+
+	items, err := client.ListItems(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "1234", items[0].Key)
+	assert.True(t, items[0].LastModified.After(before))
+}
+
+func TestClient_Delete(t *testing.T) {
+	client, err := storage.NewClient(valid.StoreConfig{
+		ContainerName: "container",
+		Prefix:        "prefix",
+		BackendType:   valid.LocalBackend,
+		Config: stow.ConfigMap{
+			local.ConfigKeyPath: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, client.Set(ctx, "1234", []byte("logs")))
+	assert.NoError(t, client.Set(ctx, "5678", []byte("logs")))
+
+	assert.NoError(t, client.Delete(ctx, "1234"))
+	assert.NoError(t, client.Delete(ctx, "missing"))
+
+	keys, err := client.List(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5678"}, keys)
+}
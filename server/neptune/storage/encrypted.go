@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EncryptionKeySize is the size of the AES-256 keys objects are encrypted with
+const EncryptionKeySize = 32
+
+type getSetter interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Set(ctx context.Context, key string, object []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// EncryptedClient encrypts objects with AES-256-GCM before writing them to the underlying client.
+// The object key is authenticated along with the object so objects can't be swapped between keys.
+type EncryptedClient struct {
+	Client getSetter
+	aead   cipher.AEAD
+}
+
+func NewEncryptedClient(client getSetter, encryptionKey []byte) (*EncryptedClient, error) {
+	if len(encryptionKey) != EncryptionKeySize {
+		return nil, errors.Errorf("encryption key must be %d bytes, got %d", EncryptionKeySize, len(encryptionKey))
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing gcm")
+	}
+
+	return &EncryptedClient{
+		Client: client,
+		aead:   aead,
+	}, nil
+}
+
+// LoadEncryptionKey reads a hex encoded key from path
+func LoadEncryptionKey(path string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding key in %s", path)
+	}
+	return key, nil
+}
+
+func (c *EncryptedClient) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	ciphertext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading item")
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("item is too short to be encrypted")
+	}
+
+	object, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting item")
+	}
+
+	return io.NopCloser(bytes.NewReader(object)), nil
+}
+
+func (c *EncryptedClient) Set(ctx context.Context, key string, object []byte) error {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "generating nonce")
+	}
+
+	return c.Client.Set(ctx, key, c.aead.Seal(nonce, nonce, object, []byte(key)))
+}
+
+func (c *EncryptedClient) Delete(ctx context.Context, key string) error {
+	return c.Client.Delete(ctx, key)
+}
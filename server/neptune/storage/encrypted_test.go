@@ -0,0 +1,90 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/local"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedClient(t *testing.T) {
+	client, err := storage.NewClient(valid.StoreConfig{
+		ContainerName: "container",
+		Prefix:        "prefix",
+		BackendType:   valid.LocalBackend,
+		Config: stow.ConfigMap{
+			local.ConfigKeyPath: t.TempDir(),
+		},
+	})
+	assert.NoError(t, err)
+
+	key := bytes.Repeat([]byte{1}, storage.EncryptionKeySize)
+	subject, err := storage.NewEncryptedClient(client, key)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, subject.Set(ctx, "plan", []byte("plan contents")))
+
+	t.Run("round trip", func(t *testing.T) {
+		r, err := subject.Get(ctx, "plan")
+		assert.NoError(t, err)
+		contents, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "plan contents", string(contents))
+	})
+
+	t.Run("encrypted at rest", func(t *testing.T) {
+		r, err := client.Get(ctx, "plan")
+		assert.NoError(t, err)
+		defer r.Close()
+		contents, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(contents), "plan contents")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, err := storage.NewEncryptedClient(client, bytes.Repeat([]byte{2}, storage.EncryptionKeySize))
+		assert.NoError(t, err)
+		_, err = other.Get(ctx, "plan")
+		assert.Error(t, err)
+	})
+
+	t.Run("swapped keys", func(t *testing.T) {
+		r, err := client.Get(ctx, "plan")
+		assert.NoError(t, err)
+		contents, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.NoError(t, r.Close())
+		assert.NoError(t, client.Set(ctx, "other", contents))
+
+		_, err = subject.Get(ctx, "other")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := storage.NewEncryptedClient(client, []byte("short"))
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, storage.EncryptionKeySize)
+	path := filepath.Join(t.TempDir(), "plan.key")
+	assert.NoError(t, os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600))
+
+	loaded, err := storage.LoadEncryptionKey(path)
+	assert.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	_, err = storage.LoadEncryptionKey(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"path"
+	"strings"
+)
+
+// RepoIndexPrefix holds empty objects which record the repo each job belongs to, job logs are
+// keyed by job id alone so this is how retention policies are applied per repo.
+const RepoIndexPrefix = "repos"
+
+// RepoIndexKey returns the index key of a job for a repo's full name, ie. repos/owner/repo/<job id>
+func RepoIndexKey(repoFullName string, jobID string) string {
+	return path.Join(RepoIndexPrefix, repoFullName, jobID)
+}
+
+// ParseRepoIndexKey returns the repo's full name and job id of an index key, ok is false if key isn't one
+func ParseRepoIndexKey(key string) (repoFullName string, jobID string, ok bool) {
+	rest := strings.TrimPrefix(key, RepoIndexPrefix+"/")
+	if rest == key {
+		return "", "", false
+	}
+
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
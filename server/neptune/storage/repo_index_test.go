@@ -0,0 +1,23 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoIndexKey(t *testing.T) {
+	key := storage.RepoIndexKey("owner/repo", "1234")
+	assert.Equal(t, "repos/owner/repo/1234", key)
+
+	repo, jobID, ok := storage.ParseRepoIndexKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, "owner/repo", repo)
+	assert.Equal(t, "1234", jobID)
+
+	for _, key := range []string{"1234", "repos/1234", "repos/owner/repo/"} {
+		_, _, ok := storage.ParseRepoIndexKey(key)
+		assert.False(t, ok, key)
+	}
+}
@@ -0,0 +1,119 @@
+package crons
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/vcs/provider/github"
+	"github.com/uber-go/tally/v4"
+)
+
+type deploymentLister interface {
+	ListDeploymentInfo(ctx context.Context) ([]*deployment.Info, error)
+}
+
+type installationRetriever interface {
+	FindOrganizationInstallation(ctx context.Context, org string) (github.Installation, error)
+}
+
+type repoRetriever interface {
+	Get(ctx context.Context, installationToken int64, owner, repo string) (models.Repo, error)
+}
+
+type driftDetector interface {
+	Detect(ctx context.Context, opts deploy.RootDriftOptions) error
+}
+
+// DriftDetection periodically kicks off drift detection for every root at its last deployed revision.
+type DriftDetection struct {
+	DeploymentStore       deploymentLister
+	InstallationRetriever installationRetriever
+	RepoRetriever         repoRetriever
+	DriftDetector         driftDetector
+	Logger                logging.Logger
+	Scope                 tally.Scope
+}
+
+// deployedRevision groups roots which share a deployed revision so they can be detected with a single clone
+type deployedRevision struct {
+	owner    string
+	name     string
+	branch   string
+	revision string
+}
+
+func (d *DriftDetection) Run(ctx context.Context) error {
+	scope := d.Scope.SubScope("drift")
+
+	infos, err := d.DeploymentStore.ListDeploymentInfo(ctx)
+	if err != nil {
+		scope.Counter("list.error").Inc(1)
+		return errors.Wrap(err, "listing deployments")
+	}
+
+	var revisions []deployedRevision
+	rootsByRevision := map[deployedRevision][]string{}
+	for _, info := range infos {
+		r := deployedRevision{
+			owner:    info.Repo.Owner,
+			name:     info.Repo.Name,
+			branch:   info.Branch,
+			revision: info.Revision,
+		}
+
+		if _, ok := rootsByRevision[r]; !ok {
+			revisions = append(revisions, r)
+		}
+		rootsByRevision[r] = append(rootsByRevision[r], info.Root.Name)
+	}
+
+	for _, r := range revisions {
+		if err := d.detect(ctx, r, rootsByRevision[r]); err != nil {
+			scope.Counter("error").Inc(1)
+			d.Logger.ErrorContext(ctx, err.Error(), map[string]interface{}{
+				"repository": fmt.Sprintf("%s/%s", r.owner, r.name),
+				"revision":   r.revision,
+			})
+			continue
+		}
+		scope.Counter("success").Inc(1)
+	}
+
+	return nil
+}
+
+func (d *DriftDetection) detect(ctx context.Context, r deployedRevision, rootNames []string) error {
+	installation, err := d.InstallationRetriever.FindOrganizationInstallation(ctx, r.owner)
+	if err != nil {
+		return errors.Wrap(err, "finding installation")
+	}
+
+	repo, err := d.RepoRetriever.Get(ctx, installation.Token, r.owner, r.name)
+	if err != nil {
+		return errors.Wrap(err, "getting repo")
+	}
+
+	// older deployments might not have persisted a branch
+	branch := r.branch
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+
+	err = d.DriftDetector.Detect(ctx, deploy.RootDriftOptions{
+		Repo:              repo,
+		RootNames:         rootNames,
+		Branch:            branch,
+		Revision:          r.revision,
+		InstallationToken: installation.Token,
+	})
+	if err != nil {
+		return errors.Wrap(err, "detecting drift")
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package crons_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/sync/crons"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/vcs/provider/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally/v4"
+)
+
+type testDeploymentLister struct {
+	infos []*deployment.Info
+	err   error
+}
+
+func (l *testDeploymentLister) ListDeploymentInfo(ctx context.Context) ([]*deployment.Info, error) {
+	return l.infos, l.err
+}
+
+type testInstallationRetriever struct{}
+
+func (r *testInstallationRetriever) FindOrganizationInstallation(ctx context.Context, org string) (github.Installation, error) {
+	return github.Installation{Token: 2}, nil
+}
+
+type testRepoRetriever struct {
+	err error
+}
+
+func (r *testRepoRetriever) Get(ctx context.Context, installationToken int64, owner, repo string) (models.Repo, error) {
+	return models.Repo{
+		FullName:      owner + "/" + repo,
+		Owner:         owner,
+		Name:          repo,
+		DefaultBranch: "main",
+	}, r.err
+}
+
+type testDriftDetector struct {
+	opts []deploy.RootDriftOptions
+}
+
+func (d *testDriftDetector) Detect(ctx context.Context, opts deploy.RootDriftOptions) error {
+	d.opts = append(d.opts, opts)
+	return nil
+}
+
+func buildInfo(repo string, root string, revision string, branch string) *deployment.Info {
+	return &deployment.Info{
+		Revision: revision,
+		Branch:   branch,
+		Repo:     deployment.Repo{Owner: "owner", Name: repo},
+		Root:     deployment.Root{Name: root},
+	}
+}
+
+func TestDriftDetection_Run(t *testing.T) {
+	t.Run("groups roots by deployed revision", func(t *testing.T) {
+		detector := &testDriftDetector{}
+		subject := &crons.DriftDetection{
+			DeploymentStore: &testDeploymentLister{
+				infos: []*deployment.Info{
+					buildInfo("repo", "root1", "abc", "main"),
+					buildInfo("repo", "root2", "def", "main"),
+					buildInfo("repo", "root3", "abc", "main"),
+					buildInfo("other", "root1", "abc", ""),
+				},
+			},
+			InstallationRetriever: &testInstallationRetriever{},
+			RepoRetriever:         &testRepoRetriever{},
+			DriftDetector:         detector,
+			Logger:                logging.NewNoopCtxLogger(t),
+			Scope:                 tally.NoopScope,
+		}
+
+		err := subject.Run(context.Background())
+		assert.NoError(t, err)
+
+		repo := models.Repo{FullName: "owner/repo", Owner: "owner", Name: "repo", DefaultBranch: "main"}
+		other := models.Repo{FullName: "owner/other", Owner: "owner", Name: "other", DefaultBranch: "main"}
+		assert.Equal(t, []deploy.RootDriftOptions{
+			{Repo: repo, RootNames: []string{"root1", "root3"}, Branch: "main", Revision: "abc", InstallationToken: 2},
+			{Repo: repo, RootNames: []string{"root2"}, Branch: "main", Revision: "def", InstallationToken: 2},
+			{Repo: other, RootNames: []string{"root1"}, Branch: "main", Revision: "abc", InstallationToken: 2},
+		}, detector.opts)
+	})
+
+	t.Run("continues past repo errors", func(t *testing.T) {
+		detector := &testDriftDetector{}
+		subject := &crons.DriftDetection{
+			DeploymentStore: &testDeploymentLister{
+				infos: []*deployment.Info{buildInfo("repo", "root1", "abc", "main")},
+			},
+			InstallationRetriever: &testInstallationRetriever{},
+			RepoRetriever:         &testRepoRetriever{err: assert.AnError},
+			DriftDetector:         detector,
+			Logger:                logging.NewNoopCtxLogger(t),
+			Scope:                 tally.NoopScope,
+		}
+
+		err := subject.Run(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, detector.opts)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		subject := &crons.DriftDetection{
+			DeploymentStore: &testDeploymentLister{err: assert.AnError},
+			Logger:          logging.NewNoopCtxLogger(t),
+			Scope:           tally.NoopScope,
+		}
+
+		err := subject.Run(context.Background())
+		assert.Error(t, err)
+	})
+}
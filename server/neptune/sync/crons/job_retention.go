@@ -0,0 +1,107 @@
+package crons
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/uber-go/tally/v4"
+)
+
+type jobLogStore interface {
+	WalkItems(ctx context.Context, prefix string, fn func(storage.Item) error) error
+	Delete(ctx context.Context, key string) error
+}
+
+// JobRetention periodically deletes job logs which are older than the max age of the repo they ran for.
+// Logs without a repo index entry, ie. jobs persisted during a worker shutdown, use the default max age.
+type JobRetention struct {
+	Store     jobLogStore
+	Retention valid.JobRetention
+	Logger    logging.Logger
+	Scope     tally.Scope
+	// Clock defaults to time.Now
+	Clock func() time.Time
+}
+
+// Run deletes expired job logs as the store is listed so the listing is never held in memory.
+// The repo index is swept first, then the remaining logs are swept with the default max age.
+func (r *JobRetention) Run(ctx context.Context) error {
+	scope := r.Scope.SubScope("job_retention")
+	now := r.now()
+
+	// only jobs of repos which keep logs longer than the default are held since every other
+	// log which is still retained is also retained by the default max age
+	retained := map[string]bool{}
+	err := r.Store.WalkItems(ctx, storage.RepoIndexPrefix+"/", func(item storage.Item) error {
+		repo, jobID, ok := storage.ParseRepoIndexKey(item.Key)
+		if !ok {
+			return nil
+		}
+
+		maxAge := r.Retention.MaxAgeFor(repo)
+		if jobLogExpired(item, maxAge, now) {
+			// the log is deleted first so its index entry is swept again if that fails
+			r.delete(ctx, scope, repo, jobID, item.Key)
+			return nil
+		}
+
+		if r.Retention.MaxAge > 0 && (maxAge == 0 || maxAge > r.Retention.MaxAge) {
+			retained[jobID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		scope.Counter("list.error").Inc(1)
+		return errors.Wrap(err, "listing job log index")
+	}
+
+	if r.Retention.MaxAge == 0 {
+		return nil
+	}
+
+	err = r.Store.WalkItems(ctx, "", func(item storage.Item) error {
+		if strings.HasPrefix(item.Key, storage.RepoIndexPrefix+"/") || retained[item.Key] {
+			return nil
+		}
+
+		if jobLogExpired(item, r.Retention.MaxAge, now) {
+			r.delete(ctx, scope, "", item.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		scope.Counter("list.error").Inc(1)
+		return errors.Wrap(err, "listing job logs")
+	}
+	return nil
+}
+
+func jobLogExpired(item storage.Item, maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && now.Sub(item.LastModified) > maxAge
+}
+
+func (r *JobRetention) delete(ctx context.Context, scope tally.Scope, repo string, keys ...string) {
+	for _, key := range keys {
+		if err := r.Store.Delete(ctx, key); err != nil {
+			scope.Counter("error").Inc(1)
+			r.Logger.ErrorContext(ctx, errors.Wrap(err, "deleting expired job log").Error(), map[string]interface{}{
+				"key":        key,
+				"repository": repo,
+			})
+			return
+		}
+	}
+	scope.Counter("deleted").Inc(1)
+}
+
+func (r *JobRetention) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock()
+}
@@ -0,0 +1,149 @@
+package crons_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
+	"github.com/runatlantis/atlantis/server/neptune/sync/crons"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally/v4"
+)
+
+type testJobLogStore struct {
+	items     []storage.Item
+	listErr   error
+	deleteErr error
+	deleted   []string
+}
+
+func (s *testJobLogStore) WalkItems(ctx context.Context, prefix string, fn func(storage.Item) error) error {
+	if s.listErr != nil {
+		return s.listErr
+	}
+	for _, item := range s.items {
+		if !strings.HasPrefix(item.Key, prefix) {
+			continue
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *testJobLogStore) Delete(ctx context.Context, key string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func TestJobRetention_Run(t *testing.T) {
+	now := time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+
+	retention := valid.JobRetention{
+		MaxAge: 30 * 24 * time.Hour,
+		Repos: []valid.RepoJobRetention{
+			{Name: "owner/compliance", MaxAge: 365 * 24 * time.Hour},
+			{Name: "owner/noisy", MaxAge: 24 * time.Hour},
+		},
+	}
+
+	t.Run("deletes expired logs per repo", func(t *testing.T) {
+		store := &testJobLogStore{
+			items: []storage.Item{
+				{Key: "compliance-job", LastModified: daysAgo(60)},
+				{Key: storage.RepoIndexKey("owner/compliance", "compliance-job"), LastModified: daysAgo(60)},
+				{Key: "noisy-job", LastModified: daysAgo(2)},
+				{Key: storage.RepoIndexKey("owner/noisy", "noisy-job"), LastModified: daysAgo(2)},
+				{Key: "unindexed-job", LastModified: daysAgo(31)},
+				{Key: "recent-job", LastModified: daysAgo(1)},
+				{Key: storage.RepoIndexKey("owner/noisy", "orphaned-job"), LastModified: daysAgo(2)},
+			},
+		}
+		scope := tally.NewTestScope("", map[string]string{})
+
+		subject := &crons.JobRetention{
+			Store:     store,
+			Retention: retention,
+			Logger:    logging.NewNoopCtxLogger(t),
+			Scope:     scope,
+			Clock:     func() time.Time { return now },
+		}
+
+		assert.NoError(t, subject.Run(context.Background()))
+		assert.ElementsMatch(t, []string{
+			"noisy-job",
+			storage.RepoIndexKey("owner/noisy", "noisy-job"),
+			"unindexed-job",
+			"orphaned-job",
+			storage.RepoIndexKey("owner/noisy", "orphaned-job"),
+		}, store.deleted)
+		assert.Equal(t, int64(3), scope.Snapshot().Counters()["job_retention.deleted+"].Value())
+	})
+
+	t.Run("keeps logs without a max age", func(t *testing.T) {
+		store := &testJobLogStore{
+			items: []storage.Item{
+				{Key: "old-job", LastModified: daysAgo(1000)},
+			},
+		}
+
+		subject := &crons.JobRetention{
+			Store: store,
+			Retention: valid.JobRetention{
+				Repos: []valid.RepoJobRetention{{Name: "owner/*", MaxAge: time.Hour}},
+			},
+			Logger: logging.NewNoopCtxLogger(t),
+			Scope:  tally.NoopScope,
+			Clock:  func() time.Time { return now },
+		}
+
+		assert.NoError(t, subject.Run(context.Background()))
+		assert.Empty(t, store.deleted)
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		store := &testJobLogStore{
+			items: []storage.Item{
+				{Key: "unindexed-job", LastModified: daysAgo(31)},
+			},
+			deleteErr: errors.New("error"),
+		}
+		scope := tally.NewTestScope("", map[string]string{})
+
+		subject := &crons.JobRetention{
+			Store:     store,
+			Retention: retention,
+			Logger:    logging.NewNoopCtxLogger(t),
+			Scope:     scope,
+			Clock:     func() time.Time { return now },
+		}
+
+		assert.NoError(t, subject.Run(context.Background()))
+		assert.Equal(t, int64(1), scope.Snapshot().Counters()["job_retention.error+"].Value())
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		scope := tally.NewTestScope("", map[string]string{})
+		subject := &crons.JobRetention{
+			Store:     &testJobLogStore{listErr: errors.New("error")},
+			Retention: retention,
+			Logger:    logging.NewNoopCtxLogger(t),
+			Scope:     scope,
+		}
+
+		assert.Error(t, subject.Run(context.Background()))
+		assert.Equal(t, int64(1), scope.Snapshot().Counters()["job_retention.list.error+"].Value())
+	})
+}
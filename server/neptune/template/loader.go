@@ -27,6 +27,7 @@ const (
 	UserForbidden         = Key("user_forbidden")
 	ApprovalRequired      = Key("approval_required")
 	PlanValidationSuccess = Key("plan_validation_success")
+	DeployFrozen          = Key("deploy_frozen")
 )
 
 var defaultTemplates = map[Key]string{
@@ -35,6 +36,7 @@ var defaultTemplates = map[Key]string{
 	UserForbidden:         userForbiddenTemplate,
 	ApprovalRequired:      approvalRequiredTemplate,
 	PlanValidationSuccess: planValidationSuccessTemplate,
+	DeployFrozen:          deployFrozenTemplate,
 }
 
 type PRCommentData struct {
@@ -64,6 +66,15 @@ type UserForbiddenData struct {
 	Org  string
 }
 
+type DeployFrozenData struct {
+	User   string
+	Window string
+	Reason string
+	Until  string
+	Team   string
+	Org    string
+}
+
 //go:embed templates/pr_comment.tmpl
 var prCommentTemplate string
 
@@ -79,6 +90,9 @@ var approvalRequiredTemplate string
 //go:embed templates/plan_validation_success.tmpl
 var planValidationSuccessTemplate string
 
+//go:embed templates/deploy_frozen.tmpl
+var deployFrozenTemplate string
+
 type Loader[T any] struct {
 	GlobalCfg valid.GlobalCfg
 }
@@ -24,11 +24,57 @@ type TerraformConfig struct {
 	DefaultVersion string
 	DownloadURL    string
 	LogFilters     valid.TerraformLogFilters
+
+	// ChecksumsFile pins archive checksums and SigningKeyFile verifies release checksums,
+	// downloads are unverified when neither is configured.
+	ChecksumsFile  string
+	SigningKeyFile string
+
+	// BinaryMirror is checked for both terraform and conftest binaries before downloading them
+	BinaryMirror BinaryMirrorConfig
+
+	// Tofu configures the opentofu engine, roots using it fail if it isn't configured
+	Tofu TofuConfig
+
+	PluginCache PluginCacheConfig
+
+	// PlanArtifacts persists plans so they can be applied by any worker
+	PlanArtifacts PlanArtifactsConfig
+}
+
+// PlanArtifactsConfig configures where plan artifacts are stored, they're kept on the planning worker if Store is nil
+type PlanArtifactsConfig struct {
+	Store *valid.StoreConfig
+	// EncryptionKeyFile contains the hex encoded AES-256 key plan artifacts are encrypted with
+	EncryptionKeyFile string
+}
+
+// PluginCacheConfig configures the plugin cache shared by every init on the worker
+type PluginCacheConfig struct {
+	// MaxSizeMB is the size the cache is evicted down to, eviction is disabled if it's 0
+	MaxSizeMB int
+	// WarmUp installs the providers of previously initialized roots on startup
+	WarmUp bool
+}
+
+// TofuConfig configures downloads of opentofu, roots can only use it if it's Enabled
+type TofuConfig struct {
+	Enabled        bool
+	DefaultVersion string
+	DownloadURL    string
+	SigningKeyFile string
+}
+
+// BinaryMirrorConfig locates pre-seeded binaries, Dir takes precedence over Store
+type BinaryMirrorConfig struct {
+	Dir   string
+	Store *valid.StoreConfig
 }
 
 type ValidationConfig struct {
 	DefaultVersion *version.Version
 	Policies       valid.PolicySets
+	ChecksumsFile  string
 }
 
 type FeatureConfig struct {
@@ -48,8 +94,12 @@ type Config struct {
 	ValidationConfig ValidationConfig
 	DeploymentConfig valid.StoreConfig
 	JobConfig        valid.StoreConfig
+	JobRetention     valid.JobRetention
 	Metrics          valid.Metrics
 	RevisionSetter   valid.RevisionSetter
+
+	// FreezeWindows hold merged revisions in deploy queues while they're active
+	FreezeWindows []valid.FreezeWindow
 	//TODO: combine this with above
 	StatsNamespace string
 
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/controllers/templates"
+	"github.com/runatlantis/atlantis/server/events/metrics"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/gateway/deploy"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/uber-go/tally/v4"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/converter"
+)
+
+// limits the number of roots whose deploy workflows are queried at the same time
+const maxConcurrentRootQueries = 10
+
+// DefaultRootsCacheTTL is how long listed roots are reused, listing them reads the deployment
+// of every root and queries each of their deploy workflows.
+const DefaultRootsCacheTTL = 30 * time.Second
+
+type deploymentStore interface {
+	ListDeploymentInfo(ctx context.Context) ([]*deployment.Info, error)
+}
+
+type workflowQuerier interface {
+	QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error)
+}
+
+// DeploymentsController shows the deploy state of every root so it can be inspected
+// without access to temporal.
+type DeploymentsController struct {
+	AtlantisVersion string
+	AtlantisURL     *url.URL
+
+	DeploymentStore          deploymentStore
+	TemporalClient           workflowQuerier
+	DeploymentsIndexTemplate templates.TemplateWriter
+
+	StatsScope tally.Scope
+	Logger     logging.Logger
+
+	// RootsCacheTTL is how long listed roots are reused across page loads, 0 disables caching
+	RootsCacheTTL time.Duration
+
+	// mutable: concurrent page loads wait on a single listing when the cache is stale
+	rootsMu       sync.Mutex
+	cachedRoots   []templates.DeployRootData
+	rootsCachedAt time.Time
+}
+
+func (d *DeploymentsController) getDeployments(w http.ResponseWriter, r *http.Request) error {
+	roots, err := d.listRoots(r.Context())
+	if err != nil {
+		d.respond(w, http.StatusInternalServerError, "listing deployments: %s", err)
+		return err
+	}
+
+	viewData := templates.DeploymentsIndexData{
+		Roots:           roots,
+		AtlantisVersion: d.AtlantisVersion,
+		CleanedBasePath: d.AtlantisURL.Path,
+	}
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(viewData); err != nil {
+			d.Logger.Error(err.Error())
+			return err
+		}
+		return nil
+	}
+
+	if err := d.DeploymentsIndexTemplate.Execute(w, viewData); err != nil {
+		d.Logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// listRoots returns the cached roots if they were listed within the cache ttl, errors aren't cached.
+func (d *DeploymentsController) listRoots(ctx context.Context) ([]templates.DeployRootData, error) {
+	d.rootsMu.Lock()
+	defer d.rootsMu.Unlock()
+
+	if d.cachedRoots != nil && time.Since(d.rootsCachedAt) < d.RootsCacheTTL {
+		return d.cachedRoots, nil
+	}
+
+	roots, err := d.buildRoots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d.cachedRoots = roots
+	d.rootsCachedAt = time.Now()
+	return roots, nil
+}
+
+func (d *DeploymentsController) buildRoots(ctx context.Context) ([]templates.DeployRootData, error) {
+	infos, err := d.DeploymentStore.ListDeploymentInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Repo.GetFullName() != infos[j].Repo.GetFullName() {
+			return infos[i].Repo.GetFullName() < infos[j].Repo.GetFullName()
+		}
+		return infos[i].Root.Name < infos[j].Root.Name
+	})
+
+	roots := make([]templates.DeployRootData, len(infos))
+	sem := make(chan struct{}, maxConcurrentRootQueries)
+	var wg sync.WaitGroup
+	for i, info := range infos {
+		wg.Add(1)
+		go func(i int, info *deployment.Info) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			roots[i] = d.buildRoot(ctx, info)
+		}(i, info)
+	}
+	wg.Wait()
+	return roots, nil
+}
+
+// GetDeployments renders the last deployment, lock and queue of every root as html,
+// or as json if it's requested with the format query param or the accept header.
+func (d *DeploymentsController) GetDeployments(w http.ResponseWriter, r *http.Request) {
+	errorCounter := d.StatsScope.Counter(metrics.ExecutionErrorMetric)
+	err := d.getDeployments(w, r)
+	if err != nil {
+		errorCounter.Inc(1)
+	}
+}
+
+func (d *DeploymentsController) buildRoot(ctx context.Context, info *deployment.Info) templates.DeployRootData {
+	root := templates.DeployRootData{
+		Repo: info.Repo.GetFullName(),
+		Root: info.Root.Name,
+		LastDeployment: &templates.DeploymentData{
+			ID:          info.ID,
+			Revision:    info.Revision,
+			Branch:      info.Branch,
+			Trigger:     info.Root.Trigger,
+			PlanJobURL:  info.PlanJobURL,
+			ApplyJobURL: info.ApplyJobURL,
+		},
+		Queue: []templates.DeploymentData{},
+	}
+
+	workflowID := deploy.BuildDeployWorkflowID(root.Repo, root.Root)
+	value, err := d.TemporalClient.QueryWorkflow(ctx, workflowID, "", workflows.DeployQueueQueryName)
+
+	// deploy workflows exit once they're idle so there's nothing queued
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return root
+	}
+	if err != nil {
+		d.Logger.Error(fmt.Sprintf("querying workflow with id %s: %s", workflowID, err))
+		root.Error = "Unable to fetch the deploy queue"
+		return root
+	}
+
+	var state workflows.DeployQueueState
+	if err := value.Get(&state); err != nil {
+		d.Logger.Error(fmt.Sprintf("decoding queue of workflow with id %s: %s", workflowID, err))
+		root.Error = "Unable to fetch the deploy queue"
+		return root
+	}
+
+	root.Locked = state.Lock.Locked
+	root.LockedRevision = state.Lock.Revision
+	if state.CurrentDeployment != nil {
+		current := toDeploymentData(*state.CurrentDeployment)
+		root.CurrentDeployment = &current
+	}
+	for _, revision := range state.Revisions {
+		root.Queue = append(root.Queue, toDeploymentData(revision))
+	}
+	return root
+}
+
+func toDeploymentData(revision workflows.DeployQueuedRevision) templates.DeploymentData {
+	return templates.DeploymentData{
+		ID:          revision.ID,
+		Revision:    revision.Revision,
+		Branch:      revision.Branch,
+		User:        revision.User,
+		Trigger:     revision.Trigger,
+		PlanJobURL:  revision.PlanJobURL,
+		ApplyJobURL: revision.ApplyJobURL,
+	}
+}
+
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func (d *DeploymentsController) respond(w http.ResponseWriter, responseCode int, format string, args ...interface{}) {
+	response := fmt.Sprintf(format, args...)
+	if responseCode >= http.StatusInternalServerError {
+		d.Logger.Warn(response)
+	}
+	w.WriteHeader(responseCode)
+	fmt.Fprintln(w, response)
+}
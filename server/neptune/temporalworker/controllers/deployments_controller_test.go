@@ -0,0 +1,287 @@
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/controllers/templates"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/neptune/temporalworker/controllers"
+	"github.com/runatlantis/atlantis/server/neptune/workflows"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally/v4"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/converter"
+)
+
+type testDeploymentStore struct {
+	infos []*deployment.Info
+	err   error
+	calls int
+}
+
+func (s *testDeploymentStore) ListDeploymentInfo(ctx context.Context) ([]*deployment.Info, error) {
+	s.calls++
+	return s.infos, s.err
+}
+
+// jsonValue round trips query results through json like temporal's data converter
+type jsonValue struct {
+	value interface{}
+}
+
+func (v jsonValue) HasValue() bool {
+	return v.value != nil
+}
+
+func (v jsonValue) Get(valuePtr interface{}) error {
+	b, err := json.Marshal(v.value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, valuePtr)
+}
+
+type queryResult struct {
+	value interface{}
+	err   error
+}
+
+type testQuerier struct {
+	mu      sync.Mutex
+	results map[string]queryResult
+	queried []string
+}
+
+func (q *testQuerier) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queried = append(q.queried, workflowID+"/"+queryType)
+	result, ok := q.results[workflowID+"/"+queryType]
+	if !ok {
+		return nil, serviceerror.NewNotFound("not found")
+	}
+	return jsonValue{value: result.value}, result.err
+}
+
+func mustParse(t *testing.T, rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+	return u
+}
+
+func newDeploymentsController(t *testing.T, store *testDeploymentStore, querier *testQuerier) *controllers.DeploymentsController {
+	return &controllers.DeploymentsController{
+		AtlantisVersion:          "1.0.0",
+		AtlantisURL:              mustParse(t, "https://atlantis.com"),
+		DeploymentStore:          store,
+		TemporalClient:           querier,
+		DeploymentsIndexTemplate: templates.DeploymentsIndexTemplate,
+		StatsScope:               tally.NewTestScope("test", map[string]string{}),
+		Logger:                   logging.NewNoopCtxLogger(t),
+	}
+}
+
+func TestDeploymentsController_GetDeployments(t *testing.T) {
+	store := &testDeploymentStore{
+		infos: []*deployment.Info{
+			{
+				ID:       "2",
+				Revision: "def",
+				Branch:   "main",
+				Repo:     deployment.Repo{Owner: "owner", Name: "repo"},
+				Root:     deployment.Root{Name: "root2", Trigger: "merge"},
+			},
+			{
+				ID:          "1",
+				Revision:    "abc",
+				Branch:      "main",
+				Repo:        deployment.Repo{Owner: "owner", Name: "repo"},
+				Root:        deployment.Root{Name: "root1", Trigger: "manual"},
+				PlanJobURL:  "https://atlantis.com/jobs/plan1",
+				ApplyJobURL: "https://atlantis.com/jobs/apply1",
+			},
+			{
+				ID:       "3",
+				Revision: "ghi",
+				Branch:   "main",
+				Repo:     deployment.Repo{Owner: "owner", Name: "broken"},
+				Root:     deployment.Root{Name: "root", Trigger: "merge"},
+			},
+		},
+	}
+	querier := &testQuerier{
+		results: map[string]queryResult{
+			"owner/repo||root1/" + workflows.DeployQueueQueryName: {
+				value: workflows.DeployQueueState{
+					Lock:              workflows.DeployQueueLockState{Locked: true, Revision: "abc"},
+					CurrentDeployment: &workflows.DeployQueuedRevision{ID: "4", Revision: "jkl", Branch: "main", User: "someone", Trigger: "merge", PlanJobURL: "https://atlantis.com/jobs/plan4"},
+					Revisions: []workflows.DeployQueuedRevision{
+						{ID: "5", Revision: "mno", Branch: "main", User: "someone", Trigger: "merge"},
+					},
+				},
+			},
+			"owner/broken||root/" + workflows.DeployQueueQueryName: {
+				err: errors.New("error"),
+			},
+		},
+	}
+
+	controller := newDeploymentsController(t, store, querier)
+
+	r := httptest.NewRequest(http.MethodGet, "/deployments?format=json", nil)
+	w := httptest.NewRecorder()
+	controller.GetDeployments(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var resp templates.DeploymentsIndexData
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, []templates.DeployRootData{
+		{
+			Repo:           "owner/broken",
+			Root:           "root",
+			LastDeployment: &templates.DeploymentData{ID: "3", Revision: "ghi", Branch: "main", Trigger: "merge"},
+			Queue:          []templates.DeploymentData{},
+			Error:          "Unable to fetch the deploy queue",
+		},
+		{
+			Repo: "owner/repo",
+			Root: "root1",
+			LastDeployment: &templates.DeploymentData{
+				ID:          "1",
+				Revision:    "abc",
+				Branch:      "main",
+				Trigger:     "manual",
+				PlanJobURL:  "https://atlantis.com/jobs/plan1",
+				ApplyJobURL: "https://atlantis.com/jobs/apply1",
+			},
+			Locked:         true,
+			LockedRevision: "abc",
+			CurrentDeployment: &templates.DeploymentData{
+				ID:         "4",
+				Revision:   "jkl",
+				Branch:     "main",
+				User:       "someone",
+				Trigger:    "merge",
+				PlanJobURL: "https://atlantis.com/jobs/plan4",
+			},
+			Queue: []templates.DeploymentData{
+				{ID: "5", Revision: "mno", Branch: "main", User: "someone", Trigger: "merge"},
+			},
+		},
+		{
+			// the deploy workflow isn't running
+			Repo:           "owner/repo",
+			Root:           "root2",
+			LastDeployment: &templates.DeploymentData{ID: "2", Revision: "def", Branch: "main", Trigger: "merge"},
+			Queue:          []templates.DeploymentData{},
+		},
+	}, resp.Roots)
+
+	// job urls are read from the deployment info and the queue, so each root is queried once
+	assert.ElementsMatch(t, []string{
+		"owner/broken||root/" + workflows.DeployQueueQueryName,
+		"owner/repo||root1/" + workflows.DeployQueueQueryName,
+		"owner/repo||root2/" + workflows.DeployQueueQueryName,
+	}, querier.queried)
+}
+
+func TestDeploymentsController_GetDeployments_HTML(t *testing.T) {
+	store := &testDeploymentStore{
+		infos: []*deployment.Info{
+			{
+				ID:         "1",
+				Revision:   "abc",
+				Branch:     "main",
+				Repo:       deployment.Repo{Owner: "owner", Name: "repo"},
+				Root:       deployment.Root{Name: "root", Trigger: "merge"},
+				PlanJobURL: "https://atlantis.com/jobs/plan1",
+			},
+		},
+	}
+
+	controller := newDeploymentsController(t, store, &testQuerier{})
+
+	r := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+	w := httptest.NewRecorder()
+	controller.GetDeployments(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.True(t, strings.Contains(body, "owner/repo"))
+	assert.True(t, strings.Contains(body, "<code>abc</code> on main"))
+	assert.True(t, strings.Contains(body, `<a href="https://atlantis.com/jobs/plan1">plan</a>`))
+}
+
+func TestDeploymentsController_GetDeployments_StoreError(t *testing.T) {
+	controller := newDeploymentsController(t, &testDeploymentStore{err: errors.New("error")}, &testQuerier{})
+
+	r := httptest.NewRequest(http.MethodGet, "/deployments", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	controller.GetDeployments(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestDeploymentsController_GetDeployments_Cached(t *testing.T) {
+	store := &testDeploymentStore{
+		infos: []*deployment.Info{
+			{
+				ID:       "1",
+				Revision: "abc",
+				Branch:   "main",
+				Repo:     deployment.Repo{Owner: "owner", Name: "repo"},
+				Root:     deployment.Root{Name: "root", Trigger: "merge"},
+			},
+		},
+	}
+	querier := &testQuerier{}
+
+	controller := newDeploymentsController(t, store, querier)
+	controller.RootsCacheTTL = time.Hour
+
+	for i := 0; i < 2; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/deployments?format=json", nil)
+		w := httptest.NewRecorder()
+		controller.GetDeployments(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp templates.DeploymentsIndexData
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Len(t, resp.Roots, 1)
+	}
+
+	// the second page load reuses the listing
+	assert.Equal(t, 1, store.calls)
+	assert.Len(t, querier.queried, 1)
+}
+
+func TestDeploymentsController_GetDeployments_ErrorNotCached(t *testing.T) {
+	store := &testDeploymentStore{err: errors.New("error")}
+	controller := newDeploymentsController(t, store, &testQuerier{})
+	controller.RootsCacheTTL = time.Hour
+
+	r := httptest.NewRequest(http.MethodGet, "/deployments?format=json", nil)
+	w := httptest.NewRecorder()
+	controller.GetDeployments(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	store.err = nil
+	r = httptest.NewRequest(http.MethodGet, "/deployments?format=json", nil)
+	w = httptest.NewRecorder()
+	controller.GetDeployments(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, store.calls)
+}
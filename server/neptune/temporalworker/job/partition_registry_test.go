@@ -11,12 +11,13 @@ import (
 )
 
 type testStore struct {
-	t      *testing.T
-	JobID  string
-	Output string
-	Err    error
-	Job    job.Job
-	Status job.JobStatus
+	t        *testing.T
+	JobID    string
+	RepoName string
+	Output   string
+	Err      error
+	Job      job.Job
+	Status   job.JobStatus
 }
 
 func (t *testStore) Get(ctx context.Context, jobID string) (*job.Job, error) {
@@ -34,8 +35,9 @@ func (t *testStore) Remove(jobID string) {
 	assert.Equal(t.t, t.JobID, jobID)
 }
 
-func (t *testStore) Close(ctx context.Context, jobID string, status job.JobStatus) error {
+func (t *testStore) Close(ctx context.Context, jobID string, repoFullName string, status job.JobStatus) error {
 	assert.Equal(t.t, t.JobID, jobID)
+	assert.Equal(t.t, t.RepoName, repoFullName)
 	assert.Equal(t.t, t.Status, status)
 	return t.Err
 }
@@ -94,11 +96,11 @@ func (t strictTestStore) Remove(jobID string) {
 	t.remove.count++
 }
 
-func (t strictTestStore) Close(ctx context.Context, jobID string, status job.JobStatus) error {
+func (t strictTestStore) Close(ctx context.Context, jobID string, repoFullName string, status job.JobStatus) error {
 	if t.close.count > len(t.close.runners)-1 {
 		t.t.FailNow()
 	}
-	err := t.close.runners[t.close.count].Close(ctx, jobID, status)
+	err := t.close.runners[t.close.count].Close(ctx, jobID, repoFullName, status)
 	t.close.count++
 	return err
 }
@@ -18,6 +18,8 @@ const PageSize = 100
 type StorageBackend interface {
 	Read(ctx context.Context, key string) ([]string, error)
 	Write(ctx context.Context, key string, logs []string) (bool, error)
+	// Index records the repo logs written to key belong to so retention can be applied per repo
+	Index(ctx context.Context, key string, repoFullName string) error
 }
 
 func NewStorageBackend(stowClient *storage.Client, scope tally.Scope, logger logging.Logger) (StorageBackend, error) {
@@ -67,6 +69,14 @@ func (s storageBackend) Write(ctx context.Context, key string, logs []string) (b
 	return true, nil
 }
 
+// Activity context since it's called from within an activity
+func (s storageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	if err := s.client.Set(ctx, storage.RepoIndexKey(repoFullName, key), []byte{}); err != nil {
+		return errors.Wrapf(err, "indexing object for job: %s", key)
+	}
+	return nil
+}
+
 type InstrumentedStorageBackend struct {
 	StorageBackend
 	scope tally.Scope
@@ -100,6 +110,19 @@ func (s *InstrumentedStorageBackend) Write(ctx context.Context, key string, logs
 	return ok, err
 }
 
+func (s *InstrumentedStorageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	indexScope := s.scope.SubScope("index")
+	failureCount := indexScope.Counter(metrics.ExecutionFailureMetric)
+	latency := indexScope.Timer(metrics.ExecutionTimeMetric).Start()
+	defer latency.Stop()
+
+	err := s.StorageBackend.Index(ctx, key, repoFullName)
+	if err != nil {
+		failureCount.Inc(1)
+	}
+	return err
+}
+
 // Used when log persistence is not configured
 type NoopStorageBackend struct{}
 
@@ -110,3 +133,7 @@ func (s *NoopStorageBackend) Read(ctx context.Context, key string) ([]string, er
 func (s *NoopStorageBackend) Write(ctx context.Context, key string, logs []string) (bool, error) {
 	return false, nil
 }
+
+func (s *NoopStorageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	return nil
+}
@@ -29,7 +29,8 @@ type Store interface {
 	Get(ctx context.Context, jobID string) (*Job, error)
 	Write(ctx context.Context, jobID string, output string) error
 	Remove(jobID string)
-	Close(ctx context.Context, jobID string, status JobStatus) error
+	// Close persists the job's output, repoFullName is the repo the job belongs to and may be empty if it's unknown
+	Close(ctx context.Context, jobID string, repoFullName string, status JobStatus) error
 	Cleanup(ctx context.Context) error
 }
 
@@ -108,7 +109,7 @@ func (m *InMemoryStore) Write(ctx context.Context, jobID string, output string)
 }
 
 // Activity context since it's called from within an activity
-func (m *InMemoryStore) Close(ctx context.Context, jobID string, status JobStatus) error {
+func (m *InMemoryStore) Close(ctx context.Context, jobID string, repoFullName string, status JobStatus) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -172,8 +173,8 @@ func (s StorageBackendJobStore) Write(ctx context.Context, jobID string, output
 }
 
 // Activity context since it's called from within an activity
-func (s *StorageBackendJobStore) Close(ctx context.Context, jobID string, status JobStatus) error {
-	if err := s.InMemoryStore.Close(ctx, jobID, status); err != nil {
+func (s *StorageBackendJobStore) Close(ctx context.Context, jobID string, repoFullName string, status JobStatus) error {
+	if err := s.InMemoryStore.Close(ctx, jobID, repoFullName, status); err != nil {
 		return err
 	}
 
@@ -195,8 +196,16 @@ func (s *StorageBackendJobStore) Close(ctx context.Context, jobID string, status
 	}
 
 	// Remove from memory if successfully persisted
-	if ok {
-		s.InMemoryStore.Remove(jobID)
+	if !ok {
+		return nil
+	}
+	s.InMemoryStore.Remove(jobID)
+
+	if repoFullName == "" {
+		return nil
+	}
+	if err := s.storageBackend.Index(ctx, jobID, repoFullName); err != nil {
+		return errors.Wrapf(err, "indexing job: %s", jobID)
 	}
 	return nil
 }
@@ -26,6 +26,11 @@ type testStorageBackend struct {
 		resp bool
 		err  error
 	}
+	index struct {
+		key      string
+		repoName string
+		err      error
+	}
 }
 
 func (t *testStorageBackend) Read(ctx context.Context, key string) ([]string, error) {
@@ -39,6 +44,12 @@ func (t *testStorageBackend) Write(ctx context.Context, key string, logs []strin
 	return t.write.resp, t.write.err
 }
 
+func (t *testStorageBackend) Index(ctx context.Context, key string, repoFullName string) error {
+	assert.Equal(t.t, t.index.key, key)
+	assert.Equal(t.t, t.index.repoName, repoFullName)
+	return t.index.err
+}
+
 func TestJobStore_Get(t *testing.T) {
 	key := "1234"
 	logs := []string{"a"}
@@ -195,7 +206,7 @@ func TestJobStore_Close(t *testing.T) {
 			},
 		}
 		jobStore := job.NewTestStorageBackedStore(logging.NewNoopCtxLogger(t), storageBackend, jobsMap)
-		err := jobStore.Close(context.TODO(), jobID, job.Complete)
+		err := jobStore.Close(context.TODO(), jobID, "owner/repo", job.Complete)
 
 		// Assert storage backend error
 		assert.EqualError(t, err, expecterErr.Error())
@@ -219,7 +230,7 @@ func TestJobStore_Close(t *testing.T) {
 		// Setup storage backend
 		storageBackend := &job.NoopStorageBackend{}
 		jobStore := job.NewTestStorageBackedStore(logging.NewNoopCtxLogger(t), storageBackend, jobsMap)
-		err := jobStore.Close(context.TODO(), jobID, job.Complete)
+		err := jobStore.Close(context.TODO(), jobID, "owner/repo", job.Complete)
 		assert.Nil(t, err)
 
 		// Assert the job is in memory
@@ -259,10 +270,18 @@ func TestJobStore_Close(t *testing.T) {
 			}{
 				key: jobID,
 			},
+			index: struct {
+				key      string
+				repoName string
+				err      error
+			}{
+				key:      jobID,
+				repoName: "owner/repo",
+			},
 		}
 
 		jobStore := job.NewTestStorageBackedStore(logging.NewNoopCtxLogger(t), storageBackend, jobsMap)
-		err := jobStore.Close(context.TODO(), jobID, job.Complete)
+		err := jobStore.Close(context.TODO(), jobID, "owner/repo", job.Complete)
 		assert.Nil(t, err)
 
 		gotJob, err := jobStore.Get(context.Background(), jobID)
@@ -274,7 +293,7 @@ func TestJobStore_Close(t *testing.T) {
 		storageBackend := &testStorageBackend{}
 		jobStore := job.NewTestStorageBackedStore(logging.NewNoopCtxLogger(t), storageBackend, map[string]*job.Job{})
 
-		err := jobStore.Close(context.TODO(), jobID, job.Complete)
+		err := jobStore.Close(context.TODO(), jobID, "owner/repo", job.Complete)
 		assert.Nil(t, err)
 	})
 }
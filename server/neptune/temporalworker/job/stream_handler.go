@@ -73,9 +73,9 @@ func (s *StreamHandler) RegisterJob(id string) chan string {
 // in order to support streaming all logs for a group to the same job id, we call this after all the activities of a group have occurred.
 // This actually is kind of broken because we don't checkpoint these across activities, so on shutdown we would lose logs for previous steps.
 // TODO: we need to rethink this a bit and create clear definitions for our data models.
-func (s *StreamHandler) CloseJob(ctx context.Context, jobID string) error {
+func (s *StreamHandler) CloseJob(ctx context.Context, jobID string, repoFullName string) error {
 	s.ReceiverRegistry.Close(ctx, jobID)
-	return s.Store.Close(ctx, jobID, Complete)
+	return s.Store.Close(ctx, jobID, repoFullName, Complete)
 }
 
 func (s *StreamHandler) CleanUp(ctx context.Context) error {
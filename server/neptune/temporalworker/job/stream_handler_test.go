@@ -104,9 +104,10 @@ func TestStreamHandler_Close(t *testing.T) {
 			}{
 				runners: []*testStore{
 					{
-						t:      t,
-						JobID:  jobID,
-						Status: job.Complete,
+						t:        t,
+						JobID:    jobID,
+						RepoName: "owner/repo",
+						Status:   job.Complete,
 					},
 				},
 			},
@@ -117,7 +118,7 @@ func TestStreamHandler_Close(t *testing.T) {
 			valid.TerraformLogFilters{},
 			logging.NewNoopCtxLogger(t),
 		)
-		err := streamHandler.CloseJob(context.Background(), jobID)
+		err := streamHandler.CloseJob(context.Background(), jobID, "owner/repo")
 		assert.NoError(t, err)
 	})
 }
@@ -20,12 +20,14 @@ import (
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/controllers/templates"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 	neptune_http "github.com/runatlantis/atlantis/server/neptune/http"
 	lyftActivities "github.com/runatlantis/atlantis/server/neptune/lyft/activities"
 	"github.com/runatlantis/atlantis/server/neptune/lyft/notifier"
+	"github.com/runatlantis/atlantis/server/neptune/storage"
 	internalSync "github.com/runatlantis/atlantis/server/neptune/sync"
 	"github.com/runatlantis/atlantis/server/neptune/sync/crons"
 	"github.com/runatlantis/atlantis/server/neptune/temporal"
@@ -34,6 +36,7 @@ import (
 	"github.com/runatlantis/atlantis/server/neptune/temporalworker/job"
 	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
 	"github.com/runatlantis/atlantis/server/static"
 	"github.com/uber-go/tally/v4"
@@ -121,9 +124,30 @@ func NewServer(config *config.Config) (*Server, error) {
 		return nil, errors.Wrap(err, "initializing temporal client")
 	}
 
+	deploymentStorageClient, err := storage.NewClient(config.DeploymentConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing deployment storage client")
+	}
+	deploymentStore, err := deployment.NewStore(deploymentStorageClient, config.CtxLogger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing deployment store")
+	}
+	deploymentsController := &controllers.DeploymentsController{
+		AtlantisVersion:          config.ServerCfg.Version,
+		AtlantisURL:              config.ServerCfg.URL,
+		DeploymentStore:          deploymentStore,
+		TemporalClient:           temporalClient,
+		DeploymentsIndexTemplate: templates.DeploymentsIndexTemplate,
+		StatsScope:               scope.SubScope("http.getdeployments"),
+		Logger:                   config.CtxLogger,
+		RootsCacheTTL:            controllers.DefaultRootsCacheTTL,
+	}
+
 	// router initialization
 	router := mux.NewRouter()
 	router.HandleFunc("/healthz", Healthz).Methods(http.MethodGet)
+	router.HandleFunc("/", deploymentsController.GetDeployments).Methods(http.MethodGet)
+	router.HandleFunc("/deployments", deploymentsController.GetDeployments).Methods(http.MethodGet)
 	router.PathPrefix("/static/").Handler(http.FileServer(&assetfs.AssetFS{Asset: static.Asset, AssetDir: static.AssetDir, AssetInfo: static.AssetInfo}))
 	router.HandleFunc("/jobs/{job-id}", jobsController.GetProjectJobs).Methods(http.MethodGet).Name(ProjectJobsViewRouteName)
 	router.HandleFunc("/jobs/{job-id}/ws", jobsController.GetProjectJobsWS).Methods(http.MethodGet)
@@ -145,7 +169,7 @@ func NewServer(config *config.Config) (*Server, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing lyft activities")
 	}
-	deployActivities, err := activities.NewDeploy(config.DeploymentConfig)
+	deployActivities, err := activities.NewDeploy(config.DeploymentConfig, config.FreezeWindows, config.CtxLogger)
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing deploy activities")
 	}
@@ -158,6 +182,9 @@ func NewServer(config *config.Config) (*Server, error) {
 		config.ServerCfg.URL,
 		config.TemporalCfg.TerraformTaskQueue,
 		jobStreamHandler,
+		activities.TerraformOptions{
+			Logger: config.CtxLogger,
+		},
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing terraform activities")
@@ -207,17 +234,38 @@ func NewServer(config *config.Config) (*Server, error) {
 		return nil, errors.Wrap(err, "initializing revision setter activities")
 	}
 
+	serverCrons := []*internalSync.Cron{
+		{
+			Executor:  crons.NewRuntimeStats(scope).Run,
+			Frequency: 1 * time.Minute,
+		},
+	}
+
+	if config.JobRetention.Enabled() {
+		jobStorageClient, err := storage.NewClient(config.JobConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing job storage client")
+		}
+
+		jobRetention := &crons.JobRetention{
+			Store:     jobStorageClient,
+			Retention: config.JobRetention,
+			Logger:    config.CtxLogger,
+			Scope:     scope,
+		}
+
+		serverCrons = append(serverCrons, &internalSync.Cron{
+			Executor:  jobRetention.Run,
+			Frequency: config.JobRetention.Interval,
+		})
+	}
+
 	cronScheduler := internalSync.NewCronScheduler(config.CtxLogger)
 
 	server := Server{
-		Logger:        config.CtxLogger,
-		CronScheduler: cronScheduler,
-		Crons: []*internalSync.Cron{
-			{
-				Executor:  crons.NewRuntimeStats(scope).Run,
-				Frequency: 1 * time.Minute,
-			},
-		},
+		Logger:                   config.CtxLogger,
+		CronScheduler:            cronScheduler,
+		Crons:                    serverCrons,
 		HTTPServerProxy:          httpServerProxy,
 		Port:                     config.ServerCfg.Port,
 		StatsScope:               scope,
@@ -240,6 +288,14 @@ func (s Server) Start() error {
 
 	ctx := context.Background()
 
+	if s.TerraformActivities.PluginCacheWarmer != nil {
+		go func() {
+			if err := s.TerraformActivities.PluginCacheWarmer(); err != nil {
+				s.Logger.WarnContext(ctx, fmt.Sprintf("warming plugin cache: %s", err))
+			}
+		}()
+	}
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
@@ -409,6 +465,14 @@ func (s Server) buildDeployWorker() worker.Worker {
 	), workflow.RegisterOptions{
 		Name: workflows.Deploy,
 	})
+	var a *lyftActivities.Activities
+	deployWorker.RegisterWorkflowWithOptions(workflows.GetDriftWithNotifiers(
+		&notifier.SNSNotifier{
+			Activity: a,
+		},
+	), workflow.RegisterOptions{
+		Name: workflows.Drift,
+	})
 	deployWorker.RegisterWorkflow(workflows.Terraform)
 	return deployWorker
 }
@@ -24,9 +24,11 @@ type Line struct {
 
 func NewAsyncClient(defaultVersion *version.Version, versionCache cache.ExecutionVersionCache) (*AsyncClient, error) {
 	// warm the cache with this version
-	_, err := versionCache.Get(defaultVersion)
-	if err != nil {
-		return nil, errors.Wrapf(err, "getting default version %s", defaultVersion)
+	if defaultVersion != nil {
+		_, err := versionCache.Get(defaultVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting default version %s", defaultVersion)
+		}
 	}
 
 	cmdBuilder := &execBuilder{
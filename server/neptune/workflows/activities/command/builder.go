@@ -22,6 +22,9 @@ func (e *execBuilder) Build(_ context.Context, v *version.Version, path string,
 	if v == nil {
 		v = e.defaultVersion
 	}
+	if v == nil {
+		return nil, errors.New("no version specified and no default version configured")
+	}
 
 	binPath, err := e.versionCache.Get(v)
 	if err != nil {
@@ -5,12 +5,21 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/command"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/temporal"
 	"go.temporal.io/sdk/activity"
+	"regexp"
 	"strings"
+	"time"
 )
 
+// conftest prefixes each warning with this, ie. WARN - <file> - <namespace> - <message>
+const warnPrefix = "WARN - "
+
+// matches a non-zero warning count in the conftest summary, ie. 2 tests, 1 passed, 1 warning, 0 failures
+var warningCountRegex = regexp.MustCompile(`\b[1-9][0-9]* warnings?\b`)
+
 type asyncClient interface {
 	RunCommand(ctx context.Context, request *command.RunCommandRequest, options ...command.RunOptions) error
 }
@@ -37,6 +46,10 @@ type ConftestRequest struct {
 	JobID       string
 	Path        string
 	ShowFile    string
+
+	// used to match policy exemptions
+	RepoName string
+	RootName string
 }
 
 type ValidationStatus int
@@ -44,12 +57,16 @@ type ValidationStatus int
 const (
 	Success ValidationStatus = iota
 	Fail
-	//todo: support warn status
+	// Warn indicates the policy set passed with warnings, these are advisory and never block
+	Warn
 )
 
 type ValidationResult struct {
 	Status    ValidationStatus
 	PolicySet PolicySet
+
+	// populated for Warn results
+	Warnings []string
 }
 
 type ConftestResponse struct {
@@ -74,6 +91,8 @@ func (c *conftestActivity) Conftest(ctx context.Context, request ConftestRequest
 	var policyNames []string
 	var totalCmdOutput []string
 	var validationResults []ValidationResult
+	var exemptions []string
+	now := time.Now()
 
 	// run each policy separately to track which pass and fail
 	for _, policy := range c.Policies {
@@ -94,13 +113,33 @@ func (c *conftestActivity) Conftest(ctx context.Context, request ConftestRequest
 			Version:           c.DefaultConftestVersion,
 		}
 		cmdOutput, cmdErr := c.runCommand(ctx, conftestRequest)
+		exemption := activeExemption(policy, request.RepoName, request.RootName, now)
+		if exemption != nil {
+			exemptions = append(exemptions, fmt.Sprintf("%s: %s (expires %s)", policy.Name, exemption.Justification, exemption.Expiry.Format(valid.ExemptionExpiryFormat)))
+		}
+
 		// Continue running other policies if one fails since it might not be the only failing one
-		if cmdErr != nil {
+		if cmdErr != nil && exemption != nil {
+			// exempted failures are surfaced as warnings so they're visible without blocking
+			validationResults = append(validationResults, ValidationResult{
+				Status:    Warn,
+				PolicySet: policy,
+				Warnings: []string{
+					fmt.Sprintf("policy failures are exempt until %s: %s", exemption.Expiry.Format(valid.ExemptionExpiryFormat), exemption.Justification),
+				},
+			})
+		} else if cmdErr != nil {
 			activity.GetLogger(ctx).Error(cmdOutput)
 			validationResults = append(validationResults, ValidationResult{
 				Status:    Fail,
 				PolicySet: policy,
 			})
+		} else if warnings := c.parseWarnings(showFile, cmdOutput); len(warnings) > 0 {
+			validationResults = append(validationResults, ValidationResult{
+				Status:    Warn,
+				PolicySet: policy,
+				Warnings:  warnings,
+			})
 		} else {
 			validationResults = append(validationResults, ValidationResult{
 				Status:    Success,
@@ -110,7 +149,7 @@ func (c *conftestActivity) Conftest(ctx context.Context, request ConftestRequest
 		totalCmdOutput = append(totalCmdOutput, c.processOutput(cmdOutput, policy, cmdErr))
 	}
 	title := c.buildTitle(policyNames)
-	output := c.sanitizeOutput(showFile, title+strings.Join(totalCmdOutput, "\n"))
+	output := c.sanitizeOutput(showFile, title+strings.Join(totalCmdOutput, "\n")+c.buildExemptions(exemptions))
 	c.writeOutput(output, request.JobID)
 	return ConftestResponse{ValidationResults: validationResults}, nil
 }
@@ -136,10 +175,50 @@ func (c *conftestActivity) buildTitle(policySetNames []string) string {
 	return fmt.Sprintf("Checking plan against the following policies: \n  %s\n\n", strings.Join(policySetNames, "\n  "))
 }
 
+func (c *conftestActivity) buildExemptions(exemptions []string) string {
+	if len(exemptions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nThe following policies are exempt for this root: \n  %s\n", strings.Join(exemptions, "\n  "))
+}
+
+func activeExemption(policy PolicySet, repoName string, rootName string, now time.Time) *PolicyExemption {
+	for _, e := range policy.Exemptions {
+		if valid.PolicyExemption(e).Matches(repoName, rootName) && valid.PolicyExemption(e).IsActive(now) {
+			exemption := e
+			return &exemption
+		}
+	}
+	return nil
+}
+
 func (c *conftestActivity) sanitizeOutput(inputFile string, output string) string {
 	return strings.Replace(output, inputFile, "<redacted plan file>", -1)
 }
 
+// parseWarnings returns the warning messages from a successful conftest run if the summary
+// reports any warnings
+func (c *conftestActivity) parseWarnings(inputFile string, output string) []string {
+	if !warningCountRegex.MatchString(output) {
+		return nil
+	}
+
+	var warnings []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, warnPrefix) {
+			continue
+		}
+		warnings = append(warnings, c.sanitizeOutput(inputFile, strings.TrimPrefix(line, warnPrefix)))
+	}
+
+	// always return at least one entry so the result is still treated as a warning
+	if len(warnings) == 0 {
+		warnings = append(warnings, "policy set returned warnings")
+	}
+	return warnings
+}
+
 func (c *conftestActivity) processOutput(output string, policySet PolicySet, err error) string {
 	// errored results need an extra newline
 	if err != nil {
@@ -1,12 +1,14 @@
 package activities
 
 import (
+	"fmt"
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/command"
 	"github.com/stretchr/testify/assert"
 	"go.temporal.io/sdk/testsuite"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestConftest_RequestValidation(t *testing.T) {
@@ -204,3 +206,162 @@ func (s *mockStat) Stat(name string) error {
 	assert.Equal(s.t, s.expectedName, name)
 	return s.error
 }
+
+func TestConftest_Warnings(t *testing.T) {
+	version, err := version.NewVersion("0.20.0")
+	assert.Nil(t, err)
+
+	showFile := "some/path/output.json"
+	cases := []struct {
+		description    string
+		output         string
+		cmdErr         error
+		expectedResult ValidationResult
+	}{
+		{
+			description: "success",
+			output:      "2 tests, 2 passed, 0 warnings, 0 failures, 0 exceptions",
+			expectedResult: ValidationResult{
+				Status:    Success,
+				PolicySet: PolicySet{Name: "policy1"},
+			},
+		},
+		{
+			description: "warnings",
+			output:      "WARN - some/path/output.json - main - instance type is deprecated\n\n2 tests, 1 passed, 1 warning, 0 failures, 0 exceptions",
+			expectedResult: ValidationResult{
+				Status:    Warn,
+				PolicySet: PolicySet{Name: "policy1"},
+				Warnings:  []string{"<redacted plan file> - main - instance type is deprecated"},
+			},
+		},
+		{
+			description: "failures take precedence",
+			output:      "WARN - some/path/output.json - main - instance type is deprecated\nFAIL - some/path/output.json - main - bucket is public\n\n2 tests, 0 passed, 1 warning, 1 failure, 0 exceptions",
+			cmdErr:      assert.AnError,
+			expectedResult: ValidationResult{
+				Status:    Fail,
+				PolicySet: PolicySet{Name: "policy1"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ts := testsuite.WorkflowTestSuite{}
+			env := ts.NewTestActivityEnvironment()
+
+			testClient := &testTfClient{
+				t:             t,
+				cmd:           command.NewSubCommand(command.ConftestTest).WithInput(showFile).WithFlags(NoColorFlag),
+				customEnvVars: map[string]string{},
+				version:       version,
+				resp:          c.output,
+				expectedError: c.cmdErr,
+			}
+			activity := conftestActivity{
+				DefaultConftestVersion: version,
+				ConftestClient:         testClient,
+				StreamHandler:          &testStreamHandler{t: t},
+				Policies:               []PolicySet{{Name: "policy1"}},
+				FileValidator:          &mockStat{t: t, expectedName: showFile},
+			}
+			env.RegisterActivity(activity.Conftest)
+
+			result, err := env.ExecuteActivity(activity.Conftest, ConftestRequest{ShowFile: showFile})
+			assert.NoError(t, err)
+
+			var resp ConftestResponse
+			assert.NoError(t, result.Get(&resp))
+			assert.Equal(t, []ValidationResult{c.expectedResult}, resp.ValidationResults)
+		})
+	}
+}
+
+func TestConftest_Exemptions(t *testing.T) {
+	version, err := version.NewVersion("0.20.0")
+	assert.Nil(t, err)
+
+	showFile := "some/path/output.json"
+	expiry := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	policySet := PolicySet{
+		Name: "policy1",
+		Exemptions: []PolicyExemption{
+			{
+				Repo:          "owner/repo",
+				Roots:         []string{"root1"},
+				Expiry:        expiry,
+				Justification: "migrating",
+			},
+		},
+	}
+
+	cases := []struct {
+		description    string
+		rootName       string
+		expectedResult ValidationResult
+		expectedOutput string
+	}{
+		{
+			description: "exempt root",
+			rootName:    "root1",
+			expectedResult: ValidationResult{
+				Status:    Warn,
+				PolicySet: policySet,
+				Warnings:  []string{fmt.Sprintf("policy failures are exempt until %s: migrating", expiry.Format("2006-01-02"))},
+			},
+			expectedOutput: fmt.Sprintf("The following policies are exempt for this root: \n  policy1: migrating (expires %s)\n", expiry.Format("2006-01-02")),
+		},
+		{
+			description: "other root",
+			rootName:    "root2",
+			expectedResult: ValidationResult{
+				Status:    Fail,
+				PolicySet: policySet,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ts := testsuite.WorkflowTestSuite{}
+			env := ts.NewTestActivityEnvironment()
+
+			testClient := &testTfClient{
+				t:             t,
+				cmd:           command.NewSubCommand(command.ConftestTest).WithInput(showFile).WithFlags(NoColorFlag),
+				customEnvVars: map[string]string{},
+				version:       version,
+				resp:          "FAIL - some/path/output.json - main - bucket is public",
+				expectedError: assert.AnError,
+			}
+			streamHandler := &testStreamHandler{t: t}
+			activity := conftestActivity{
+				DefaultConftestVersion: version,
+				ConftestClient:         testClient,
+				StreamHandler:          streamHandler,
+				Policies:               []PolicySet{policySet},
+				FileValidator:          &mockStat{t: t, expectedName: showFile},
+			}
+			env.RegisterActivity(activity.Conftest)
+
+			result, err := env.ExecuteActivity(activity.Conftest, ConftestRequest{
+				ShowFile: showFile,
+				RepoName: "owner/repo",
+				RootName: c.rootName,
+			})
+			assert.NoError(t, err)
+
+			var resp ConftestResponse
+			assert.NoError(t, result.Get(&resp))
+			assert.Equal(t, []ValidationResult{c.expectedResult}, resp.ValidationResults)
+
+			streamHandler.Wait()
+			if c.expectedOutput != "" {
+				assert.Contains(t, strings.Join(streamHandler.received, ""), c.expectedOutput)
+			} else {
+				assert.NotContains(t, strings.Join(streamHandler.received, ""), "exempt")
+			}
+		})
+	}
+}
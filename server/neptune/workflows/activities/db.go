@@ -10,6 +10,9 @@ import (
 type store interface {
 	GetDeploymentInfo(ctx context.Context, repoName string, rootName string) (*deployment.Info, error)
 	SetDeploymentInfo(ctx context.Context, deploymentInfo *deployment.Info) error
+	GetQueue(ctx context.Context, repoName string, rootName string) (*deployment.Queue, error)
+	SetQueue(ctx context.Context, repoName string, rootName string, queue *deployment.Queue) error
+	AppendDeploymentHistory(ctx context.Context, entry *deployment.HistoryEntry) error
 }
 
 type dbActivities struct {
@@ -48,3 +51,51 @@ func (a *dbActivities) StoreLatestDeployment(ctx context.Context, request StoreL
 
 	return nil
 }
+
+type AppendDeploymentHistoryRequest struct {
+	Entry *deployment.HistoryEntry
+}
+
+func (a *dbActivities) AppendDeploymentHistory(ctx context.Context, request AppendDeploymentHistoryRequest) error {
+	err := a.DeploymentInfoStore.AppendDeploymentHistory(ctx, request.Entry)
+	if err != nil {
+		return errors.Wrapf(err, "appending deployment history for %s/%s [%s] ", request.Entry.Repo.GetFullName(), request.Entry.Root.Name, request.Entry.ID)
+	}
+
+	return nil
+}
+
+type FetchDeployQueueRequest struct {
+	FullRepositoryName string
+	RootName           string
+}
+
+type FetchDeployQueueResponse struct {
+	Queue *deployment.Queue
+}
+
+func (a *dbActivities) FetchDeployQueue(ctx context.Context, request FetchDeployQueueRequest) (FetchDeployQueueResponse, error) {
+	queue, err := a.DeploymentInfoStore.GetQueue(ctx, request.FullRepositoryName, request.RootName)
+	if err != nil {
+		return FetchDeployQueueResponse{}, errors.Wrapf(err, "fetching deploy queue for %s/%s", request.FullRepositoryName, request.RootName)
+	}
+
+	return FetchDeployQueueResponse{
+		Queue: queue,
+	}, nil
+}
+
+type StoreDeployQueueRequest struct {
+	FullRepositoryName string
+	RootName           string
+	Queue              *deployment.Queue
+}
+
+func (a *dbActivities) StoreDeployQueue(ctx context.Context, request StoreDeployQueueRequest) error {
+	err := a.DeploymentInfoStore.SetQueue(ctx, request.FullRepositoryName, request.RootName, request.Queue)
+	if err != nil {
+		return errors.Wrapf(err, "uploading deploy queue for %s/%s", request.FullRepositoryName, request.RootName)
+	}
+
+	return nil
+}
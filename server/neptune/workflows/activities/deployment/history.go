@@ -0,0 +1,91 @@
+package deployment
+
+import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+)
+
+const HistorySchemaVersion = 1.0
+
+type Outcome string
+
+const (
+	SuccessOutcome Outcome = "success"
+	FailureOutcome Outcome = "failure"
+	// RejectedOutcome is recorded when the plan was rejected so nothing was applied
+	RejectedOutcome Outcome = "rejected"
+)
+
+type ResourceAction string
+
+const (
+	CreateAction  ResourceAction = "create"
+	UpdateAction  ResourceAction = "update"
+	DeleteAction  ResourceAction = "delete"
+	ReplaceAction ResourceAction = "replace"
+	MoveAction    ResourceAction = "move"
+	ImportAction  ResourceAction = "import"
+)
+
+// HistoryEntry records a single deploy of a root. Entries are appended to the root's
+// history and never modified, like Info changes to this object should be backwards compatible.
+type HistoryEntry struct {
+	Version  int
+	ID       string
+	Revision string
+	Branch   string
+	User     string
+	Repo     Repo
+	Root     Root
+
+	// Changes only keeps the address and action of each changed resource, attribute values
+	// aren't persisted since entries are kept indefinitely.
+	Changes []ResourceChange
+
+	// PlanReview is only populated for deployments whose plan was manually approved
+	PlanReview *PlanReview `json:",omitempty"`
+
+	// RolledBackRevision is only populated for rollbacks
+	RolledBackRevision string `json:",omitempty"`
+
+	// PromotedBy is only populated for deployments promoted past a manual promotion gate
+	PromotedBy string `json:",omitempty"`
+
+	Outcome     Outcome
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+type ResourceChange struct {
+	Address string
+	Action  ResourceAction
+}
+
+// NewResourceChanges flattens the plan summary into the resource changes of a history entry,
+// replacements are included in the summary's creations and deletions so they are excluded from both.
+func NewResourceChanges(summary terraform.PlanSummary) []ResourceChange {
+	replaced := map[string]bool{}
+	for _, r := range summary.Replacements {
+		replaced[r.Address] = true
+	}
+
+	var changes []ResourceChange
+	add := func(action ResourceAction, resources []terraform.ResourceSummary, skipReplaced bool) {
+		for _, r := range resources {
+			if skipReplaced && replaced[r.Address] {
+				continue
+			}
+			changes = append(changes, ResourceChange{Address: r.Address, Action: action})
+		}
+	}
+
+	add(CreateAction, summary.Creations, true)
+	add(UpdateAction, summary.Updates, false)
+	add(DeleteAction, summary.Deletions, true)
+	add(ReplaceAction, summary.Replacements, false)
+	add(MoveAction, summary.Moves, false)
+	add(ImportAction, summary.Imports, false)
+
+	return changes
+}
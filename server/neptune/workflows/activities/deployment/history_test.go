@@ -0,0 +1,33 @@
+package deployment_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResourceChanges(t *testing.T) {
+	summary := terraform.PlanSummary{
+		Creations: []terraform.ResourceSummary{{Address: "created"}, {Address: "replaced"}},
+		Updates: []terraform.ResourceSummary{{
+			Address:          "updated",
+			AttributeChanges: []terraform.AttributeChange{{Path: "name", Before: "a", After: "b"}},
+		}},
+		Deletions:    []terraform.ResourceSummary{{Address: "deleted"}, {Address: "replaced"}},
+		Replacements: []terraform.ResourceSummary{{Address: "replaced", ReplaceReasons: []string{"name"}}},
+		Moves:        []terraform.ResourceSummary{{Address: "moved", PreviousAddress: "old"}},
+		Imports:      []terraform.ResourceSummary{{Address: "imported", ImportID: "id"}},
+		Outputs:      []terraform.OutputChange{{Name: "output", Action: "update"}},
+	}
+
+	assert.Equal(t, []deployment.ResourceChange{
+		{Address: "created", Action: deployment.CreateAction},
+		{Address: "updated", Action: deployment.UpdateAction},
+		{Address: "deleted", Action: deployment.DeleteAction},
+		{Address: "replaced", Action: deployment.ReplaceAction},
+		{Address: "moved", Action: deployment.MoveAction},
+		{Address: "imported", Action: deployment.ImportAction},
+	}, deployment.NewResourceChanges(summary))
+}
@@ -12,6 +12,24 @@ type Info struct {
 	Branch   string
 	Repo     Repo
 	Root     Root
+
+	// PlanReview is only populated for deployments whose plan was manually approved
+	PlanReview *PlanReview `json:",omitempty"`
+
+	// RolledBackRevision is the revision which was deployed before a rollback to this one
+	RolledBackRevision string `json:",omitempty"`
+
+	// PromotedBy is the user which promoted the deployment past a manual promotion gate
+	PromotedBy string `json:",omitempty"`
+
+	// PlanJobURL and ApplyJobURL link to the output of the deployment's terraform jobs
+	PlanJobURL  string `json:",omitempty"`
+	ApplyJobURL string `json:",omitempty"`
+}
+
+type PlanReview struct {
+	User   string
+	Reason string
 }
 
 type Repo struct {
@@ -24,8 +42,9 @@ func (r Repo) GetFullName() string {
 }
 
 type Root struct {
-	Name        string
-	Trigger     string
-	ManualRerun bool
-	ManualForce bool
+	Name           string
+	Trigger        string
+	ManualRerun    bool
+	ManualForce    bool
+	ManualRollback bool
 }
@@ -0,0 +1,26 @@
+package deployment
+
+import (
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+)
+
+const QueueSchemaVersion = 1.0
+
+// Queue is a snapshot of the revisions waiting to be deployed for a given root.
+// Like Info, this object is persisted so changes should be backwards compatible.
+type Queue struct {
+	Version   int
+	Revisions []QueuedRevision
+}
+
+type QueuedRevision struct {
+	ID             string
+	CheckRunID     int64
+	Revision       string
+	Branch         string
+	InitiatingUser github.User
+	Root           terraform.Root
+	Repo           github.Repo
+	Tags           map[string]string
+}
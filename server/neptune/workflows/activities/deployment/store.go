@@ -5,29 +5,87 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/storage"
 )
 
 type client interface {
 	Get(ctx context.Context, key string) (io.ReadCloser, error)
 	Set(ctx context.Context, key string, object []byte) error
+	List(ctx context.Context, prefix string) ([]string, error)
 }
 
-func NewStore(stowClient client) (*Store, error) {
+const (
+	deploymentInfoFilename = "deployment.json"
+
+	// rootIndexPrefix holds an empty object for every root with deployment info so listing
+	// them doesn't walk the queue and history objects stored for each root.
+	rootIndexPrefix = "_index/roots/"
+
+	// rootIndexBackfilledKey marks that deployment info written before the index existed
+	// has been added to it.
+	rootIndexBackfilledKey = "_index/roots.backfilled"
+)
+
+func NewStore(stowClient client, logger logging.Logger) (*Store, error) {
 	return &Store{
 		stowClient: stowClient,
+		logger:     logger,
 	}, nil
 }
 
 type Store struct {
 	stowClient client
+	logger     logging.Logger
 }
 
 func (s *Store) GetDeploymentInfo(ctx context.Context, repoName string, rootName string) (*Info, error) {
-	key := BuildKey(repoName, rootName)
+	return s.getDeploymentInfo(ctx, BuildKey(repoName, rootName))
+}
+
+// ListDeploymentInfo returns the latest deployment of every root in the store.
+// Deployment info which can't be read is logged and skipped.
+func (s *Store) ListDeploymentInfo(ctx context.Context) ([]*Info, error) {
+	if err := s.backfillRootIndex(ctx); err != nil {
+		return nil, errors.Wrap(err, "backfilling root index")
+	}
 
+	keys, err := s.stowClient.List(ctx, rootIndexPrefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing items")
+	}
+
+	var infos []*Info
+	for _, indexKey := range keys {
+		key := strings.TrimPrefix(indexKey, rootIndexPrefix)
+		if !strings.HasSuffix(key, "/"+deploymentInfoFilename) {
+			continue
+		}
+
+		info, err := s.getDeploymentInfo(ctx, key)
+		if _, ok := err.(*storage.ContainerNotFoundError); ok {
+			return nil, err
+		}
+		if err != nil {
+			s.logger.WarnContext(ctx, fmt.Sprintf("skipping deployment info %s", key), map[string]interface{}{"err": err})
+			continue
+		}
+
+		// could have been removed since we've listed
+		if info == nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (s *Store) getDeploymentInfo(ctx context.Context, key string) (*Info, error) {
 	reader, err := s.stowClient.Get(ctx, key)
 	if err != nil {
 		switch err.(type) {
@@ -63,6 +121,104 @@ func (s *Store) SetDeploymentInfo(ctx context.Context, deploymentInfo *Info) err
 		return errors.Wrap(err, "marshalling deployment info")
 	}
 
+	err = s.stowClient.Set(ctx, key, object)
+	if err != nil {
+		return errors.Wrap(err, "writing to store")
+	}
+
+	err = s.stowClient.Set(ctx, rootIndexPrefix+key, []byte{})
+	if err != nil {
+		return errors.Wrap(err, "writing root index")
+	}
+	return nil
+}
+
+// backfillRootIndex indexes deployment info written before the root index existed. This walks
+// the whole container so it only happens once per store.
+func (s *Store) backfillRootIndex(ctx context.Context) error {
+	reader, err := s.stowClient.Get(ctx, rootIndexBackfilledKey)
+	if err == nil {
+		return reader.Close()
+	}
+	if _, ok := err.(*storage.ItemNotFoundError); !ok {
+		return errors.Wrap(err, "getting item")
+	}
+
+	keys, err := s.stowClient.List(ctx, "")
+	if err != nil {
+		return errors.Wrap(err, "listing items")
+	}
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, rootIndexPrefix) || !strings.HasSuffix(key, "/"+deploymentInfoFilename) {
+			continue
+		}
+
+		if err := s.stowClient.Set(ctx, rootIndexPrefix+key, []byte{}); err != nil {
+			return errors.Wrap(err, "writing root index")
+		}
+	}
+
+	if err := s.stowClient.Set(ctx, rootIndexBackfilledKey, []byte{}); err != nil {
+		return errors.Wrap(err, "writing to store")
+	}
+	return nil
+}
+
+func (s *Store) GetQueue(ctx context.Context, repoName string, rootName string) (*Queue, error) {
+	key := BuildQueueKey(repoName, rootName)
+
+	reader, err := s.stowClient.Get(ctx, key)
+	if err != nil {
+		switch err.(type) {
+		// Fail if container is not found
+		case *storage.ContainerNotFoundError:
+			return nil, err
+
+		// Nothing has been queued for this root yet
+		case *storage.ItemNotFoundError:
+			return nil, nil
+
+		default:
+			return nil, errors.Wrap(err, "getting item")
+		}
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+
+	var queue Queue
+	err = decoder.Decode(&queue)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding item")
+	}
+
+	return &queue, nil
+}
+
+func (s *Store) SetQueue(ctx context.Context, repoName string, rootName string, queue *Queue) error {
+	key := BuildQueueKey(repoName, rootName)
+	object, err := json.Marshal(queue)
+	if err != nil {
+		return errors.Wrap(err, "marshalling queue")
+	}
+
+	err = s.stowClient.Set(ctx, key, object)
+	if err != nil {
+		return errors.Wrap(err, "writing to store")
+	}
+	return nil
+}
+
+// AppendDeploymentHistory adds a deployment to its root's history. Entries are keyed by their
+// completion time and id so appending the same entry again is safe.
+func (s *Store) AppendDeploymentHistory(ctx context.Context, entry *HistoryEntry) error {
+	key := BuildHistoryKey(entry.Repo.GetFullName(), entry.Root.Name, entry)
+	object, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "marshalling history entry")
+	}
+
 	err = s.stowClient.Set(ctx, key, object)
 	if err != nil {
 		return errors.Wrap(err, "writing to store")
@@ -70,6 +226,76 @@ func (s *Store) SetDeploymentInfo(ctx context.Context, deploymentInfo *Info) err
 	return nil
 }
 
+// ListDeploymentHistory returns up to limit deployments of a root, most recent first. If cursor is set
+// only deployments which completed before it are returned. The returned cursor fetches the next page
+// and is empty once there are no more deployments.
+func (s *Store) ListDeploymentHistory(ctx context.Context, repoName string, rootName string, cursor string, limit int) ([]*HistoryEntry, string, error) {
+	prefix := buildHistoryPrefix(repoName, rootName)
+	keys, err := s.stowClient.List(ctx, prefix)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "listing items")
+	}
+
+	var names []string
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, prefix)
+		if name == key || strings.Contains(name, "/") {
+			continue
+		}
+		if cursor != "" && name >= cursor {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	// names start with the completion time so this orders them by it
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	var next string
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+		next = names[limit-1]
+	}
+
+	entries := make([]*HistoryEntry, 0, len(names))
+	for _, name := range names {
+		entry, err := s.getHistoryEntry(ctx, prefix+name)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "getting history entry %s", name)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, next, nil
+}
+
+func (s *Store) getHistoryEntry(ctx context.Context, key string) (*HistoryEntry, error) {
+	reader, err := s.stowClient.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting item")
+	}
+	defer reader.Close()
+
+	var entry HistoryEntry
+	if err := json.NewDecoder(reader).Decode(&entry); err != nil {
+		return nil, errors.Wrap(err, "decoding item")
+	}
+
+	return &entry, nil
+}
+
 func BuildKey(repo string, root string) string {
-	return fmt.Sprintf("%s/%s/deployment.json", repo, root)
+	return fmt.Sprintf("%s/%s/%s", repo, root, deploymentInfoFilename)
+}
+
+func BuildQueueKey(repo string, root string) string {
+	return fmt.Sprintf("%s/%s/queue.json", repo, root)
+}
+
+func BuildHistoryKey(repo string, root string, entry *HistoryEntry) string {
+	return fmt.Sprintf("%s%020d-%s.json", buildHistoryPrefix(repo, root), entry.CompletedAt.UnixNano(), entry.ID)
+}
+
+func buildHistoryPrefix(repo string, root string) string {
+	return fmt.Sprintf("%s/%s/history/", repo, root)
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/storage"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +21,10 @@ type testStowClient struct {
 		readCloser io.ReadCloser
 		err        error
 	}
+	list struct {
+		keys []string
+		err  error
+	}
 }
 
 func (t *testStowClient) Get(ctx context.Context, key string) (io.ReadCloser, error) {
@@ -25,6 +32,10 @@ func (t *testStowClient) Get(ctx context.Context, key string) (io.ReadCloser, er
 	return t.get.readCloser, t.get.err
 }
 
+func (t *testStowClient) List(ctx context.Context, prefix string) ([]string, error) {
+	return t.list.keys, t.list.err
+}
+
 // Unused
 func (t *testStowClient) Set(ctx context.Context, key string, object []byte) error {
 	return nil
@@ -48,7 +59,7 @@ func TestStore_GetDeploymentInfo(t *testing.T) {
 				err: &storage.ContainerNotFoundError{Err: clientErr},
 			},
 		}
-		store, err := deployment.NewStore(stowClient)
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
 		assert.Nil(t, err)
 
 		deploymentInfo, err := store.GetDeploymentInfo(context.TODO(), repoName, rootName)
@@ -68,7 +79,7 @@ func TestStore_GetDeploymentInfo(t *testing.T) {
 				err: &storage.ItemNotFoundError{Err: clientErr},
 			},
 		}
-		store, err := deployment.NewStore(stowClient)
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
 		assert.Nil(t, err)
 
 		deploymentInfo, err := store.GetDeploymentInfo(context.TODO(), repoName, rootName)
@@ -76,3 +87,192 @@ func TestStore_GetDeploymentInfo(t *testing.T) {
 		assert.Nil(t, deploymentInfo)
 	})
 }
+
+func TestStore_GetQueue(t *testing.T) {
+	repoName := "repo"
+	rootName := "root"
+	key := deployment.BuildQueueKey(repoName, rootName)
+	clientErr := errors.New("error")
+
+	t.Run("empty queue when item not found", func(t *testing.T) {
+		stowClient := &testStowClient{
+			t: t,
+			get: struct {
+				key        string
+				readCloser io.ReadCloser
+				err        error
+			}{
+				key: key,
+				err: &storage.ItemNotFoundError{Err: clientErr},
+			},
+		}
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
+		assert.Nil(t, err)
+
+		queue, err := store.GetQueue(context.TODO(), repoName, rootName)
+		assert.Nil(t, err)
+		assert.Nil(t, queue)
+	})
+
+	t.Run("decodes queue", func(t *testing.T) {
+		stowClient := &testStowClient{
+			t: t,
+			get: struct {
+				key        string
+				readCloser io.ReadCloser
+				err        error
+			}{
+				key:        key,
+				readCloser: io.NopCloser(strings.NewReader(`{"Version":1,"Revisions":[{"ID":"1234","Revision":"abc"}]}`)),
+			},
+		}
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
+		assert.Nil(t, err)
+
+		queue, err := store.GetQueue(context.TODO(), repoName, rootName)
+		assert.Nil(t, err)
+		assert.Equal(t, &deployment.Queue{
+			Version:   1,
+			Revisions: []deployment.QueuedRevision{{ID: "1234", Revision: "abc"}},
+		}, queue)
+	})
+}
+
+func TestStore_ListDeploymentInfo(t *testing.T) {
+	info := func(root string) *deployment.Info {
+		return &deployment.Info{
+			Version:  1,
+			ID:       "id-" + root,
+			Revision: "abc",
+			Repo:     deployment.Repo{Owner: "owner", Name: "repo"},
+			Root:     deployment.Root{Name: root},
+		}
+	}
+
+	t.Run("backfills index and skips bad items", func(t *testing.T) {
+		stowClient := &memoryStowClient{items: map[string][]byte{
+			deployment.BuildKey("owner/repo", "root"):      []byte(`{"Version":1,"ID":"id-root","Revision":"abc","Repo":{"Owner":"owner","Name":"repo"},"Root":{"Name":"root"}}`),
+			deployment.BuildQueueKey("owner/repo", "root"): []byte(`{}`),
+			deployment.BuildKey("owner/repo", "bad"):       []byte(`not json`),
+		}}
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
+		assert.Nil(t, err)
+
+		infos, err := store.ListDeploymentInfo(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, []*deployment.Info{info("root")}, infos)
+
+		// roots deployed afterwards are found through the index without walking the container again
+		assert.Nil(t, store.SetDeploymentInfo(context.TODO(), info("root-2")))
+		stowClient.listed = nil
+
+		infos, err = store.ListDeploymentInfo(context.TODO())
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []*deployment.Info{info("root"), info("root-2")}, infos)
+		assert.Equal(t, []string{"_index/roots/"}, stowClient.listed)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		stowClient := &memoryStowClient{
+			items:   map[string][]byte{},
+			listErr: errors.New("error"),
+		}
+		store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
+		assert.Nil(t, err)
+
+		_, err = store.ListDeploymentInfo(context.TODO())
+		assert.Error(t, err)
+	})
+}
+
+// memoryStowClient stores items in memory, listing them in an arbitrary order
+type memoryStowClient struct {
+	items   map[string][]byte
+	listErr error
+
+	// listed records the prefix of every List call
+	listed []string
+}
+
+func (c *memoryStowClient) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	item, ok := c.items[key]
+	if !ok {
+		return nil, &storage.ItemNotFoundError{Err: errors.New("not found")}
+	}
+	return io.NopCloser(strings.NewReader(string(item))), nil
+}
+
+func (c *memoryStowClient) Set(ctx context.Context, key string, object []byte) error {
+	c.items[key] = object
+	return nil
+}
+
+func (c *memoryStowClient) List(ctx context.Context, prefix string) ([]string, error) {
+	c.listed = append(c.listed, prefix)
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+
+	var keys []string
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func TestStore_DeploymentHistory(t *testing.T) {
+	stowClient := &memoryStowClient{items: map[string][]byte{}}
+	store, err := deployment.NewStore(stowClient, logging.NewNoopCtxLogger(t))
+	assert.Nil(t, err)
+
+	start := time.Date(2022, time.October, 4, 12, 0, 0, 0, time.UTC)
+	entry := func(id string, root string, completed time.Duration) *deployment.HistoryEntry {
+		return &deployment.HistoryEntry{
+			Version:     deployment.HistorySchemaVersion,
+			ID:          id,
+			Revision:    "rev-" + id,
+			User:        "nish",
+			Repo:        deployment.Repo{Owner: "owner", Name: "repo"},
+			Root:        deployment.Root{Name: root, Trigger: "merge"},
+			Changes:     []deployment.ResourceChange{{Address: "addr", Action: deployment.UpdateAction}},
+			Outcome:     deployment.SuccessOutcome,
+			StartedAt:   start,
+			CompletedAt: start.Add(completed),
+		}
+	}
+
+	entries := []*deployment.HistoryEntry{
+		entry("1", "root", time.Minute),
+		entry("3", "root", 3*time.Minute),
+		entry("2", "root", 2*time.Minute),
+		entry("4", "root-2", 4*time.Minute),
+	}
+	for _, e := range entries {
+		assert.Nil(t, store.AppendDeploymentHistory(context.TODO(), e))
+	}
+
+	// appending the same deployment again doesn't duplicate it
+	assert.Nil(t, store.AppendDeploymentHistory(context.TODO(), entries[0]))
+
+	// the latest deployment is left untouched
+	info, err := store.GetDeploymentInfo(context.TODO(), "owner/repo", "root")
+	assert.Nil(t, err)
+	assert.Nil(t, info)
+
+	page, cursor, err := store.ListDeploymentHistory(context.TODO(), "owner/repo", "root", "", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*deployment.HistoryEntry{entries[1], entries[2]}, page)
+	assert.NotEmpty(t, cursor)
+
+	page, cursor, err = store.ListDeploymentHistory(context.TODO(), "owner/repo", "root", cursor, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*deployment.HistoryEntry{entries[0]}, page)
+	assert.Empty(t, cursor)
+
+	page, cursor, err = store.ListDeploymentHistory(context.TODO(), "owner/repo", "missing", "", 2)
+	assert.Nil(t, err)
+	assert.Empty(t, page)
+	assert.Empty(t, cursor)
+}
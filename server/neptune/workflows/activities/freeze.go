@@ -0,0 +1,77 @@
+package activities
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+)
+
+// freezeActivities evaluates the server's current freeze windows so that config changes apply to
+// revisions which are already queued.  Windows are evaluated here instead of within workflows since
+// recurring ones depend on the worker's timezone database.
+type freezeActivities struct {
+	FreezeWindows []valid.FreezeWindow
+}
+
+type FetchFreezeStateRequest struct {
+	Repo github.Repo
+	Root string
+	Now  time.Time
+}
+
+// FetchFreezeStateResponse describes the active window which freezes the root the longest, Until is zero if
+// the root isn't frozen.
+type FetchFreezeStateResponse struct {
+	Name   string
+	Reason string
+	Until  time.Time
+}
+
+func (a *freezeActivities) FetchFreezeState(ctx context.Context, request FetchFreezeStateRequest) (FetchFreezeStateResponse, error) {
+	// repo globs are matched against the same host qualified id as the gateway (ie. github.com/owner/repo)
+	host, err := cloneURLHostname(request.Repo.URL)
+	if err != nil {
+		return FetchFreezeStateResponse{}, errors.Wrap(err, "parsing clone url")
+	}
+	repoID := host + "/" + request.Repo.GetFullName()
+
+	var windows []valid.FreezeWindow
+	for _, w := range a.FreezeWindows {
+		if w.Matches(repoID, request.Root) {
+			windows = append(windows, w)
+		}
+	}
+
+	window, until, frozen := valid.ActiveFreezeWindow(windows, request.Now)
+	if !frozen {
+		return FetchFreezeStateResponse{}, nil
+	}
+
+	return FetchFreezeStateResponse{
+		Name:   window.Name,
+		Reason: window.Reason,
+		Until:  until,
+	}, nil
+}
+
+// cloneURLHostname supports both http(s) clone urls and scp-like ssh ones (ie. git@github.com:owner/repo.git)
+func cloneURLHostname(cloneURL string) (string, error) {
+	if !strings.Contains(cloneURL, "://") {
+		if _, hostPath, ok := strings.Cut(cloneURL, "@"); ok {
+			if host, _, ok := strings.Cut(hostPath, ":"); ok {
+				return host, nil
+			}
+		}
+	}
+
+	u, err := url.Parse(cloneURL)
+	if err != nil {
+		return "", err
+	}
+	return u.Hostname(), nil
+}
@@ -0,0 +1,79 @@
+package activities
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchFreezeState(t *testing.T) {
+	now := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	a := &freezeActivities{
+		FreezeWindows: []valid.FreezeWindow{
+			{
+				Name:   "holidays",
+				Reason: "change freeze",
+				Repos:  []string{"github.com/owner/*"},
+				Roots:  []string{"prod-*"},
+				Start:  now.Add(-time.Hour),
+				End:    now.Add(time.Hour),
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		url         string
+		root        string
+		expected    FetchFreezeStateResponse
+	}{
+		{
+			description: "https clone url",
+			url:         "https://github.com/owner/repo.git",
+			root:        "prod-network",
+			expected:    FetchFreezeStateResponse{Name: "holidays", Reason: "change freeze", Until: now.Add(time.Hour)},
+		},
+		{
+			description: "ssh clone url",
+			url:         "git@github.com:owner/repo.git",
+			root:        "prod-network",
+			expected:    FetchFreezeStateResponse{Name: "holidays", Reason: "change freeze", Until: now.Add(time.Hour)},
+		},
+		{
+			description: "root doesn't match",
+			url:         "https://github.com/owner/repo.git",
+			root:        "staging-network",
+		},
+		{
+			description: "host doesn't match",
+			url:         "https://github.example.com/owner/repo.git",
+			root:        "prod-network",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			resp, err := a.FetchFreezeState(context.Background(), FetchFreezeStateRequest{
+				Repo: github.Repo{Owner: "owner", Name: "repo", URL: c.url},
+				Root: c.root,
+				Now:  now,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, resp)
+		})
+	}
+
+	t.Run("window ended", func(t *testing.T) {
+		resp, err := a.FetchFreezeState(context.Background(), FetchFreezeStateRequest{
+			Repo: github.Repo{Owner: "owner", Name: "repo", URL: "https://github.com/owner/repo.git"},
+			Root: "prod-network",
+			Now:  now.Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, FetchFreezeStateResponse{}, resp)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	key "github.com/runatlantis/atlantis/server/neptune/context"
 	"go.temporal.io/sdk/activity"
@@ -44,6 +45,9 @@ type githubClient interface { //nolint:interfacebloat
 	ListCommits(ctx internal.Context, owner string, repo string, number int) ([]*github.RepositoryCommit, error)
 	DismissReview(ctx internal.Context, owner, repo string, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error)
 	ListTeamMembers(ctx internal.Context, org string, teamSlug string) ([]*github.User, error)
+	CreateComment(ctx internal.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	ListComments(ctx internal.Context, owner string, repo string, number int) ([]*github.IssueComment, error)
+	EditComment(ctx internal.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
 }
 
 type DiffDirection string
@@ -239,6 +243,9 @@ func getCheckStateAndConclusion(internalState internal.CheckRunState) (string, s
 	case internal.CheckRunSkipped:
 		state = "completed"
 		conclusion = "skipped"
+	case internal.CheckRunNeutral:
+		state = "completed"
+		conclusion = "neutral"
 	default:
 		state = string(internalState)
 	}
@@ -511,6 +518,66 @@ func (a *githubActivities) GithubDismiss(ctx context.Context, request DismissReq
 	return DismissResponse{}, nil
 }
 
+type UpsertCommentRequest struct {
+	Repo     internal.Repo
+	PRNumber int
+
+	// Marker identifies the comment to update, it's embedded in the body as a hidden html comment
+	Marker string
+	Body   string
+}
+
+type UpsertCommentResponse struct{}
+
+// GithubUpsertComment updates the PR comment identified by the request's marker, the comment is
+// created if it doesn't exist yet.
+func (a *githubActivities) GithubUpsertComment(ctx context.Context, request UpsertCommentRequest) (UpsertCommentResponse, error) {
+	shouldComment, err := a.shouldComment(request.Repo)
+	if err != nil {
+		return UpsertCommentResponse{}, err
+	}
+	if !shouldComment {
+		return UpsertCommentResponse{}, nil
+	}
+
+	ghCtx := internal.ContextWithInstallationToken(ctx, request.Repo.Credentials.InstallationToken)
+	marker := fmt.Sprintf("<!-- %s -->", request.Marker)
+	comment := &github.IssueComment{Body: github.String(fmt.Sprintf("%s\n%s", request.Body, marker))}
+
+	comments, err := a.Client.ListComments(ghCtx, request.Repo.Owner, request.Repo.Name, request.PRNumber)
+	if err != nil {
+		return UpsertCommentResponse{}, errors.Wrap(err, "listing pr comments")
+	}
+
+	for _, c := range comments {
+		if !strings.Contains(c.GetBody(), marker) {
+			continue
+		}
+
+		if _, _, err := a.Client.EditComment(ghCtx, request.Repo.Owner, request.Repo.Name, c.GetID(), comment); err != nil {
+			return UpsertCommentResponse{}, errors.Wrap(err, "editing pr comment")
+		}
+		return UpsertCommentResponse{}, nil
+	}
+
+	if _, _, err := a.Client.CreateComment(ghCtx, request.Repo.Owner, request.Repo.Name, request.PRNumber, comment); err != nil {
+		return UpsertCommentResponse{}, errors.Wrap(err, "creating pr comment")
+	}
+	return UpsertCommentResponse{}, nil
+}
+
+// shouldComment returns whether PR comments are enabled for the repo
+func (a *githubActivities) shouldComment(repo internal.Repo) (bool, error) {
+	shouldAllocate, err := a.Allocator.ShouldAllocate(feature.LegacyDeprecation, feature.FeatureContext{
+		RepoName: repo.GetFullName(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "unable to allocate legacy deprecation feature flag")
+	}
+	// skip PR comments if we're in PR mode and legacy deprecation is not enabled
+	return shouldAllocate, nil
+}
+
 type ListTeamMembersRequest struct {
 	Repo     internal.Repo
 	Org      string
@@ -9,6 +9,12 @@ import (
 const (
 	UnlockLabel       = "Unlock"
 	UnlockDescription = "Unlock this plan to proceed"
+
+	RollbackLabel       = "Rollback"
+	RollbackDescription = "Redeploy this revision to this root"
+
+	PromoteLabel       = "Promote"
+	PromoteDescription = "Promote this revision to this root"
 )
 
 type CheckRunState string
@@ -36,6 +42,20 @@ func CreateUnlockAction() CheckRunAction {
 	}
 }
 
+func CreateRollbackAction() CheckRunAction {
+	return CheckRunAction{
+		Description: RollbackDescription,
+		Label:       RollbackLabel,
+	}
+}
+
+func CreatePromoteAction() CheckRunAction {
+	return CheckRunAction{
+		Description: PromoteDescription,
+		Label:       PromoteLabel,
+	}
+}
+
 func CreatePlanReviewAction(t PlanReviewActionType) CheckRunAction {
 	return CheckRunAction{
 		Description: fmt.Sprintf("%s this plan to proceed", string(t)),
@@ -52,6 +72,7 @@ const (
 	CheckRunPending        CheckRunState = "in_progress"
 	CheckRunQueued         CheckRunState = "queued"
 	CheckRunSkipped        CheckRunState = "skipped"
+	CheckRunNeutral        CheckRunState = "neutral"
 	CheckRunActionRequired CheckRunState = "action_required"
 	CheckRunUnknown        CheckRunState = ""
 
@@ -161,6 +161,40 @@ func (c *Client) DismissReview(ctx Context, owner string, repo string, number in
 	return client.PullRequests.DismissReview(ctx, owner, repo, number, reviewID, review)
 }
 
+func (c *Client) CreateComment(ctx Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	client, err := c.ClientCreator.NewInstallationClient(ctx.GetInstallationToken())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating client from installation")
+	}
+	return client.Issues.CreateComment(ctx, owner, repo, number, comment)
+}
+
+func (c *Client) ListComments(ctx Context, owner string, repo string, number int) ([]*github.IssueComment, error) {
+	client, err := c.ClientCreator.NewInstallationClient(ctx.GetInstallationToken())
+	if err != nil {
+		return nil, errors.Wrap(err, "creating client from installation")
+	}
+
+	run := func(ctx context.Context, nextPage int) ([]*github.IssueComment, *github.Response, error) {
+		listOptions := github.IssueListCommentsOptions{
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		listOptions.Page = nextPage
+		return client.Issues.ListComments(ctx, owner, repo, number, &listOptions)
+	}
+	return gh_helper.Iterate(ctx, run)
+}
+
+func (c *Client) EditComment(ctx Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	client, err := c.ClientCreator.NewInstallationClient(ctx.GetInstallationToken())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating client from installation")
+	}
+	return client.Issues.EditComment(ctx, owner, repo, commentID, comment)
+}
+
 func (c *Client) ListTeamMembers(ctx Context, org string, teamSlug string) ([]*github.User, error) {
 	client, err := c.ClientCreator.NewInstallationClient(ctx.GetInstallationToken())
 	if err != nil {
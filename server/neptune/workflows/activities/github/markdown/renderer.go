@@ -5,6 +5,7 @@ import (
 	_ "embed" //embedding files
 	"fmt"
 	"html/template"
+	"strings"
 
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
@@ -15,12 +16,75 @@ import (
 //go:embed templates/planconfirm.tmpl
 var planConfirmStr string
 
+//go:embed templates/rollback.tmpl
+var rollbackStr string
+
 //go:embed templates/checkrun.tmpl
 var checkrunTemplateStr string
 
+//go:embed templates/drift.tmpl
+var driftTemplateStr string
+
+//go:embed templates/plansummary.tmpl
+var planSummaryTemplateStr string
+
+//go:embed templates/policywarnings.tmpl
+var policyWarningsTemplateStr string
+
+//go:embed templates/policywarningscomment.tmpl
+var policyWarningsCommentTemplateStr string
+
+// codeEscaper escapes characters which would otherwise be interpreted as markdown inside <code>,
+// terraform values are arbitrary so a | would end a table cell and a * would start emphasis
+var codeEscaper = strings.NewReplacer(
+	"|", "&#124;",
+	"`", "&#96;",
+	"*", "&#42;",
+	"~", "&#126;",
+	"\\", "&#92;",
+)
+
+var templateFuncs = template.FuncMap{
+	"code": func(s string) template.HTML {
+		//nolint:gosec // the value is html escaped before markdown characters are replaced
+		return template.HTML("<code>" + codeEscaper.Replace(template.HTMLEscapeString(s)) + "</code>")
+	},
+}
+
 // panics if we can't read the template
-var checkrunTemplate = template.Must(template.New("").Parse(checkrunTemplateStr))
+var checkrunTemplate = template.Must(template.Must(template.Must(template.New("").Funcs(templateFuncs).Parse(checkrunTemplateStr)).Parse(planSummaryTemplateStr)).Parse(policyWarningsTemplateStr))
 var planConfirmTemplate = template.Must(template.New("").Parse(planConfirmStr))
+var rollbackTemplate = template.Must(template.New("").Parse(rollbackStr))
+var driftTemplate = template.Must(template.Must(template.New("").Funcs(templateFuncs).Parse(driftTemplateStr)).Parse(planSummaryTemplateStr))
+var policyWarningsCommentTemplate = template.Must(template.Must(template.New("").Parse(policyWarningsCommentTemplateStr)).Parse(policyWarningsTemplateStr))
+
+// github rejects check run summaries larger than 65535 characters, we leave some room for
+// the remainder of the template
+const maxPlanSummaryLength = 50000
+
+type driftTemplateData struct {
+	RevisionURL string
+	PlanLogURL  string
+	PlanSummary *planSummaryTemplateData
+}
+
+type policyWarningsCommentTemplateData struct {
+	RevisionURL    string
+	PolicyWarnings []state.PolicyWarning
+}
+
+type planSummaryTemplateData struct {
+	// creations and deletions exclude replacements since they're rendered separately
+	Creations    []terraform.ResourceSummary
+	Deletions    []terraform.ResourceSummary
+	Updates      []terraform.ResourceSummary
+	Replacements []terraform.ResourceSummary
+	Moves        []terraform.ResourceSummary
+	Imports      []terraform.ResourceSummary
+	Outputs      []terraform.OutputChange
+	Modules      []terraform.ModuleSummary
+	Truncated    bool
+}
 
 type planconfirmTemplateData struct {
 	Revision              string
@@ -32,6 +96,12 @@ type planconfirmTemplateData struct {
 	LatestOnDefaultBranch bool
 }
 
+type rollbackTemplateData struct {
+	RevisionURL     string
+	FromRevisionURL string
+	User            string
+}
+
 type checkrunTemplateData struct {
 	ApplyActionsSummary     string
 	PlanStatus              string
@@ -47,6 +117,9 @@ type checkrunTemplateData struct {
 	HeartbeatTimeout        bool
 	PRMode                  bool
 	Skipped                 bool
+	PlanSummary             *planSummaryTemplateData
+	PlanReview              *state.PlanReview
+	PolicyWarnings          []state.PolicyWarning
 }
 
 func RenderWorkflowStateTmpl(workflowState *state.Workflow) string {
@@ -87,6 +160,9 @@ func RenderWorkflowStateTmpl(workflowState *state.Workflow) string {
 		HeartbeatTimeout:        hearbeatTimeout,
 		ApplyActionsSummary:     applyActionsSummary,
 		Skipped:                 skipped,
+		PlanSummary:             newPlanSummaryTemplateData(getPlanSummary(workflowState.Plan)),
+		PlanReview:              workflowState.PlanReview,
+		PolicyWarnings:          getPolicyWarnings(workflowState.Validate),
 	})
 }
 
@@ -102,6 +178,104 @@ func RenderPlanConfirm(user string, commit github.Commit, deployedBranch string,
 	return renderTemplate(planConfirmTemplate, data)
 }
 
+// RenderRollbackConfirm renders the reason a rollback's plan must be confirmed, fromRevision is
+// the deployed revision which is being rolled back.
+func RenderRollbackConfirm(user string, commit github.Commit, fromRevision string, repo github.Repo) string {
+	return renderTemplate(rollbackTemplate, rollbackTemplateData{
+		RevisionURL:     github.BuildRevisionURLMarkdown(repo.GetFullName(), commit.Revision),
+		FromRevisionURL: github.BuildRevisionURLMarkdown(repo.GetFullName(), fromRevision),
+		User:            user,
+	})
+}
+
+// RenderPolicyWarningsComment renders the pull request comment listing advisory policy warnings for a revision
+func RenderPolicyWarningsComment(repo github.Repo, revision string, warnings []state.PolicyWarning) string {
+	return renderTemplate(policyWarningsCommentTemplate, policyWarningsCommentTemplateData{
+		RevisionURL:    github.BuildRevisionURLMarkdown(repo.GetFullName(), revision),
+		PolicyWarnings: warnings,
+	})
+}
+
+func RenderDriftTmpl(repo github.Repo, revision string, planJob *state.Job, summary terraform.PlanSummary) string {
+	_, planLogURL := getJobStatusAndOutput(planJob)
+
+	return renderTemplate(driftTemplate, driftTemplateData{
+		RevisionURL: github.BuildRevisionURLMarkdown(repo.GetFullName(), revision),
+		PlanLogURL:  planLogURL,
+		PlanSummary: newPlanSummaryTemplateData(summary),
+	})
+}
+
+func getPlanSummary(jobState *state.Job) terraform.PlanSummary {
+	if jobState == nil || jobState.Output == nil {
+		return terraform.PlanSummary{}
+	}
+	return jobState.Output.Summary
+}
+
+func getPolicyWarnings(jobState *state.Job) []state.PolicyWarning {
+	if jobState == nil || jobState.Output == nil {
+		return nil
+	}
+	return jobState.Output.PolicyWarnings
+}
+
+// newPlanSummaryTemplateData returns nil if there is nothing to render
+func newPlanSummaryTemplateData(summary terraform.PlanSummary) *planSummaryTemplateData {
+	if summary.IsEmpty() && len(summary.Moves) == 0 && len(summary.Imports) == 0 && len(summary.Outputs) == 0 {
+		return nil
+	}
+
+	replaced := map[string]bool{}
+	for _, r := range summary.Replacements {
+		replaced[r.Address] = true
+	}
+
+	data := &planSummaryTemplateData{
+		Creations:    excludeAddresses(summary.Creations, replaced),
+		Deletions:    excludeAddresses(summary.Deletions, replaced),
+		Updates:      summary.Updates,
+		Replacements: summary.Replacements,
+		Moves:        summary.Moves,
+		Imports:      summary.Imports,
+		Outputs:      summary.Outputs,
+		Modules:      summary.Modules,
+		Truncated:    summary.AttributeChangesOmitted,
+	}
+
+	// fallback to addresses only for large plans
+	if len(renderTemplate(planSummaryTemplate(), data)) > maxPlanSummaryLength {
+		data.Updates = withoutAttributeChanges(data.Updates)
+		data.Replacements = withoutAttributeChanges(data.Replacements)
+		data.Truncated = true
+	}
+
+	return data
+}
+
+func planSummaryTemplate() *template.Template {
+	return checkrunTemplate.Lookup("plansummary")
+}
+
+func excludeAddresses(resources []terraform.ResourceSummary, addresses map[string]bool) []terraform.ResourceSummary {
+	var result []terraform.ResourceSummary
+	for _, r := range resources {
+		if !addresses[r.Address] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func withoutAttributeChanges(resources []terraform.ResourceSummary) []terraform.ResourceSummary {
+	var result []terraform.ResourceSummary
+	for _, r := range resources {
+		r.AttributeChanges = nil
+		result = append(result, r)
+	}
+	return result
+}
+
 func getJobStatusAndOutput(jobState *state.Job) (string, string) {
 	var status string
 	var output string
@@ -0,0 +1,173 @@
+package markdown_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github/markdown"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildWorkflowState(summary terraform.PlanSummary) *state.Workflow {
+	return &state.Workflow{
+		Plan: &state.Job{
+			Status: state.SuccessJobStatus,
+			Output: &state.JobOutput{
+				URL:     &url.URL{Scheme: "https", Host: "atlantis.com", Path: "/jobs/1234"},
+				Summary: summary,
+			},
+		},
+	}
+}
+
+func TestRenderWorkflowStateTmpl_PlanSummary(t *testing.T) {
+	summary := terraform.PlanSummary{
+		Creations: []terraform.ResourceSummary{
+			{Address: `aws_instance.web["a"]`},
+			{Address: "aws_db_instance.db"},
+		},
+		Deletions: []terraform.ResourceSummary{
+			{Address: "aws_db_instance.db"},
+		},
+		Updates: []terraform.ResourceSummary{
+			{
+				Address: "aws_s3_bucket.bucket",
+				AttributeChanges: []terraform.AttributeChange{
+					{Path: "tags.env", Before: `"staging"`, After: `"prod"`},
+					{Path: "password", Before: terraform.SensitiveValue, After: terraform.SensitiveValue, Sensitive: true},
+				},
+			},
+		},
+		Replacements: []terraform.ResourceSummary{
+			{Address: "aws_db_instance.db", ReplaceReasons: []string{"engine"}},
+		},
+		Moves: []terraform.ResourceSummary{
+			{Address: "aws_iam_role.new", PreviousAddress: "aws_iam_role.old"},
+		},
+		Imports: []terraform.ResourceSummary{
+			{Address: "aws_iam_role.imported", ImportID: "role"},
+		},
+		Outputs: []terraform.OutputChange{
+			{Name: "url", Action: "update", Before: `"a"`, After: `"b"`},
+		},
+		Modules: []terraform.ModuleSummary{
+			{Address: terraform.RootModuleAddress, Creations: 1, Updates: 1, Replacements: 1},
+		},
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(buildWorkflowState(summary))
+
+	assert.Contains(t, result, "## Plan Summary")
+	assert.Contains(t, result, "| <code>root</code> | 1 | 1 | 1 | 0 |")
+	assert.Contains(t, result, "* <code>aws_instance.web[&#34;a&#34;]</code>")
+	assert.Contains(t, result, "  * <code>tags.env</code>: <code>&#34;staging&#34;</code> → <code>&#34;prod&#34;</code>")
+	assert.Contains(t, result, "  * <code>password</code>: <code>(sensitive value)</code> → <code>(sensitive value)</code>")
+	assert.Contains(t, result, "* <code>aws_db_instance.db</code> forced by <code>engine</code>")
+	assert.Contains(t, result, "* <code>aws_iam_role.old</code> → <code>aws_iam_role.new</code>")
+	assert.Contains(t, result, "* <code>aws_iam_role.imported</code> from <code>role</code>")
+	assert.Contains(t, result, "* <code>url</code> (update): <code>&#34;a&#34;</code> → <code>&#34;b&#34;</code>")
+
+	// replacements are only rendered once
+	assert.Equal(t, 1, strings.Count(result, "<code>aws_db_instance.db</code>"))
+	assert.NotContains(t, result, "### Deletions")
+}
+
+func TestRenderWorkflowStateTmpl_EscapesAttributeValues(t *testing.T) {
+	summary := terraform.PlanSummary{
+		Updates: []terraform.ResourceSummary{
+			{
+				Address: "aws_s3_bucket.bucket",
+				AttributeChanges: []terraform.AttributeChange{
+					{Path: "policy", Before: `"a|b"`, After: `"</code>*bold*"`},
+				},
+			},
+		},
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(buildWorkflowState(summary))
+	assert.Contains(t, result, "  * <code>policy</code>: <code>&#34;a&#124;b&#34;</code> → <code>&#34;&lt;/code&gt;&#42;bold&#42;&#34;</code>")
+}
+
+func TestRenderWorkflowStateTmpl_OmittedAttributeChanges(t *testing.T) {
+	summary := terraform.PlanSummary{
+		Updates:                 []terraform.ResourceSummary{{Address: "aws_s3_bucket.bucket"}},
+		AttributeChangesOmitted: true,
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(buildWorkflowState(summary))
+	assert.Contains(t, result, "Attribute changes have been omitted")
+}
+
+func TestRenderWorkflowStateTmpl_EmptyPlanSummary(t *testing.T) {
+	result := markdown.RenderWorkflowStateTmpl(buildWorkflowState(terraform.PlanSummary{}))
+	assert.NotContains(t, result, "Plan Summary")
+}
+
+func TestRenderWorkflowStateTmpl_TruncatesLargePlanSummary(t *testing.T) {
+	var updates []terraform.ResourceSummary
+	for i := 0; i < 1000; i++ {
+		updates = append(updates, terraform.ResourceSummary{
+			Address: "aws_s3_bucket.bucket",
+			AttributeChanges: []terraform.AttributeChange{
+				{Path: "tags.env", Before: strings.Repeat("a", 100), After: strings.Repeat("b", 100)},
+			},
+		})
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(buildWorkflowState(terraform.PlanSummary{Updates: updates}))
+	assert.NotContains(t, result, "tags.env")
+	assert.Contains(t, result, "Attribute changes have been omitted")
+	assert.Less(t, len(result), 65535)
+}
+
+func TestRenderWorkflowStateTmpl_PlanReview(t *testing.T) {
+	workflowState := buildWorkflowState(terraform.PlanSummary{})
+	workflowState.PlanReview = &state.PlanReview{
+		Status: state.ApprovedPlanReviewStatus,
+		User:   "nish",
+		Reason: "expected deletion",
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(workflowState)
+	assert.Contains(t, result, "**Plan approved** by @nish: expected deletion")
+}
+
+func TestRenderWorkflowStateTmpl_PolicyWarnings(t *testing.T) {
+	workflowState := buildWorkflowState(terraform.PlanSummary{})
+	workflowState.Validate = &state.Job{
+		Status: state.SuccessJobStatus,
+		Output: &state.JobOutput{
+			URL: &url.URL{Scheme: "https", Host: "atlantis.com", Path: "/jobs/5678"},
+			PolicyWarnings: []state.PolicyWarning{
+				{
+					PolicySet: "policy1",
+					Warnings:  []string{"instance type is deprecated", "missing tags"},
+				},
+			},
+		},
+	}
+
+	result := markdown.RenderWorkflowStateTmpl(workflowState)
+	assert.Contains(t, result, "## Policy Warnings :warning:")
+	assert.Contains(t, result, "**policy1**\n* instance type is deprecated\n* missing tags\n")
+}
+
+func TestRenderPolicyWarningsComment(t *testing.T) {
+	result := markdown.RenderPolicyWarningsComment(github.Repo{Owner: "owner", Name: "repo"}, "1234", []state.PolicyWarning{
+		{
+			PolicySet: "policy1",
+			Warnings:  []string{"instance type is deprecated"},
+		},
+	})
+	assert.Contains(t, result, "**policy1**\n* instance type is deprecated\n")
+	assert.Contains(t, result, "Evaluated against revision [1234](https://github.com/owner/repo/commit/1234).")
+}
+
+func TestRenderRollbackConfirm(t *testing.T) {
+	result := markdown.RenderRollbackConfirm("nish", github.Commit{Revision: "abc"}, "def", github.Repo{Owner: "owner", Name: "repo"})
+	assert.Contains(t, result, "Rollback from deployed revision [def](https://github.com/owner/repo/commit/def) to [abc](https://github.com/owner/repo/commit/abc) requested by @nish.")
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type closer interface {
-	CloseJob(ctx context.Context, jobID string) error
+	CloseJob(ctx context.Context, jobID string, repoFullName string) error
 }
 
 type jobActivities struct {
@@ -17,10 +17,12 @@ type jobActivities struct {
 
 type CloseJobRequest struct {
 	JobID string
+	// RepoName is the full name of the repo the job ran for, it's used to apply job log retention per repo
+	RepoName string
 }
 
 func (t *jobActivities) CloseJob(ctx context.Context, request CloseJobRequest) error {
-	err := t.StreamCloser.CloseJob(ctx, request.JobID)
+	err := t.StreamCloser.CloseJob(ctx, request.JobID, request.RepoName)
 	if err != nil {
 		activity.GetLogger(ctx).Error(errors.Wrapf(err, "closing job").Error())
 	}
@@ -5,7 +5,10 @@ import (
 	"github.com/hashicorp/go-getter"
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
 	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"path/filepath"
@@ -18,36 +21,86 @@ var HashiGetAny = func(dst, src string) error {
 	return getter.GetAny(dst, src)
 }
 
+var HashiGetFile = func(dst, src string) error {
+	return getter.GetFile(dst, src)
+}
+
 type TFVersionLoader struct {
 	downloadURL string
+	verifier    *checksum.Verifier
+	engine      valid.Engine
+}
+
+func NewTFVersionLoader(downloadURL string, verifier *checksum.Verifier) *TFVersionLoader {
+	return &TFVersionLoader{
+		downloadURL: downloadURL,
+		verifier:    verifier,
+	}
 }
 
-func NewTFVersionLoader(downloadURL string) *TFVersionLoader {
+// NewTofuVersionLoader returns a loader for opentofu releases
+func NewTofuVersionLoader(downloadURL string, verifier *checksum.Verifier) *TFVersionLoader {
 	return &TFVersionLoader{
 		downloadURL: downloadURL,
+		verifier:    verifier,
+		engine:      valid.OpenTofuEngine,
 	}
 }
 
 // TODO: migrate away from runtime_models.FilePath
 func (t *TFVersionLoader) LoadVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
-	urlPrefix := fmt.Sprintf("%s/terraform/%s/terraform_%s", t.downloadURL, v.String(), v.String())
-	binURL := fmt.Sprintf("%s_%s_%s.zip", urlPrefix, runtime.GOOS, runtime.GOARCH)
-	checksumURL := fmt.Sprintf("%s_SHA256SUMS", urlPrefix)
-	fullSrcURL := fmt.Sprintf("%s?checksum=file:%s", binURL, checksumURL)
+	return t.LoadRelease(t.Release(v), v, destPath)
+}
+
+func (t *TFVersionLoader) Release(v *version.Version) checksum.Release {
+	return terraform.EngineRelease(t.engine, t.downloadURL, v)
+}
+
+// LoadRelease downloads and extracts the release's archive into destPath, the release's files can also be local paths
+func (t *TFVersionLoader) LoadRelease(release checksum.Release, v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	checksumQuery, err := t.verifier.Query(release)
+	if err != nil {
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "verifying %s version %s", t.engine.OrDefault(), v.String())
+	}
+	fullSrcURL := fmt.Sprintf("%s?checksum=%s", release.ArchiveURL, checksumQuery)
 	if err := HashiGetAny(destPath, fullSrcURL); err != nil {
-		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading terraform version %s at %q", v.String(), fullSrcURL)
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading %s version %s at %q", t.engine.OrDefault(), v.String(), fullSrcURL)
 	}
-	binPath := filepath.Join(destPath, "terraform")
+	binPath := filepath.Join(destPath, t.engine.BinaryName())
 	return runtime_models.LocalFilePath(binPath), nil
 }
 
-type ConftestVersionLoader struct{}
+type ConftestVersionLoader struct {
+	verifier *checksum.Verifier
+}
+
+func NewConftestVersionLoader(verifier *checksum.Verifier) *ConftestVersionLoader {
+	return &ConftestVersionLoader{
+		verifier: verifier,
+	}
+}
 
 func (c *ConftestVersionLoader) LoadVersion(v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	return c.LoadRelease(c.Release(v), v, destPath)
+}
+
+func (c *ConftestVersionLoader) Release(v *version.Version) checksum.Release {
 	urlPrefix := fmt.Sprintf("%s%s", conftestDownloadURL, v.Original())
-	binURL := fmt.Sprintf("%s/conftest_%s_%s_x86_64.tar.gz", urlPrefix, v.Original(), cases.Title(language.English).String(runtime.GOOS))
-	checksumURL := fmt.Sprintf("%s/checksums.txt", urlPrefix)
-	fullSrcURL := fmt.Sprintf("%s?checksum=file:%s", binURL, checksumURL)
+
+	// conftest releases aren't signed so these can only be verified with pinned checksums
+	return checksum.Release{
+		ArchiveURL:   fmt.Sprintf("%s/conftest_%s_%s_x86_64.tar.gz", urlPrefix, v.Original(), cases.Title(language.English).String(runtime.GOOS)),
+		ChecksumsURL: fmt.Sprintf("%s/checksums.txt", urlPrefix),
+	}
+}
+
+// LoadRelease downloads and extracts the release's archive into destPath, the release's files can also be local paths
+func (c *ConftestVersionLoader) LoadRelease(release checksum.Release, v *version.Version, destPath string) (runtime_models.FilePath, error) {
+	checksumQuery, err := c.verifier.Query(release)
+	if err != nil {
+		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "verifying conftest version %s", v.String())
+	}
+	fullSrcURL := fmt.Sprintf("%s?checksum=%s", release.ArchiveURL, checksumQuery)
 	if err := HashiGetAny(destPath, fullSrcURL); err != nil {
 		return runtime_models.LocalFilePath(""), errors.Wrapf(err, "downloading conftest version %s at %q", v.String(), fullSrcURL)
 	}
@@ -1,17 +1,24 @@
 package activities
 
 import (
+	"bytes"
+	"context"
 	"github.com/runatlantis/atlantis/server/lyft/feature"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/palantir/go-githubapp/githubapp"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime/cache"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
+	"github.com/runatlantis/atlantis/server/core/runtime/plugincache"
+	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/storage"
 	"github.com/runatlantis/atlantis/server/neptune/temporalworker/config"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/command"
@@ -33,6 +40,9 @@ const (
 	// TerraformPluginCacheDir is the name of the dir inside our data dir
 	// where we tell terraform to cache plugins and modules.
 	TerraformPluginCacheDirName = "plugin-cache"
+	// PluginCacheRootsDirName is the name of the dir inside our data dir
+	// where the lock files used to warm the plugin cache are recorded.
+	PluginCacheRootsDirName = "plugin-cache-roots"
 )
 
 // Exported Activites should be here.
@@ -45,15 +55,16 @@ const (
 type Deploy struct {
 	*dbActivities
 	*slackActivities
+	*freezeActivities
 }
 
-func NewDeploy(deploymentStoreCfg valid.StoreConfig) (*Deploy, error) {
+func NewDeploy(deploymentStoreCfg valid.StoreConfig, freezeWindows []valid.FreezeWindow, logger logging.Logger) (*Deploy, error) {
 	storageClient, err := storage.NewClient(deploymentStoreCfg)
 	if err != nil {
 		return nil, errors.Wrap(err, "intializing stow client")
 	}
 
-	deploymentStore, err := deployment.NewStore(storageClient)
+	deploymentStore, err := deployment.NewStore(storageClient, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing deployment info store")
 	}
@@ -65,6 +76,9 @@ func NewDeploy(deploymentStoreCfg valid.StoreConfig) (*Deploy, error) {
 		// TODO: Add token once bot is created
 		slackActivities: &slackActivities{Client: slack.New("",
 			slack.OptionHTTPClient(http.DefaultClient))},
+		freezeActivities: &freezeActivities{
+			FreezeWindows: freezeWindows,
+		},
 	}, nil
 }
 
@@ -75,6 +89,10 @@ type Terraform struct {
 	*workerInfoActivity
 	*cleanupActivities
 	*jobActivities
+	*planArtifactActivities
+
+	// PluginCacheWarmer is nil if the plugin cache isn't warmed on startup
+	PluginCacheWarmer func() error
 }
 
 type StreamCloser interface {
@@ -86,12 +104,22 @@ type TerraformOptions struct {
 	TFVersionCache          cache.ExecutionVersionCache
 	ConftestVersionCache    cache.ExecutionVersionCache
 	GitCredentialsRefresher gitCredentialsRefresher
+	Logger                  logging.Logger
 }
 
 type PolicySet struct {
-	Name  string
-	Owner string
-	Paths []string
+	Name       string
+	Owner      string
+	Paths      []string
+	Exemptions []PolicyExemption
+}
+
+// PolicyExemption ignores failures of a policy set for a repo, optionally scoped to specific roots, until it expires
+type PolicyExemption struct {
+	Repo          string
+	Roots         []string
+	Expiry        time.Time
+	Justification string
 }
 
 func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.ValidationConfig, ghAppConfig githubapp.Config, dataDir string, serverURL *url.URL, taskQueue string, streamHandler StreamCloser, opts ...TerraformOptions) (*Terraform, error) {
@@ -104,11 +132,17 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 	if err != nil {
 		return nil, err
 	}
+
+	rootsDir, err := mkSubDir(dataDir, PluginCacheRootsDirName)
+	if err != nil {
+		return nil, err
+	}
 	gitCredentialsFileLock := &file.RWLock{}
 
 	var tfVersionCache cache.ExecutionVersionCache
 	var conftestVersionCache cache.ExecutionVersionCache
 	var credentialsRefresher gitCredentialsRefresher
+	var logger logging.Logger
 	for _, o := range opts {
 		if o.TFVersionCache != nil {
 			tfVersionCache = o.TFVersionCache
@@ -121,23 +155,42 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 		if credentialsRefresher != nil {
 			credentialsRefresher = o.GitCredentialsRefresher
 		}
+
+		if o.Logger != nil {
+			logger = o.Logger
+		}
 	}
 
-	tfLoader := NewTFVersionLoader(tfConfig.DownloadURL)
+	tfVerifier, err := checksum.NewVerifierWithDefaultKey(tfConfig.ChecksumsFile, tfConfig.SigningKeyFile, checksum.HashiCorpKey, HashiGetFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing terraform verifier")
+	}
+
+	binaryMirror, err := mirror.New(tfConfig.BinaryMirror.Dir, tfConfig.BinaryMirror.Store)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing binary mirror")
+	}
+
+	tfLoader := NewTFVersionLoader(tfConfig.DownloadURL, tfVerifier)
 	if tfVersionCache == nil {
 		tfVersionCache = cache.NewExecutionVersionLayeredLoadingCache(
 			"terraform",
 			binDir,
-			tfLoader.LoadVersion,
+			mirror.NewLoader(binaryMirror, "terraform", tfLoader),
 		)
 	}
 
-	conftestLoader := ConftestVersionLoader{}
+	conftestVerifier, err := checksum.NewVerifier(validationConfig.ChecksumsFile, "", HashiGetFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing conftest verifier")
+	}
+
+	conftestLoader := NewConftestVersionLoader(conftestVerifier)
 	if conftestVersionCache == nil {
 		conftestVersionCache = cache.NewExecutionVersionLayeredLoadingCache(
 			"conftest",
 			binDir,
-			conftestLoader.LoadVersion,
+			mirror.NewLoader(binaryMirror, "conftest", conftestLoader),
 		)
 	}
 
@@ -162,6 +215,11 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 		return nil, err
 	}
 
+	tofuClient, defaultTofuVersion, err := newTofuClient(tfConfig.Tofu, tfConfig.ChecksumsFile, binDir, binaryMirror)
+	if err != nil {
+		return nil, err
+	}
+
 	conftestClient, err := command.NewAsyncClient(
 		defaultConftestVersion,
 		conftestVersionCache,
@@ -170,6 +228,25 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 		return nil, err
 	}
 
+	pluginCache, err := plugincache.New(cacheDir, int64(tfConfig.PluginCache.MaxSizeMB)*1024*1024, rootsDir, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing plugin cache")
+	}
+
+	var pluginCacheWarmer func() error
+	if tfConfig.PluginCache.WarmUp {
+		pluginCacheWarmer = func() error {
+			return pluginCache.WarmUp(func(dir string) error {
+				return initProviders(tfClient, defaultTfVersion, cacheDir, dir)
+			})
+		}
+	}
+
+	planArtifactStore, err := newPlanArtifactStore(tfConfig.PlanArtifacts)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing plan artifact store")
+	}
+
 	policies := convertPolicies(validationConfig.Policies.PolicySets)
 
 	return &Terraform{
@@ -182,10 +259,13 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 			TerraformClient:        tfClient,
 			StreamHandler:          streamHandler,
 			DefaultTFVersion:       defaultTfVersion,
+			TofuClient:             tofuClient,
+			DefaultTofuVersion:     defaultTofuVersion,
 			GitCLICredentials:      credentialsRefresher,
 			GitCredentialsFileLock: gitCredentialsFileLock,
 			FileWriter:             &file.Writer{},
 			CacheDir:               cacheDir,
+			PluginCache:            pluginCache,
 		},
 		conftestActivity: &conftestActivity{
 			DefaultConftestVersion: defaultConftestVersion,
@@ -197,9 +277,99 @@ func NewTerraform(tfConfig config.TerraformConfig, validationConfig config.Valid
 		jobActivities: &jobActivities{
 			StreamCloser: streamHandler,
 		},
+		planArtifactActivities: &planArtifactActivities{
+			Store: planArtifactStore,
+		},
+		PluginCacheWarmer: pluginCacheWarmer,
 	}, nil
 }
 
+// initProviders runs init without a backend in dir so its providers are installed into the plugin cache
+func initProviders(client TerraformClient, v *version.Version, cacheDir string, dir string) error {
+	output := &bytes.Buffer{}
+	err := client.RunCommand(context.Background(), &command.RunCommandRequest{
+		RootPath: dir,
+		SubCommand: command.NewSubCommand(command.TerraformInit).WithUniqueArgs(
+			DisableInputArg,
+			DisableBackendArg,
+		),
+		AdditionalEnvVars: map[string]string{
+			TFInAutomation:   TFInAutomationVal,
+			TFPluginCacheDir: cacheDir,
+		},
+		Version: v,
+	}, command.RunOptions{
+		StdOut: output,
+		StdErr: output,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "running init in %s: %s", dir, output.String())
+	}
+	return nil
+}
+
+// newPlanArtifactStore returns an encrypted store for plan artifacts, the store is nil if one isn't configured
+func newPlanArtifactStore(cfg config.PlanArtifactsConfig) (planArtifactStore, error) {
+	if cfg.Store == nil {
+		return nil, nil
+	}
+
+	if cfg.EncryptionKeyFile == "" {
+		return nil, errors.New("an encryption key file is required to persist plan artifacts")
+	}
+
+	key, err := storage.LoadEncryptionKey(cfg.EncryptionKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading encryption key")
+	}
+
+	client, err := storage.NewClient(*cfg.Store)
+	if err != nil {
+		return nil, errors.Wrap(err, "intializing stow client")
+	}
+
+	store, err := storage.NewEncryptedClient(client, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing encrypted client")
+	}
+	return store, nil
+}
+
+// newTofuClient returns a client for roots using the opentofu engine, the client is nil if opentofu isn't configured
+func newTofuClient(tofuConfig config.TofuConfig, checksumsFile string, binDir string, binaryMirror mirror.Mirror) (TerraformClient, *version.Version, error) {
+	if !tofuConfig.Enabled {
+		return nil, nil, nil
+	}
+
+	var defaultVersion *version.Version
+	if tofuConfig.DefaultVersion != "" {
+		v, err := version.NewVersion(tofuConfig.DefaultVersion)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "parsing version %s", tofuConfig.DefaultVersion)
+		}
+		defaultVersion = v
+	}
+
+	verifier, err := checksum.NewVerifier(checksumsFile, tofuConfig.SigningKeyFile, HashiGetFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "initializing opentofu verifier")
+	}
+
+	loader := NewTofuVersionLoader(tofuConfig.DownloadURL, verifier)
+	client, err := command.NewAsyncClient(
+		defaultVersion,
+		cache.NewExecutionVersionLayeredLoadingCache(
+			valid.OpenTofuEngine.BinaryName(),
+			binDir,
+			mirror.NewLoader(binaryMirror, valid.OpenTofuEngine.BinaryName(), loader),
+		),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, defaultVersion, nil
+}
+
 type Github struct {
 	*githubActivities
 }
@@ -276,10 +446,15 @@ func NewRevisionSetterWithClient(client revisionSetterClient, cfg valid.Revision
 func convertPolicies(policies []valid.PolicySet) []PolicySet {
 	var convertedPolicies []PolicySet
 	for _, policy := range policies {
+		var exemptions []PolicyExemption
+		for _, e := range policy.Exemptions {
+			exemptions = append(exemptions, PolicyExemption(e))
+		}
 		convertedPolicies = append(convertedPolicies, PolicySet{
-			Name:  policy.Name,
-			Owner: policy.Owner,
-			Paths: policy.Paths,
+			Name:       policy.Name,
+			Owner:      policy.Owner,
+			Paths:      policy.Paths,
+			Exemptions: exemptions,
 		})
 	}
 	return convertedPolicies
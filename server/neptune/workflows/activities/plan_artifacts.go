@@ -0,0 +1,191 @@
+package activities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.temporal.io/sdk/activity"
+)
+
+// LockFile pins the providers a root was planned with
+const LockFile = ".terraform.lock.hcl"
+
+// PlanArtifactChecksumError is returned when a restored artifact doesn't match the one which was planned,
+// callers shouldn't retry since the stored artifact won't change.
+type PlanArtifactChecksumError struct {
+	err error
+}
+
+func (e PlanArtifactChecksumError) Error() string {
+	return e.err.Error()
+}
+
+type planArtifactStore interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Set(ctx context.Context, key string, object []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+type planArtifactActivities struct {
+	// Store is nil if plan artifacts aren't persisted, in which case applies must run on the planning worker
+	Store planArtifactStore
+}
+
+// PlanArtifact is a file needed to apply a plan on a worker other than the one which planned it
+type PlanArtifact struct {
+	// Name is the slash separated path of the file relative to the root
+	Name string
+	Key  string
+	// Checksum is the hex encoded sha256 of the unencrypted file
+	Checksum string
+}
+
+type StorePlanArtifactsRequest struct {
+	DeploymentID string
+	Path         string
+	PlanFile     string
+	PlanJSONFile string
+}
+
+type StorePlanArtifactsResponse struct {
+	// Artifacts is empty if plan artifacts aren't persisted
+	Artifacts []PlanArtifact
+}
+
+// StorePlanArtifacts uploads the plan, its show output and the root's lock file
+func (a *planArtifactActivities) StorePlanArtifacts(ctx context.Context, request StorePlanArtifactsRequest) (StorePlanArtifactsResponse, error) {
+	if a.Store == nil {
+		return StorePlanArtifactsResponse{}, nil
+	}
+
+	files := []string{request.PlanFile}
+	if request.PlanJSONFile != "" {
+		files = append(files, request.PlanJSONFile)
+	}
+
+	lockFile := filepath.Join(request.Path, LockFile)
+	if _, err := os.Stat(lockFile); err == nil {
+		files = append(files, lockFile)
+	}
+
+	var artifacts []PlanArtifact
+	for _, f := range files {
+		name, err := filepath.Rel(request.Path, f)
+		if err != nil {
+			return StorePlanArtifactsResponse{}, errors.Wrapf(err, "resolving %s relative to root", f)
+		}
+		name = filepath.ToSlash(name)
+
+		contents, err := os.ReadFile(f)
+		if err != nil {
+			return StorePlanArtifactsResponse{}, errors.Wrapf(err, "reading %s", f)
+		}
+
+		key := path.Join(request.DeploymentID, name)
+		if err := a.Store.Set(ctx, key, contents); err != nil {
+			return StorePlanArtifactsResponse{}, errors.Wrapf(err, "storing %s", name)
+		}
+
+		artifacts = append(artifacts, PlanArtifact{
+			Name:     name,
+			Key:      key,
+			Checksum: sha256Sum(contents),
+		})
+	}
+
+	return StorePlanArtifactsResponse{Artifacts: artifacts}, nil
+}
+
+type RestorePlanArtifactsRequest struct {
+	Path      string
+	Artifacts []PlanArtifact
+}
+
+type RestorePlanArtifactsResponse struct {
+	// RootMissing is true if the root is no longer checked out on this worker, ie. it restarted since planning
+	RootMissing bool
+}
+
+// RestorePlanArtifacts writes stored artifacts into the root, files which already match their checksum are kept as is
+func (a *planArtifactActivities) RestorePlanArtifacts(ctx context.Context, request RestorePlanArtifactsRequest) (RestorePlanArtifactsResponse, error) {
+	if _, err := os.Stat(request.Path); os.IsNotExist(err) {
+		return RestorePlanArtifactsResponse{RootMissing: true}, nil
+	}
+
+	for _, artifact := range request.Artifacts {
+		dst := filepath.Join(request.Path, filepath.FromSlash(artifact.Name))
+
+		if contents, err := os.ReadFile(dst); err == nil && sha256Sum(contents) == artifact.Checksum {
+			continue
+		}
+
+		if a.Store == nil {
+			return RestorePlanArtifactsResponse{}, errors.New("plan artifacts aren't persisted on this worker")
+		}
+
+		activity.GetLogger(ctx).Info("restoring plan artifact", "name", artifact.Name)
+
+		contents, err := a.get(ctx, artifact.Key)
+		if err != nil {
+			return RestorePlanArtifactsResponse{}, errors.Wrapf(err, "fetching %s", artifact.Name)
+		}
+
+		if sha256Sum(contents) != artifact.Checksum {
+			return RestorePlanArtifactsResponse{}, PlanArtifactChecksumError{
+				err: errors.Errorf("checksum of %s doesn't match the planned artifact", artifact.Name),
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return RestorePlanArtifactsResponse{}, errors.Wrapf(err, "creating dir for %s", artifact.Name)
+		}
+
+		if err := os.WriteFile(dst, contents, 0600); err != nil {
+			return RestorePlanArtifactsResponse{}, errors.Wrapf(err, "writing %s", artifact.Name)
+		}
+	}
+
+	return RestorePlanArtifactsResponse{}, nil
+}
+
+type DeletePlanArtifactsRequest struct {
+	Artifacts []PlanArtifact
+}
+
+type DeletePlanArtifactsResponse struct{}
+
+// DeletePlanArtifacts removes stored artifacts once their plan can no longer be applied, artifacts which
+// were already deleted are ignored so this can be retried.
+func (a *planArtifactActivities) DeletePlanArtifacts(ctx context.Context, request DeletePlanArtifactsRequest) (DeletePlanArtifactsResponse, error) {
+	if a.Store == nil {
+		return DeletePlanArtifactsResponse{}, nil
+	}
+
+	for _, artifact := range request.Artifacts {
+		if err := a.Store.Delete(ctx, artifact.Key); err != nil {
+			return DeletePlanArtifactsResponse{}, errors.Wrapf(err, "deleting %s", artifact.Name)
+		}
+	}
+	return DeletePlanArtifactsResponse{}, nil
+}
+
+func (a *planArtifactActivities) get(ctx context.Context, key string) ([]byte, error) {
+	r, err := a.Store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func sha256Sum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
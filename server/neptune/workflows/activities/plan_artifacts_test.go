@@ -0,0 +1,160 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/testsuite"
+)
+
+type testPlanArtifactStore struct {
+	items map[string][]byte
+}
+
+func (s *testPlanArtifactStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.items[key])), nil
+}
+
+func (s *testPlanArtifactStore) Set(ctx context.Context, key string, object []byte) error {
+	s.items[key] = object
+	return nil
+}
+
+func (s *testPlanArtifactStore) Delete(ctx context.Context, key string) error {
+	delete(s.items, key)
+	return nil
+}
+
+func writeRoot(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+	return dir
+}
+
+func TestPlanArtifacts(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+
+	planningRoot := writeRoot(t, map[string]string{
+		PlanOutputFile:     "plan",
+		PlanOutputJSONFile: "{}",
+		LockFile:           "locks",
+	})
+
+	store := &testPlanArtifactStore{items: map[string][]byte{}}
+	subject := &planArtifactActivities{Store: store}
+
+	env := ts.NewTestActivityEnvironment()
+	env.RegisterActivity(subject)
+
+	result, err := env.ExecuteActivity(subject.StorePlanArtifacts, StorePlanArtifactsRequest{
+		DeploymentID: "1234",
+		Path:         planningRoot,
+		PlanFile:     filepath.Join(planningRoot, PlanOutputFile),
+		PlanJSONFile: filepath.Join(planningRoot, PlanOutputJSONFile),
+	})
+	assert.NoError(t, err)
+
+	var resp StorePlanArtifactsResponse
+	assert.NoError(t, result.Get(&resp))
+	assert.Equal(t, []PlanArtifact{
+		{Name: PlanOutputFile, Key: "1234/output.tfplan", Checksum: sha256Sum([]byte("plan"))},
+		{Name: PlanOutputJSONFile, Key: "1234/output.json", Checksum: sha256Sum([]byte("{}"))},
+		{Name: LockFile, Key: "1234/.terraform.lock.hcl", Checksum: sha256Sum([]byte("locks"))},
+	}, resp.Artifacts)
+
+	t.Run("restore on another worker", func(t *testing.T) {
+		applyingRoot := writeRoot(t, map[string]string{
+			LockFile: "stale locks",
+		})
+
+		_, err := env.ExecuteActivity(subject.RestorePlanArtifacts, RestorePlanArtifactsRequest{
+			Path:      applyingRoot,
+			Artifacts: resp.Artifacts,
+		})
+		assert.NoError(t, err)
+
+		for name, expected := range map[string]string{
+			PlanOutputFile:     "plan",
+			PlanOutputJSONFile: "{}",
+			LockFile:           "locks",
+		} {
+			contents, err := os.ReadFile(filepath.Join(applyingRoot, name))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(contents))
+		}
+	})
+
+	t.Run("tampered artifact", func(t *testing.T) {
+		store.items["1234/output.tfplan"] = []byte("different plan")
+
+		_, err := env.ExecuteActivity(subject.RestorePlanArtifacts, RestorePlanArtifactsRequest{
+			Path:      t.TempDir(),
+			Artifacts: resp.Artifacts,
+		})
+		assert.ErrorContains(t, err, "checksum of output.tfplan doesn't match")
+	})
+
+	t.Run("root missing", func(t *testing.T) {
+		result, err := env.ExecuteActivity(subject.RestorePlanArtifacts, RestorePlanArtifactsRequest{
+			Path:      filepath.Join(t.TempDir(), "missing"),
+			Artifacts: resp.Artifacts,
+		})
+		assert.NoError(t, err)
+
+		var restoreResp RestorePlanArtifactsResponse
+		assert.NoError(t, result.Get(&restoreResp))
+		assert.True(t, restoreResp.RootMissing)
+	})
+
+	t.Run("already on the planning worker", func(t *testing.T) {
+		noStore := &planArtifactActivities{}
+		env := ts.NewTestActivityEnvironment()
+		env.RegisterActivity(noStore)
+
+		_, err := env.ExecuteActivity(noStore.RestorePlanArtifacts, RestorePlanArtifactsRequest{
+			Path:      planningRoot,
+			Artifacts: resp.Artifacts,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := env.ExecuteActivity(subject.DeletePlanArtifacts, DeletePlanArtifactsRequest{
+			Artifacts: resp.Artifacts,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, store.items)
+
+		// deleting again is a noop so the activity can be retried
+		_, err = env.ExecuteActivity(subject.DeletePlanArtifacts, DeletePlanArtifactsRequest{
+			Artifacts: resp.Artifacts,
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestStorePlanArtifacts_NotConfigured(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestActivityEnvironment()
+
+	subject := &planArtifactActivities{}
+	env.RegisterActivity(subject)
+
+	result, err := env.ExecuteActivity(subject.StorePlanArtifacts, StorePlanArtifactsRequest{
+		DeploymentID: "1234",
+		Path:         "some/path",
+		PlanFile:     "some/path/output.tfplan",
+	})
+	assert.NoError(t, err)
+
+	var resp StorePlanArtifactsResponse
+	assert.NoError(t, result.Get(&resp))
+	assert.Empty(t, resp.Artifacts)
+}
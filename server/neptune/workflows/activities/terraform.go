@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/palantir/go-githubapp/githubapp"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/file"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/temporal"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
@@ -63,6 +64,19 @@ var RefreshArg = command.Argument{
 	Value: "true",
 }
 
+// DisableLockArg is used by drift plans so that they never contend with deploys
+// for the state lock, drift plans are never applied so they don't need it.
+var DisableLockArg = command.Argument{
+	Key:   "lock",
+	Value: "false",
+}
+
+// DisableBackendArg is used to only install a root's modules and providers
+var DisableBackendArg = command.Argument{
+	Key:   "backend",
+	Value: "false",
+}
+
 const (
 	outArgKey          = "out"
 	PlanOutputFile     = "output.tfplan"
@@ -84,19 +98,28 @@ type gitCredentialsRefresher interface {
 	Refresh(ctx context.Context, token int64) error
 }
 
+type pluginCache interface {
+	Init(rootPath string, installProviders func() error, init func() error) error
+}
+
 type writer interface {
 	Write(name string, data []byte) error
 }
 
 type terraformActivities struct {
-	TerraformClient        TerraformClient
-	DefaultTFVersion       *version.Version
+	TerraformClient  TerraformClient
+	DefaultTFVersion *version.Version
+	// TofuClient runs roots with the opentofu engine, it is nil if opentofu isn't configured
+	TofuClient             TerraformClient
+	DefaultTofuVersion     *version.Version
 	StreamHandler          streamer
 	GHAppConfig            githubapp.Config
 	GitCLICredentials      gitCredentialsRefresher
 	GitCredentialsFileLock *file.RWLock
 	FileWriter             writer
 	CacheDir               string
+	// PluginCache is optional, inits run without coordinating with each other if it's nil
+	PluginCache pluginCache
 }
 
 func NewTerraformActivities(
@@ -140,6 +163,7 @@ type TerraformInitRequest struct {
 	DynamicEnvs          []EnvVar
 	JobID                string
 	TfVersion            string
+	Engine               valid.Engine
 	Path                 string
 	GithubInstallationID int64
 }
@@ -152,8 +176,8 @@ func (t *terraformActivities) TerraformInit(ctx context.Context, request Terrafo
 	cancel := temporal.StartHeartbeat(ctx, temporal.HeartbeatTimeout)
 	defer cancel()
 
-	// Resolve the tf version to be used for this operation
-	tfVersion, err := t.resolveVersion(request.TfVersion)
+	// Resolve the client and tf version to be used for this operation
+	client, tfVersion, err := t.resolve(request.Engine, request.TfVersion)
 	if err != nil {
 		return TerraformInitResponse{}, err
 	}
@@ -187,7 +211,23 @@ func (t *terraformActivities) TerraformInit(ctx context.Context, request Terrafo
 	t.GitCredentialsFileLock.RLock()
 	defer t.GitCredentialsFileLock.RUnlock()
 
-	out, err := t.runCommandWithOutputStream(ctx, request.JobID, r)
+	var out string
+	runInit := func() error {
+		out, err = t.runCommandWithOutputStream(ctx, client, request.JobID, r)
+		return err
+	}
+
+	if t.PluginCache != nil {
+		installRequest := *r
+		installRequest.SubCommand = command.NewSubCommand(command.TerraformInit).WithUniqueArgs(append(append([]command.Argument{}, args...), DisableBackendArg)...)
+		installProviders := func() error {
+			out, err = t.runCommandWithOutputStream(ctx, client, request.JobID, &installRequest)
+			return err
+		}
+		err = t.PluginCache.Init(request.Path, installProviders, runInit)
+	} else {
+		err = runInit()
+	}
 	if err != nil {
 		activity.GetLogger(ctx).Error(out)
 		return TerraformInitResponse{}, wrapTerraformError(err, "running init command")
@@ -202,6 +242,7 @@ type TerraformPlanRequest struct {
 	DynamicEnvs  []EnvVar
 	JobID        string
 	TfVersion    string
+	Engine       valid.Engine
 	Path         string
 	PlanMode     *terraform.PlanMode
 	WorkflowMode terraform.WorkflowMode
@@ -216,7 +257,7 @@ type TerraformPlanResponse struct {
 func (t *terraformActivities) TerraformPlan(ctx context.Context, request TerraformPlanRequest) (TerraformPlanResponse, error) {
 	cancel := temporal.StartHeartbeat(ctx, temporal.HeartbeatTimeout)
 	defer cancel()
-	tfVersion, err := t.resolveVersion(request.TfVersion)
+	client, tfVersion, err := t.resolve(request.Engine, request.TfVersion)
 	if err != nil {
 		return TerraformPlanResponse{}, err
 	}
@@ -230,6 +271,9 @@ func (t *terraformActivities) TerraformPlan(ctx context.Context, request Terrafo
 			Value: planFile,
 		},
 	}
+	if request.WorkflowMode == terraform.Drift {
+		args = append(args, DisableLockArg)
+	}
 	args = append(args, request.Args...)
 	var flags []command.Flag
 
@@ -249,7 +293,7 @@ func (t *terraformActivities) TerraformPlan(ctx context.Context, request Terrafo
 		AdditionalEnvVars: envs,
 		Version:           tfVersion,
 	}
-	out, err := t.runCommandWithOutputStream(ctx, request.JobID, planRequest)
+	out, err := t.runCommandWithOutputStream(ctx, client, request.JobID, planRequest)
 
 	if err != nil {
 		activity.GetLogger(ctx).Error(out)
@@ -269,21 +313,26 @@ func (t *terraformActivities) TerraformPlan(ctx context.Context, request Terrafo
 	}
 
 	showResultBuffer := &bytes.Buffer{}
-	showErr := t.TerraformClient.RunCommand(ctx, showRequest, command.RunOptions{
+	showErr := client.RunCommand(ctx, showRequest, command.RunOptions{
 		StdOut: showResultBuffer,
 		StdErr: showResultBuffer,
 	})
 
 	// if used by the validate step, we will fail when we can't find the file
 	if showErr != nil {
-		activity.GetLogger(ctx).Error("error with terraform show", key.ErrKey, err)
+		activity.GetLogger(ctx).Error("error with terraform show", key.ErrKey, showErr)
+
+		// drift detection relies entirely on the summary, an empty one would be reported as no drift
+		if request.WorkflowMode == terraform.Drift {
+			return TerraformPlanResponse{}, wrapTerraformError(showErr, "running show command")
+		}
 	}
 
 	showResults := showResultBuffer.Bytes()
 
-	// write show results to disk
+	// write show results to disk, deploys persist them alongside the plan for applies
 	var planJSONFile string
-	if showErr == nil && request.WorkflowMode == terraform.PR {
+	if showErr == nil && request.WorkflowMode != terraform.Drift {
 		planJSONFile = filepath.Join(request.Path, PlanOutputJSONFile)
 		if err = t.FileWriter.Write(planJSONFile, showResults); err != nil {
 			activity.GetLogger(ctx).Error("error writing show results to disk", key.ErrKey, err)
@@ -294,6 +343,10 @@ func (t *terraformActivities) TerraformPlan(ctx context.Context, request Terrafo
 	summary, err := terraform.NewPlanSummaryFromJSON(showResults)
 	if err != nil {
 		activity.GetLogger(ctx).Error("error building plan summary", key.ErrKey, err)
+
+		if request.WorkflowMode == terraform.Drift {
+			return TerraformPlanResponse{}, wrapTerraformError(err, "building plan summary")
+		}
 	}
 
 	return TerraformPlanResponse{
@@ -310,6 +363,7 @@ type TerraformApplyRequest struct {
 	DynamicEnvs []EnvVar
 	JobID       string
 	TfVersion   string
+	Engine      valid.Engine
 	Path        string
 	PlanFile    string
 }
@@ -321,7 +375,7 @@ type TerraformApplyResponse struct {
 func (t *terraformActivities) TerraformApply(ctx context.Context, request TerraformApplyRequest) (TerraformApplyResponse, error) {
 	cancel := temporal.StartHeartbeat(ctx, temporal.HeartbeatTimeout)
 	defer cancel()
-	tfVersion, err := t.resolveVersion(request.TfVersion)
+	client, tfVersion, err := t.resolve(request.Engine, request.TfVersion)
 	if err != nil {
 		return TerraformApplyResponse{}, err
 	}
@@ -342,7 +396,7 @@ func (t *terraformActivities) TerraformApply(ctx context.Context, request Terraf
 		AdditionalEnvVars: envs,
 		Version:           tfVersion,
 	}
-	out, err := t.runCommandWithOutputStream(ctx, request.JobID, applyRequest)
+	out, err := t.runCommandWithOutputStream(ctx, client, request.JobID, applyRequest)
 
 	if err != nil {
 		activity.GetLogger(ctx).Error(out)
@@ -352,7 +406,7 @@ func (t *terraformActivities) TerraformApply(ctx context.Context, request Terraf
 	return TerraformApplyResponse{}, nil
 }
 
-func (t *terraformActivities) runCommandWithOutputStream(ctx context.Context, jobID string, request *command.RunCommandRequest) (string, error) {
+func (t *terraformActivities) runCommandWithOutputStream(ctx context.Context, client TerraformClient, jobID string, request *command.RunCommandRequest) (string, error) {
 	reader, writer := io.Pipe()
 
 	var wg sync.WaitGroup
@@ -366,7 +420,7 @@ func (t *terraformActivities) runCommandWithOutputStream(ctx context.Context, jo
 				activity.GetLogger(ctx).Error("closing pipe writer", key.ErrKey, e)
 			}
 		}()
-		err = t.TerraformClient.RunCommand(ctx, request, command.RunOptions{
+		err = client.RunCommand(ctx, request, command.RunOptions{
 			StdOut: writer,
 			StdErr: writer,
 		})
@@ -394,10 +448,31 @@ func (t *terraformActivities) runCommandWithOutputStream(ctx context.Context, jo
 	return output.String(), err
 }
 
-func (t *terraformActivities) resolveVersion(v string) (*version.Version, error) {
+// resolve returns the client for the given engine along with the version to run
+func (t *terraformActivities) resolve(engine valid.Engine, v string) (TerraformClient, *version.Version, error) {
+	if engine.OrDefault() != valid.OpenTofuEngine {
+		version, err := resolveVersion(v, t.DefaultTFVersion)
+		return t.TerraformClient, version, err
+	}
+
+	if t.TofuClient == nil {
+		return nil, nil, NewTerraformClientError(errors.New("opentofu is not configured on this worker"))
+	}
+
+	version, err := resolveVersion(v, t.DefaultTofuVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version == nil {
+		return nil, nil, NewTerraformClientError(errors.New("no opentofu version specified and no default version configured"))
+	}
+	return t.TofuClient, version, nil
+}
+
+func resolveVersion(v string, defaultVersion *version.Version) (*version.Version, error) {
 	// Use default version if configured version is empty
 	if v == "" {
-		return t.DefaultTFVersion, nil
+		return defaultVersion, nil
 	}
 
 	version, err := version.NewVersion(v)
@@ -408,7 +483,7 @@ func (t *terraformActivities) resolveVersion(v string) (*version.Version, error)
 	if version != nil {
 		return version, nil
 	}
-	return t.DefaultTFVersion, nil
+	return defaultVersion, nil
 }
 
 func (t *terraformActivities) addTerraformEnvs(envs map[string]string, path string, tfVersion *version.Version) {
@@ -17,6 +17,26 @@ const (
 	ManualApproval
 )
 
+// PlanApprovalPolicy determines the approval for a plan from the resource changes within it.
+// Each resource change is assigned the approval of the first matching rule, falling back to
+// the default, and any change requiring manual approval requires it for the entire plan.
+type PlanApprovalPolicy struct {
+	Default PlanApprovalType
+
+	// MaxChanges requires manual approval for plans with more resource changes, 0 is unlimited
+	MaxChanges int
+	Rules      []PlanApprovalRule
+}
+
+// PlanApprovalRule matches resource changes by action (create, update, delete, replace),
+// resource type and address glob. Empty criteria match all resource changes.
+type PlanApprovalRule struct {
+	Actions       []string
+	ResourceTypes []string
+	Addresses     []string
+	Approval      PlanApprovalType
+}
+
 type PlanMode string
 
 func NewDestroyPlanMode() *PlanMode {
@@ -27,6 +47,9 @@ func NewDestroyPlanMode() *PlanMode {
 type PlanJob struct {
 	Mode     *PlanMode
 	Approval PlanApproval
+
+	// ApprovalPolicy is evaluated against the plan summary when the plan is otherwise auto approved
+	ApprovalPolicy *PlanApprovalPolicy
 	execute.Job
 }
 
@@ -53,4 +76,7 @@ type WorkflowMode int
 const (
 	Deploy WorkflowMode = iota
 	PR
+
+	// Drift only runs a plan in order to detect changes made outside of atlantis
+	Drift
 )
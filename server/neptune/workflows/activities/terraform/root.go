@@ -3,7 +3,9 @@ package terraform
 import (
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/execute"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 )
@@ -15,6 +17,7 @@ type Root struct {
 	// Path is the relative path from the repo
 	Path         string
 	TfVersion    string
+	Engine       valid.Engine
 	Apply        execute.Job
 	Plan         PlanJob
 	Validate     execute.Job
@@ -22,6 +25,13 @@ type Root struct {
 
 	TriggerInfo TriggerInfo
 
+	// DependsOn and Dependents are the roots this root is ordered against for the revision being deployed
+	DependsOn  []string
+	Dependents []string
+
+	// PromotionGate is applied after the roots in DependsOn deploy and is only set for later stages of a promotion chain
+	PromotionGate *PromotionGate
+
 	// replace with trigger info
 	Trigger Trigger
 	Rerun   bool
@@ -57,11 +67,17 @@ func (r Root) WithPlanApprovalOverride(a PlanApproval) Root {
 	return r
 }
 
+type PromotionGate struct {
+	Soak   time.Duration
+	Manual bool
+}
+
 type Trigger string
 type TriggerInfo struct {
-	Type  Trigger
-	Force bool
-	Rerun bool
+	Type     Trigger
+	Force    bool
+	Rerun    bool
+	Rollback bool
 }
 
 const (
@@ -2,28 +2,106 @@ package terraform
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-json"
 	"github.com/pkg/errors"
 )
 
+const (
+	SensitiveValue     = "(sensitive value)"
+	UnknownAfterApply  = "(known after apply)"
+	RootModuleAddress  = "root"
+	MaxAttributeLength = 100
+
+	// MaxAttributeChanges bounds the attribute changes kept across a plan summary since it's
+	// passed around in activity results, signals and workflow history.
+	MaxAttributeChanges = 200
+)
+
+// AttributeChange is a single attribute path which differs between the prior and planned state of a resource.
+// Sensitive values are never persisted and are replaced with SensitiveValue.
+type AttributeChange struct {
+	Path      string
+	Before    string
+	After     string
+	Sensitive bool
+}
+
 type ResourceSummary struct {
 	Address string
+
+	// populated for updates and replacements
+	AttributeChanges []AttributeChange
+
+	// populated for replacements, these are the attribute paths which force the replacement
+	ReplaceReasons []string
+
+	// populated when a resource has been moved from another address
+	PreviousAddress string
+
+	// populated when a resource is being imported
+	ImportID string
+}
+
+type OutputChange struct {
+	Name      string
+	Action    string
+	Before    string
+	After     string
+	Sensitive bool
+}
+
+// ModuleSummary contains resource change counts for a single module
+type ModuleSummary struct {
+	Address      string
+	Creations    int
+	Updates      int
+	Deletions    int
+	Replacements int
 }
 
 type PlanSummary struct {
-	Creations []ResourceSummary
-	Deletions []ResourceSummary
-	Updates   []ResourceSummary
+	// Creations and Deletions include replaced resources as well
+	Creations    []ResourceSummary
+	Deletions    []ResourceSummary
+	Updates      []ResourceSummary
+	Replacements []ResourceSummary
+	Moves        []ResourceSummary
+	Imports      []ResourceSummary
+	Outputs      []OutputChange
+	Modules      []ModuleSummary
+
+	// AttributeChangesOmitted is set when the plan has more than MaxAttributeChanges
+	// attribute changes, in which case none of them are kept.
+	AttributeChangesOmitted bool
 }
 
 func (s PlanSummary) IsEmpty() bool {
 	return len(s.Creations) == 0 && len(s.Deletions) == 0 && len(s.Updates) == 0
 }
 
-// Generates a super simple plan summary with changes grouped by action
-// creation, deletion, update.
-// changes are only represented using addresses for now.
+// resourceChangeExtensions contains fields of the plan json which aren't supported
+// by our version of terraform-json yet.
+type resourceChangeExtensions struct {
+	ResourceChanges []struct {
+		Address         string `json:"address"`
+		PreviousAddress string `json:"previous_address"`
+		Change          struct {
+			ReplacePaths []interface{} `json:"replace_paths"`
+			Importing    *struct {
+				ID string `json:"id"`
+			} `json:"importing"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// Generates a plan summary with changes grouped by action
+// creation, deletion, update, replacement along with any moved or imported resources,
+// output changes and resource change counts per module.
 func NewPlanSummaryFromJSON(b []byte) (PlanSummary, error) {
 	if len(b) == 0 {
 		return PlanSummary{}, nil
@@ -35,30 +113,376 @@ func NewPlanSummaryFromJSON(b []byte) (PlanSummary, error) {
 		return PlanSummary{}, errors.Wrap(err, "parsing plan json")
 	}
 
-	var creations []ResourceSummary
-	var deletions []ResourceSummary
-	var updates []ResourceSummary
-	for _, c := range plan.ResourceChanges {
-		summary := ResourceSummary{
-			Address: c.Address,
+	var extensions resourceChangeExtensions
+	if err := json.Unmarshal(b, &extensions); err != nil {
+		return PlanSummary{}, errors.Wrap(err, "parsing plan json extensions")
+	}
+
+	var summary PlanSummary
+	modules := map[string]*ModuleSummary{}
+	for i, c := range plan.ResourceChanges {
+		if c.Change == nil {
+			continue
+		}
+
+		// both are decoded from the same list so indices line up
+		extension := extensions.ResourceChanges[i]
+
+		moduleAddress := c.ModuleAddress
+		if moduleAddress == "" {
+			moduleAddress = RootModuleAddress
+		}
+		module, ok := modules[moduleAddress]
+		if !ok {
+			module = &ModuleSummary{Address: moduleAddress}
+			modules[moduleAddress] = module
 		}
+
 		actions := c.Change.Actions
-		if actions.Delete() || actions.Replace() {
-			deletions = append(deletions, summary)
+		if extension.PreviousAddress != "" && extension.PreviousAddress != c.Address {
+			summary.Moves = append(summary.Moves, ResourceSummary{
+				Address:         c.Address,
+				PreviousAddress: extension.PreviousAddress,
+			})
+		}
+
+		if extension.Change.Importing != nil {
+			summary.Imports = append(summary.Imports, ResourceSummary{
+				Address:  c.Address,
+				ImportID: extension.Change.Importing.ID,
+			})
+		}
+
+		if actions.Replace() {
+			summary.Replacements = append(summary.Replacements, ResourceSummary{
+				Address:          c.Address,
+				AttributeChanges: diffAttributes(c.Change),
+				ReplaceReasons:   replaceReasons(extension.Change.ReplacePaths),
+			})
+			module.Replacements++
+		}
+
+		summary.Deletions, summary.Creations, summary.Updates = groupByAction(
+			c, summary.Deletions, summary.Creations, summary.Updates, module,
+		)
+	}
+
+	for name, c := range plan.OutputChanges {
+		if c == nil || c.Actions.NoOp() {
+			continue
+		}
+		summary.Outputs = append(summary.Outputs, newOutputChange(name, c))
+	}
+	sort.Slice(summary.Outputs, func(i, j int) bool {
+		return summary.Outputs[i].Name < summary.Outputs[j].Name
+	})
+
+	for _, m := range modules {
+		if m.Creations == 0 && m.Updates == 0 && m.Deletions == 0 && m.Replacements == 0 {
+			continue
+		}
+		summary.Modules = append(summary.Modules, *m)
+	}
+	sort.Slice(summary.Modules, func(i, j int) bool {
+		return summary.Modules[i].Address < summary.Modules[j].Address
+	})
+
+	if countAttributeChanges(summary.Updates)+countAttributeChanges(summary.Replacements) > MaxAttributeChanges {
+		summary.Updates = withoutAttributeChanges(summary.Updates)
+		summary.Replacements = withoutAttributeChanges(summary.Replacements)
+		summary.AttributeChangesOmitted = true
+	}
+
+	return summary, nil
+}
+
+func countAttributeChanges(resources []ResourceSummary) int {
+	var count int
+	for _, r := range resources {
+		count += len(r.AttributeChanges)
+	}
+	return count
+}
+
+func withoutAttributeChanges(resources []ResourceSummary) []ResourceSummary {
+	for i := range resources {
+		resources[i].AttributeChanges = nil
+	}
+	return resources
+}
+
+func groupByAction(c *tfjson.ResourceChange, deletions, creations, updates []ResourceSummary, module *ModuleSummary) ([]ResourceSummary, []ResourceSummary, []ResourceSummary) {
+	summary := ResourceSummary{
+		Address: c.Address,
+	}
+	actions := c.Change.Actions
+	if actions.Delete() || actions.Replace() {
+		deletions = append(deletions, summary)
+	}
+
+	if actions.Create() || actions.Replace() {
+		creations = append(creations, summary)
+	}
+
+	// replacements are counted separately
+	if actions.Delete() {
+		module.Deletions++
+	}
+
+	if actions.Create() {
+		module.Creations++
+	}
+
+	if actions.Update() {
+		summary.AttributeChanges = diffAttributes(c.Change)
+		updates = append(updates, summary)
+		module.Updates++
+	}
+
+	return deletions, creations, updates
+}
+
+func newOutputChange(name string, c *tfjson.Change) OutputChange {
+	var action string
+	switch {
+	case c.Actions.Create():
+		action = string(tfjson.ActionCreate)
+	case c.Actions.Delete():
+		action = string(tfjson.ActionDelete)
+	default:
+		action = string(tfjson.ActionUpdate)
+	}
+
+	sensitive := isSensitive(c.BeforeSensitive) || isSensitive(c.AfterSensitive)
+	output := OutputChange{
+		Name:      name,
+		Action:    action,
+		Sensitive: sensitive,
+	}
+
+	if sensitive {
+		output.Before = SensitiveValue
+		output.After = SensitiveValue
+		return output
+	}
+
+	output.Before = formatValue(c.Before)
+	output.After = formatValue(c.After)
+	if isSensitive(c.AfterUnknown) {
+		output.After = UnknownAfterApply
+	}
+	return output
+}
+
+func diffAttributes(c *tfjson.Change) []AttributeChange {
+	var changes []AttributeChange
+	diffValue("", c.Before, c.After, c.AfterUnknown, c.BeforeSensitive, c.AfterSensitive, &changes)
+	return changes
+}
+
+// diffValue recursively walks the before and after values of a resource and records each leaf attribute
+// which differs.  unknown, beforeSensitive and afterSensitive mirror the structure of the values
+// with a boolean leaf marking the attribute as unknown or sensitive respectively.
+func diffValue(path string, before, after, unknown, beforeSensitive, afterSensitive interface{}, changes *[]AttributeChange) {
+	sensitive := isSensitive(beforeSensitive) || isSensitive(afterSensitive)
+
+	// the whole before value is rendered here so it's masked if any nested attribute is sensitive
+	if isSensitive(unknown) {
+		change := AttributeChange{
+			Path:      path,
+			Before:    formatValue(before),
+			After:     UnknownAfterApply,
+			Sensitive: sensitive || containsSensitive(beforeSensitive),
+		}
+		if change.Sensitive {
+			change.Before = SensitiveValue
 		}
+		*changes = append(*changes, change)
+		return
+	}
 
-		if actions.Create() || actions.Replace() {
-			creations = append(creations, summary)
+	if sensitive {
+		if !reflect.DeepEqual(before, after) {
+			*changes = append(*changes, AttributeChange{
+				Path:      path,
+				Before:    SensitiveValue,
+				After:     SensitiveValue,
+				Sensitive: true,
+			})
 		}
+		return
+	}
 
-		if actions.Update() {
-			updates = append(updates, summary)
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := map[string]struct{}{}
+		for k := range beforeMap {
+			keys[k] = struct{}{}
 		}
+		for k := range afterMap {
+			keys[k] = struct{}{}
+		}
+
+		// computed attributes are omitted from after
+		if unknownMap, ok := unknown.(map[string]interface{}); ok {
+			for k := range unknownMap {
+				keys[k] = struct{}{}
+			}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, k := range sortedKeys {
+			diffValue(
+				joinPath(path, k), beforeMap[k], afterMap[k],
+				childKey(unknown, k), childKey(beforeSensitive, k), childKey(afterSensitive, k),
+				changes,
+			)
+		}
+		return
 	}
 
-	return PlanSummary{
-		Creations: creations,
-		Deletions: deletions,
-		Updates:   updates,
-	}, nil
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if (beforeIsList || before == nil) && (afterIsList || after == nil) && (beforeIsList || afterIsList) {
+		length := len(beforeList)
+		if len(afterList) > length {
+			length = len(afterList)
+		}
+
+		for i := 0; i < length; i++ {
+			diffValue(
+				fmt.Sprintf("%s[%d]", path, i), childIndex(beforeList, i), childIndex(afterList, i),
+				childKey(unknown, i), childKey(beforeSensitive, i), childKey(afterSensitive, i),
+				changes,
+			)
+		}
+		return
+	}
+
+	// before and after are scalars or have mismatched types (ie. a block which was added), so either may
+	// still hold nested sensitive attributes
+	if !reflect.DeepEqual(before, after) {
+		change := AttributeChange{
+			Path:   path,
+			Before: formatValue(before),
+			After:  formatValue(after),
+		}
+		if containsSensitive(beforeSensitive) {
+			change.Before = SensitiveValue
+			change.Sensitive = true
+		}
+		if containsSensitive(afterSensitive) {
+			change.After = SensitiveValue
+			change.Sensitive = true
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+func replaceReasons(paths []interface{}) []string {
+	var reasons []string
+	for _, p := range paths {
+		steps, ok := p.([]interface{})
+		if !ok {
+			continue
+		}
+
+		var path string
+		for _, step := range steps {
+			switch s := step.(type) {
+			case string:
+				path = joinPath(path, s)
+			case float64:
+				path = fmt.Sprintf("%s[%d]", path, int(s))
+			}
+		}
+		reasons = append(reasons, path)
+	}
+	return reasons
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// childKey returns the nested value at the given map key or list index.  A boolean true is
+// propagated since it applies to the entire subtree.
+func childKey(v interface{}, key interface{}) interface{} {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			return t[k]
+		}
+	case []interface{}:
+		if i, ok := key.(int); ok {
+			return childIndex(t, i)
+		}
+	}
+	return nil
+}
+
+func childIndex(l []interface{}, i int) interface{} {
+	if i < len(l) {
+		return l[i]
+	}
+	return nil
+}
+
+func isSensitive(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// containsSensitive returns true if v or any value nested within it is marked as sensitive
+func containsSensitive(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case map[string]interface{}:
+		for _, child := range t {
+			if containsSensitive(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range t {
+			if containsSensitive(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+
+	var s string
+	if str, ok := v.(string); ok {
+		s = fmt.Sprintf("%q", str)
+	} else {
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprintf("%v", v)
+		} else {
+			s = string(b)
+		}
+	}
+
+	if r := []rune(s); len(r) > MaxAttributeLength {
+		s = strings.TrimSpace(string(r[:MaxAttributeLength])) + "..."
+	}
+	return s
 }
@@ -1,6 +1,8 @@
 package terraform_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
@@ -30,6 +32,14 @@ func TestSummary(t *testing.T) {
 					Address: "type.resource_delete",
 				},
 			},
+			Modules: []terraform.ModuleSummary{
+				{
+					Address:   terraform.RootModuleAddress,
+					Creations: 1,
+					Updates:   1,
+					Deletions: 1,
+				},
+			},
 		}, summary)
 	})
 
@@ -55,6 +65,17 @@ func TestSummary_replace(t *testing.T) {
 				Address: "type.resource_replace",
 			},
 		},
+		Replacements: []terraform.ResourceSummary{
+			{
+				Address: "type.resource_replace",
+			},
+		},
+		Modules: []terraform.ModuleSummary{
+			{
+				Address:      terraform.RootModuleAddress,
+				Replacements: 1,
+			},
+		},
 	}, summary)
 }
 
@@ -67,3 +88,176 @@ func TestSummary_empty(t *testing.T) {
 	assert.Equal(t, terraform.PlanSummary{}, summary)
 	assert.True(t, summary.IsEmpty())
 }
+
+func TestSummary_tofu(t *testing.T) {
+	// opentofu reports its own version in the terraform_version field
+	plan := "{\"format_version\": \"1.2\",\"terraform_version\": \"1.6.0\",\"resource_changes\":[{\"change\":{\"actions\":[\"create\"]},\"address\":\"type.resource_create\"}]}"
+
+	summary, err := terraform.NewPlanSummaryFromJSON([]byte(plan))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []terraform.ResourceSummary{{Address: "type.resource_create"}}, summary.Creations)
+}
+
+func TestSummary_attributes(t *testing.T) {
+	plan := `{
+  "format_version": "1.1",
+  "resource_changes": [
+    {
+      "address": "module.app.aws_instance.web",
+      "module_address": "module.app",
+      "change": {
+        "actions": ["update"],
+        "before": {"ami": "ami-1", "password": "hunter2", "tags": {"env": "staging", "team": "infra"}, "ports": [80]},
+        "after": {"ami": "ami-2", "password": "hunter3", "tags": {"env": "prod", "team": "infra"}, "ports": [80, 443]},
+        "after_unknown": {"arn": true},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true}
+      }
+    },
+    {
+      "address": "aws_db_instance.db",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"engine": "postgres", "id": "db-1"},
+        "after": {"engine": "mysql"},
+        "after_unknown": {"id": true},
+        "replace_paths": [["engine"]]
+      }
+    }
+  ],
+  "output_changes": {
+    "url": {"actions": ["update"], "before": "a", "after": "b"},
+    "secret": {"actions": ["create"], "before": null, "after": "s", "after_sensitive": true},
+    "unchanged": {"actions": ["no-op"], "before": "c", "after": "c"}
+  }
+}`
+
+	summary, err := terraform.NewPlanSummaryFromJSON([]byte(plan))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []terraform.ResourceSummary{
+		{
+			Address: "module.app.aws_instance.web",
+			AttributeChanges: []terraform.AttributeChange{
+				{Path: "ami", Before: `"ami-1"`, After: `"ami-2"`},
+				{Path: "arn", Before: "null", After: terraform.UnknownAfterApply},
+				{Path: "password", Before: terraform.SensitiveValue, After: terraform.SensitiveValue, Sensitive: true},
+				{Path: "ports[1]", Before: "null", After: "443"},
+				{Path: "tags.env", Before: `"staging"`, After: `"prod"`},
+			},
+		},
+	}, summary.Updates)
+
+	assert.Equal(t, []terraform.ResourceSummary{
+		{
+			Address: "aws_db_instance.db",
+			AttributeChanges: []terraform.AttributeChange{
+				{Path: "engine", Before: `"postgres"`, After: `"mysql"`},
+				{Path: "id", Before: `"db-1"`, After: terraform.UnknownAfterApply},
+			},
+			ReplaceReasons: []string{"engine"},
+		},
+	}, summary.Replacements)
+
+	assert.Equal(t, []terraform.OutputChange{
+		{Name: "secret", Action: "create", Before: terraform.SensitiveValue, After: terraform.SensitiveValue, Sensitive: true},
+		{Name: "url", Action: "update", Before: `"a"`, After: `"b"`},
+	}, summary.Outputs)
+
+	assert.Equal(t, []terraform.ModuleSummary{
+		{Address: "module.app", Updates: 1},
+		{Address: terraform.RootModuleAddress, Replacements: 1},
+	}, summary.Modules)
+}
+
+func TestSummary_nestedSensitiveAttributes(t *testing.T) {
+	plan := `{
+  "format_version": "1.1",
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "change": {
+        "actions": ["update"],
+        "before": {"cfg": {"password": "hunter2", "user": "admin"}, "opts": "none"},
+        "after": {"opts": {"token": "s3cret"}},
+        "after_unknown": {"cfg": true},
+        "before_sensitive": {"cfg": {"password": true}},
+        "after_sensitive": {"opts": {"token": true}}
+      }
+    }
+  ]
+}`
+
+	summary, err := terraform.NewPlanSummaryFromJSON([]byte(plan))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []terraform.ResourceSummary{
+		{
+			Address: "aws_instance.web",
+			AttributeChanges: []terraform.AttributeChange{
+				{Path: "cfg", Before: terraform.SensitiveValue, After: terraform.UnknownAfterApply, Sensitive: true},
+				{Path: "opts", Before: `"none"`, After: terraform.SensitiveValue, Sensitive: true},
+			},
+		},
+	}, summary.Updates)
+}
+
+func TestSummary_omitsAttributesOfLargePlans(t *testing.T) {
+	before := map[string]interface{}{}
+	after := map[string]interface{}{}
+	for i := 0; i <= terraform.MaxAttributeChanges; i++ {
+		before[fmt.Sprintf("attr%d", i)] = "a"
+		after[fmt.Sprintf("attr%d", i)] = "b"
+	}
+
+	plan, err := json.Marshal(map[string]interface{}{
+		"format_version": "1.1",
+		"resource_changes": []interface{}{
+			map[string]interface{}{
+				"address": "aws_instance.web",
+				"change": map[string]interface{}{
+					"actions": []string{"update"},
+					"before":  before,
+					"after":   after,
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	summary, err := terraform.NewPlanSummaryFromJSON(plan)
+	assert.NoError(t, err)
+	assert.True(t, summary.AttributeChangesOmitted)
+	assert.Equal(t, []terraform.ResourceSummary{{Address: "aws_instance.web"}}, summary.Updates)
+}
+
+func TestSummary_movedAndImported(t *testing.T) {
+	plan := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.new",
+      "previous_address": "aws_s3_bucket.old",
+      "change": {"actions": ["no-op"], "before": {}, "after": {}}
+    },
+    {
+      "address": "aws_iam_role.imported",
+      "change": {"actions": ["no-op"], "before": {}, "after": {}, "importing": {"id": "role-name"}}
+    }
+  ]
+}`
+
+	summary, err := terraform.NewPlanSummaryFromJSON([]byte(plan))
+	assert.NoError(t, err)
+
+	assert.Equal(t, terraform.PlanSummary{
+		Moves: []terraform.ResourceSummary{
+			{Address: "aws_s3_bucket.new", PreviousAddress: "aws_s3_bucket.old"},
+		},
+		Imports: []terraform.ResourceSummary{
+			{Address: "aws_iam_role.imported", ImportID: "role-name"},
+		},
+	}, summary)
+	assert.True(t, summary.IsEmpty())
+}
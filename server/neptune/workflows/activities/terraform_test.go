@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/file"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/stretchr/testify/assert"
@@ -62,11 +63,11 @@ func (t *multiCallTfClient) RunCommand(ctx context.Context, request *command.Run
 	if t.count >= len(t.clients) {
 		return fmt.Errorf("expected less calls to RunCommand")
 	}
-	_ = t.clients[t.count].RunCommand(ctx, request, options...)
+	err := t.clients[t.count].RunCommand(ctx, request, options...)
 
 	t.count++
 
-	return nil
+	return err
 }
 
 func (t *multiCallTfClient) AssertExpectations() error {
@@ -411,6 +412,23 @@ func TestTerraformPlan_RequestValidation(t *testing.T) {
 			ExpectedArgs:    defaultArgs,
 			ExpectedVersion: defaultVersion,
 		},
+		{
+			// testing
+			WorkflowMode: terraform.Drift,
+			ExpectedArgs: append(defaultArgs, command.Argument{
+				Key:   "lock",
+				Value: "false",
+			}),
+
+			// default
+			ExpectedVersion: defaultVersion,
+			ExpectedEnvs: map[string]string{
+				"ATLANTIS_TERRAFORM_VERSION": "1.0.2",
+				"DIR":                        "some/path",
+				"TF_IN_AUTOMATION":           "true",
+				"TF_PLUGIN_CACHE_DIR":        "some/dir",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -442,7 +460,7 @@ func TestTerraformPlan_RequestValidation(t *testing.T) {
 						cmd:           command.NewSubCommand(command.TerraformShow).WithFlags(command.Flag{Value: "json"}).WithInput("some/path/output.tfplan"),
 						customEnvVars: c.ExpectedEnvs,
 						version:       expectedVersion,
-						resp:          "{}",
+						resp:          "{\"format_version\": \"1.0\"}",
 					},
 				},
 			}
@@ -547,7 +565,12 @@ func TestTerraformPlan_ReturnsResponse(t *testing.T) {
 
 	credsRefresher := &testCredsRefresher{}
 
-	tfActivity := NewTerraformActivities(&testTfClient, expectedVersion, streamHandler, credsRefresher, &file.RWLock{}, &mockWriter{}, "some/dir")
+	fileWriter := &mockWriter{
+		t:            t,
+		expectedName: "some/path/output.json",
+	}
+
+	tfActivity := NewTerraformActivities(&testTfClient, expectedVersion, streamHandler, credsRefresher, &file.RWLock{}, fileWriter, "some/dir")
 
 	env.RegisterActivity(tfActivity)
 
@@ -559,13 +582,20 @@ func TestTerraformPlan_ReturnsResponse(t *testing.T) {
 	assert.NoError(t, result.Get(&resp))
 
 	assert.Equal(t, TerraformPlanResponse{
-		PlanFile: "some/path/output.tfplan",
+		PlanFile:     "some/path/output.tfplan",
+		PlanJSONFile: "some/path/output.json",
 		Summary: terraform.PlanSummary{
 			Updates: []terraform.ResourceSummary{
 				{
 					Address: "type.resource",
 				},
 			},
+			Modules: []terraform.ModuleSummary{
+				{
+					Address: terraform.RootModuleAddress,
+					Updates: 1,
+				},
+			},
 		},
 	}, resp)
 
@@ -574,6 +604,65 @@ func TestTerraformPlan_ReturnsResponse(t *testing.T) {
 	assert.True(t, streamHandler.called)
 }
 
+func TestTerraformPlan_DriftShowError(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestActivityEnvironment()
+
+	path := "some/path"
+	jobID := "1234"
+	envs := map[string]string{
+		"ATLANTIS_TERRAFORM_VERSION": "1.0.2",
+		"DIR":                        "some/path",
+		"TF_IN_AUTOMATION":           "true",
+		"TF_PLUGIN_CACHE_DIR":        "some/dir",
+	}
+
+	expectedVersion, err := version.NewVersion("1.0.2")
+	assert.Nil(t, err)
+
+	testTfClient := multiCallTfClient{
+		clients: []*testTfClient{
+			{
+				t:     t,
+				jobID: jobID,
+				path:  path,
+				cmd: command.NewSubCommand(command.TerraformPlan).WithUniqueArgs(
+					DisableInputArg,
+					RefreshArg,
+					command.Argument{Key: "out", Value: "some/path/output.tfplan"},
+					DisableLockArg,
+				),
+				customEnvVars: envs,
+				version:       expectedVersion,
+			},
+			{
+				t:             t,
+				jobID:         jobID,
+				path:          path,
+				cmd:           command.NewSubCommand(command.TerraformShow).WithFlags(command.Flag{Value: "json"}).WithInput("some/path/output.tfplan"),
+				customEnvVars: envs,
+				version:       expectedVersion,
+				expectedError: assert.AnError,
+			},
+		},
+	}
+
+	req := TerraformPlanRequest{
+		JobID:        jobID,
+		Path:         path,
+		WorkflowMode: terraform.Drift,
+	}
+
+	tfActivity := NewTerraformActivities(&testTfClient, expectedVersion, &testStreamHandler{
+		t: t,
+	}, &testCredsRefresher{}, &file.RWLock{}, &mockWriter{t: t}, "some/dir")
+	env.RegisterActivity(tfActivity)
+
+	_, err = env.ExecuteActivity(tfActivity.TerraformPlan, req)
+	assert.Error(t, err)
+	assert.NoError(t, testTfClient.AssertExpectations())
+}
+
 func TestTerraformApply_RequestValidation(t *testing.T) {
 	defaultArgs := []command.Argument{
 		{
@@ -758,3 +847,113 @@ func (m *mockWriter) Write(name string, _ []byte) error {
 	assert.Equal(m.t, m.expectedName, name)
 	return nil
 }
+
+func TestTerraformInit_OpenTofu(t *testing.T) {
+	defaultArgs := []command.Argument{
+		{
+			Key:   "input",
+			Value: "false",
+		},
+	}
+
+	path := "some/path"
+	jobID := "1234"
+
+	tfVersion, err := version.NewVersion("1.0.2")
+	assert.NoError(t, err)
+	tofuVersion, err := version.NewVersion("1.6.0")
+	assert.NoError(t, err)
+
+	req := TerraformInitRequest{
+		JobID:  jobID,
+		Path:   path,
+		Engine: valid.OpenTofuEngine,
+	}
+
+	t.Run("runs tofu client", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestActivityEnvironment()
+
+		tofuClient := &testTfClient{
+			t:     t,
+			jobID: jobID,
+			path:  path,
+			cmd:   command.NewSubCommand(command.TerraformInit).WithUniqueArgs(defaultArgs...),
+			customEnvVars: map[string]string{
+				"ATLANTIS_TERRAFORM_VERSION": "1.6.0",
+				"DIR":                        "some/path",
+				"TF_IN_AUTOMATION":           "true",
+				"TF_PLUGIN_CACHE_DIR":        "some/dir",
+			},
+			version: tofuVersion,
+		}
+
+		// any terraform client call fails
+		tfClient := &multiCallTfClient{}
+
+		tfActivity := NewTerraformActivities(tfClient, tfVersion, &testStreamHandler{t: t}, &testCredsRefresher{t: t}, &file.RWLock{}, &mockWriter{}, "some/dir")
+		tfActivity.TofuClient = tofuClient
+		tfActivity.DefaultTofuVersion = tofuVersion
+		env.RegisterActivity(tfActivity)
+
+		_, err = env.ExecuteActivity(tfActivity.TerraformInit, req)
+		assert.NoError(t, err)
+		assert.NoError(t, tfClient.AssertExpectations())
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestActivityEnvironment()
+
+		tfActivity := NewTerraformActivities(&multiCallTfClient{}, tfVersion, &testStreamHandler{t: t}, &testCredsRefresher{t: t}, &file.RWLock{}, &mockWriter{}, "some/dir")
+		env.RegisterActivity(tfActivity)
+
+		_, err = env.ExecuteActivity(tfActivity.TerraformInit, req)
+		assert.ErrorContains(t, err, "opentofu is not configured")
+	})
+}
+
+type testPluginCache struct {
+	rootPath string
+}
+
+func (c *testPluginCache) Init(rootPath string, installProviders func() error, init func() error) error {
+	c.rootPath = rootPath
+	return init()
+}
+
+func TestTerraformInit_UsesPluginCache(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestActivityEnvironment()
+
+	expectedVersion, err := version.NewVersion("1.0.2")
+	assert.NoError(t, err)
+
+	tfClient := &testTfClient{
+		t:    t,
+		path: "some/path",
+		cmd: command.NewSubCommand(command.TerraformInit).WithUniqueArgs(command.Argument{
+			Key:   "input",
+			Value: "false",
+		}),
+		customEnvVars: map[string]string{
+			"ATLANTIS_TERRAFORM_VERSION": "1.0.2",
+			"DIR":                        "some/path",
+			"TF_IN_AUTOMATION":           "true",
+			"TF_PLUGIN_CACHE_DIR":        "some/dir",
+		},
+		version: expectedVersion,
+	}
+
+	pluginCache := &testPluginCache{}
+	tfActivity := NewTerraformActivities(tfClient, expectedVersion, &testStreamHandler{t: t}, &testCredsRefresher{t: t}, &file.RWLock{}, &mockWriter{}, "some/dir")
+	tfActivity.PluginCache = pluginCache
+	env.RegisterActivity(tfActivity)
+
+	_, err = env.ExecuteActivity(tfActivity.TerraformInit, TerraformInitRequest{
+		JobID: "1234",
+		Path:  "some/path",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "some/path", pluginCache.rootPath)
+}
@@ -23,21 +23,50 @@ type User = request.User
 type PlanMode = request.PlanMode
 type Trigger = request.Trigger
 type DeployTriggerInfo = request.TriggerInfo
+type PlanApprovalPolicy = request.PlanApprovalPolicy
+type PlanApprovalRule = request.PlanApprovalRule
+type PlanApprovalType = request.PlanApprovalType
+type PromotionGate = request.PromotionGate
 
 const DestroyPlanMode = request.DestroyPlanMode
 const NormalPlanMode = request.NormalPlanMode
 
+const AutoApproval = request.AutoApproval
+const ManualApproval = request.ManualApproval
+
 const ManualTrigger = request.ManualTrigger
 const MergeTrigger = request.MergeTrigger
 
 const DeployUnlockSignalName = queue.UnlockSignalName
+const DeployRemoveRevisionSignalName = queue.RemoveRevisionSignalName
+const DeployReorderRevisionsSignalName = queue.ReorderRevisionsSignalName
+const DeployPromoteSignalName = queue.PromoteSignalName
+const DeployQueueQueryName = deploy.QueueQueryName
+const DeploymentStatusQueryName = deploy.DeploymentStatusQueryName
+
+const QueuedDeploymentStatus = deploy.QueuedDeploymentStatus
+const RunningDeploymentStatus = deploy.RunningDeploymentStatus
+const SuccessDeploymentStatus = deploy.SuccessDeploymentStatus
+const FailureDeploymentStatus = deploy.FailureDeploymentStatus
+const UnknownDeploymentStatus = deploy.UnknownDeploymentStatus
 
 type DeployUnlockSignalRequest = queue.UnlockSignalRequest
+type DeployRemoveRevisionSignalRequest = queue.RemoveRevisionSignalRequest
+type DeployReorderRevisionsSignalRequest = queue.ReorderRevisionsSignalRequest
+type DeployPromoteSignalRequest = queue.PromoteSignalRequest
+type DeployQueueState = deploy.QueueState
+type DeployQueuedRevision = deploy.QueuedRevision
+type DeployQueueLockState = deploy.QueueLockState
+type DeploymentState = deploy.DeploymentState
+type DeploymentStatus = deploy.DeploymentStatus
 type DeployNewRevisionSignalRequest = revision.NewRevisionRequest
 
 var DeployTaskQueue = deploy.TaskQueue
 var DeployNewRevisionSignalID = revision.NewRevisionSignalID
 
+// BuildDeployWorkflowID is shared with the workflow since it signals the deploy workflows of dependent roots
+var BuildDeployWorkflowID = queue.BuildDeployWorkflowID
+
 // Workflow name
 var Deploy = "Deploy"
 
@@ -21,6 +21,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime/cache"
+	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/neptune/temporalworker/config"
 	"github.com/runatlantis/atlantis/server/neptune/workflows"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
@@ -168,7 +169,7 @@ func buildConfig(t *testing.T) config.Config {
 func initAndRegisterActivities(t *testing.T, env *testsuite.TestWorkflowEnvironment, revReq workflows.DeployNewRevisionSignalRequest) *testSingletons {
 	cfg := buildConfig(t)
 
-	deployActivities, err := activities.NewDeploy(cfg.DeploymentConfig)
+	deployActivities, err := activities.NewDeploy(cfg.DeploymentConfig, nil, logging.NewNoopCtxLogger(t))
 
 	assert.NoError(t, err)
 
@@ -253,7 +254,7 @@ func (sc *testStreamCloser) RegisterJob(id string) chan string {
 	return ch
 }
 
-func (sc *testStreamCloser) CloseJob(ctx context.Context, jobID string) error {
+func (sc *testStreamCloser) CloseJob(ctx context.Context, jobID string, repoFullName string) error {
 	return nil
 }
 
@@ -299,6 +300,18 @@ func (c *testGithubClient) DismissReview(ctx internalGithub.Context, owner, repo
 	return &github.PullRequestReview{}, &github.Response{}, nil
 }
 
+func (c *testGithubClient) CreateComment(ctx internalGithub.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	return &github.IssueComment{}, &github.Response{}, nil
+}
+
+func (c *testGithubClient) ListComments(ctx internalGithub.Context, owner string, repo string, number int) ([]*github.IssueComment, error) {
+	return []*github.IssueComment{}, nil
+}
+
+func (c *testGithubClient) EditComment(ctx internalGithub.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	return &github.IssueComment{}, &github.Response{}, nil
+}
+
 func (c *testGithubClient) ListReviews(ctx internalGithub.Context, owner string, repo string, number int) ([]*github.PullRequestReview, error) {
 	return []*github.PullRequestReview{
 		{
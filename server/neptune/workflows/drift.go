@@ -0,0 +1,29 @@
+package workflows
+
+import (
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/drift"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Export anything that callers need such as requests, signals, etc.
+type DriftRequest = drift.Request
+type DriftResponse = drift.Response
+
+// Workflow name
+var Drift = "Drift"
+
+// Workflow function is a closure, so make sure to register with a name
+type DriftFunc func(workflow.Context, DriftRequest) (DriftResponse, error)
+
+// GetDriftWithNotifiers returns a function closure for the drift workflow which
+// notifies the provided plugins of any state changes.
+func GetDriftWithNotifiers(notifiers ...plugins.TerraformWorkflowNotifier) DriftFunc {
+	return func(ctx workflow.Context, req DriftRequest) (DriftResponse, error) {
+		return drift.Workflow(ctx, req, Terraform, notifiers...)
+	}
+}
+
+func GetDrift() DriftFunc {
+	return GetDriftWithNotifiers()
+}
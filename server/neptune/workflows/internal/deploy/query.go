@@ -0,0 +1,134 @@
+package deploy
+
+import (
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	QueueQueryName            = "queue"
+	DeploymentStatusQueryName = "deployment-status"
+)
+
+type DeploymentStatus string
+
+const (
+	QueuedDeploymentStatus  DeploymentStatus = "queued"
+	RunningDeploymentStatus DeploymentStatus = "running"
+	SuccessDeploymentStatus DeploymentStatus = "success"
+	FailureDeploymentStatus DeploymentStatus = "failure"
+
+	// UnknownDeploymentStatus is returned for deployments this workflow isn't aware of,
+	// this could be because it was completed a while ago, or it was never received.
+	UnknownDeploymentStatus DeploymentStatus = "unknown"
+)
+
+// DeploymentState is the response to a deployment status query
+type DeploymentState struct {
+	ID       string
+	Revision string
+	Status   DeploymentStatus
+}
+
+// QueueState is the response to a queue query, it is intentionally decoupled
+// from the internal queue representation since it's consumed by external callers.
+type QueueState struct {
+	Lock              QueueLockState
+	CurrentDeployment *QueuedRevision
+	Revisions         []QueuedRevision
+	LatestDeployment  *deployment.Info
+}
+
+type QueueLockState struct {
+	Locked   bool
+	Revision string
+}
+
+type QueuedRevision struct {
+	ID       string
+	Revision string
+	Branch   string
+	User     string
+	Trigger  string
+
+	// only populated for the current deployment
+	PlanJobURL  string `json:",omitempty"`
+	ApplyJobURL string `json:",omitempty"`
+}
+
+type queryableQueue interface {
+	Scan() []terraform.DeploymentInfo
+	GetLockState() queue.LockState
+}
+
+type queryableWorker interface {
+	GetCurrentDeploymentState() queue.CurrentDeployment
+	GetLatestDeployment() *deployment.Info
+	IsDeploying() bool
+	GetDeploymentResult(id string) (queue.DeploymentResult, bool)
+}
+
+func setQueueQueryHandler(ctx workflow.Context, q queryableQueue, w queryableWorker) error {
+	return workflow.SetQueryHandler(ctx, QueueQueryName, func() (QueueState, error) {
+		lock := q.GetLockState()
+		state := QueueState{
+			Lock: QueueLockState{
+				Locked:   lock.Status == queue.LockedStatus,
+				Revision: lock.Revision,
+			},
+			LatestDeployment: w.GetLatestDeployment(),
+		}
+
+		if w.IsDeploying() {
+			current := w.GetCurrentDeploymentState()
+			revision := toQueuedRevision(current.Deployment)
+			revision.PlanJobURL = current.JobURLs.Plan
+			revision.ApplyJobURL = current.JobURLs.Apply
+			state.CurrentDeployment = &revision
+		}
+
+		for _, info := range q.Scan() {
+			state.Revisions = append(state.Revisions, toQueuedRevision(info))
+		}
+
+		return state, nil
+	})
+}
+
+func setDeploymentStatusQueryHandler(ctx workflow.Context, q queryableQueue, w queryableWorker) error {
+	return workflow.SetQueryHandler(ctx, DeploymentStatusQueryName, func(id string) (DeploymentState, error) {
+		if w.IsDeploying() {
+			if current := w.GetCurrentDeploymentState().Deployment; current.ID.String() == id {
+				return DeploymentState{ID: id, Revision: current.Commit.Revision, Status: RunningDeploymentStatus}, nil
+			}
+		}
+
+		for _, info := range q.Scan() {
+			if info.ID.String() == id {
+				return DeploymentState{ID: id, Revision: info.Commit.Revision, Status: QueuedDeploymentStatus}, nil
+			}
+		}
+
+		if result, ok := w.GetDeploymentResult(id); ok {
+			status := FailureDeploymentStatus
+			if result.Status == queue.SuccessDeploymentResult {
+				status = SuccessDeploymentStatus
+			}
+			return DeploymentState{ID: id, Revision: result.Revision, Status: status}, nil
+		}
+
+		return DeploymentState{ID: id, Status: UnknownDeploymentStatus}, nil
+	})
+}
+
+func toQueuedRevision(info terraform.DeploymentInfo) QueuedRevision {
+	return QueuedRevision{
+		ID:       info.ID.String(),
+		Revision: info.Commit.Revision,
+		Branch:   info.Commit.Branch,
+		User:     info.InitiatingUser.Username,
+		Trigger:  string(info.Root.TriggerInfo.Type),
+	}
+}
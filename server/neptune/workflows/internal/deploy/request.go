@@ -1,8 +1,21 @@
 package deploy
 
+import (
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+)
+
 type Request struct {
 	Repo Repo
 	Root Root
+
+	// ContinueAsNewThreshold is the number of revisions received before the workflow continues as new
+	// in order to bound the size of its history. DefaultContinueAsNewThreshold is used if this isn't set.
+	ContinueAsNewThreshold int
+
+	// State is only set by the workflow itself when continuing as new
+	State *State
 }
 
 // Repo Names and Root Names are assumed to be static throughout the lifetime
@@ -16,3 +29,15 @@ type Repo struct {
 type Root struct {
 	Name string
 }
+
+// State is the in-memory state of a workflow run which is carried forward when continuing as new
+type State struct {
+	Queue            []terraform.DeploymentInfo
+	Lock             queue.LockState
+	LatestDeployment *deployment.Info
+	CheckRuns        map[string]int64
+	Results          []queue.DeploymentResult
+
+	UpstreamDeployments []queue.UpstreamDeploymentSignalRequest
+	Promotions          map[string]string
+}
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/execute"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/request"
@@ -19,18 +20,58 @@ func Root(external request.Root) terraform.Root {
 			Approval: terraform.PlanApproval{
 				Type: terraform.PlanApprovalType(external.PlanApproval.Type),
 			},
+			ApprovalPolicy: approvalPolicy(external.ApprovalPolicy),
 		},
 		Path:      external.RepoRelPath,
 		TfVersion: external.TfVersion,
+		Engine:    valid.Engine(external.Engine),
 		TriggerInfo: terraform.TriggerInfo{
-			Type:  terraform.Trigger(external.TriggerInfo.Type),
-			Force: external.TriggerInfo.Force,
-			Rerun: external.TriggerInfo.Rerun,
+			Type:     terraform.Trigger(external.TriggerInfo.Type),
+			Force:    external.TriggerInfo.Force,
+			Rerun:    external.TriggerInfo.Rerun,
+			Rollback: external.TriggerInfo.Rollback,
 		},
 		Trigger:      terraform.Trigger(external.TriggerInfo.Type),
 		Force:        external.TriggerInfo.Force,
 		Rerun:        external.TriggerInfo.Rerun,
 		TrackedFiles: external.TrackedFiles,
+		DependsOn:    external.DependsOn,
+		Dependents:   external.Dependents,
+
+		PromotionGate: promotionGate(external.PromotionGate),
+	}
+}
+
+func promotionGate(gate *request.PromotionGate) *terraform.PromotionGate {
+	if gate == nil {
+		return nil
+	}
+
+	return &terraform.PromotionGate{
+		Soak:   gate.Soak,
+		Manual: gate.Manual,
+	}
+}
+
+func approvalPolicy(policy *request.PlanApprovalPolicy) *terraform.PlanApprovalPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	var rules []terraform.PlanApprovalRule
+	for _, r := range policy.Rules {
+		rules = append(rules, terraform.PlanApprovalRule{
+			Actions:       r.Actions,
+			ResourceTypes: r.ResourceTypes,
+			Addresses:     r.Addresses,
+			Approval:      terraform.PlanApprovalType(r.Approval),
+		})
+	}
+
+	return &terraform.PlanApprovalPolicy{
+		Default:    terraform.PlanApprovalType(policy.Default),
+		MaxChanges: policy.MaxChanges,
+		Rules:      rules,
 	}
 }
 
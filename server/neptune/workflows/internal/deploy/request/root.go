@@ -1,5 +1,7 @@
 package request
 
+import "time"
+
 type PlanMode string
 
 const (
@@ -11,6 +13,9 @@ type TriggerInfo struct {
 	Type  Trigger
 	Force bool
 	Rerun bool
+
+	// Rollback deploys a revision even if it's behind the latest deployed revision
+	Rollback bool
 }
 
 type Trigger string
@@ -31,6 +36,19 @@ const (
 	ManualApproval
 )
 
+type PlanApprovalPolicy struct {
+	Default    PlanApprovalType
+	MaxChanges int
+	Rules      []PlanApprovalRule
+}
+
+type PlanApprovalRule struct {
+	Actions       []string
+	ResourceTypes []string
+	Addresses     []string
+	Approval      PlanApprovalType
+}
+
 type Root struct {
 	Name         string
 	Apply        Job
@@ -38,16 +56,39 @@ type Root struct {
 	RepoRelPath  string
 	TrackedFiles []string
 	TfVersion    string
+	// Engine is either terraform or opentofu, empty defaults to terraform
+	Engine       string
 	PlanMode     PlanMode
 	PlanApproval PlanApproval
 	TriggerInfo  TriggerInfo
 
+	// ApprovalPolicy is optional and overrides the default approval behavior
+	ApprovalPolicy *PlanApprovalPolicy
+
+	// DependsOn are roots deployed for the same revision which have to deploy it successfully first
+	DependsOn []string
+
+	// Dependents are roots deployed for the same revision which depend on this one
+	Dependents []string
+
+	// PromotionGate is only set for roots which are promoted to from an earlier stage
+	// of a promotion chain and is applied once the roots in DependsOn have deployed
+	PromotionGate *PromotionGate
+
 	// todo: keeping for backwards compatibility with existing workflows
 	// remove once ALL workers are reading the new field.
 	Trigger Trigger
 	Rerun   bool
 }
 
+type PromotionGate struct {
+	// Soak is how long to wait after the upstream roots deploy the revision
+	Soak time.Duration
+
+	// Manual requires a user to promote the revision once it's done soaking
+	Manual bool
+}
+
 type Job struct {
 	Steps []Step
 }
@@ -3,6 +3,9 @@ package queue
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
 
 	key "github.com/runatlantis/atlantis/server/neptune/context"
@@ -30,12 +33,13 @@ func NewValidationError(msg string, format ...interface{}) *ValidationError {
 }
 
 type terraformWorkflowRunner interface {
-	Run(ctx workflow.Context, deploymentInfo terraform.DeploymentInfo, planApprovalOverride terraformActivities.PlanApproval, scope metrics.Scope) error
+	Run(ctx workflow.Context, deploymentInfo terraform.DeploymentInfo, planApprovalOverride terraformActivities.PlanApproval, scope metrics.Scope) (terraform.Result, error)
 }
 
 type dbActivities interface {
 	FetchLatestDeployment(ctx context.Context, request activities.FetchLatestDeploymentRequest) (activities.FetchLatestDeploymentResponse, error)
 	StoreLatestDeployment(ctx context.Context, request activities.StoreLatestDeploymentRequest) error
+	AppendDeploymentHistory(ctx context.Context, request activities.AppendDeploymentHistoryRequest) error
 }
 
 type githubActivities interface {
@@ -53,6 +57,13 @@ type Deployer struct {
 	TerraformWorkflowRunner terraformWorkflowRunner
 	GithubCheckRunCache     CheckRunClient
 	PRRevisionWorkflow      Workflow
+
+	// Upstream is optional, revisions aren't ordered against other roots without it
+	Upstream *UpstreamDeployments
+
+	// Preempted is optional and reports whether higher priority deployments are queued, revisions
+	// waiting on upstream roots or a promotion are requeued behind them instead of holding them up.
+	Preempted func() bool
 }
 
 type Workflow func(ctx workflow.Context, request prrevision.Request) error
@@ -64,11 +75,34 @@ const (
 )
 
 func (p *Deployer) Deploy(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, latestDeployment *deployment.Info, scope metrics.Scope) (*deployment.Info, error) {
+	info, err := p.deploy(ctx, requestedDeployment, latestDeployment, scope)
+
+	// preempted revisions are requeued, dependents are notified once they're deployed
+	var preemptedErr *PreemptedError
+	if errors.As(err, &preemptedErr) {
+		return info, err
+	}
+
+	// dependents are notified regardless of the outcome so they don't wait on this root
+	p.notifyDependents(ctx, requestedDeployment, err, scope)
+
+	if p.Upstream != nil {
+		p.Upstream.Promotions.Remove(requestedDeployment.ID.String())
+	}
+	return info, err
+}
+
+func (p *Deployer) deploy(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, latestDeployment *deployment.Info, scope metrics.Scope) (*deployment.Info, error) {
+	if err := p.awaitUpstreamDeployments(ctx, requestedDeployment, scope); err != nil {
+		return nil, err
+	}
+
 	commitDirection, err := p.getDeployRequestCommitDirection(ctx, requestedDeployment, latestDeployment, scope)
 	if err != nil {
 		return nil, err
 	}
-	if commitDirection == activities.DirectionBehind {
+	// rollbacks deliberately deploy an earlier revision, their plans are always confirmed instead
+	if commitDirection == activities.DirectionBehind && !requestedDeployment.Root.TriggerInfo.Rollback {
 		scope.Counter("invalid_commit_direction_err").Inc(1)
 		// always returns error for caller to skip revision
 		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunFailure, DirectionBehindSummary, nil)
@@ -81,35 +115,201 @@ func (p *Deployer) Deploy(ctx workflow.Context, requestedDeployment terraform.De
 		return nil, NewValidationError("requested revision %s is a re-run attempt but not identical to the latest deployed revision %s", requestedDeployment.Commit.Revision, latestDeployment.Revision)
 	}
 
+	startedAt := workflow.Now(ctx)
+
 	// don't wrap this err as it's not necessary and will mess with any err type assertions we might need to do
-	err = p.TerraformWorkflowRunner.Run(
+	result, err := p.TerraformWorkflowRunner.Run(
 		ctx,
 		requestedDeployment,
 		terraform.BuildPlanApproval(requestedDeployment, latestDeployment, commitDirection, scope),
 		scope,
 	)
 
-	// No need to persist deployment if it's a PlanRejectionError
+	// No need to persist deployment if it's a PlanRejectionError, the rejection is still recorded in the history
 	if _, ok := err.(*terraform.PlanRejectionError); ok {
+		p.appendRejectedDeploymentHistory(ctx, requestedDeployment, result, startedAt, scope)
 		return nil, err
 	}
 
+	info := requestedDeployment.BuildPersistableInfo()
+	info.PlanReview = result.PlanReview
+	info.PlanJobURL = result.JobURLs.Plan
+	info.ApplyJobURL = result.JobURLs.Apply
+	info.PromotedBy = p.promoter(requestedDeployment)
+	if requestedDeployment.Root.TriggerInfo.Rollback && latestDeployment != nil {
+		info.RolledBackRevision = latestDeployment.Revision
+	}
+
+	outcome := deployment.SuccessOutcome
+	if err != nil {
+		outcome = deployment.FailureOutcome
+	}
+	entry := buildHistoryEntry(requestedDeployment, info, result, outcome, startedAt, workflow.Now(ctx))
+
 	// log error and continue deploys if any of the post deploy task fails
-	if err := p.runPostDeployTasks(ctx, requestedDeployment, scope); err != nil {
+	if err := p.runPostDeployTasks(ctx, requestedDeployment, info, entry, scope); err != nil {
 		workflow.GetLogger(ctx).Error("error running post deploy tasks", key.ErrKey, err)
 	}
 
 	// Count this as deployment as latest if it's not a PlanRejectionError which means it is a TerraformClientError
 	// We do this as a safety measure to avoid deploying out of order revision after a failed deploy since it could still
 	// mutate the state file
-	return requestedDeployment.BuildPersistableInfo(), err
+	return info, err
+}
+
+// awaitUpstreamDeployments blocks until the roots this root depends on have deployed the requested revision and
+// returns a validation error if any of them failed to.
+func (p *Deployer) awaitUpstreamDeployments(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, scope metrics.Scope) error {
+	roots := requestedDeployment.Root.DependsOn
+	if len(roots) == 0 || p.Upstream == nil {
+		return nil
+	}
+
+	if workflow.GetVersion(ctx, version.UpstreamDeployments, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return nil
+	}
+
+	// manual deploys and rollbacks are explicitly requested for this root so they aren't ordered against upstream roots
+	if requestedDeployment.Root.TriggerInfo.Type == terraformActivities.ManualTrigger {
+		return nil
+	}
+
+	// waits are canceled once higher priority deployments are queued, check runs are still updated with ctx
+	waitCtx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	var preempted bool
+	if p.Preempted != nil {
+		workflow.Go(waitCtx, func(ctx workflow.Context) {
+			if err := workflow.Await(ctx, p.Preempted); err == nil {
+				preempted = true
+				cancel()
+			}
+		})
+	}
+
+	err := p.awaitUpstream(ctx, waitCtx, requestedDeployment, scope)
+	if preempted {
+		scope.Counter("upstream_wait_preempted").Inc(1)
+		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunQueued, PreemptedSummary, nil)
+		return &PreemptedError{Revision: requestedDeployment.Commit.Revision}
+	}
+	return err
+}
+
+// awaitUpstream waits on upstream roots and the promotion gate with waitCtx, which can be canceled independently of ctx.
+func (p *Deployer) awaitUpstream(ctx workflow.Context, waitCtx workflow.Context, requestedDeployment terraform.DeploymentInfo, scope metrics.Scope) error {
+	roots := requestedDeployment.Root.DependsOn
+	rootList := strings.Join(roots, ", ")
+	p.updateCheckRun(ctx, requestedDeployment, github.CheckRunQueued, fmt.Sprintf(UpstreamWaitingSummary, rootList), nil)
+
+	failed, err := p.Upstream.Await(waitCtx, requestedDeployment.Commit.Revision, roots)
+
+	var timeoutErr *UpstreamTimeoutError
+	if errors.As(err, &timeoutErr) {
+		scope.Counter("upstream_timeout_err").Inc(1)
+		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunFailure, fmt.Sprintf(UpstreamTimeoutSummary, strings.Join(timeoutErr.Roots, ", "), p.Upstream.Timeout), nil)
+		return NewValidationError("requested revision %s timed out waiting on upstream roots %s", requestedDeployment.Commit.Revision, strings.Join(timeoutErr.Roots, ", "))
+	}
+
+	// only happens when the worker is shutting down or the wait is preempted
+	if err != nil {
+		return NewValidationError("waiting on upstream roots: %s", err)
+	}
+
+	if len(failed) > 0 {
+		scope.Counter("upstream_failure_err").Inc(1)
+		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunFailure, fmt.Sprintf(UpstreamFailureSummary, strings.Join(failed, ", ")), nil)
+		return NewValidationError("upstream roots %s failed to deploy requested revision %s", strings.Join(failed, ", "), requestedDeployment.Commit.Revision)
+	}
+
+	return p.awaitPromotion(ctx, waitCtx, requestedDeployment, scope)
 }
 
-func (p *Deployer) runPostDeployTasks(ctx workflow.Context, deployment terraform.DeploymentInfo, scope metrics.Scope) error {
-	if err := p.persistLatestDeployment(ctx, deployment.BuildPersistableInfo()); err != nil {
+// awaitPromotion soaks the requested revision after upstream roots deploy it and then waits
+// for it to be promoted if the root is behind a manual gate.
+func (p *Deployer) awaitPromotion(ctx workflow.Context, waitCtx workflow.Context, requestedDeployment terraform.DeploymentInfo, scope metrics.Scope) error {
+	gate := requestedDeployment.Root.PromotionGate
+	if gate == nil {
+		return nil
+	}
+
+	if workflow.GetVersion(ctx, version.PromotionGate, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return nil
+	}
+
+	roots := requestedDeployment.Root.DependsOn
+	rootList := strings.Join(roots, ", ")
+
+	// the soak starts once upstream roots deploy, which might have been a while ago if this root was busy
+	soakEnd := p.Upstream.CompletedAt(requestedDeployment.Commit.Revision, roots).Add(gate.Soak)
+	if remaining := soakEnd.Sub(workflow.Now(ctx)); gate.Soak > 0 && remaining > 0 {
+		scope.Counter("promotion_soak").Inc(1)
+		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunQueued, fmt.Sprintf(PromotionSoakingSummary, rootList, soakEnd.Format(time.RFC1123)), nil)
+		if err := workflow.Sleep(waitCtx, remaining); err != nil {
+			return NewValidationError("soaking requested revision: %s", err)
+		}
+	}
+
+	if !gate.Manual {
+		return nil
+	}
+
+	p.updateCheckRun(ctx, requestedDeployment, github.CheckRunActionRequired, fmt.Sprintf(PromotionGateSummary, rootList), []github.CheckRunAction{github.CreatePromoteAction()})
+
+	user, promoted := p.Upstream.Promotions.Await(waitCtx, requestedDeployment.ID.String())
+	if waitCtx.Err() != nil {
+		return NewValidationError("waiting on promotion: %s", waitCtx.Err())
+	}
+
+	if !promoted {
+		scope.Counter("promotion_timeout_err").Inc(1)
+		p.updateCheckRun(ctx, requestedDeployment, github.CheckRunFailure, fmt.Sprintf(PromotionTimeoutSummary, p.Upstream.Promotions.Timeout), nil)
+		return NewValidationError("requested revision %s wasn't promoted within %s", requestedDeployment.Commit.Revision, p.Upstream.Promotions.Timeout)
+	}
+
+	scope.Counter("promoted").Inc(1)
+	workflow.GetLogger(ctx).Info("revision promoted", "user", user)
+	p.updateCheckRun(ctx, requestedDeployment, github.CheckRunQueued, fmt.Sprintf(PromotedSummary, user), nil)
+	return nil
+}
+
+// promoter returns the user which promoted the requested deployment past its manual gate, if any.
+func (p *Deployer) promoter(requestedDeployment terraform.DeploymentInfo) string {
+	if p.Upstream == nil {
+		return ""
+	}
+	user, _ := p.Upstream.Promotions.Promoter(requestedDeployment.ID.String())
+	return user
+}
+
+func (p *Deployer) notifyDependents(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, deployErr error, scope metrics.Scope) {
+	if len(requestedDeployment.Root.Dependents) == 0 {
+		return
+	}
+
+	if workflow.GetVersion(ctx, version.UpstreamDeployments, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return
+	}
+
+	status := SuccessDeploymentResult
+	if deployErr != nil {
+		status = FailureDeploymentResult
+	}
+	notifyDependents(ctx, requestedDeployment, status, scope)
+}
+
+func (p *Deployer) runPostDeployTasks(ctx workflow.Context, deployment terraform.DeploymentInfo, info *deployment.Info, entry *deployment.HistoryEntry, scope metrics.Scope) error {
+	if err := p.persistLatestDeployment(ctx, info); err != nil {
 		return errors.Wrap(err, "persisting deployment")
 	}
 
+	// the history is only used for audits so failing to append to it shouldn't block anything else
+	if err := p.appendDeploymentHistory(ctx, entry); err != nil {
+		scope.Counter("history_append_error").Inc(1)
+		workflow.GetLogger(ctx).Error("error appending deployment history", key.ErrKey, err)
+	}
+
 	if err := p.startPRRevisionWorkflow(ctx, deployment); err != nil {
 		scope.Counter("prrevision_start_error").Inc(1)
 		return errors.Wrap(err, "starting PR Revision workflow")
@@ -209,3 +409,55 @@ func (p *Deployer) persistLatestDeployment(ctx workflow.Context, deploymentInfo
 	}
 	return nil
 }
+
+func (p *Deployer) appendDeploymentHistory(ctx workflow.Context, entry *deployment.HistoryEntry) error {
+	if workflow.GetVersion(ctx, version.DeploymentHistory, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return nil
+	}
+
+	err := workflow.ExecuteActivity(ctx, p.Activities.AppendDeploymentHistory, activities.AppendDeploymentHistoryRequest{
+		Entry: entry,
+	}).Get(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "appending deployment history")
+	}
+	return nil
+}
+
+// appendRejectedDeploymentHistory records who rejected the plan, the history is only used for audits so errors are logged
+func (p *Deployer) appendRejectedDeploymentHistory(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, result terraform.Result, startedAt time.Time, scope metrics.Scope) {
+	if workflow.GetVersion(ctx, version.RejectedDeploymentHistory, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return
+	}
+
+	info := requestedDeployment.BuildPersistableInfo()
+	info.PlanReview = result.PlanReview
+	info.PlanJobURL = result.JobURLs.Plan
+	info.PromotedBy = p.promoter(requestedDeployment)
+
+	entry := buildHistoryEntry(requestedDeployment, info, result, deployment.RejectedOutcome, startedAt, workflow.Now(ctx))
+	if err := p.appendDeploymentHistory(ctx, entry); err != nil {
+		scope.Counter("history_append_error").Inc(1)
+		workflow.GetLogger(ctx).Error("error appending deployment history", key.ErrKey, err)
+	}
+}
+
+func buildHistoryEntry(requestedDeployment terraform.DeploymentInfo, info *deployment.Info, result terraform.Result, outcome deployment.Outcome, startedAt time.Time, completedAt time.Time) *deployment.HistoryEntry {
+	return &deployment.HistoryEntry{
+		Version:     deployment.HistorySchemaVersion,
+		ID:          info.ID,
+		Revision:    info.Revision,
+		Branch:      info.Branch,
+		User:        requestedDeployment.InitiatingUser.Username,
+		Repo:        info.Repo,
+		Root:        info.Root,
+		Changes:     deployment.NewResourceChanges(result.PlanSummary),
+		PlanReview:  info.PlanReview,
+		Outcome:     outcome,
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+
+		RolledBackRevision: info.RolledBackRevision,
+		PromotedBy:         info.PromotedBy,
+	}
+}
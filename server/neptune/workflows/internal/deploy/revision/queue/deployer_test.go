@@ -39,15 +39,18 @@ func testPRRevWorkflow(ctx workflow.Context, request prrevision.Request) error {
 type testTerraformWorkflowRunner struct {
 	expectedDeployment terraform.DeploymentInfo
 	expectedErrorType  ErrorType
+	planReview         *deployment.PlanReview
+	planSummary        model.PlanSummary
+	jobURLs            terraform.JobURLs
 }
 
-func (r testTerraformWorkflowRunner) Run(ctx workflow.Context, deploymentInfo terraform.DeploymentInfo, PlanApproval model.PlanApproval, scope metrics.Scope) error {
+func (r testTerraformWorkflowRunner) Run(ctx workflow.Context, deploymentInfo terraform.DeploymentInfo, PlanApproval model.PlanApproval, scope metrics.Scope) (terraform.Result, error) {
 	if r.expectedErrorType == PlanRejectionError {
-		return terraform.NewPlanRejectionError("plan rejected")
+		return terraform.Result{PlanReview: r.planReview, PlanSummary: r.planSummary, JobURLs: r.jobURLs}, terraform.NewPlanRejectionError("plan rejected")
 	} else if r.expectedErrorType == TerraformClientError {
-		return activities.NewTerraformClientError(errors.New("error"))
+		return terraform.Result{PlanSummary: r.planSummary}, activities.NewTerraformClientError(errors.New("error"))
 	}
-	return nil
+	return terraform.Result{PlanReview: r.planReview, PlanSummary: r.planSummary, JobURLs: r.jobURLs}, nil
 }
 
 type testDeployActivity struct{}
@@ -60,10 +63,18 @@ func (t *testDeployActivity) StoreLatestDeployment(ctx context.Context, deployer
 	return nil
 }
 
+func (t *testDeployActivity) AppendDeploymentHistory(ctx context.Context, deployerRequest activities.AppendDeploymentHistoryRequest) error {
+	return nil
+}
+
 func (t *testDeployActivity) GithubCompareCommit(ctx context.Context, deployerRequest activities.CompareCommitRequest) (activities.CompareCommitResponse, error) {
 	return activities.CompareCommitResponse{}, nil
 }
 
+func (t *testDeployActivity) FetchFreezeState(ctx context.Context, request activities.FetchFreezeStateRequest) (activities.FetchFreezeStateResponse, error) {
+	return activities.FetchFreezeStateResponse{}, nil
+}
+
 func (t *testDeployActivity) GithubUpdateCheckRun(ctx context.Context, deployerRequest activities.UpdateCheckRunRequest) (activities.UpdateCheckRunResponse, error) {
 	return activities.UpdateCheckRunResponse{}, nil
 }
@@ -74,6 +85,9 @@ type deployerRequest struct {
 	ErrType           ErrorType
 	ExpectedGHRequest notifier.GithubCheckRunRequest
 	ExpectedT         *testing.T
+	PlanReview        *deployment.PlanReview
+	PlanSummary       model.PlanSummary
+	JobURLs           terraform.JobURLs
 }
 
 func testDeployerWorkflow(ctx workflow.Context, r deployerRequest) (*deployment.Info, error) {
@@ -89,6 +103,9 @@ func testDeployerWorkflow(ctx workflow.Context, r deployerRequest) (*deployment.
 		TerraformWorkflowRunner: &testTerraformWorkflowRunner{
 			expectedDeployment: r.Info,
 			expectedErrorType:  r.ErrType,
+			planReview:         r.PlanReview,
+			planSummary:        r.PlanSummary,
+			jobURLs:            r.JobURLs,
 		},
 		GithubCheckRunCache: &testCheckRunClient{
 			expectedRequest:      r.ExpectedGHRequest,
@@ -174,6 +191,78 @@ func TestDeployer_FirstDeploy(t *testing.T) {
 	assert.Equal(t, latestDeployedRevision, resp)
 }
 
+func TestDeployer_PersistsPlanReviewAndJobURLs(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	repo := github.Repo{
+		Owner: "owner",
+		Name:  "test",
+	}
+
+	root := model.Root{
+		Name: "root_1",
+	}
+
+	deploymentInfo := terraform.DeploymentInfo{
+		ID: uuid.UUID{},
+		Commit: github.Commit{
+			Revision: "3455",
+			Branch:   "default-branch",
+		},
+		CheckRunID: 1234,
+		Root:       root,
+		Repo:       repo,
+	}
+
+	planReview := &deployment.PlanReview{
+		User:   "nish",
+		Reason: "expected deletion",
+	}
+
+	storeDeploymentRequest := activities.StoreLatestDeploymentRequest{
+		DeploymentInfo: &deployment.Info{
+			Version:  deployment.InfoSchemaVersion,
+			ID:       deploymentInfo.ID.String(),
+			Revision: deploymentInfo.Commit.Revision,
+			Branch:   deploymentInfo.Commit.Branch,
+			Root: deployment.Root{
+				Name: deploymentInfo.Root.Name,
+			},
+			Repo: deployment.Repo{
+				Owner: deploymentInfo.Repo.Owner,
+				Name:  deploymentInfo.Repo.Name,
+			},
+			PlanReview:  planReview,
+			PlanJobURL:  "https://atlantis.com/jobs/plan",
+			ApplyJobURL: "https://atlantis.com/jobs/apply",
+		},
+	}
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, storeDeploymentRequest).Return(nil)
+
+	env.ExecuteWorkflow(testDeployerWorkflow, deployerRequest{
+		Info:       deploymentInfo,
+		PlanReview: planReview,
+		JobURLs: terraform.JobURLs{
+			Plan:  "https://atlantis.com/jobs/plan",
+			Apply: "https://atlantis.com/jobs/apply",
+		},
+	})
+
+	env.AssertExpectations(t)
+
+	var resp *deployment.Info
+	err := env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+
+	assert.Equal(t, storeDeploymentRequest.DeploymentInfo, resp)
+}
+
 func TestDeployer_CompareCommit_DeployAhead(t *testing.T) {
 	ts := testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
@@ -561,6 +650,100 @@ func TestDeployer_CompareCommit_DeployDiverged(t *testing.T) {
 	}, resp)
 }
 
+func TestDeployer_CompareCommit_DeployRollback(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	repo := github.Repo{
+		Owner: "owner",
+		Name:  "test",
+	}
+
+	root := model.Root{
+		Name: "root_1",
+		TriggerInfo: model.TriggerInfo{
+			Type:     model.ManualTrigger,
+			Rollback: true,
+		},
+	}
+
+	deploymentInfo := terraform.DeploymentInfo{
+		ID: uuid.UUID{},
+		Commit: github.Commit{
+			Revision: "3255",
+			Branch:   "default-branch",
+		},
+		CheckRunID: 1234,
+		Root:       root,
+		Repo:       repo,
+	}
+
+	latestDeployedRevision := &deployment.Info{
+		ID:       deploymentInfo.ID.String(),
+		Version:  1.0,
+		Revision: "3455",
+		Branch:   "default-branch",
+		Root: deployment.Root{
+			Name: deploymentInfo.Root.Name,
+		},
+		Repo: deployment.Repo{
+			Owner: deploymentInfo.Repo.Owner,
+			Name:  deploymentInfo.Repo.Name,
+		},
+	}
+
+	expectedInfo := &deployment.Info{
+		Version:  deployment.InfoSchemaVersion,
+		ID:       deploymentInfo.ID.String(),
+		Revision: deploymentInfo.Commit.Revision,
+		Branch:   deploymentInfo.Commit.Branch,
+		Root: deployment.Root{
+			Name:           deploymentInfo.Root.Name,
+			Trigger:        string(model.ManualTrigger),
+			ManualRollback: true,
+		},
+		Repo: deployment.Repo{
+			Owner: deploymentInfo.Repo.Owner,
+			Name:  deploymentInfo.Repo.Name,
+		},
+		RolledBackRevision: "3455",
+	}
+
+	compareCommitRequest := activities.CompareCommitRequest{
+		Repo:                   repo,
+		DeployRequestRevision:  deploymentInfo.Commit.Revision,
+		LatestDeployedRevision: latestDeployedRevision.Revision,
+	}
+
+	compareCommitResponse := activities.CompareCommitResponse{
+		CommitComparison: activities.DirectionBehind,
+	}
+
+	env.OnActivity(da.GithubCompareCommit, mock.Anything, compareCommitRequest).Return(compareCommitResponse, nil)
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, activities.StoreLatestDeploymentRequest{
+		DeploymentInfo: expectedInfo,
+	}).Return(nil)
+	env.OnActivity(da.AppendDeploymentHistory, mock.Anything, mock.MatchedBy(func(request activities.AppendDeploymentHistoryRequest) bool {
+		return request.Entry.RolledBackRevision == "3455" && request.Entry.Root.ManualRollback
+	})).Return(nil)
+
+	env.ExecuteWorkflow(testDeployerWorkflow, deployerRequest{
+		Info:         deploymentInfo,
+		LatestDeploy: latestDeployedRevision,
+	})
+
+	env.AssertExpectations(t)
+
+	var resp *deployment.Info
+	err := env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedInfo, resp)
+}
+
 func TestDeployer_WorkflowFailure_PlanRejection_SkipUpdateLatestDeployment(t *testing.T) {
 	ts := testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
@@ -727,6 +910,117 @@ func TestDeployer_TerraformClientError_UpdateLatestDeployment(t *testing.T) {
 	assert.Equal(t, "TerraformClientError", appErr.Type())
 }
 
+func TestDeployer_AppendsDeploymentHistory(t *testing.T) {
+	deploymentInfo := terraform.DeploymentInfo{
+		ID: uuid.UUID{},
+		Commit: github.Commit{
+			Revision: "3455",
+			Branch:   "default-branch",
+		},
+		InitiatingUser: github.User{Username: "nish"},
+		CheckRunID:     1234,
+		Root:           model.Root{Name: "root_1"},
+		Repo:           github.Repo{Owner: "owner", Name: "test"},
+	}
+
+	planSummary := model.PlanSummary{
+		Updates: []model.ResourceSummary{{
+			Address:          "addr",
+			AttributeChanges: []model.AttributeChange{{Path: "tags.name", Before: "a", After: "b"}},
+		}},
+	}
+
+	// attribute values aren't persisted
+	changes := []deployment.ResourceChange{{Address: "addr", Action: deployment.UpdateAction}}
+
+	t.Run("failed deploy", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+		env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+		da := &testDeployActivity{}
+		env.RegisterActivity(da)
+
+		env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+		env.OnActivity(da.AppendDeploymentHistory, mock.Anything, mock.MatchedBy(func(request activities.AppendDeploymentHistoryRequest) bool {
+			entry := request.Entry
+			return entry.Version == deployment.HistorySchemaVersion &&
+				entry.ID == deploymentInfo.ID.String() &&
+				entry.Revision == "3455" &&
+				entry.Branch == "default-branch" &&
+				entry.User == "nish" &&
+				entry.Repo == deployment.Repo{Owner: "owner", Name: "test"} &&
+				entry.Root.Name == "root_1" &&
+				assert.ObjectsAreEqual(changes, entry.Changes) &&
+				entry.Outcome == deployment.FailureOutcome &&
+				!entry.StartedAt.IsZero() &&
+				!entry.CompletedAt.Before(entry.StartedAt)
+		})).Return(nil)
+
+		env.ExecuteWorkflow(testDeployerWorkflow, deployerRequest{
+			Info:        deploymentInfo,
+			ErrType:     TerraformClientError,
+			PlanSummary: planSummary,
+		})
+
+		env.AssertExpectations(t)
+		assert.Error(t, env.GetWorkflowError())
+	})
+
+	t.Run("rejected plan", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+
+		da := &testDeployActivity{}
+		env.RegisterActivity(da)
+
+		review := &deployment.PlanReview{User: "reviewer", Reason: "unexpected deletes"}
+		env.OnActivity(da.AppendDeploymentHistory, mock.Anything, mock.MatchedBy(func(request activities.AppendDeploymentHistoryRequest) bool {
+			entry := request.Entry
+			return entry.ID == deploymentInfo.ID.String() &&
+				entry.Revision == "3455" &&
+				entry.User == "nish" &&
+				assert.ObjectsAreEqual(changes, entry.Changes) &&
+				assert.ObjectsAreEqual(review, entry.PlanReview) &&
+				entry.Outcome == deployment.RejectedOutcome
+		})).Return(nil).Once()
+
+		env.ExecuteWorkflow(testDeployerWorkflow, deployerRequest{
+			Info:        deploymentInfo,
+			ErrType:     PlanRejectionError,
+			PlanSummary: planSummary,
+			PlanReview:  review,
+		})
+
+		// the rejected revision isn't persisted as the latest deployment
+		env.AssertExpectations(t)
+		env.AssertNumberOfCalls(t, "StoreLatestDeployment", 0)
+		assert.Error(t, env.GetWorkflowError())
+	})
+
+	t.Run("workflows started before history", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+		env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+		env.OnGetVersion(version.DeploymentHistory, workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+
+		da := &testDeployActivity{}
+		env.RegisterActivity(da)
+
+		env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+		env.OnActivity(da.AppendDeploymentHistory, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		env.ExecuteWorkflow(testDeployerWorkflow, deployerRequest{
+			Info:        deploymentInfo,
+			PlanSummary: planSummary,
+		})
+
+		env.AssertExpectations(t)
+		env.AssertNumberOfCalls(t, "AppendDeploymentHistory", 0)
+		assert.NoError(t, env.GetWorkflowError())
+	})
+}
+
 func TestDeployer_SetPRRevision(t *testing.T) {
 	ts := testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
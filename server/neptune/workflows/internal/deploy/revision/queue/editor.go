@@ -0,0 +1,147 @@
+package queue
+
+import (
+	"fmt"
+
+	metricNames "github.com/runatlantis/atlantis/server/events/metrics"
+	key "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/version"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	RemoveRevisionSignalName   = "remove-revision"
+	ReorderRevisionsSignalName = "reorder-revisions"
+
+	RemovedRevisionSummary = "This revision was removed from the deploy queue by %s."
+)
+
+type RemoveRevisionSignalRequest struct {
+	DeploymentID string
+	User         string
+}
+
+type ReorderRevisionsSignalRequest struct {
+	// DeploymentIDs are moved to the front of the queue in the order provided
+	DeploymentIDs []string
+	User          string
+}
+
+type editableQueue interface {
+	Remove(id string) (terraform.DeploymentInfo, bool)
+	ReorderMergedItems(ids []string)
+}
+
+// Editor handles signals from administrators which manipulate the contents of the queue.
+type Editor struct {
+	Queue          editableQueue
+	CheckRunClient CheckRunClient
+
+	RemoveSignalChannel  workflow.ReceiveChannel
+	ReorderSignalChannel workflow.ReceiveChannel
+}
+
+func NewEditor(ctx workflow.Context, queue editableQueue, checkRunClient CheckRunClient) *Editor {
+	return &Editor{
+		Queue:                queue,
+		CheckRunClient:       checkRunClient,
+		RemoveSignalChannel:  workflow.GetSignalChannel(ctx, RemoveRevisionSignalName),
+		ReorderSignalChannel: workflow.GetSignalChannel(ctx, ReorderRevisionsSignalName),
+	}
+}
+
+// Run blocks and processes edit signals until the context is canceled.
+func (e *Editor) Run(ctx workflow.Context) {
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(e.RemoveSignalChannel, func(c workflow.ReceiveChannel, more bool) {
+		var request RemoveRevisionSignalRequest
+		c.Receive(ctx, &request)
+		e.remove(ctx, request)
+	})
+	selector.AddReceive(e.ReorderSignalChannel, func(c workflow.ReceiveChannel, more bool) {
+		var request ReorderRevisionsSignalRequest
+		c.Receive(ctx, &request)
+		e.reorder(ctx, request)
+	})
+	selector.AddFuture(awaitCancellation(ctx), func(f workflow.Future) {})
+
+	for ctx.Err() == nil {
+		selector.Select(ctx)
+	}
+}
+
+// Drain applies any buffered edit signals without blocking, this is used to ensure
+// they aren't dropped when the workflow continues as new.
+func (e *Editor) Drain(ctx workflow.Context) {
+	var removeRequest RemoveRevisionSignalRequest
+	for e.RemoveSignalChannel.ReceiveAsync(&removeRequest) {
+		e.remove(ctx, removeRequest)
+	}
+
+	var reorderRequest ReorderRevisionsSignalRequest
+	for e.ReorderSignalChannel.ReceiveAsync(&reorderRequest) {
+		e.reorder(ctx, reorderRequest)
+	}
+}
+
+func (e *Editor) reorder(ctx workflow.Context, request ReorderRevisionsSignalRequest) {
+	e.emitSignalStat(ctx, ReorderRevisionsSignalName)
+	workflow.GetLogger(ctx).Info("reordering deploy queue", "user", request.User)
+	e.Queue.ReorderMergedItems(request.DeploymentIDs)
+}
+
+func (e *Editor) remove(ctx workflow.Context, request RemoveRevisionSignalRequest) {
+	e.emitSignalStat(ctx, RemoveRevisionSignalName)
+
+	info, ok := e.Queue.Remove(request.DeploymentID)
+	if !ok {
+		workflow.GetLogger(ctx).Warn("attempted to remove deployment which isn't queued", "id", request.DeploymentID)
+		return
+	}
+
+	workflow.GetLogger(ctx).Info("removed revision from deploy queue", "revision", info.Commit.Revision, "user", request.User)
+
+	// dependent roots would otherwise wait on this revision until they time out
+	if len(info.Root.Dependents) > 0 && workflow.GetVersion(ctx, version.UpstreamDeployments, workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		notifyDependents(ctx, info, SkippedDeploymentResult, metrics.NewScope(ctx, "editor"))
+	}
+
+	ctx = workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
+		MaximumAttempts: UpdateCheckRunRetryCount,
+	})
+	_, err := e.CheckRunClient.CreateOrUpdate(ctx, info.ID.String(), notifier.GithubCheckRunRequest{
+		Title:   notifier.BuildDeployCheckRunTitle(info.Root.Name),
+		Sha:     info.Commit.Revision,
+		Repo:    info.Repo,
+		State:   github.CheckRunSkipped,
+		Summary: fmt.Sprintf(RemovedRevisionSummary, request.User),
+	})
+	if err != nil {
+		workflow.GetLogger(ctx).Error("unable to update check run for removed revision", key.ErrKey, err)
+	}
+}
+
+func (e *Editor) emitSignalStat(ctx workflow.Context, signalName string) {
+	workflow.GetMetricsHandler(ctx).WithTags(map[string]string{metricNames.SignalNameTag: signalName}).
+		Counter(metricNames.SignalReceive).
+		Inc(1)
+}
+
+func awaitCancellation(ctx workflow.Context) workflow.Future {
+	future, settable := workflow.NewFuture(ctx)
+
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		err := workflow.Await(ctx, func() bool {
+			return false
+		})
+
+		settable.SetError(err)
+	})
+
+	return future
+}
@@ -0,0 +1,175 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	model "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type recordingCheckRunClient struct {
+	DeploymentIDs []string
+	Requests      []notifier.GithubCheckRunRequest
+}
+
+func (c *recordingCheckRunClient) CreateOrUpdate(ctx workflow.Context, deploymentID string, request notifier.GithubCheckRunRequest) (int64, error) {
+	c.DeploymentIDs = append(c.DeploymentIDs, deploymentID)
+	c.Requests = append(c.Requests, request)
+	return 1, nil
+}
+
+type editorRequest struct {
+	Queue []terraform.DeploymentInfo
+}
+
+type editorResponse struct {
+	Queue     []terraform.DeploymentInfo
+	CheckRuns recordingCheckRunClient
+}
+
+func testEditorWorkflow(ctx workflow.Context, r editorRequest) (editorResponse, error) {
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+	for _, i := range r.Queue {
+		q.Push(i)
+	}
+
+	checkRunClient := &recordingCheckRunClient{}
+	editor := queue.NewEditor(ctx, q, checkRunClient)
+
+	editorCtx, cancel := workflow.WithCancel(ctx)
+	wg := workflow.NewWaitGroup(ctx)
+	wg.Add(1)
+	workflow.Go(editorCtx, func(ctx workflow.Context) {
+		defer wg.Done()
+		editor.Run(ctx)
+	})
+
+	_ = workflow.Sleep(ctx, 10*time.Second)
+
+	cancel()
+	wg.Wait(ctx)
+
+	return editorResponse{
+		Queue:     q.Scan(),
+		CheckRuns: *checkRunClient,
+	}, nil
+}
+
+func buildMergedDeployment(revision string) terraform.DeploymentInfo {
+	return terraform.DeploymentInfo{
+		ID: uuid.New(),
+		Commit: github.Commit{
+			Revision: revision,
+		},
+		Repo: github.Repo{
+			Name: "repo",
+		},
+		Root: model.Root{
+			Name: "root",
+			TriggerInfo: model.TriggerInfo{
+				Type: model.MergeTrigger,
+			},
+		},
+	}
+}
+
+func TestEditor_Remove(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	first := buildMergedDeployment("1")
+	second := buildMergedDeployment("2")
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.RemoveRevisionSignalName, queue.RemoveRevisionSignalRequest{
+			DeploymentID: first.ID.String(),
+			User:         "nish",
+		})
+	}, 2*time.Second)
+
+	env.ExecuteWorkflow(testEditorWorkflow, editorRequest{
+		Queue: []terraform.DeploymentInfo{first, second},
+	})
+
+	env.AssertExpectations(t)
+
+	var resp editorResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Equal(t, []terraform.DeploymentInfo{second}, resp.Queue)
+	assert.Equal(t, []string{first.ID.String()}, resp.CheckRuns.DeploymentIDs)
+	assert.Equal(t, []notifier.GithubCheckRunRequest{
+		{
+			Title:   "atlantis/deploy: root",
+			Sha:     "1",
+			Repo:    first.Repo,
+			State:   github.CheckRunSkipped,
+			Summary: "This revision was removed from the deploy queue by nish.",
+		},
+	}, resp.CheckRuns.Requests)
+}
+
+func TestEditor_Remove_NotifiesDependents(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	removed := buildMergedDeployment("1")
+	removed.Repo.Owner = "owner"
+	removed.Root.Dependents = []string{"service"}
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.RemoveRevisionSignalName, queue.RemoveRevisionSignalRequest{
+			DeploymentID: removed.ID.String(),
+			User:         "nish",
+		})
+	}, 2*time.Second)
+	env.OnSignalExternalWorkflow(mock.Anything, "owner/repo||service", "", queue.UpstreamDeploymentSignalName, mock.MatchedBy(func(r queue.UpstreamDeploymentSignalRequest) bool {
+		return r.Root == "root" && r.Revision == "1" && r.Status == queue.SkippedDeploymentResult
+	})).Return(nil).Once()
+
+	env.ExecuteWorkflow(testEditorWorkflow, editorRequest{
+		Queue: []terraform.DeploymentInfo{removed},
+	})
+
+	env.AssertExpectations(t)
+
+	var resp editorResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Queue)
+}
+
+func TestEditor_Reorder(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	first := buildMergedDeployment("1")
+	second := buildMergedDeployment("2")
+	third := buildMergedDeployment("3")
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.ReorderRevisionsSignalName, queue.ReorderRevisionsSignalRequest{
+			DeploymentIDs: []string{third.ID.String()},
+			User:          "nish",
+		})
+	}, 2*time.Second)
+
+	env.ExecuteWorkflow(testEditorWorkflow, editorRequest{
+		Queue: []terraform.DeploymentInfo{first, second, third},
+	})
+
+	env.AssertExpectations(t)
+
+	var resp editorResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Equal(t, []terraform.DeploymentInfo{third, first, second}, resp.Queue)
+	assert.Empty(t, resp.CheckRuns.Requests)
+}
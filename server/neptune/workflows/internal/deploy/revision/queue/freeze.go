@@ -0,0 +1,24 @@
+package queue
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	FrozenSummary = "This deploy is frozen until %s by freeze window %q."
+
+	// FreezeStateRetryInterval is how long merged items are held when freeze windows can't be evaluated
+	FreezeStateRetryInterval = 5 * time.Minute
+	UnknownFreezeWindow      = "unknown"
+	UnknownFreezeReason      = "Freeze windows couldn't be evaluated, merged deploys are held until they can be."
+)
+
+// BuildFrozenSummary describes why merged items are held in the queue
+func BuildFrozenSummary(freeze FreezeState) string {
+	summary := fmt.Sprintf(FrozenSummary, freeze.Until.UTC().Format(time.RFC1123), freeze.Name)
+	if freeze.Reason != "" {
+		summary = fmt.Sprintf("%s  %s", summary, freeze.Reason)
+	}
+	return summary
+}
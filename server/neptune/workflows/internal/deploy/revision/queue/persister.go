@@ -0,0 +1,168 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	key "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	tfModel "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+type persisterActivities interface {
+	FetchDeployQueue(ctx context.Context, request activities.FetchDeployQueueRequest) (activities.FetchDeployQueueResponse, error)
+	StoreDeployQueue(ctx context.Context, request activities.StoreDeployQueueRequest) error
+}
+
+type persistableQueue interface {
+	Push(terraform.DeploymentInfo)
+	Scan() []terraform.DeploymentInfo
+	GetChangeCount() int
+	SetLockForMergedItems(ctx workflow.Context, state LockState)
+}
+
+type inProgressDeployment interface {
+	InProgressDeployment() (terraform.DeploymentInfo, bool)
+}
+
+// Persister snapshots the deploy queue to the deployment store whenever its contents change,
+// this allows us to restore queued revisions in the event that the workflow is terminated.
+type Persister struct {
+	Queue      persistableQueue
+	Activities persisterActivities
+	RepoName   string
+	RootName   string
+
+	// InProgress is the revision popped off the queue which is being deployed, it's persisted ahead of the
+	// queued revisions so it isn't dropped if the workflow terminates mid deploy.  It's nil for workflows
+	// which predate persisting in progress revisions.
+	InProgress inProgressDeployment
+
+	// mutable
+	persistedChangeCount  int
+	persistedInProgressID string
+}
+
+func NewPersister(q persistableQueue, a persisterActivities, repoName, rootName string) *Persister {
+	return &Persister{
+		Queue:                q,
+		Activities:           a,
+		RepoName:             repoName,
+		RootName:             rootName,
+		persistedChangeCount: q.GetChangeCount(),
+	}
+}
+
+// Restore pushes any previously persisted revisions back onto the queue.  Manual deployments
+// lock the queue in the same way they do when they are first received.
+func (p *Persister) Restore(ctx workflow.Context) error {
+	var resp activities.FetchDeployQueueResponse
+	err := workflow.ExecuteActivity(ctx, p.Activities.FetchDeployQueue, activities.FetchDeployQueueRequest{
+		FullRepositoryName: p.RepoName,
+		RootName:           p.RootName,
+	}).Get(ctx, &resp)
+	if err != nil {
+		return errors.Wrap(err, "fetching deploy queue")
+	}
+
+	if resp.Queue == nil {
+		return nil
+	}
+
+	for _, r := range resp.Queue.Revisions {
+		info, err := terraform.NewDeploymentInfoFromQueuedRevision(r)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("unable to restore queued revision, skipping", "revision", r.Revision, key.ErrKey, err)
+			continue
+		}
+
+		if info.Root.TriggerInfo.Type == tfModel.ManualTrigger {
+			p.Queue.SetLockForMergedItems(ctx, LockState{
+				Status:   LockedStatus,
+				Revision: info.Commit.Revision,
+			})
+		}
+		p.Queue.Push(info)
+	}
+
+	// what we've restored is already persisted
+	p.persistedChangeCount = p.Queue.GetChangeCount()
+
+	return nil
+}
+
+// Run blocks and persists the queue every time it changes until the context is canceled.
+// Any outstanding changes are flushed on cancellation.
+func (p *Persister) Run(ctx workflow.Context) {
+	for {
+		err := workflow.Await(ctx, p.hasChanges)
+
+		// Await can return before observing the cancellation if the condition is already met
+		if temporal.IsCanceledError(err) || ctx.Err() != nil {
+			break
+		}
+
+		p.persist(ctx)
+	}
+
+	if p.hasChanges() {
+		// use a disconnected context so we can flush our last changes
+		disconnectedCtx, _ := workflow.NewDisconnectedContext(ctx)
+		p.persist(disconnectedCtx)
+	}
+}
+
+func (p *Persister) hasChanges() bool {
+	return p.Queue.GetChangeCount() != p.persistedChangeCount || p.inProgressID() != p.persistedInProgressID
+}
+
+func (p *Persister) inProgressID() string {
+	if p.InProgress == nil {
+		return ""
+	}
+
+	info, ok := p.InProgress.InProgressDeployment()
+	if !ok {
+		return ""
+	}
+	return info.ID.String()
+}
+
+func (p *Persister) persist(ctx workflow.Context) {
+	changeCount := p.Queue.GetChangeCount()
+	inProgressID := p.inProgressID()
+
+	snapshot := &deployment.Queue{
+		Version: deployment.QueueSchemaVersion,
+	}
+
+	// the in progress revision goes first so it's restored ahead of what was queued behind it
+	if p.InProgress != nil {
+		if info, ok := p.InProgress.InProgressDeployment(); ok {
+			snapshot.Revisions = append(snapshot.Revisions, info.BuildPersistableQueuedRevision())
+		}
+	}
+
+	for _, i := range p.Queue.Scan() {
+		snapshot.Revisions = append(snapshot.Revisions, i.BuildPersistableQueuedRevision())
+	}
+
+	err := workflow.ExecuteActivity(ctx, p.Activities.StoreDeployQueue, activities.StoreDeployQueueRequest{
+		FullRepositoryName: p.RepoName,
+		RootName:           p.RootName,
+		Queue:              snapshot,
+	}).Get(ctx, nil)
+
+	// we'll try again on the next change to the queue
+	if err != nil {
+		workflow.GetLogger(ctx).Error("unable to persist deploy queue", key.ErrKey, err)
+		return
+	}
+
+	p.persistedChangeCount = changeCount
+	p.persistedInProgressID = inProgressID
+}
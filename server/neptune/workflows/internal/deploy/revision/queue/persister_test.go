@@ -0,0 +1,259 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	model "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type testPersisterActivities struct{}
+
+func (a *testPersisterActivities) FetchDeployQueue(ctx context.Context, request activities.FetchDeployQueueRequest) (activities.FetchDeployQueueResponse, error) {
+	return activities.FetchDeployQueueResponse{}, nil
+}
+
+func (a *testPersisterActivities) StoreDeployQueue(ctx context.Context, request activities.StoreDeployQueueRequest) error {
+	return nil
+}
+
+type persisterRequest struct {
+	Push []terraform.DeploymentInfo
+	Pop  int
+}
+
+type persisterResponse struct {
+	Queue []terraform.DeploymentInfo
+	Lock  queue.LockState
+}
+
+func testPersisterWorkflow(ctx workflow.Context, r persisterRequest) (persisterResponse, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	var a *testPersisterActivities
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+
+	persister := queue.NewPersister(q, a, "owner/repo", "root")
+	if err := persister.Restore(ctx); err != nil {
+		return persisterResponse{}, err
+	}
+
+	persisterCtx, cancel := workflow.WithCancel(ctx)
+	wg := workflow.NewWaitGroup(ctx)
+	wg.Add(1)
+	workflow.Go(persisterCtx, func(ctx workflow.Context) {
+		defer wg.Done()
+		persister.Run(ctx)
+	})
+
+	for _, i := range r.Push {
+		q.Push(i)
+		_ = workflow.Sleep(ctx, time.Second)
+	}
+
+	for i := 0; i < r.Pop; i++ {
+		_, _ = q.Pop()
+	}
+
+	cancel()
+	wg.Wait(ctx)
+
+	return persisterResponse{
+		Queue: q.Scan(),
+		Lock:  q.GetLockState(),
+	}, nil
+}
+
+type testInProgressDeployment struct {
+	info *terraform.DeploymentInfo
+}
+
+func (d *testInProgressDeployment) InProgressDeployment() (terraform.DeploymentInfo, bool) {
+	if d.info == nil {
+		return terraform.DeploymentInfo{}, false
+	}
+	return *d.info, true
+}
+
+// testPersisterInProgressWorkflow pops the first revision and deploys it while the persister runs
+func testPersisterInProgressWorkflow(ctx workflow.Context, r persisterRequest) (persisterResponse, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	var a *testPersisterActivities
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+	inProgress := &testInProgressDeployment{}
+
+	persister := queue.NewPersister(q, a, "owner/repo", "root")
+	persister.InProgress = inProgress
+
+	persisterCtx, cancel := workflow.WithCancel(ctx)
+	wg := workflow.NewWaitGroup(ctx)
+	wg.Add(1)
+	workflow.Go(persisterCtx, func(ctx workflow.Context) {
+		defer wg.Done()
+		persister.Run(ctx)
+	})
+
+	for _, i := range r.Push {
+		q.Push(i)
+	}
+	_ = workflow.Sleep(ctx, time.Second)
+
+	info, err := q.Pop()
+	if err != nil {
+		return persisterResponse{}, err
+	}
+	inProgress.info = &info
+	_ = workflow.Sleep(ctx, time.Second)
+
+	inProgress.info = nil
+	_ = workflow.Sleep(ctx, time.Second)
+
+	cancel()
+	wg.Wait(ctx)
+
+	return persisterResponse{
+		Queue: q.Scan(),
+	}, nil
+}
+
+func TestPersister(t *testing.T) {
+	repo := github.Repo{Owner: "owner", Name: "repo"}
+	mergeInfo := terraform.DeploymentInfo{
+		ID:         uuid.New(),
+		CheckRunID: 1,
+		Commit:     github.Commit{Revision: "1234", Branch: "main"},
+		Repo:       repo,
+		Root:       model.Root{Name: "root", TriggerInfo: model.TriggerInfo{Type: model.MergeTrigger}},
+	}
+	manualInfo := terraform.DeploymentInfo{
+		ID:         uuid.New(),
+		CheckRunID: 2,
+		Commit:     github.Commit{Revision: "5678", Branch: "main"},
+		Repo:       repo,
+		Root:       model.Root{Name: "root", TriggerInfo: model.TriggerInfo{Type: model.ManualTrigger}},
+	}
+
+	t.Run("restores persisted revisions", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+
+		a := &testPersisterActivities{}
+		env.RegisterActivity(a)
+		env.OnActivity(a.FetchDeployQueue, mock.Anything, activities.FetchDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+		}).Return(activities.FetchDeployQueueResponse{
+			Queue: &deployment.Queue{
+				Version: deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{
+					mergeInfo.BuildPersistableQueuedRevision(),
+					manualInfo.BuildPersistableQueuedRevision(),
+				},
+			},
+		}, nil)
+
+		env.ExecuteWorkflow(testPersisterWorkflow, persisterRequest{})
+
+		var resp persisterResponse
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		assert.Equal(t, []terraform.DeploymentInfo{manualInfo, mergeInfo}, resp.Queue)
+		assert.Equal(t, queue.LockState{Status: queue.LockedStatus, Revision: "5678"}, resp.Lock)
+		env.AssertNotCalled(t, "StoreDeployQueue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("persists queue changes", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+
+		a := &testPersisterActivities{}
+		env.RegisterActivity(a)
+		env.OnActivity(a.FetchDeployQueue, mock.Anything, mock.Anything).Return(activities.FetchDeployQueueResponse{}, nil)
+		env.OnActivity(a.StoreDeployQueue, mock.Anything, activities.StoreDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+			Queue: &deployment.Queue{
+				Version:   deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{mergeInfo.BuildPersistableQueuedRevision()},
+			},
+		}).Return(nil).Once()
+		env.OnActivity(a.StoreDeployQueue, mock.Anything, activities.StoreDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+			Queue: &deployment.Queue{
+				Version:   deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{manualInfo.BuildPersistableQueuedRevision(), mergeInfo.BuildPersistableQueuedRevision()},
+			},
+		}).Return(nil).Once()
+
+		// the final pop is flushed on shutdown
+		env.OnActivity(a.StoreDeployQueue, mock.Anything, activities.StoreDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+			Queue: &deployment.Queue{
+				Version:   deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{mergeInfo.BuildPersistableQueuedRevision()},
+			},
+		}).Return(nil).Once()
+
+		env.ExecuteWorkflow(testPersisterWorkflow, persisterRequest{
+			Push: []terraform.DeploymentInfo{mergeInfo, manualInfo},
+			Pop:  1,
+		})
+
+		var resp persisterResponse
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		assert.Equal(t, []terraform.DeploymentInfo{mergeInfo}, resp.Queue)
+		env.AssertExpectations(t)
+	})
+	t.Run("persists in progress revision", func(t *testing.T) {
+		ts := testsuite.WorkflowTestSuite{}
+		env := ts.NewTestWorkflowEnvironment()
+
+		a := &testPersisterActivities{}
+		env.RegisterActivity(a)
+
+		// the popped revision remains persisted until it's deployed
+		env.OnActivity(a.StoreDeployQueue, mock.Anything, activities.StoreDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+			Queue: &deployment.Queue{
+				Version:   deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{manualInfo.BuildPersistableQueuedRevision(), mergeInfo.BuildPersistableQueuedRevision()},
+			},
+		}).Return(nil).Times(2)
+		env.OnActivity(a.StoreDeployQueue, mock.Anything, activities.StoreDeployQueueRequest{
+			FullRepositoryName: "owner/repo",
+			RootName:           "root",
+			Queue: &deployment.Queue{
+				Version:   deployment.QueueSchemaVersion,
+				Revisions: []deployment.QueuedRevision{mergeInfo.BuildPersistableQueuedRevision()},
+			},
+		}).Return(nil).Once()
+
+		env.ExecuteWorkflow(testPersisterInProgressWorkflow, persisterRequest{
+			Push: []terraform.DeploymentInfo{mergeInfo, manualInfo},
+		})
+
+		var resp persisterResponse
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		assert.Equal(t, []terraform.DeploymentInfo{mergeInfo}, resp.Queue)
+		env.AssertExpectations(t)
+	})
+}
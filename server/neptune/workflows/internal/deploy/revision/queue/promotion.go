@@ -0,0 +1,131 @@
+package queue
+
+import (
+	"time"
+
+	metricNames "github.com/runatlantis/atlantis/server/events/metrics"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	PromoteSignalName = "promote"
+
+	// PromotionGateTimeout bounds how long a revision waits to be promoted since the queue is blocked in the meantime
+	PromotionGateTimeout = 24 * time.Hour
+
+	PromotionSoakingSummary = "Upstream roots %s deployed this revision, this deploy is soaking until %s."
+	PromotionGateSummary    = "Upstream roots %s deployed this revision, promote it to deploy this root."
+	PromotionTimeoutSummary = "This revision was not deployed since it wasn't promoted within %s."
+	PromotedSummary         = "This revision was promoted by %s."
+)
+
+// PromoteSignalRequest promotes a deployment waiting at the manual gate of a promotion chain.
+type PromoteSignalRequest struct {
+	DeploymentID string
+	User         string
+}
+
+// Promotions receives promote signals for deployments waiting at a manual gate.
+type Promotions struct {
+	SignalChannel workflow.ReceiveChannel
+	Timeout       time.Duration
+
+	// mutable: the user which promoted each deployment keyed by deployment id, promotions are
+	// kept until the deployment completes so they survive preemption and continuing as new.
+	received map[string]string
+}
+
+func NewPromotions(ctx workflow.Context, received map[string]string) *Promotions {
+	p := &Promotions{
+		SignalChannel: workflow.GetSignalChannel(ctx, PromoteSignalName),
+		Timeout:       PromotionGateTimeout,
+		received:      make(map[string]string),
+	}
+	for id, user := range received {
+		p.received[id] = user
+	}
+	return p
+}
+
+// Await blocks until the deployment is promoted and returns the user which promoted it,
+// false is returned if this times out.  Promotions of other deployments are recorded for
+// when they're awaited.
+func (p *Promotions) Await(ctx workflow.Context, deploymentID string) (string, bool) {
+	// promotions received before a preempted wait was canceled are honored once it resumes
+	p.Drain(ctx)
+	if user, ok := p.received[deploymentID]; ok {
+		return user, true
+	}
+
+	ctx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	var timedOut bool
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(p.SignalChannel, func(c workflow.ReceiveChannel, more bool) {
+		var request PromoteSignalRequest
+		c.Receive(ctx, &request)
+		p.record(ctx, request)
+	})
+	selector.AddFuture(workflow.NewTimer(ctx, p.Timeout), func(f workflow.Future) {
+		timedOut = f.Get(ctx, nil) == nil
+	})
+
+	for {
+		if user, ok := p.received[deploymentID]; ok {
+			return user, true
+		}
+		if timedOut || ctx.Err() != nil {
+			return "", false
+		}
+		selector.Select(ctx)
+	}
+}
+
+// Promoter returns the user which promoted the deployment, if any.
+func (p *Promotions) Promoter(deploymentID string) (string, bool) {
+	user, ok := p.received[deploymentID]
+	return user, ok
+}
+
+// Remove forgets the promotion of a deployment once it's completed.
+func (p *Promotions) Remove(deploymentID string) {
+	delete(p.received, deploymentID)
+}
+
+// Drain records any buffered signals without blocking, this is used to ensure
+// they aren't dropped when the workflow continues as new.
+func (p *Promotions) Drain(ctx workflow.Context) {
+	var request PromoteSignalRequest
+	for p.SignalChannel.ReceiveAsync(&request) {
+		p.record(ctx, request)
+	}
+}
+
+// Snapshot returns the recorded promotions so they can be carried over to the next run of the workflow
+func (p *Promotions) Snapshot() map[string]string {
+	snapshot := make(map[string]string, len(p.received))
+	for id, user := range p.received {
+		snapshot[id] = user
+	}
+	return snapshot
+}
+
+func (p *Promotions) record(ctx workflow.Context, request PromoteSignalRequest) {
+	workflow.GetMetricsHandler(ctx).WithTags(map[string]string{metricNames.SignalNameTag: PromoteSignalName}).
+		Counter(metricNames.SignalReceive).
+		Inc(1)
+
+	// the first promotion wins, later ones are redundant
+	if _, ok := p.received[request.DeploymentID]; ok {
+		return
+	}
+
+	// promotions are only removed once their deployment completes so bound these in case
+	// deployments which are never completed are promoted
+	if len(p.received) >= MaxDeploymentResults {
+		workflow.GetLogger(ctx).Warn("dropping promotion since too many are pending", "deployment_id", request.DeploymentID)
+		return
+	}
+	p.received[request.DeploymentID] = request.User
+}
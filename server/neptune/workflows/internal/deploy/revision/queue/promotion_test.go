@@ -0,0 +1,208 @@
+package queue_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	model "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestDeployer_Promotion_Soak(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:          "prod",
+		DependsOn:     []string{"staging"},
+		PromotionGate: &model.PromotionGate{Soak: time.Hour},
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{
+			Root:        "staging",
+			Revision:    "3455",
+			Status:      queue.SuccessDeploymentResult,
+			CompletedAt: env.Now(),
+		})
+	}, time.Minute)
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.NotNil(t, resp.Info)
+	assert.GreaterOrEqual(t, resp.Elapsed, time.Hour+time.Minute)
+	assert.Len(t, resp.Summaries, 2)
+	assert.Contains(t, resp.Summaries[1], "Upstream roots staging deployed this revision, this deploy is soaking until")
+}
+
+func TestDeployer_Promotion_SoakElapsed(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:          "prod",
+		DependsOn:     []string{"staging"},
+		PromotionGate: &model.PromotionGate{Soak: time.Hour},
+	})
+
+	// the upstream root deployed long enough ago that there's nothing left to soak
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{
+			Root:        "staging",
+			Revision:    "3455",
+			Status:      queue.SuccessDeploymentResult,
+			CompletedAt: env.Now().Add(-2 * time.Hour),
+		})
+	}, time.Minute)
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.Less(t, resp.Elapsed, time.Hour)
+	assert.Equal(t, []string{fmt.Sprintf(queue.UpstreamWaitingSummary, "staging")}, resp.Summaries)
+}
+
+func TestDeployer_Promotion_ManualGate(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:          "prod",
+		DependsOn:     []string{"staging"},
+		PromotionGate: &model.PromotionGate{Manual: true},
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "staging", Revision: "3455", Status: queue.SuccessDeploymentResult})
+	}, time.Minute)
+
+	// promotions of other deployments are kept until they're awaited
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.PromoteSignalName, queue.PromoteSignalRequest{DeploymentID: "1234", User: "nish"})
+	}, 2*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.PromoteSignalName, queue.PromoteSignalRequest{DeploymentID: info.ID.String(), User: "nish"})
+	}, time.Hour)
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.NotNil(t, resp.Info)
+	assert.GreaterOrEqual(t, resp.Elapsed, time.Hour)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "staging"),
+		fmt.Sprintf(queue.PromotionGateSummary, "staging"),
+		fmt.Sprintf(queue.PromotedSummary, "nish"),
+	}, resp.Summaries)
+	assert.Equal(t, []github.CheckRunAction{github.CreatePromoteAction()}, resp.Actions[1])
+	assert.Equal(t, "nish", resp.Info.PromotedBy)
+	assert.Equal(t, map[string]string{"1234": "nish"}, resp.Promotions)
+}
+
+func TestDeployer_Promotion_ReceivedWhilePreempted(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:          "prod",
+		DependsOn:     []string{"staging"},
+		PromotionGate: &model.PromotionGate{Manual: true},
+	})
+
+	// the revision is promoted after its wait was preempted and before it's requeued
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.PromoteSignalName, queue.PromoteSignalRequest{DeploymentID: info.ID.String(), User: "nish"})
+	}, 2*time.Minute)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "staging", Revision: "3455", Status: queue.SuccessDeploymentResult})
+	}, time.Hour)
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info, PreemptAfter: time.Minute, Requeue: true})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.NotNil(t, resp.Info)
+	assert.Equal(t, "nish", resp.Info.PromotedBy)
+	assert.Empty(t, resp.Promotions)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "staging"),
+		queue.PreemptedSummary,
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "staging"),
+		fmt.Sprintf(queue.PromotionGateSummary, "staging"),
+		fmt.Sprintf(queue.PromotedSummary, "nish"),
+	}, resp.Summaries)
+}
+
+func TestDeployer_Promotion_ManualGateTimeout(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:          "prod",
+		DependsOn:     []string{"staging"},
+		PromotionGate: &model.PromotionGate{Manual: true},
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "staging", Revision: "3455", Status: queue.SuccessDeploymentResult})
+	}, time.Minute)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info, GateTimeout: time.Hour})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Nil(t, resp.Info)
+	assert.Equal(t, "requested revision 3455 wasn't promoted within 1h0m0s", resp.Err)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "staging"),
+		fmt.Sprintf(queue.PromotionGateSummary, "staging"),
+		fmt.Sprintf(queue.PromotionTimeoutSummary, time.Hour),
+	}, resp.Summaries)
+}
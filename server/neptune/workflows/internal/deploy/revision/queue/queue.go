@@ -3,6 +3,7 @@ package queue
 import (
 	"container/list"
 	"fmt"
+	"time"
 
 	activity "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
@@ -17,6 +18,17 @@ type LockState struct {
 	Status   LockStatus
 }
 
+// FreezeState is set while an active freeze window holds merged items in the queue
+type FreezeState struct {
+	Until  time.Time
+	Name   string
+	Reason string
+}
+
+func (s FreezeState) IsFrozen() bool {
+	return !s.Until.IsZero()
+}
+
 const (
 	UnlockedStatus LockStatus = iota
 	LockedStatus
@@ -31,6 +43,12 @@ type Deploy struct {
 
 	// mutable: default is unlocked
 	lock LockState
+
+	// mutable: default is unfrozen
+	freeze FreezeState
+
+	// mutable: incremented each time the contents of the queue change
+	changes int
 }
 
 func NewQueue(callback func(workflow.Context, *Deploy), scope metrics.Scope) *Deploy {
@@ -55,13 +73,42 @@ func (q *Deploy) SetLockForMergedItems(ctx workflow.Context, state LockState) {
 	q.lockStatusCallback(ctx, q)
 }
 
+func (q *Deploy) GetFreezeState() FreezeState {
+	return q.freeze
+}
+
+// SetFreezeForMergedItems holds merged items while the state is frozen, callbacks are only invoked
+// if the freeze state changes.
+func (q *Deploy) SetFreezeForMergedItems(ctx workflow.Context, state FreezeState) {
+	if state == q.freeze {
+		return
+	}
+
+	if state.IsFrozen() {
+		q.scope.Counter("frozen").Inc(1)
+	} else {
+		q.scope.Counter("unfrozen").Inc(1)
+	}
+	q.freeze = state
+	q.lockStatusCallback(ctx, q)
+}
+
 func (q *Deploy) CanPop() bool {
-	return q.queue.HasItemsOfPriority(High) || (q.lock.Status == UnlockedStatus && !q.queue.IsEmpty())
+	return q.queue.HasItemsOfPriority(High) || (q.lock.Status == UnlockedStatus && !q.freeze.IsFrozen() && !q.queue.IsEmpty())
+}
+
+// HasPriorityItems returns true if manual deploys or rollbacks are queued
+func (q *Deploy) HasPriorityItems() bool {
+	return q.queue.HasItemsOfPriority(High)
 }
 
 func (q *Deploy) Pop() (terraform.DeploymentInfo, error) {
 	defer q.scope.Gauge(QueueDepthStat).Update(float64(q.queue.Size()))
-	return q.queue.Pop()
+	info, err := q.queue.Pop()
+	if err == nil {
+		q.changes++
+	}
+	return info, err
 }
 
 func (q *Deploy) Scan() []terraform.DeploymentInfo {
@@ -76,8 +123,25 @@ func (q *Deploy) IsEmpty() bool {
 	return q.queue.IsEmpty()
 }
 
+// Restore populates the queue with state carried over from a previous workflow run.
+// Unlike Push and SetLockForMergedItems, this doesn't invoke any callbacks since that state
+// has already been reported.
+func (q *Deploy) Restore(items []terraform.DeploymentInfo, lock LockState) {
+	for _, i := range items {
+		q.Push(i)
+	}
+	q.lock = lock
+}
+
+// GetChangeCount returns a counter which is incremented every time an item is added to or removed from the queue.
+// This allows callers to detect modifications without diffing the queue contents.
+func (q *Deploy) GetChangeCount() int {
+	return q.changes
+}
+
 func (q *Deploy) Push(msg terraform.DeploymentInfo) {
 	defer q.scope.Gauge(QueueDepthStat).Update(float64(q.queue.Size()))
+	q.changes++
 	if msg.Root.TriggerInfo.Type == activity.ManualTrigger {
 		q.queue.Push(msg, High)
 		return
@@ -85,6 +149,37 @@ func (q *Deploy) Push(msg terraform.DeploymentInfo) {
 	q.queue.Push(msg, Low)
 }
 
+// Requeue pushes a popped deployment back to the front of its priority so it's the next one popped from it
+func (q *Deploy) Requeue(msg terraform.DeploymentInfo) {
+	defer q.scope.Gauge(QueueDepthStat).Update(float64(q.queue.Size()))
+	q.changes++
+	if msg.Root.TriggerInfo.Type == activity.ManualTrigger {
+		q.queue.PushFront(msg, High)
+		return
+	}
+	q.queue.PushFront(msg, Low)
+}
+
+// Remove removes the deployment with the given id from the queue regardless of its priority
+// and returns it. False is returned if the deployment isn't in the queue.
+func (q *Deploy) Remove(id string) (terraform.DeploymentInfo, bool) {
+	defer q.scope.Gauge(QueueDepthStat).Update(float64(q.queue.Size()))
+	info, ok := q.queue.Remove(id)
+	if ok {
+		q.changes++
+	}
+	return info, ok
+}
+
+// ReorderMergedItems moves the merged items with the given ids to the front of the queue in the order
+// provided. Items not referenced keep their relative order behind them and unknown ids are ignored.
+func (q *Deploy) ReorderMergedItems(ids []string) {
+	// waiters are only woken up if the order actually changed
+	if q.queue.Reorder(Low, ids) {
+		q.changes++
+	}
+}
+
 // priority is a simple 2 priority queue implementation
 // priority is determined before an item enters a queue and does not change
 type priority struct {
@@ -142,6 +237,10 @@ func (q *priority) Push(msg terraform.DeploymentInfo, priority priorityType) {
 	q.queues[priority].PushBack(msg)
 }
 
+func (q *priority) PushFront(msg terraform.DeploymentInfo, priority priorityType) {
+	q.queues[priority].PushFront(msg)
+}
+
 func (q *priority) Pop() (terraform.DeploymentInfo, error) {
 	priority := High
 	if q.queues[High].Len() == 0 {
@@ -156,3 +255,48 @@ func (q *priority) Pop() (terraform.DeploymentInfo, error) {
 	// naughty casting
 	return result.(terraform.DeploymentInfo), nil
 }
+
+func (q *priority) Remove(id string) (terraform.DeploymentInfo, bool) {
+	for _, l := range q.queues {
+		for e := l.Front(); e != nil; e = e.Next() {
+			info := e.Value.(terraform.DeploymentInfo)
+			if info.ID.String() == id {
+				l.Remove(e)
+				return info, true
+			}
+		}
+	}
+	return terraform.DeploymentInfo{}, false
+}
+
+// Reorder returns true if the order of the items changed
+func (q *priority) Reorder(priority priorityType, ids []string) bool {
+	l := q.queues[priority]
+	before := listIDs(l)
+
+	// iterate in reverse so that the first id ends up at the front
+	for i := len(ids) - 1; i >= 0; i-- {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if e.Value.(terraform.DeploymentInfo).ID.String() == ids[i] {
+				l.MoveToFront(e)
+				break
+			}
+		}
+	}
+
+	after := listIDs(l)
+	for i := range before {
+		if before[i] != after[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func listIDs(l *list.List) []string {
+	var ids []string
+	for e := l.Front(); e != nil; e = e.Next() {
+		ids = append(ids, e.Value.(terraform.DeploymentInfo).ID.String())
+	}
+	return ids
+}
@@ -3,6 +3,8 @@ package queue
 import (
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +81,45 @@ func TestPriorityQueue(t *testing.T) {
 		High,
 	}
 
+	t.Run("remove", func(t *testing.T) {
+		q := newPriorityQueue()
+
+		first := wrapWithID("1")
+		second := wrapWithID("2")
+		q.Push(first, Low)
+		q.Push(second, High)
+
+		info, ok := q.Remove(first.ID.String())
+		assert.True(t, ok)
+		assert.Equal(t, "1", unwrap(info))
+		assert.Equal(t, 1, q.Size())
+
+		_, ok = q.Remove(first.ID.String())
+		assert.False(t, ok)
+
+		info, err := q.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, "2", unwrap(info))
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		q := newPriorityQueue()
+
+		first := wrapWithID("1")
+		second := wrapWithID("2")
+		third := wrapWithID("3")
+		q.Push(first, Low)
+		q.Push(second, Low)
+		q.Push(third, Low)
+
+		assert.True(t, q.Reorder(Low, []string{third.ID.String(), "unknown", second.ID.String()}))
+		assert.Equal(t, []terraform.DeploymentInfo{third, second, first}, q.Scan(Low))
+
+		// already in this order
+		assert.False(t, q.Reorder(Low, []string{third.ID.String(), second.ID.String()}))
+		assert.Equal(t, []terraform.DeploymentInfo{third, second, first}, q.Scan(Low))
+	})
+
 	for _, p := range priorities {
 		t.Run("has items of priority", func(t *testing.T) {
 			q := newPriorityQueue()
@@ -104,6 +145,12 @@ func wrap(msg string) terraform.DeploymentInfo {
 	}}
 }
 
+func wrapWithID(msg string) terraform.DeploymentInfo {
+	info := wrap(msg)
+	info.ID = uuid.New()
+	return info
+}
+
 func unwrap(msg terraform.DeploymentInfo) string {
 	return msg.Commit.Revision
 }
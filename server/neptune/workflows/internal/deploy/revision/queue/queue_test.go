@@ -2,7 +2,9 @@ package queue_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 	activity "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
@@ -33,6 +35,25 @@ func TestQueue(t *testing.T) {
 		assert.Equal(t, msg1, info)
 	})
 
+	t.Run("reorder only counts changes", func(t *testing.T) {
+		q := queue.NewQueue(nil, metrics.NewNullableScope())
+
+		msg1 := wrap("1", activity.MergeTrigger)
+		msg1.ID = uuid.New()
+		q.Push(msg1)
+		msg2 := wrap("2", activity.MergeTrigger)
+		msg2.ID = uuid.New()
+		q.Push(msg2)
+
+		changes := q.GetChangeCount()
+		q.ReorderMergedItems([]string{msg1.ID.String()})
+		assert.Equal(t, changes, q.GetChangeCount())
+
+		q.ReorderMergedItems([]string{msg2.ID.String()})
+		assert.Equal(t, changes+1, q.GetChangeCount())
+		assert.Equal(t, []terraform.DeploymentInfo{msg2, msg1}, q.GetOrderedMergedItems())
+	})
+
 	t.Run("test lock state callback", func(t *testing.T) {
 		var called bool
 		q := queue.NewQueue(func(ctx workflow.Context, d *queue.Deploy) {
@@ -87,6 +108,34 @@ func TestQueue(t *testing.T) {
 		q.Push(msg1)
 		assert.Equal(t, true, q.CanPop())
 	})
+	t.Run("freeze merge trigger", func(t *testing.T) {
+		now := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+
+		var calls int
+		q := queue.NewQueue(func(ctx workflow.Context, d *queue.Deploy) {
+			calls++
+		}, metrics.NewNullableScope())
+		q.Push(wrap("1", activity.MergeTrigger))
+
+		state := queue.FreezeState{Until: now.Add(time.Hour), Name: "holidays", Reason: "change freeze"}
+		q.SetFreezeForMergedItems(test.Background(), state)
+		assert.Equal(t, state, q.GetFreezeState())
+		assert.Equal(t, false, q.CanPop())
+
+		// unchanged state doesn't invoke the callback again
+		q.SetFreezeForMergedItems(test.Background(), state)
+		assert.Equal(t, 1, calls)
+
+		// manual triggers aren't frozen
+		q.Push(wrap("2", activity.ManualTrigger))
+		assert.Equal(t, true, q.CanPop())
+		_, err := q.Pop()
+		assert.NoError(t, err)
+
+		q.SetFreezeForMergedItems(test.Background(), queue.FreezeState{})
+		assert.Equal(t, true, q.CanPop())
+		assert.Equal(t, 2, calls)
+	})
 }
 
 func wrap(msg string, trigger activity.Trigger) terraform.DeploymentInfo {
@@ -30,6 +30,8 @@ func (u *LockStateUpdater) UpdateQueuedRevisions(ctx workflow.Context, queue *De
 		state = github.CheckRunActionRequired
 		revisionLink := github.BuildRevisionURLMarkdown(repoFullName, lock.Revision)
 		summary = fmt.Sprintf("This deploy is locked from a manual deployment for revision %s.  Unlock to proceed.", revisionLink)
+	} else if freeze := queue.GetFreezeState(); freeze.IsFrozen() {
+		summary = BuildFrozenSummary(freeze)
 	}
 
 	for _, i := range infos {
@@ -145,9 +145,58 @@ func TestLockStateUpdater_locked_new_version(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestLockStateUpdater_frozen(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	a := &testDeployActivity{}
+	env.RegisterActivity(a)
+
+	now := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	info := terraform.DeploymentInfo{
+		CheckRunID: 123,
+		ID:         uuid.New(),
+		Commit: github.Commit{
+			Revision: "1",
+		},
+		Root: tfActivity.Root{
+			Name: "root",
+			TriggerInfo: tfActivity.TriggerInfo{
+				Type: tfActivity.MergeTrigger,
+			},
+		},
+		Repo: github.Repo{
+			Name: "repo",
+		},
+	}
+
+	env.ExecuteWorkflow(testUpdaterWorkflow, updaterReq{
+		Queue: []terraform.DeploymentInfo{info},
+		Freeze: queue.FreezeState{
+			Name:  "holidays",
+			Until: now.Add(48 * time.Hour),
+		},
+		ExpectedRequest: notifier.GithubCheckRunRequest{
+			Title:   notifier.BuildDeployCheckRunTitle(info.Root.Name),
+			State:   github.CheckRunQueued,
+			Repo:    info.Repo,
+			Summary: "This deploy is frozen until Mon, 26 Dec 2022 00:00:00 UTC by freeze window \"holidays\".",
+			Sha:     info.Commit.Revision,
+		},
+		ExpectedDeploymentID: info.ID.String(),
+		ExpectedT:            t,
+	})
+
+	err := env.GetWorkflowResult(nil)
+	env.AssertExpectations(t)
+
+	assert.NoError(t, err)
+}
+
 type updaterReq struct {
 	Queue                []terraform.DeploymentInfo
 	Lock                 queue.LockState
+	Freeze               queue.FreezeState
 	ExpectedRequest      notifier.GithubCheckRunRequest
 	ExpectedDeploymentID string
 	ExpectedT            *testing.T
@@ -175,6 +224,7 @@ func testUpdaterWorkflow(ctx workflow.Context, r updaterReq) error {
 	}
 
 	q.SetLockForMergedItems(ctx, r.Lock)
+	q.SetFreezeForMergedItems(ctx, r.Freeze)
 	subject.UpdateQueuedRevisions(ctx, q, "some-org/some-repo")
 
 	return nil
@@ -0,0 +1,213 @@
+package queue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metricNames "github.com/runatlantis/atlantis/server/events/metrics"
+	key "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	UpstreamDeploymentSignalName = "upstream-deployment"
+
+	// UpstreamDeploymentTimeout bounds how long a revision waits on its upstream roots, this leaves
+	// room for upstream revisions waiting on plan approval or a manual deploy lock.
+	UpstreamDeploymentTimeout = 24 * time.Hour
+
+	UpstreamWaitingSummary = "This deploy is waiting for upstream roots %s to deploy this revision."
+	UpstreamFailureSummary = "This revision was not deployed since upstream roots %s failed to deploy or skipped it."
+	UpstreamTimeoutSummary = "This revision was not deployed since upstream roots %s didn't deploy it within %s."
+	PreemptedSummary       = "This revision was requeued behind a manual deploy and will resume waiting on upstream roots once it's done."
+)
+
+// PreemptedError is returned when a revision waiting on upstream roots is requeued behind higher priority deployments
+type PreemptedError struct {
+	Revision string
+}
+
+func (e *PreemptedError) Error() string {
+	return fmt.Sprintf("revision %s was preempted by higher priority deployments", e.Revision)
+}
+
+// UpstreamTimeoutError is returned when upstream roots don't deploy a revision in time
+type UpstreamTimeoutError struct {
+	Roots []string
+}
+
+func (e *UpstreamTimeoutError) Error() string {
+	return fmt.Sprintf("timed out waiting on upstream roots %s", strings.Join(e.Roots, ", "))
+}
+
+// UpstreamDeploymentSignalRequest is sent by a root's deploy workflow to the deploy workflows of
+// roots which depend on it once it's done with a revision.
+type UpstreamDeploymentSignalRequest struct {
+	Root        string
+	Revision    string
+	Status      DeploymentResultStatus
+	CompletedAt time.Time
+}
+
+// BuildDeployWorkflowID returns the id of a root's deploy workflow, this is used to signal dependent roots.
+func BuildDeployWorkflowID(repoName string, rootName string) string {
+	return fmt.Sprintf("%s||%s", repoName, rootName)
+}
+
+// notifyDependents signals the deploy workflows of the roots which depend on the deployment's root
+// with its status so they don't wait on it.
+func notifyDependents(ctx workflow.Context, info terraform.DeploymentInfo, status DeploymentResultStatus, scope metrics.Scope) {
+	for _, root := range info.Root.Dependents {
+		workflowID := BuildDeployWorkflowID(info.Repo.GetFullName(), root)
+		err := workflow.SignalExternalWorkflow(ctx, workflowID, "", UpstreamDeploymentSignalName, UpstreamDeploymentSignalRequest{
+			Root:        info.Root.Name,
+			Revision:    info.Commit.Revision,
+			Status:      status,
+			CompletedAt: workflow.Now(ctx),
+		}).Get(ctx, nil)
+
+		// the dependent will time out waiting on us so there's not much else we can do here
+		if err != nil {
+			scope.Counter("dependent_signal_error").Inc(1)
+			workflow.GetLogger(ctx).Error("error signaling dependent root", "workflow_id", workflowID, key.ErrKey, err)
+		}
+	}
+}
+
+// UpstreamDeployments keeps track of the results of upstream roots for revisions this root depends on.
+type UpstreamDeployments struct {
+	SignalChannel workflow.ReceiveChannel
+	Timeout       time.Duration
+
+	// Promotions are awaited once upstream roots deploy for roots with a manual promotion gate
+	Promotions *Promotions
+
+	// mutable
+	results []UpstreamDeploymentSignalRequest
+}
+
+func NewUpstreamDeployments(ctx workflow.Context, results []UpstreamDeploymentSignalRequest, promotions map[string]string) *UpstreamDeployments {
+	return &UpstreamDeployments{
+		SignalChannel: workflow.GetSignalChannel(ctx, UpstreamDeploymentSignalName),
+		Timeout:       UpstreamDeploymentTimeout,
+		Promotions:    NewPromotions(ctx, promotions),
+		results:       append([]UpstreamDeploymentSignalRequest{}, results...),
+	}
+}
+
+// Await blocks until each of the upstream roots has deployed the revision and returns the roots which failed to.
+// An UpstreamTimeoutError is returned if this times out.
+func (u *UpstreamDeployments) Await(ctx workflow.Context, revision string, roots []string) ([]string, error) {
+	u.Drain(ctx)
+
+	if len(u.pending(revision, roots)) > 0 {
+		if err := u.await(ctx, revision, roots); err != nil {
+			return nil, err
+		}
+	}
+
+	var failed []string
+	for _, root := range roots {
+		if status, _ := u.result(revision, root); status != SuccessDeploymentResult {
+			failed = append(failed, root)
+		}
+	}
+	return failed, nil
+}
+
+func (u *UpstreamDeployments) await(ctx workflow.Context, revision string, roots []string) error {
+	ctx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	var timedOut bool
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(u.SignalChannel, func(c workflow.ReceiveChannel, more bool) {
+		var request UpstreamDeploymentSignalRequest
+		c.Receive(ctx, &request)
+		u.record(ctx, request)
+	})
+	selector.AddFuture(workflow.NewTimer(ctx, u.Timeout), func(f workflow.Future) {
+		timedOut = f.Get(ctx, nil) == nil
+	})
+
+	for pending := u.pending(revision, roots); len(pending) > 0; pending = u.pending(revision, roots) {
+		selector.Select(ctx)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if timedOut {
+			return &UpstreamTimeoutError{Roots: pending}
+		}
+	}
+	return nil
+}
+
+// CompletedAt returns when the last of the upstream roots finished deploying the revision.
+func (u *UpstreamDeployments) CompletedAt(revision string, roots []string) time.Time {
+	var completedAt time.Time
+	for _, root := range roots {
+		for i := len(u.results) - 1; i >= 0; i-- {
+			r := u.results[i]
+			if r.Revision != revision || r.Root != root {
+				continue
+			}
+			if r.CompletedAt.After(completedAt) {
+				completedAt = r.CompletedAt
+			}
+			break
+		}
+	}
+	return completedAt
+}
+
+// Drain records any buffered upstream deployment and promote signals without blocking, this is used to ensure
+// they aren't dropped when the workflow continues as new.
+func (u *UpstreamDeployments) Drain(ctx workflow.Context) {
+	var request UpstreamDeploymentSignalRequest
+	for u.SignalChannel.ReceiveAsync(&request) {
+		u.record(ctx, request)
+	}
+	u.Promotions.Drain(ctx)
+}
+
+// Snapshot returns the recorded results so they can be carried over to the next run of the workflow
+func (u *UpstreamDeployments) Snapshot() []UpstreamDeploymentSignalRequest {
+	return append([]UpstreamDeploymentSignalRequest{}, u.results...)
+}
+
+// pending returns the upstream roots which haven't deployed the revision yet.
+func (u *UpstreamDeployments) pending(revision string, roots []string) []string {
+	var pending []string
+	for _, root := range roots {
+		if _, ok := u.result(revision, root); !ok {
+			pending = append(pending, root)
+		}
+	}
+	return pending
+}
+
+func (u *UpstreamDeployments) result(revision string, root string) (DeploymentResultStatus, bool) {
+	// most recent result wins in case an upstream root redeploys a revision
+	for i := len(u.results) - 1; i >= 0; i-- {
+		if u.results[i].Revision == revision && u.results[i].Root == root {
+			return u.results[i].Status, true
+		}
+	}
+	return "", false
+}
+
+func (u *UpstreamDeployments) record(ctx workflow.Context, request UpstreamDeploymentSignalRequest) {
+	workflow.GetMetricsHandler(ctx).WithTags(map[string]string{metricNames.SignalNameTag: UpstreamDeploymentSignalName}).
+		Counter(metricNames.SignalReceive).
+		Inc(1)
+
+	u.results = append(u.results, request)
+	if len(u.results) > MaxDeploymentResults {
+		u.results = u.results[len(u.results)-MaxDeploymentResults:]
+	}
+}
@@ -0,0 +1,311 @@
+package queue_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	model "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/version"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type upstreamRequest struct {
+	Info        terraform.DeploymentInfo
+	Timeout     time.Duration
+	GateTimeout time.Duration
+
+	// PreemptAfter queues a higher priority deployment after the given duration
+	PreemptAfter time.Duration
+	// Requeue deploys the revision again once it's preempted
+	Requeue bool
+}
+
+type upstreamResponse struct {
+	Info      *deployment.Info
+	Err       string
+	Summaries []string
+	Actions   [][]github.CheckRunAction
+	Elapsed   time.Duration
+
+	// Promotions are those which haven't been completed yet
+	Promotions map[string]string
+}
+
+func testUpstreamDeployerWorkflow(ctx workflow.Context, r upstreamRequest) (upstreamResponse, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	upstream := queue.NewUpstreamDeployments(ctx, nil, nil)
+	if r.Timeout > 0 {
+		upstream.Timeout = r.Timeout
+	}
+	if r.GateTimeout > 0 {
+		upstream.Promotions.Timeout = r.GateTimeout
+	}
+
+	var preempted bool
+	if r.PreemptAfter > 0 {
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			preempted = workflow.Sleep(ctx, r.PreemptAfter) == nil
+		})
+	}
+
+	checkRunClient := &recordingCheckRunClient{}
+
+	var a *testDeployActivity
+	deployer := &queue.Deployer{
+		Activities:              a,
+		TerraformWorkflowRunner: &testTerraformWorkflowRunner{},
+		GithubCheckRunCache:     checkRunClient,
+		PRRevisionWorkflow:      testPRRevWorkflow,
+		Upstream:                upstream,
+		Preempted: func() bool {
+			return preempted
+		},
+	}
+
+	start := workflow.Now(ctx)
+	info, err := deployer.Deploy(ctx, r.Info, nil, metrics.NewNullableScope())
+
+	var preemptedErr *queue.PreemptedError
+	if r.Requeue && errors.As(err, &preemptedErr) {
+		preempted = false
+		info, err = deployer.Deploy(ctx, r.Info, nil, metrics.NewNullableScope())
+	}
+
+	resp := upstreamResponse{Info: info, Elapsed: workflow.Now(ctx).Sub(start), Promotions: upstream.Promotions.Snapshot()}
+	if err != nil {
+		resp.Err = err.Error()
+	}
+	for _, request := range checkRunClient.Requests {
+		resp.Summaries = append(resp.Summaries, request.Summary)
+		resp.Actions = append(resp.Actions, request.Actions)
+	}
+	return resp, nil
+}
+
+func buildUpstreamDeploymentInfo(root model.Root) terraform.DeploymentInfo {
+	return terraform.DeploymentInfo{
+		ID: uuid.UUID{},
+		Commit: github.Commit{
+			Revision: "3455",
+			Branch:   "default-branch",
+		},
+		Root: root,
+		Repo: github.Repo{
+			Owner: "owner",
+			Name:  "test",
+		},
+	}
+}
+
+func TestDeployer_Upstream_Success(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:      "service",
+		DependsOn: []string{"network", "dns"},
+	})
+
+	// results for other revisions and roots are ignored
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "network", Revision: "1234", Status: queue.FailureDeploymentResult})
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "network", Revision: "3455", Status: queue.SuccessDeploymentResult})
+	}, time.Minute)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "dns", Revision: "3455", Status: queue.SuccessDeploymentResult})
+	}, 2*time.Minute)
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.NotNil(t, resp.Info)
+	assert.Equal(t, []string{fmt.Sprintf(queue.UpstreamWaitingSummary, "network, dns")}, resp.Summaries)
+}
+
+func TestDeployer_Upstream_Failure(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:      "service",
+		DependsOn: []string{"network"},
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "network", Revision: "3455", Status: queue.FailureDeploymentResult})
+	}, time.Minute)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Nil(t, resp.Info)
+	assert.Equal(t, "upstream roots network failed to deploy requested revision 3455", resp.Err)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "network"),
+		fmt.Sprintf(queue.UpstreamFailureSummary, "network"),
+	}, resp.Summaries)
+}
+
+func TestDeployer_Upstream_Timeout(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:      "service",
+		DependsOn: []string{"network"},
+	})
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info, Timeout: time.Hour})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Nil(t, resp.Info)
+	assert.Equal(t, "requested revision 3455 timed out waiting on upstream roots network", resp.Err)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "network"),
+		fmt.Sprintf(queue.UpstreamTimeoutSummary, "network", time.Hour),
+	}, resp.Summaries)
+}
+
+func TestDeployer_NotifiesDependents(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:       "network",
+		Dependents: []string{"service"},
+	})
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+	env.OnSignalExternalWorkflow(mock.Anything, "owner/test||service", "", queue.UpstreamDeploymentSignalName, mock.MatchedBy(func(r queue.UpstreamDeploymentSignalRequest) bool {
+		return r.Root == "network" && r.Revision == "3455" && r.Status == queue.SuccessDeploymentResult && !r.CompletedAt.IsZero()
+	})).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+}
+
+func TestDeployer_NotifiesDependents_Failure(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:       "network",
+		DependsOn:  []string{"vpc"},
+		Dependents: []string{"service"},
+	})
+
+	// failures cascade down to our own dependents
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(queue.UpstreamDeploymentSignalName, queue.UpstreamDeploymentSignalRequest{Root: "vpc", Revision: "3455", Status: queue.FailureDeploymentResult})
+	}, time.Minute)
+	env.OnSignalExternalWorkflow(mock.Anything, "owner/test||service", "", queue.UpstreamDeploymentSignalName, mock.MatchedBy(func(r queue.UpstreamDeploymentSignalRequest) bool {
+		return r.Root == "network" && r.Revision == "3455" && r.Status == queue.FailureDeploymentResult && !r.CompletedAt.IsZero()
+	})).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.NotEmpty(t, resp.Err)
+}
+
+func TestDeployer_Upstream_ManualTrigger(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(version.SetPRRevision, workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:        "service",
+		DependsOn:   []string{"network"},
+		TriggerInfo: model.TriggerInfo{Type: model.ManualTrigger},
+	})
+
+	env.OnActivity(da.StoreLatestDeployment, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info})
+	env.AssertExpectations(t)
+
+	// manual deploys don't wait on upstream roots
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Empty(t, resp.Err)
+	assert.NotNil(t, resp.Info)
+	assert.Empty(t, resp.Summaries)
+	assert.Zero(t, resp.Elapsed)
+}
+
+func TestDeployer_Upstream_Preempted(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	// dependents aren't signaled since the revision is deployed later
+	info := buildUpstreamDeploymentInfo(model.Root{
+		Name:       "service",
+		DependsOn:  []string{"network"},
+		Dependents: []string{"app"},
+	})
+
+	env.ExecuteWorkflow(testUpstreamDeployerWorkflow, upstreamRequest{Info: info, PreemptAfter: time.Hour})
+	env.AssertExpectations(t)
+
+	var resp upstreamResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Nil(t, resp.Info)
+	assert.Equal(t, "revision 3455 was preempted by higher priority deployments", resp.Err)
+	assert.Equal(t, time.Hour, resp.Elapsed)
+	assert.Equal(t, []string{
+		fmt.Sprintf(queue.UpstreamWaitingSummary, "network"),
+		queue.PreemptedSummary,
+	}, resp.Summaries)
+}
@@ -1,8 +1,10 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
+	"time"
 
 	key "github.com/runatlantis/atlantis/server/neptune/context"
 
@@ -10,10 +12,13 @@ import (
 
 	"github.com/pkg/errors"
 	internalContext "github.com/runatlantis/atlantis/server/neptune/context"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	tfModel "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/version"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
@@ -22,16 +27,27 @@ import (
 type queue interface {
 	IsEmpty() bool
 	CanPop() bool
+	HasPriorityItems() bool
 	Pop() (terraform.DeploymentInfo, error)
+	Requeue(msg terraform.DeploymentInfo)
+	GetOrderedMergedItems() []terraform.DeploymentInfo
 	SetLockForMergedItems(ctx workflow.Context, state LockState)
+	GetFreezeState() FreezeState
+	SetFreezeForMergedItems(ctx workflow.Context, state FreezeState)
+	GetChangeCount() int
 }
 
 type deployer interface {
 	Deploy(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo, latestDeployment *deployment.Info, scope metrics.Scope) (*deployment.Info, error)
 }
 
+type freezeActivities interface {
+	FetchFreezeState(ctx context.Context, request activities.FetchFreezeStateRequest) (activities.FetchFreezeStateResponse, error)
+}
+
 type workerActivities interface {
 	deployerActivities
+	freezeActivities
 }
 
 type WorkerState string
@@ -53,6 +69,9 @@ type CurrentDeploymentStatus int
 type CurrentDeployment struct {
 	Deployment terraform.DeploymentInfo
 	Status     CurrentDeploymentStatus
+
+	// JobURLs are updated as the deployment's terraform workflow reports its state
+	JobURLs terraform.JobURLs
 }
 
 const (
@@ -60,14 +79,37 @@ const (
 	CompleteStatus
 )
 
+type DeploymentResultStatus string
+
+const (
+	SuccessDeploymentResult DeploymentResultStatus = "success"
+	FailureDeploymentResult DeploymentResultStatus = "failure"
+
+	// SkippedDeploymentResult is only sent to dependent roots for revisions which were removed from
+	// the queue, revisions held by a lock or freeze window are still pending until they're deployed
+	SkippedDeploymentResult DeploymentResultStatus = "skipped"
+
+	// MaxDeploymentResults is the number of completed deployments we keep track of
+	MaxDeploymentResults = 50
+)
+
+// DeploymentResult is the outcome of a deployment processed by this worker
+type DeploymentResult struct {
+	ID       string
+	Revision string
+	Status   DeploymentResultStatus
+}
+
 type Worker struct {
-	Queue    queue
-	Deployer deployer
+	Queue      queue
+	Deployer   deployer
+	Activities freezeActivities
 
 	// mutable
 	state             WorkerState
 	latestDeployment  *deployment.Info
 	currentDeployment CurrentDeployment
+	results           []DeploymentResult
 }
 
 type actionType string
@@ -86,22 +128,11 @@ func NewWorker(
 	prRevWorkflow Workflow,
 	repoName, rootName string,
 	githubCheckRunCache CheckRunClient,
+	upstream *UpstreamDeployments,
 	additionalNotifiers ...plugins.TerraformWorkflowNotifier,
 ) (*Worker, error) {
-	notifiers := []terraform.WorkflowNotifier{
-		&notifier.CheckRunNotifier{
-			CheckRunSessionCache: githubCheckRunCache,
-			Mode:                 tfModel.Deploy,
-		},
-	}
-
-	tfWorkflowRunner := terraform.NewWorkflowRunner(tfWorkflow, notifiers, additionalNotifiers...)
-	deployer := &Deployer{
-		Activities:              a,
-		TerraformWorkflowRunner: tfWorkflowRunner,
-		GithubCheckRunCache:     githubCheckRunCache,
-		PRRevisionWorkflow:      prRevWorkflow,
-	}
+	worker := &Worker{Queue: q, Activities: a}
+	deployer := newDeployer(a, tfWorkflow, prRevWorkflow, githubCheckRunCache, upstream, worker, additionalNotifiers...)
 
 	latestDeployment, err := deployer.FetchLatestDeployment(ctx, repoName, rootName)
 	if err != nil {
@@ -117,11 +148,58 @@ func NewWorker(
 		})
 	}
 
-	return &Worker{
+	worker.Deployer = deployer
+	worker.latestDeployment = latestDeployment
+	return worker, nil
+}
+
+// NewWorkerWithLatestDeployment builds a worker from a known latest deployment, this is used when continuing as new
+// since the lock state is carried forward and doesn't need to be rebuilt.
+func NewWorkerWithLatestDeployment(
+	q queue,
+	a workerActivities,
+	tfWorkflow terraform.Workflow,
+	prRevWorkflow Workflow,
+	githubCheckRunCache CheckRunClient,
+	upstream *UpstreamDeployments,
+	latestDeployment *deployment.Info,
+	additionalNotifiers ...plugins.TerraformWorkflowNotifier,
+) *Worker {
+	worker := &Worker{
 		Queue:            q,
-		Deployer:         deployer,
+		Activities:       a,
 		latestDeployment: latestDeployment,
-	}, nil
+	}
+	worker.Deployer = newDeployer(a, tfWorkflow, prRevWorkflow, githubCheckRunCache, upstream, worker, additionalNotifiers...)
+	return worker
+}
+
+func newDeployer(
+	a workerActivities,
+	tfWorkflow terraform.Workflow,
+	prRevWorkflow Workflow,
+	githubCheckRunCache CheckRunClient,
+	upstream *UpstreamDeployments,
+	worker *Worker,
+	additionalNotifiers ...plugins.TerraformWorkflowNotifier,
+) *Deployer {
+	notifiers := []terraform.WorkflowNotifier{
+		&notifier.CheckRunNotifier{
+			CheckRunSessionCache: githubCheckRunCache,
+			Mode:                 tfModel.Deploy,
+		},
+		&jobURLRecorder{worker: worker},
+	}
+
+	tfWorkflowRunner := terraform.NewWorkflowRunner(tfWorkflow, notifiers, additionalNotifiers...)
+	return &Deployer{
+		Activities:              a,
+		TerraformWorkflowRunner: tfWorkflowRunner,
+		GithubCheckRunCache:     githubCheckRunCache,
+		PRRevisionWorkflow:      prRevWorkflow,
+		Upstream:                upstream,
+		Preempted:               worker.Queue.HasPriorityItems,
+	}
 }
 
 // Work pops work off the queue and if the queue is empty,
@@ -172,6 +250,12 @@ func (w *Worker) Work(ctx workflow.Context) {
 			workflow.GetLogger(ctx).Info("Received cancelled signal, worker is shutting down")
 			return
 		case process:
+			// the worker might have been canceled while work was becoming available,
+			// in which case we leave the queue intact
+			if ctx.Err() != nil {
+				workflow.GetLogger(ctx).Info("Received cancelled signal, worker is shutting down")
+				return
+			}
 			workflow.GetLogger(ctx).Info("Processing... ")
 		case receive:
 			workflow.GetLogger(ctx).Info("Received unlock signal... ")
@@ -204,6 +288,17 @@ func (w *Worker) Work(ctx workflow.Context) {
 		w.emitRevisionRequestStats(scope, msg)
 		currentDeployment, err = w.deploy(ctx, msg, w.latestDeployment, scope)
 
+		// the revision is deployed once the higher priority deployments are done
+		if _, ok := err.(*PreemptedError); ok {
+			scope.Counter("preempted").Inc(1)
+			workflow.GetLogger(ctx).Info("requeueing revision behind higher priority deployments")
+			w.Queue.Requeue(msg)
+			selector.AddFuture(w.awaitWork(ctx), callback)
+			continue
+		}
+
+		w.recordResult(msg, err)
+
 		// since there was no error we can safely count this as our latest deploy
 		if err == nil {
 			scope.Counter("success").Inc(1)
@@ -247,6 +342,10 @@ func (w *Worker) emitRevisionRequestStats(scope metrics.Scope, request terraform
 		scope.Counter("force_deploy_requested").Inc(1)
 	}
 
+	if request.Root.TriggerInfo.Rollback {
+		scope.Counter("rollback_requested").Inc(1)
+	}
+
 	planMode := request.Root.Plan.GetPlanMode().String()
 	if planMode != "" {
 		scope.Counter(fmt.Sprintf("%s_plan_mode_requested", planMode)).Inc(1)
@@ -261,24 +360,157 @@ func (w *Worker) GetCurrentDeploymentState() CurrentDeployment {
 	return w.currentDeployment
 }
 
+// jobURLRecorder keeps track of the current deployment's job urls so they can be queried
+// from the deploy workflow instead of its terraform workflow.
+type jobURLRecorder struct {
+	worker *Worker
+}
+
+func (r *jobURLRecorder) Notify(_ workflow.Context, info notifier.Info, s *state.Workflow) error {
+	if r.worker.currentDeployment.Deployment.ID != info.ID {
+		return nil
+	}
+	r.worker.currentDeployment.JobURLs = terraform.NewJobURLs(s)
+	return nil
+}
+
+// IsDeploying returns true while a revision popped off the queue is being deployed
+func (w *Worker) IsDeploying() bool {
+	return w.state == WorkingWorkerState && w.currentDeployment.Status == InProgressStatus
+}
+
+// InProgressDeployment returns the revision which is being deployed, it's no longer in the queue at this point
+func (w *Worker) InProgressDeployment() (terraform.DeploymentInfo, bool) {
+	if !w.IsDeploying() {
+		return terraform.DeploymentInfo{}, false
+	}
+	return w.currentDeployment.Deployment, true
+}
+
 func (w *Worker) GetLatestDeployment() *deployment.Info {
 	return w.latestDeployment
 }
 
+// GetDeploymentResult returns the result of a recently completed deployment
+func (w *Worker) GetDeploymentResult(id string) (DeploymentResult, bool) {
+	for _, r := range w.results {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return DeploymentResult{}, false
+}
+
+// GetDeploymentResults returns the most recently completed deployments, oldest first
+func (w *Worker) GetDeploymentResults() []DeploymentResult {
+	return append([]DeploymentResult{}, w.results...)
+}
+
+// RestoreDeploymentResults seeds results from a prior run of the workflow
+func (w *Worker) RestoreDeploymentResults(results []DeploymentResult) {
+	w.results = append([]DeploymentResult{}, results...)
+}
+
+func (w *Worker) recordResult(info terraform.DeploymentInfo, err error) {
+	status := SuccessDeploymentResult
+	if err != nil {
+		status = FailureDeploymentResult
+	}
+
+	w.results = append(w.results, DeploymentResult{
+		ID:       info.ID.String(),
+		Revision: info.Commit.Revision,
+		Status:   status,
+	})
+
+	if len(w.results) > MaxDeploymentResults {
+		w.results = w.results[len(w.results)-MaxDeploymentResults:]
+	}
+}
+
 func (w *Worker) awaitWork(ctx workflow.Context) workflow.Future {
 	future, settable := workflow.NewFuture(ctx)
 
 	workflow.Go(ctx, func(ctx workflow.Context) {
-		err := workflow.Await(ctx, func() bool {
-			return w.Queue.CanPop()
-		})
-
-		settable.SetError(err)
+		settable.SetError(w.awaitUnfrozenWork(ctx))
 	})
 
 	return future
 }
 
+// awaitUnfrozenWork waits until there is work which can be popped. Merged items are held while
+// a freeze window is active and the freeze is re-evaluated when it ends or the queue changes.
+// Windows are evaluated by an activity so config changes apply to items which are already queued.
+func (w *Worker) awaitUnfrozenWork(ctx workflow.Context) error {
+	v := workflow.GetVersion(ctx, version.FreezeWindows, workflow.DefaultVersion, 1)
+	if v == workflow.DefaultVersion {
+		return workflow.Await(ctx, w.Queue.CanPop)
+	}
+
+	for {
+		changes := w.Queue.GetChangeCount()
+		now := workflow.Now(ctx)
+		freeze := w.fetchFreezeState(ctx, now)
+		w.Queue.SetFreezeForMergedItems(ctx, freeze)
+		if w.Queue.CanPop() {
+			return nil
+		}
+
+		condition := func() bool {
+			return w.Queue.CanPop() || w.Queue.GetChangeCount() != changes
+		}
+
+		if !freeze.IsFrozen() {
+			if err := workflow.Await(ctx, condition); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := workflow.AwaitWithTimeout(ctx, freeze.Until.Sub(now), condition); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchFreezeState returns the freeze state of the next merged item at now. Merged items stay held if it
+// can't be fetched, the current freeze is kept while it's active otherwise they're held until the fetch is retried.
+func (w *Worker) fetchFreezeState(ctx workflow.Context, now time.Time) FreezeState {
+	items := w.Queue.GetOrderedMergedItems()
+	if len(items) == 0 {
+		return FreezeState{}
+	}
+
+	// manual deploys aren't held by freezes and they're popped first anyways
+	if w.Queue.HasPriorityItems() {
+		return w.Queue.GetFreezeState()
+	}
+
+	var resp activities.FetchFreezeStateResponse
+	err := workflow.ExecuteActivity(ctx, w.Activities.FetchFreezeState, activities.FetchFreezeStateRequest{
+		Repo: items[0].Repo,
+		Root: items[0].Root.Name,
+		Now:  now,
+	}).Get(ctx, &resp)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("error fetching freeze state, holding merged items", key.ErrKey, err)
+		if current := w.Queue.GetFreezeState(); current.Until.After(now) {
+			return current
+		}
+		return FreezeState{
+			Until:  now.Add(FreezeStateRetryInterval),
+			Name:   UnknownFreezeWindow,
+			Reason: UnknownFreezeReason,
+		}
+	}
+
+	return FreezeState{
+		Until:  resp.Until,
+		Name:   resp.Name,
+		Reason: resp.Reason,
+	}
+}
+
 func setContextKeys(ctx workflow.Context, requestedDeployment terraform.DeploymentInfo) workflow.Context {
 	ctx = workflow.WithValue(ctx, internalContext.SHAKey, requestedDeployment.Commit.Revision)
 	ctx = workflow.WithValue(ctx, internalContext.BranchKey, requestedDeployment.Commit.Branch)
@@ -2,6 +2,7 @@ package queue_test
 
 import (
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -47,10 +48,33 @@ func (q *testQueue) Push(msg internalTerraform.DeploymentInfo) {
 	q.Queue.PushBack(msg)
 }
 
+func (q *testQueue) HasPriorityItems() bool {
+	return false
+}
+
+func (q *testQueue) Requeue(msg internalTerraform.DeploymentInfo) {
+	q.Queue.PushFront(msg)
+}
+
+// GetOrderedMergedItems returns nothing since the test queue doesn't hold merged items
+func (q *testQueue) GetOrderedMergedItems() []internalTerraform.DeploymentInfo {
+	return nil
+}
+
 func (q *testQueue) SetLockForMergedItems(ctx workflow.Context, state queue.LockState) {
 	q.Lock = state
 }
 
+func (q *testQueue) GetFreezeState() queue.FreezeState {
+	return queue.FreezeState{}
+}
+
+func (q *testQueue) SetFreezeForMergedItems(ctx workflow.Context, state queue.FreezeState) {}
+
+func (q *testQueue) GetChangeCount() int {
+	return q.Queue.Len()
+}
+
 type workerRequest struct {
 	Queue                         []internalTerraform.DeploymentInfo
 	ExpectedValidationErrors      []*queue.ValidationError
@@ -71,6 +95,7 @@ type queueAndState struct {
 	Lock              queue.LockState
 	CurrentDeployment queue.CurrentDeployment
 	LatestDeployment  *deployment.Info
+	Results           []queue.DeploymentResult
 }
 
 type testDeployer struct {
@@ -142,6 +167,7 @@ func testWorkerWorkflow(ctx workflow.Context, r workerRequest) (workerResponse,
 			Lock:              q.Lock,
 			CurrentDeployment: worker.GetCurrentDeploymentState(),
 			LatestDeployment:  worker.GetLatestDeployment(),
+			Results:           worker.GetDeploymentResults(),
 		}, nil
 	})
 	if err != nil {
@@ -315,6 +341,11 @@ func TestWorker_DeploysItems_ValidationError_SkipLatestDeploymentUpdate(t *testi
 			Revision: "1",
 		}, *q.LatestDeployment)
 
+		assert.Equal(t, []queue.DeploymentResult{
+			{ID: uuid.UUID{}.String(), Revision: "1", Status: queue.SuccessDeploymentResult},
+			{ID: uuid.UUID{}.String(), Revision: "2", Status: queue.FailureDeploymentResult},
+		}, q.Results)
+
 		env.CancelWorkflow()
 	}, 10*time.Second)
 
@@ -400,7 +431,7 @@ func TestNewWorker(t *testing.T) {
 			ScheduleToCloseTimeout: 5 * time.Second,
 		})
 		q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
-		_, err := queue.NewWorker(ctx, q, &testDeployActivity{}, emptyWorkflow, emptyPRRevWorkflow, "nish/repo", "root", &testCheckRunClient{})
+		_, err := queue.NewWorker(ctx, q, &testDeployActivity{}, emptyWorkflow, emptyPRRevWorkflow, "nish/repo", "root", &testCheckRunClient{}, nil)
 		return res{
 			Lock: q.GetLockState(),
 		}, err
@@ -673,3 +704,250 @@ func TestWorker_DeploysItems_TerraformClientError_UpdateLatestDeployment(t *test
 	}, resp.CapturedArgs)
 	assert.True(t, resp.QueueIsEmpty)
 }
+
+type recordingDeployer struct {
+	start    time.Time
+	deployed map[string]time.Duration
+}
+
+func (d *recordingDeployer) Deploy(ctx workflow.Context, requestedDeployment internalTerraform.DeploymentInfo, latestDeployment *deployment.Info, _ metrics.Scope) (*deployment.Info, error) {
+	d.deployed[requestedDeployment.Commit.Revision] = workflow.Now(ctx).Sub(d.start)
+	return &deployment.Info{Revision: requestedDeployment.Commit.Revision}, nil
+}
+
+func testFrozenWorkerWorkflow(ctx workflow.Context) (map[string]time.Duration, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	now := workflow.Now(ctx)
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+	q.Push(internalTerraform.DeploymentInfo{
+		Commit: github.Commit{Revision: "merged"},
+		Root: terraform.Root{
+			Name:        "root",
+			TriggerInfo: terraform.TriggerInfo{Type: terraform.MergeTrigger},
+		},
+	})
+	q.Push(internalTerraform.DeploymentInfo{
+		Commit: github.Commit{Revision: "manual"},
+		Root: terraform.Root{
+			Name:        "root",
+			TriggerInfo: terraform.TriggerInfo{Type: terraform.ManualTrigger},
+		},
+	})
+
+	var a *testDeployActivity
+	deployer := &recordingDeployer{start: now, deployed: make(map[string]time.Duration)}
+	worker := queue.Worker{
+		Queue:      q,
+		Deployer:   deployer,
+		Activities: a,
+	}
+	worker.Work(ctx)
+
+	return deployer.deployed, nil
+}
+
+func TestWorker_FreezeWindow(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	// the freeze is evaluated against the current time on each attempt
+	end := env.Now().Add(2 * time.Hour)
+	env.OnActivity(da.FetchFreezeState, mock.Anything, mock.MatchedBy(func(r activities.FetchFreezeStateRequest) bool {
+		return r.Root == "root"
+	})).Return(func(_ context.Context, r activities.FetchFreezeStateRequest) (activities.FetchFreezeStateResponse, error) {
+		if !r.Now.Before(end) {
+			return activities.FetchFreezeStateResponse{}, nil
+		}
+		return activities.FetchFreezeStateResponse{Name: "incident", Until: end}, nil
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.CancelWorkflow()
+	}, 3*time.Hour)
+
+	env.ExecuteWorkflow(testFrozenWorkerWorkflow)
+	env.AssertExpectations(t)
+
+	var deployed map[string]time.Duration
+	assert.NoError(t, env.GetWorkflowResult(&deployed))
+
+	// manual deploys aren't held by the freeze while merged ones wait for it to end
+	assert.Len(t, deployed, 2)
+	assert.Less(t, deployed["manual"], time.Hour)
+	assert.Equal(t, 2*time.Hour, deployed["merged"])
+}
+
+func TestWorker_FreezeWindowError(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	da := &testDeployActivity{}
+	env.RegisterActivity(da)
+
+	// freeze windows can't be evaluated for the first hour
+	recovered := env.Now().Add(time.Hour)
+	env.OnActivity(da.FetchFreezeState, mock.Anything, mock.Anything).Return(func(_ context.Context, r activities.FetchFreezeStateRequest) (activities.FetchFreezeStateResponse, error) {
+		if r.Now.Before(recovered) {
+			return activities.FetchFreezeStateResponse{}, errors.New("error")
+		}
+		return activities.FetchFreezeStateResponse{}, nil
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.CancelWorkflow()
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(testFrozenWorkerWorkflow)
+	env.AssertExpectations(t)
+
+	var deployed map[string]time.Duration
+	assert.NoError(t, env.GetWorkflowResult(&deployed))
+
+	// merged items are held until the freeze is evaluated again after the error stops
+	assert.Len(t, deployed, 2)
+	assert.Less(t, deployed["manual"], time.Hour)
+	assert.GreaterOrEqual(t, deployed["merged"], time.Hour)
+	assert.Less(t, deployed["merged"], time.Hour+2*queue.FreezeStateRetryInterval)
+}
+
+// preemptingDeployer preempts the first deploy of a merged revision by queueing a manual deploy
+type preemptingDeployer struct {
+	queue    *queue.Deploy
+	deployed []string
+}
+
+func (d *preemptingDeployer) Deploy(ctx workflow.Context, requestedDeployment internalTerraform.DeploymentInfo, latestDeployment *deployment.Info, _ metrics.Scope) (*deployment.Info, error) {
+	d.deployed = append(d.deployed, requestedDeployment.Commit.Revision)
+	if len(d.deployed) == 1 {
+		d.queue.Push(internalTerraform.DeploymentInfo{
+			Commit: github.Commit{Revision: "manual"},
+			Root: terraform.Root{
+				TriggerInfo: terraform.TriggerInfo{Type: terraform.ManualTrigger},
+			},
+		})
+		return nil, &queue.PreemptedError{Revision: requestedDeployment.Commit.Revision}
+	}
+	return &deployment.Info{Revision: requestedDeployment.Commit.Revision}, nil
+}
+
+type preemptedWorkerResponse struct {
+	Deployed []string
+	Results  []queue.DeploymentResult
+}
+
+func testPreemptedWorkerWorkflow(ctx workflow.Context) (preemptedWorkerResponse, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+	q.Push(internalTerraform.DeploymentInfo{
+		Commit: github.Commit{Revision: "merged"},
+		Root: terraform.Root{
+			TriggerInfo: terraform.TriggerInfo{Type: terraform.MergeTrigger},
+		},
+	})
+
+	var a *testDeployActivity
+	deployer := &preemptingDeployer{queue: q}
+	worker := queue.Worker{
+		Queue:      q,
+		Deployer:   deployer,
+		Activities: a,
+	}
+	worker.Work(ctx)
+
+	return preemptedWorkerResponse{
+		Deployed: deployer.deployed,
+		Results:  worker.GetDeploymentResults(),
+	}, nil
+}
+
+func TestWorker_RequeuesPreemptedRevisions(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		env.CancelWorkflow()
+	}, time.Hour)
+
+	env.RegisterActivity(&testDeployActivity{})
+	env.ExecuteWorkflow(testPreemptedWorkerWorkflow)
+	env.AssertExpectations(t)
+
+	var resp preemptedWorkerResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+
+	// the preempted revision is deployed once the manual deploy is done and only its final result is kept
+	assert.Equal(t, []string{"merged", "manual", "merged"}, resp.Deployed)
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, "manual", resp.Results[0].Revision)
+	assert.Equal(t, "merged", resp.Results[1].Revision)
+}
+
+func testHeldWorkerWorkflow(ctx workflow.Context) (map[string]time.Duration, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 5 * time.Second,
+	})
+
+	q := queue.NewQueue(noopCallback, metrics.NewNullableScope())
+	q.SetLockForMergedItems(ctx, queue.LockState{Status: queue.LockedStatus, Revision: "1234"})
+	q.Push(internalTerraform.DeploymentInfo{
+		Commit: github.Commit{Revision: "held"},
+		Repo:   github.Repo{Owner: "owner", Name: "test"},
+		Root: terraform.Root{
+			Name:        "network",
+			Dependents:  []string{"service"},
+			TriggerInfo: terraform.TriggerInfo{Type: terraform.MergeTrigger},
+		},
+	})
+
+	// queue changes re-evaluate held items
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		if workflow.Sleep(ctx, time.Minute) == nil {
+			q.Push(internalTerraform.DeploymentInfo{
+				Commit: github.Commit{Revision: "other"},
+				Root: terraform.Root{
+					TriggerInfo: terraform.TriggerInfo{Type: terraform.MergeTrigger},
+				},
+			})
+		}
+	})
+
+	var a *testDeployActivity
+	deployer := &recordingDeployer{start: workflow.Now(ctx), deployed: make(map[string]time.Duration)}
+	worker := queue.Worker{
+		Queue:      q,
+		Deployer:   deployer,
+		Activities: a,
+	}
+	worker.Work(ctx)
+
+	return deployer.deployed, nil
+}
+
+func TestWorker_HeldRevisionsKeepDependentsWaiting(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		env.CancelWorkflow()
+	}, time.Hour)
+
+	// dependents keep waiting on held revisions since they're deployed once the lock is released
+	env.OnSignalExternalWorkflow(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterActivity(&testDeployActivity{})
+	env.ExecuteWorkflow(testHeldWorkerWorkflow)
+	env.AssertNotCalled(t, "workflow.SignalExternalWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	var deployed map[string]time.Duration
+	assert.NoError(t, env.GetWorkflowResult(&deployed))
+	assert.Empty(t, deployed)
+}
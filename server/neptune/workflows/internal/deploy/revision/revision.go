@@ -38,11 +38,15 @@ type NewRevisionRequest struct {
 	Root           request.Root
 	Repo           request.Repo
 	Tags           map[string]string
+
+	// DeploymentID is optional, callers can provide this in order to track the deployment of this revision
+	DeploymentID string
 }
 
 type Queue interface {
 	Push(terraform.DeploymentInfo)
 	GetLockState() queue.LockState
+	GetFreezeState() queue.FreezeState
 	SetLockForMergedItems(ctx workflow.Context, state queue.LockState)
 	Scan() []terraform.DeploymentInfo
 }
@@ -95,7 +99,7 @@ func (n *Receiver) Receive(c workflow.ReceiveChannel, more bool) {
 	})
 
 	// generate an id for this deployment and pass that to our check run
-	id, err := n.idGenerator(ctx)
+	id, err := n.deploymentID(ctx, request)
 
 	if err != nil {
 		workflow.GetLogger(ctx).Error("generating deployment id", key.ErrKey, err)
@@ -132,6 +136,19 @@ func (n *Receiver) Receive(c workflow.ReceiveChannel, more bool) {
 	})
 }
 
+func (n *Receiver) deploymentID(ctx workflow.Context, request NewRevisionRequest) (uuid.UUID, error) {
+	if request.DeploymentID == "" {
+		return n.idGenerator(ctx)
+	}
+
+	id, err := uuid.Parse(request.DeploymentID)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("invalid deployment id provided, generating a new one", "id", request.DeploymentID)
+		return n.idGenerator(ctx)
+	}
+	return id, nil
+}
+
 func (n *Receiver) createCheckRun(ctx workflow.Context, id, revision string, root activity.Root, repo github.Repo) int64 {
 	lock := n.queue.GetLockState()
 	var actions []github.CheckRunAction
@@ -143,6 +160,8 @@ func (n *Receiver) createCheckRun(ctx workflow.Context, id, revision string, roo
 		state = github.CheckRunActionRequired
 		revisionLink := github.BuildRevisionURLMarkdown(repo.GetFullName(), lock.Revision)
 		summary = fmt.Sprintf("This deploy is locked from a manual deployment for revision %s.  Unlock to proceed.", revisionLink)
+	} else if freeze := n.queue.GetFreezeState(); freeze.IsFrozen() && (root.TriggerInfo.Type == activity.MergeTrigger) {
+		summary = queue.BuildFrozenSummary(freeze)
 	}
 
 	cid, err := n.checkRunClient.CreateOrUpdate(ctx, id, notifier.GithubCheckRunRequest{
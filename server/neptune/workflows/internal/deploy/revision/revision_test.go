@@ -29,8 +29,9 @@ func (t *testCheckRunClient) CreateOrUpdate(ctx workflow.Context, deploymentID s
 }
 
 type testQueue struct {
-	Queue []terraformWorkflow.DeploymentInfo
-	Lock  queue.LockState
+	Queue  []terraformWorkflow.DeploymentInfo
+	Lock   queue.LockState
+	Freeze queue.FreezeState
 }
 
 func (q *testQueue) Scan() []terraformWorkflow.DeploymentInfo {
@@ -45,6 +46,10 @@ func (q *testQueue) GetLockState() queue.LockState {
 	return q.Lock
 }
 
+func (q *testQueue) GetFreezeState() queue.FreezeState {
+	return q.Freeze
+}
+
 func (q *testQueue) SetLockForMergedItems(ctx workflow.Context, state queue.LockState) {
 	q.Lock = state
 }
@@ -60,6 +65,7 @@ func (t testWorker) GetCurrentDeploymentState() queue.CurrentDeployment {
 type req struct {
 	ID              uuid.UUID
 	Lock            queue.LockState
+	Freeze          queue.FreezeState
 	Current         queue.CurrentDeployment
 	InitialElements []terraformWorkflow.DeploymentInfo
 	ExpectedRequest notifier.GithubCheckRunRequest
@@ -78,8 +84,9 @@ func testWorkflow(ctx workflow.Context, r req) (response, error) {
 	})
 	var timeout bool
 	queue := &testQueue{
-		Lock:  r.Lock,
-		Queue: r.InitialElements,
+		Lock:   r.Lock,
+		Freeze: r.Freeze,
+		Queue:  r.InitialElements,
 	}
 
 	worker := &testWorker{
@@ -171,6 +178,47 @@ func TestEnqueue(t *testing.T) {
 	assert.False(t, resp.Timeout)
 }
 
+func TestEnqueue_ProvidedDeploymentID(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	rev := "1234"
+	providedID := uuid.New()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("test-signal", revision.NewRevisionRequest{
+			Revision: rev,
+			Root: request.Root{
+				Name: "root",
+				TriggerInfo: request.TriggerInfo{
+					Type: request.MergeTrigger,
+				},
+			},
+			Repo:         request.Repo{Name: "nish"},
+			DeploymentID: providedID.String(),
+		})
+	}, 0)
+
+	env.ExecuteWorkflow(testWorkflow, req{
+		ID: uuid.Must(uuid.NewUUID()),
+		ExpectedRequest: notifier.GithubCheckRunRequest{
+			Title: "atlantis/deploy: root",
+			Sha:   rev,
+			Repo:  github.Repo{Name: "nish"},
+			State: github.CheckRunQueued,
+		},
+		ExpectedT: t,
+	})
+	env.AssertExpectations(t)
+
+	var resp response
+	err := env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+
+	assert.Len(t, resp.Queue, 1)
+	assert.Equal(t, providedID, resp.Queue[0].ID)
+}
+
 func TestEnqueue_ManualTrigger(t *testing.T) {
 	ts := testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
@@ -366,6 +414,52 @@ func TestEnqueue_MergeTrigger_QueueAlreadyLocked(t *testing.T) {
 	assert.False(t, resp.Timeout)
 }
 
+func TestEnqueue_MergeTrigger_QueueFrozen(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	rev := "1234"
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("test-signal", revision.NewRevisionRequest{
+			Revision: rev,
+			Root: request.Root{
+				Name: "root",
+				TriggerInfo: request.TriggerInfo{
+					Type: request.MergeTrigger,
+				},
+			},
+			Repo: request.Repo{Name: "nish"},
+		})
+	}, 0)
+
+	id := uuid.Must(uuid.NewUUID())
+
+	env.ExecuteWorkflow(testWorkflow, req{
+		ID: id,
+		Freeze: queue.FreezeState{
+			Until:  time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+			Name:   "holidays",
+			Reason: "Change freeze over the holidays.",
+		},
+		ExpectedRequest: notifier.GithubCheckRunRequest{
+			Title:   "atlantis/deploy: root",
+			Sha:     rev,
+			Repo:    github.Repo{Name: "nish"},
+			Summary: "This deploy is frozen until Mon, 26 Dec 2022 00:00:00 UTC by freeze window \"holidays\".  Change freeze over the holidays.",
+			State:   github.CheckRunQueued,
+		},
+		ExpectedT: t,
+	})
+	env.AssertExpectations(t)
+	assert.True(t, env.IsWorkflowCompleted())
+
+	var resp response
+	err := env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Queue, 1)
+}
+
 func TestEnqueue_ManualTrigger_RequestAlreadyInQueue(t *testing.T) {
 	ts := testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
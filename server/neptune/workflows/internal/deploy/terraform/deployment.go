@@ -9,6 +9,7 @@ import (
 	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 type NotificationConfig struct {
@@ -48,10 +49,11 @@ func (i DeploymentInfo) BuildPersistableInfo() *deployment.Info {
 		Revision: i.Commit.Revision,
 		Branch:   i.Commit.Branch,
 		Root: deployment.Root{
-			Name:        i.Root.Name,
-			Trigger:     string(i.Root.TriggerInfo.Type),
-			ManualRerun: i.Root.TriggerInfo.Rerun,
-			ManualForce: i.Root.TriggerInfo.Force,
+			Name:           i.Root.Name,
+			Trigger:        string(i.Root.TriggerInfo.Type),
+			ManualRerun:    i.Root.TriggerInfo.Rerun,
+			ManualForce:    i.Root.TriggerInfo.Force,
+			ManualRollback: i.Root.TriggerInfo.Rollback,
 		},
 		Repo: deployment.Repo{
 			Name:  i.Repo.Name,
@@ -60,6 +62,40 @@ func (i DeploymentInfo) BuildPersistableInfo() *deployment.Info {
 	}
 }
 
+func (i DeploymentInfo) BuildPersistableQueuedRevision() deployment.QueuedRevision {
+	return deployment.QueuedRevision{
+		ID:             i.ID.String(),
+		CheckRunID:     i.CheckRunID,
+		Revision:       i.Commit.Revision,
+		Branch:         i.Commit.Branch,
+		InitiatingUser: i.InitiatingUser,
+		Root:           i.Root,
+		Repo:           i.Repo,
+		Tags:           i.Tags,
+	}
+}
+
+// NewDeploymentInfoFromQueuedRevision rebuilds a DeploymentInfo from its persisted queue representation
+func NewDeploymentInfoFromQueuedRevision(r deployment.QueuedRevision) (DeploymentInfo, error) {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		return DeploymentInfo{}, errors.Wrapf(err, "parsing deployment id %s", r.ID)
+	}
+
+	return DeploymentInfo{
+		ID:             id,
+		CheckRunID:     r.CheckRunID,
+		InitiatingUser: r.InitiatingUser,
+		Root:           r.Root,
+		Repo:           r.Repo,
+		Tags:           r.Tags,
+		Commit: github.Commit{
+			Revision: r.Revision,
+			Branch:   r.Branch,
+		},
+	}, nil
+}
+
 func (i DeploymentInfo) ToInternalInfo() notifier.Info {
 	return notifier.Info{
 		ID:       i.ID,
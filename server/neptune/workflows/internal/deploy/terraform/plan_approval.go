@@ -10,6 +10,27 @@ import (
 )
 
 func BuildPlanApproval(requestedDeployment DeploymentInfo, latestDeployment *deployment.Info, diffDirection activities.DiffDirection, scope metrics.Scope) terraform.PlanApproval {
+	// rollbacks are always confirmed regardless of the direction since they're deliberately going back in history
+	if requestedDeployment.Root.TriggerInfo.Rollback {
+		scope.SubScopeWithTags(map[string]string{
+			constants.ManualOverrideReasonTag: RollbackMetric,
+		}).Counter(constants.ManualOverride).Inc(1)
+
+		var fromRevision string
+		if latestDeployment != nil {
+			fromRevision = latestDeployment.Revision
+		}
+
+		return terraform.PlanApproval{
+			Type: terraform.ManualApproval,
+			Reason: markdown.RenderRollbackConfirm(
+				requestedDeployment.InitiatingUser.Username,
+				requestedDeployment.Commit,
+				fromRevision,
+				requestedDeployment.Repo),
+		}
+	}
+
 	if diffDirection == activities.DirectionDiverged {
 		scope.SubScopeWithTags(map[string]string{
 			constants.ManualOverrideReasonTag: DivergedMetric,
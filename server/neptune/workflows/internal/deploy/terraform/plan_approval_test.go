@@ -6,6 +6,7 @@ import (
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	terraformActivities "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
 	"github.com/stretchr/testify/assert"
@@ -30,3 +31,17 @@ func TestPlanAppr_Default(t *testing.T) {
 
 	assert.Equal(t, "Requested Revision has diverged from deployed revision [rev](https://github.com/owner/nish/commit/rev) triggered by @nishkrishnan\n\nDeployed revision contains unmerged changes.  Deploying this revision could cause an outage, please confirm with revision owner @nishkrishnan whether this is desirable.\n\n", output.Reason)
 }
+
+func TestPlanAppr_Rollback(t *testing.T) {
+	output := terraform.BuildPlanApproval(terraform.DeploymentInfo{
+		Repo:           github.Repo{Name: "nish", Owner: "owner", DefaultBranch: "main"},
+		InitiatingUser: github.User{Username: "nishkrishnan"},
+		Commit:         github.Commit{Branch: "main", Revision: "old"},
+		Root: terraformActivities.Root{
+			TriggerInfo: terraformActivities.TriggerInfo{Type: terraformActivities.ManualTrigger, Rollback: true},
+		},
+	}, &deployment.Info{Branch: "main", Revision: "rev"}, activities.DirectionBehind, metrics.NewNullableScope())
+
+	assert.Equal(t, terraformActivities.ManualApproval, output.Type)
+	assert.Equal(t, "Rollback from deployed revision [rev](https://github.com/owner/nish/commit/rev) to [old](https://github.com/owner/nish/commit/old) requested by @nishkrishnan.\n\nConfirm the plan to deploy this earlier revision.  Merged revisions won't be deployed to this root until it's unlocked.\n", output.Reason)
+}
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	terraformActivities "github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform"
@@ -12,6 +13,7 @@ import (
 )
 
 const DivergedMetric = "diverged"
+const RollbackMetric = "rollback"
 const PlanRejected = "planrejected"
 
 type PlanRejectionError struct {
@@ -31,7 +33,42 @@ func (e PlanRejectionError) Error() string {
 type Workflow func(ctx workflow.Context, request terraform.Request) (terraform.Response, error)
 
 type stateReceiver interface {
-	Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo DeploymentInfo)
+	Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo DeploymentInfo) *state.Workflow
+}
+
+// Result is what's persisted about a run of the terraform workflow
+type Result struct {
+	// PlanReview is only set if a manual plan review was required for the deployment
+	PlanReview *deployment.PlanReview
+
+	// PlanSummary is set once the plan completes, so it's also available for failed applies
+	PlanSummary terraformActivities.PlanSummary
+
+	JobURLs JobURLs
+}
+
+// JobURLs link to the output of a deployment's terraform jobs, they're empty until the job starts.
+type JobURLs struct {
+	Plan  string
+	Apply string
+}
+
+// NewJobURLs returns the job urls of a terraform workflow's state
+func NewJobURLs(s *state.Workflow) JobURLs {
+	if s == nil {
+		return JobURLs{}
+	}
+	return JobURLs{
+		Plan:  jobURL(s.Plan),
+		Apply: jobURL(s.Apply),
+	}
+}
+
+func jobURL(job *state.Job) string {
+	if job == nil || job.Output == nil || job.Output.URL == nil {
+		return ""
+	}
+	return job.Output.URL.String()
 }
 
 func NewWorkflowRunner(w Workflow, internalNotifiers []WorkflowNotifier, additionalNotifiers ...plugins.TerraformWorkflowNotifier) *WorkflowRunner {
@@ -49,7 +86,7 @@ type WorkflowRunner struct {
 	Workflow      Workflow
 }
 
-func (r *WorkflowRunner) Run(ctx workflow.Context, deploymentInfo DeploymentInfo, planApproval terraformActivities.PlanApproval, scope metrics.Scope) error {
+func (r *WorkflowRunner) Run(ctx workflow.Context, deploymentInfo DeploymentInfo, planApproval terraformActivities.PlanApproval, scope metrics.Scope) (Result, error) {
 	id := deploymentInfo.ID
 	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
 		WorkflowID: id.String(),
@@ -79,7 +116,7 @@ func (r *WorkflowRunner) Run(ctx workflow.Context, deploymentInfo DeploymentInfo
 	return r.awaitWorkflow(ctx, future, deploymentInfo)
 }
 
-func (r *WorkflowRunner) awaitWorkflow(ctx workflow.Context, future workflow.ChildWorkflowFuture, deploymentInfo DeploymentInfo) error {
+func (r *WorkflowRunner) awaitWorkflow(ctx workflow.Context, future workflow.ChildWorkflowFuture, deploymentInfo DeploymentInfo) (Result, error) {
 	selector := workflow.NewNamedSelector(ctx, "TerraformChildWorkflow")
 
 	// our child workflow will signal us when there is a state change which we will handle accordingly.
@@ -89,14 +126,18 @@ func (r *WorkflowRunner) awaitWorkflow(ctx workflow.Context, future workflow.Chi
 	// which is necessary for knowing which check run id to update.
 	// TODO: figure out how to solve this
 	ch := workflow.GetSignalChannel(ctx, state.WorkflowStateChangeSignal)
+	var latestState *state.Workflow
 	selector.AddReceive(ch, func(c workflow.ReceiveChannel, _ bool) {
-		r.StateReceiver.Receive(ctx, c, deploymentInfo)
+		if s := r.StateReceiver.Receive(ctx, c, deploymentInfo); s != nil {
+			latestState = s
+		}
 	})
 	var workflowComplete bool
 	var err error
+	var resp terraform.Response
 	selector.AddFuture(future, func(f workflow.Future) {
 		workflowComplete = true
-		err = f.Get(ctx, nil)
+		err = f.Get(ctx, &resp)
 	})
 
 	for {
@@ -122,11 +163,51 @@ func (r *WorkflowRunner) awaitWorkflow(ctx workflow.Context, future workflow.Chi
 		if appErr.Type() == terraform.PlanRejectedErrorType {
 			v := workflow.GetVersion(ctx, PlanRejected, workflow.DefaultVersion, workflow.Version(1))
 			if v == workflow.DefaultVersion {
-				return PlanRejectionError{msg: msg}
+				return Result{}, PlanRejectionError{msg: msg}
 			}
-			return NewPlanRejectionError(msg)
+
+			// the workflow's response isn't available so the review is taken from its latest state
+			return Result{
+				PlanReview:  toRejectedPlanReview(latestState),
+				PlanSummary: planSummary(latestState),
+				JobURLs:     NewJobURLs(latestState),
+			}, NewPlanRejectionError(msg)
 		}
 	}
 
-	return errors.Wrap(err, "executing terraform workflow")
+	return Result{
+		PlanReview:  toPersistablePlanReview(resp.PlanReview),
+		PlanSummary: planSummary(latestState),
+		JobURLs:     NewJobURLs(latestState),
+	}, errors.Wrap(err, "executing terraform workflow")
+}
+
+func planSummary(s *state.Workflow) terraformActivities.PlanSummary {
+	if s == nil || s.Plan == nil || s.Plan.Output == nil {
+		return terraformActivities.PlanSummary{}
+	}
+	return s.Plan.Output.Summary
+}
+
+func toPersistablePlanReview(review *state.PlanReview) *deployment.PlanReview {
+	if review == nil || review.Status != state.ApprovedPlanReviewStatus {
+		return nil
+	}
+
+	return &deployment.PlanReview{
+		User:   review.User,
+		Reason: review.Reason,
+	}
+}
+
+// toRejectedPlanReview returns nil if the plan wasn't rejected by a user, ie. the review timed out
+func toRejectedPlanReview(s *state.Workflow) *deployment.PlanReview {
+	if s == nil || s.PlanReview == nil || s.PlanReview.Status != state.RejectedPlanReviewStatus {
+		return nil
+	}
+
+	return &deployment.PlanReview{
+		User:   s.PlanReview.User,
+		Reason: s.PlanReview.Reason,
+	}
 }
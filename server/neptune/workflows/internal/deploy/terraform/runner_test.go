@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/deployment"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	internalTerraform "github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/terraform"
@@ -22,18 +23,41 @@ type testStateReceiver struct {
 	payloads []testSignalPayload
 }
 
-func (r *testStateReceiver) Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo internalTerraform.DeploymentInfo) {
+var testPlanSummary = terraform.PlanSummary{
+	Updates: []terraform.ResourceSummary{{Address: "addr"}},
+}
+
+func (r *testStateReceiver) Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo internalTerraform.DeploymentInfo) *state.Workflow {
 	var payload testSignalPayload
 	c.Receive(ctx, &payload)
 
 	r.payloads = append(r.payloads, payload)
+	return &state.Workflow{
+		Plan: &state.Job{
+			Output: &state.JobOutput{Summary: testPlanSummary},
+		},
+		PlanReview: payload.PlanReview,
+	}
 }
 
 type testSignalPayload struct {
-	S string
+	S          string
+	PlanReview *state.PlanReview
 }
 
 func testTerraformWorklfowWithPlanRejectionError(ctx workflow.Context, request terraformWorkflow.Request) (terraformWorkflow.Response, error) {
+	parentExecution := workflow.GetInfo(ctx).ParentWorkflowExecution
+	payload := testSignalPayload{
+		PlanReview: &state.PlanReview{
+			Status: state.RejectedPlanReviewStatus,
+			User:   "nish",
+			Reason: "unexpected deletes",
+		},
+	}
+	if err := workflow.SignalExternalWorkflow(ctx, parentExecution.ID, parentExecution.RunID, state.WorkflowStateChangeSignal, payload).Get(ctx, nil); err != nil {
+		return terraformWorkflow.Response{}, err
+	}
+
 	return terraformWorkflow.Response{}, temporal.NewNonRetryableApplicationError("some message", terraformWorkflow.PlanRejectedErrorType, terraformWorkflow.ApplicationError{ErrType: terraformWorkflow.PlanRejectedErrorType, Msg: "something"})
 }
 
@@ -66,6 +90,8 @@ type request struct {
 type response struct {
 	Payloads      []testSignalPayload
 	PlanRejection bool
+	PlanReview    *deployment.PlanReview
+	PlanSummary   terraform.PlanSummary
 }
 
 func parentWorkflow(ctx workflow.Context, r request) (response, error) {
@@ -80,17 +106,21 @@ func parentWorkflow(ctx workflow.Context, r request) (response, error) {
 		runner.Workflow = testTerraformWorkflow
 	}
 
-	if err := runner.Run(ctx, r.Info, r.PlanApproval, metrics.NewNullableScope()); err != nil {
+	result, err := runner.Run(ctx, r.Info, r.PlanApproval, metrics.NewNullableScope())
+	if err != nil {
 		if _, ok := err.(*internalTerraform.PlanRejectionError); ok {
 			return response{
 				PlanRejection: true,
+				PlanReview:    result.PlanReview,
 			}, nil
 		}
 		return response{}, err
 	}
 
 	return response{
-		Payloads: receiver.payloads,
+		Payloads:    receiver.payloads,
+		PlanReview:  result.PlanReview,
+		PlanSummary: result.PlanSummary,
 	}, nil
 }
 
@@ -126,6 +156,7 @@ func TestWorkflowRunner_Run(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Len(t, resp.Payloads, 2)
+	assert.Equal(t, testPlanSummary, resp.PlanSummary)
 
 	for _, p := range resp.Payloads {
 		assert.Equal(t, testSignalPayload{
@@ -159,7 +190,13 @@ func TestWorkflowRunner_RunWithDivergedCommit(t *testing.T) {
 		DeploymentID: r.Info.ID.String(),
 		Revision:     r.Info.Commit.Revision,
 	}).Return(func(ctx workflow.Context, request terraformWorkflow.Request) (terraformWorkflow.Response, error) {
-		return terraformWorkflow.Response{}, nil
+		return terraformWorkflow.Response{
+			PlanReview: &state.PlanReview{
+				Status: state.ApprovedPlanReviewStatus,
+				User:   "nish",
+				Reason: "rolling back",
+			},
+		}, nil
 	})
 
 	env.ExecuteWorkflow(parentWorkflow, r)
@@ -169,6 +206,10 @@ func TestWorkflowRunner_RunWithDivergedCommit(t *testing.T) {
 	var resp response
 	err := env.GetWorkflowResult(&resp)
 	assert.NoError(t, err)
+	assert.Equal(t, &deployment.PlanReview{
+		User:   "nish",
+		Reason: "rolling back",
+	}, resp.PlanReview)
 }
 
 func TestWorkflowRunner_PlanRejected(t *testing.T) {
@@ -189,4 +230,8 @@ func TestWorkflowRunner_PlanRejected(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.True(t, resp.PlanRejection)
+	assert.Equal(t, &deployment.PlanReview{
+		User:   "nish",
+		Reason: "unexpected deletes",
+	}, resp.PlanReview)
 }
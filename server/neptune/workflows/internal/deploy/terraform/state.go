@@ -22,7 +22,8 @@ type StateReceiver struct {
 	AdditionalNotifiers []plugins.TerraformWorkflowNotifier
 }
 
-func (n *StateReceiver) Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo DeploymentInfo) {
+// Receive notifies of the received state and returns it
+func (n *StateReceiver) Receive(ctx workflow.Context, c workflow.ReceiveChannel, deploymentInfo DeploymentInfo) *state.Workflow {
 	var workflowState *state.Workflow
 	c.Receive(ctx, &workflowState)
 
@@ -45,4 +46,6 @@ func (n *StateReceiver) Receive(ctx workflow.Context, c workflow.ReceiveChannel,
 			workflow.GetLogger(ctx).Error(errors.Wrap(err, "notifying workflow state change").Error())
 		}
 	}
+
+	return workflowState
 }
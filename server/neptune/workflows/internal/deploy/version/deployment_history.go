@@ -0,0 +1,6 @@
+package version
+
+const DeploymentHistory = "deployment-history"
+
+// RejectedDeploymentHistory appends deploys with rejected plans to the history
+const RejectedDeploymentHistory = "rejected-deployment-history"
@@ -0,0 +1,3 @@
+package version
+
+const FreezeWindows = "freeze-windows"
@@ -0,0 +1,3 @@
+package version
+
+const UpstreamDeployments = "upstream-deployments"
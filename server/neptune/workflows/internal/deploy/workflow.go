@@ -20,16 +20,23 @@ import (
 )
 
 const (
-	TaskQueue          = "deploy"
-	AddNotifierVersion = "add-notifier"
+	TaskQueue                = "deploy"
+	AddNotifierVersion       = "add-notifier"
+	PersistQueueVersion      = "persist-queue"
+	PersistInProgressVersion = "persist-in-progress"
+	ContinueAsNewVersion     = "continue-as-new"
+
+	// DefaultContinueAsNewThreshold is the number of revisions a workflow receives before continuing as new
+	DefaultContinueAsNewThreshold = 250
 
 	RevisionReceiveTimeout = 60 * time.Minute
 
 	QueueStatusNotifierHourPST = 10
 	QueueStatusNotifierHourUTC = 17
 
-	ActiveDeployWorkflowStat  = "active"
-	SuccessDeployWorkflowStat = "success"
+	ActiveDeployWorkflowStat        = "active"
+	SuccessDeployWorkflowStat       = "success"
+	ContinueAsNewDeployWorkflowStat = workflowMetrics.ContinueAsNewStat
 )
 
 type workerActivities struct {
@@ -44,6 +51,7 @@ const (
 	OnTimeout
 	OnReceive
 	OnNotify
+	OnContinueAsNew
 	OnUnknown
 )
 
@@ -62,6 +70,16 @@ type SignalReceiver interface {
 type QueueWorker interface {
 	Work(ctx workflow.Context)
 	GetState() queue.WorkerState
+	IsDeploying() bool
+}
+
+type QueuePersister interface {
+	Run(ctx workflow.Context)
+}
+
+type QueueEditor interface {
+	Run(ctx workflow.Context)
+	Drain(ctx workflow.Context)
 }
 
 type ChildWorkflows struct {
@@ -96,6 +114,22 @@ type Runner struct {
 	Notifier                 QueueStatusNotifier
 	NotifierPeriod           DurationGenerator
 	NotifierHour             int
+
+	// optional, nil for workflows started before queue persistence was introduced
+	QueuePersister QueuePersister
+
+	// optional, handles removing and reordering queued revisions
+	QueueEditor QueueEditor
+
+	// ContinueAsNewThreshold is the number of revisions received before we continue as new. It's only 0, which
+	// disables this, for workflows started before continue as new was supported since an unset request
+	// threshold is replaced with DefaultContinueAsNewThreshold.
+	ContinueAsNewThreshold int
+	// ContinueAsNewRequest builds the request for the next run, it's invoked once the worker has shutdown
+	ContinueAsNewRequest func(ctx workflow.Context) Request
+
+	// mutable
+	revisionCount int
 }
 
 func newRunner(ctx workflow.Context, request Request, children ChildWorkflows, plugins plugins.Deploy) (*Runner, error) {
@@ -107,7 +141,23 @@ func newRunner(ctx workflow.Context, request Request, children ChildWorkflows, p
 
 	scope := workflowMetrics.NewScope(ctx)
 
-	checkRunCache := notifier.NewGithubCheckRunCache(a)
+	continueAsNewThreshold := request.ContinueAsNewThreshold
+	if continueAsNewThreshold == 0 {
+		continueAsNewThreshold = DefaultContinueAsNewThreshold
+	}
+
+	// workflows started prior to this change could have received more revisions than our threshold
+	// so we need to guard this to avoid non-determinism errors
+	if v := workflow.GetVersion(ctx, ContinueAsNewVersion, workflow.DefaultVersion, workflow.Version(1)); v == workflow.DefaultVersion {
+		continueAsNewThreshold = 0
+	}
+
+	var checkRunCache *notifier.GithubCheckRunCache
+	if request.State != nil {
+		checkRunCache = notifier.NewGithubCheckRunCacheFromSnapshot(a, request.State.CheckRuns)
+	} else {
+		checkRunCache = notifier.NewGithubCheckRunCache(a)
+	}
 
 	lockStateUpdater := queue.LockStateUpdater{
 		GithubCheckRunCache: checkRunCache,
@@ -116,14 +166,54 @@ func newRunner(ctx workflow.Context, request Request, children ChildWorkflows, p
 		lockStateUpdater.UpdateQueuedRevisions(ctx, d, request.Repo.FullName)
 	}, scope)
 
-	worker, err := queue.NewWorker(ctx, revisionQueue, a, children.Terraform, children.SetPRRevision, request.Repo.FullName, request.Root.Name, checkRunCache, plugins.Notifiers...)
-	if err != nil {
-		return nil, err
+	var worker *queue.Worker
+	var upstream *queue.UpstreamDeployments
+	if request.State != nil {
+		revisionQueue.Restore(request.State.Queue, request.State.Lock)
+		upstream = queue.NewUpstreamDeployments(ctx, request.State.UpstreamDeployments, request.State.Promotions)
+		worker = queue.NewWorkerWithLatestDeployment(revisionQueue, a, children.Terraform, children.SetPRRevision, checkRunCache, upstream, request.State.LatestDeployment, plugins.Notifiers...)
+		worker.RestoreDeploymentResults(request.State.Results)
+	} else {
+		var err error
+		upstream = queue.NewUpstreamDeployments(ctx, nil, nil)
+		worker, err = queue.NewWorker(ctx, revisionQueue, a, children.Terraform, children.SetPRRevision, request.Repo.FullName, request.Root.Name, checkRunCache, upstream, plugins.Notifiers...)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	revisionReceiver := revision.NewReceiver(ctx, revisionQueue, checkRunCache, sideeffect.GenerateUUID, worker)
 
+	var queuePersister QueuePersister
+	if v := workflow.GetVersion(ctx, PersistQueueVersion, workflow.DefaultVersion, workflow.Version(1)); v > workflow.DefaultVersion {
+		persister := queue.NewPersister(revisionQueue, a, request.Repo.FullName, request.Root.Name)
+		if v := workflow.GetVersion(ctx, PersistInProgressVersion, workflow.DefaultVersion, workflow.Version(1)); v > workflow.DefaultVersion {
+			persister.InProgress = worker
+		}
+
+		// state carried over from a previous run takes precedence over what's been persisted
+		if request.State == nil {
+			if err := persister.Restore(ctx); err != nil {
+				return nil, errors.Wrap(err, "restoring deploy queue")
+			}
+		}
+		queuePersister = persister
+	}
+
+	if err := setQueueQueryHandler(ctx, revisionQueue, worker); err != nil {
+		return nil, errors.Wrap(err, "setting queue query handler")
+	}
+
+	if err := setDeploymentStatusQueryHandler(ctx, revisionQueue, worker); err != nil {
+		return nil, errors.Wrap(err, "setting deployment status query handler")
+	}
+
+	queueEditor := queue.NewEditor(ctx, revisionQueue, checkRunCache)
+	unlockSignalChannel := workflow.GetSignalChannel(ctx, queue.UnlockSignalName)
+
 	return &Runner{
+		QueuePersister:           queuePersister,
+		QueueEditor:              queueEditor,
 		Queue:                    revisionQueue,
 		Timeout:                  RevisionReceiveTimeout,
 		QueueWorker:              worker,
@@ -137,6 +227,35 @@ func newRunner(ctx workflow.Context, request Request, children ChildWorkflows, p
 			DeployQueue: revisionQueue,
 			Activities:  a,
 		},
+		ContinueAsNewThreshold: continueAsNewThreshold,
+		ContinueAsNewRequest: func(ctx workflow.Context) Request {
+			// the worker is no longer around to receive unlock signals so let's handle these ourselves
+			// to ensure they aren't dropped
+			var unlockRequest queue.UnlockSignalRequest
+			for unlockSignalChannel.ReceiveAsync(&unlockRequest) {
+				revisionQueue.SetLockForMergedItems(ctx, queue.LockState{
+					Status: queue.UnlockedStatus,
+				})
+			}
+			queueEditor.Drain(ctx)
+			upstream.Drain(ctx)
+
+			return Request{
+				Repo:                   request.Repo,
+				Root:                   request.Root,
+				ContinueAsNewThreshold: request.ContinueAsNewThreshold,
+				State: &State{
+					Queue:            revisionQueue.Scan(),
+					Lock:             revisionQueue.GetLockState(),
+					LatestDeployment: worker.GetLatestDeployment(),
+					CheckRuns:        checkRunCache.Snapshot(),
+					Results:          worker.GetDeploymentResults(),
+
+					UpstreamDeployments: upstream.Snapshot(),
+					Promotions:          upstream.Promotions.Snapshot(),
+				},
+			}
+		},
 	}, nil
 }
 
@@ -157,14 +276,28 @@ func (r *Runner) Run(ctx workflow.Context) error {
 	wg.Add(1)
 
 	// if this panics in anyway, we'll need to ship a fix to the running workflows, else risk dropping
-	// signals
-	// should we have some way of persisting our queue in case of workflow termination?
-	// Let's address this in a followup
+	// signals. Queued revisions are persisted though, so they'll be restored once the workflow restarts.
 	workflow.Go(workerCtx, func(ctx workflow.Context) {
 		defer wg.Done()
 		r.QueueWorker.Work(ctx)
 	})
 
+	if r.QueuePersister != nil {
+		wg.Add(1)
+		workflow.Go(workerCtx, func(ctx workflow.Context) {
+			defer wg.Done()
+			r.QueuePersister.Run(ctx)
+		})
+	}
+
+	if r.QueueEditor != nil {
+		wg.Add(1)
+		workflow.Go(workerCtx, func(ctx workflow.Context) {
+			defer wg.Done()
+			r.QueueEditor.Run(ctx)
+		})
+	}
+
 	newRevisionTimerFunc := func(f workflow.Future) {
 		err := f.Get(ctx, nil)
 
@@ -181,6 +314,7 @@ func (r *Runner) Run(ctx workflow.Context) error {
 	}
 	s.AddReceive(r.NewRevisionSignalChannel, func(c workflow.ReceiveChannel, more bool) {
 		r.RevisionReceiver.Receive(c, more)
+		r.revisionCount++
 		action = OnReceive
 	})
 	cancelTimer, _ := s.AddTimeout(ctx, r.Timeout, newRevisionTimerFunc)
@@ -209,6 +343,8 @@ func (r *Runner) Run(ctx workflow.Context) error {
 		s.AddTimeout(ctx, notifierPeriod, notifyTimerFunc)
 	}
 
+	var continueAsNewPending bool
+
 	// main loop which handles external signals
 	// and in turn signals the queue worker
 OUT:
@@ -227,6 +363,28 @@ OUT:
 		case OnReceive:
 			cancelTimer()
 			cancelTimer, _ = s.AddTimeout(ctx, r.Timeout, newRevisionTimerFunc)
+
+			// only wait on the worker once, subsequent revisions will be carried over
+			if r.shouldContinueAsNew() && !continueAsNewPending {
+				continueAsNewPending = true
+				s.AddFuture(r.awaitIdleWorker(ctx), func(f workflow.Future) {
+					action = OnContinueAsNew
+				})
+			}
+		case OnContinueAsNew:
+			// guard against the worker picking up more work before we got here
+			if r.QueueWorker.IsDeploying() {
+				s.AddFuture(r.awaitIdleWorker(ctx), func(f workflow.Future) {
+					action = OnContinueAsNew
+				})
+				continue
+			}
+
+			workflow.GetLogger(ctx).Info("continuing as new", "revisions", r.revisionCount)
+			shutdownWorker()
+			wg.Wait(ctx)
+
+			return r.continueAsNew(ctx)
 		case OnTimeout:
 			workflow.GetLogger(ctx).Info("revision receiver timeout")
 
@@ -251,3 +409,34 @@ OUT:
 
 	return nil
 }
+
+func (r *Runner) shouldContinueAsNew() bool {
+	return r.ContinueAsNewThreshold > 0 && r.revisionCount >= r.ContinueAsNewThreshold
+}
+
+func (r *Runner) awaitIdleWorker(ctx workflow.Context) workflow.Future {
+	future, settable := workflow.NewFuture(ctx)
+
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		err := workflow.Await(ctx, func() bool {
+			return !r.QueueWorker.IsDeploying()
+		})
+
+		settable.SetError(err)
+	})
+
+	return future
+}
+
+func (r *Runner) continueAsNew(ctx workflow.Context) error {
+	// drain any buffered revisions so they are carried over to the next run instead of being dropped
+	drainSelector := workflow.NewSelector(ctx)
+	drainSelector.AddReceive(r.NewRevisionSignalChannel, r.RevisionReceiver.Receive)
+	for drainSelector.HasPending() {
+		drainSelector.Select(ctx)
+	}
+
+	r.Scope.Counter(ContinueAsNewDeployWorkflowStat).Inc(1)
+
+	return workflow.NewContinueAsNewError(ctx, workflow.GetInfo(ctx).WorkflowType.Name, r.ContinueAsNewRequest(ctx))
+}
@@ -0,0 +1,50 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/revision/queue"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+// testContinueAsNewWorkflow continues as new twice, the status query is served by the last run
+func testContinueAsNewWorkflow(ctx workflow.Context, state State) error {
+	request := Request{
+		Repo:  Repo{FullName: "owner/repo"},
+		Root:  Root{Name: "root"},
+		State: &state,
+	}
+
+	for i := 0; i < 2; i++ {
+		runner, err := newRunner(ctx, request, ChildWorkflows{}, plugins.Deploy{})
+		if err != nil {
+			return err
+		}
+		request = runner.ContinueAsNewRequest(ctx)
+	}
+
+	_, err := newRunner(ctx, request, ChildWorkflows{}, plugins.Deploy{})
+	return err
+}
+
+func TestContinueAsNew_DeploymentStatus(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	env.ExecuteWorkflow(testContinueAsNewWorkflow, State{
+		Results: []queue.DeploymentResult{
+			{ID: "1234", Revision: "abc", Status: queue.SuccessDeploymentResult},
+		},
+	})
+	assert.NoError(t, env.GetWorkflowError())
+
+	value, err := env.QueryWorkflow(DeploymentStatusQueryName, "1234")
+	assert.NoError(t, err)
+
+	var status DeploymentState
+	assert.NoError(t, value.Get(&status))
+	assert.Equal(t, DeploymentState{ID: "1234", Revision: "abc", Status: SuccessDeploymentStatus}, status)
+}
@@ -23,6 +23,10 @@ func (w *queueWorker) GetState() queue.WorkerState {
 	return w.state
 }
 
+func (w *queueWorker) IsDeploying() bool {
+	return w.state == queue.WorkingWorkerState
+}
+
 func (w *queueWorker) Work(ctx workflow.Context) {
 	w.state = queue.WorkingWorkerState
 	// sleep and then flip to waiting
@@ -69,8 +73,9 @@ type response struct {
 }
 
 type request struct {
-	WorkerState queue.WorkerState
-	QueueItem   string
+	WorkerState            queue.WorkerState
+	QueueItem              string
+	ContinueAsNewThreshold int
 }
 
 func testWorkflow(ctx workflow.Context, r request) (response, error) {
@@ -92,6 +97,12 @@ func testWorkflow(ctx workflow.Context, r request) (response, error) {
 		RevisionReceiver:         receiver,
 		NewRevisionSignalChannel: workflow.GetSignalChannel(ctx, testSignalID),
 		Scope:                    metrics.NewNullableScope(),
+		ContinueAsNewThreshold:   r.ContinueAsNewThreshold,
+		ContinueAsNewRequest: func(ctx workflow.Context) deploy.Request {
+			return deploy.Request{
+				State: &deploy.State{},
+			}
+		},
 	}
 
 	workflow.Go(ctx, func(ctx workflow.Context) {
@@ -177,3 +188,24 @@ func TestRunner(t *testing.T) {
 		assert.Equal(t, response{WorkerCtxCancelled: true, ReceiverCalled: true, NotifierCalled: true}, resp)
 	})
 }
+
+func TestRunner_ContinueAsNew(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.OnGetVersion(deploy.AddNotifierVersion, workflow.DefaultVersion, workflow.Version(2)).Return(workflow.DefaultVersion)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(testSignalID, "")
+	}, 5*time.Second)
+
+	// the worker is busy for the first 60 seconds so we shouldn't continue as new until then
+	env.ExecuteWorkflow(testWorkflow, request{
+		QueueItem:              "hi",
+		ContinueAsNewThreshold: 1,
+	})
+
+	err := env.GetWorkflowError()
+	var continueAsNewErr *workflow.ContinueAsNewError
+	assert.ErrorAs(t, err, &continueAsNewErr)
+	assert.Equal(t, "testWorkflow", continueAsNewErr.WorkflowType.Name)
+}
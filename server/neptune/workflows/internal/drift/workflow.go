@@ -0,0 +1,189 @@
+package drift
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github/markdown"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/request"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/request/converter"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/sideeffect"
+	internalTerraform "github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	DetectedMetric = "detected"
+	FailureMetric  = "failure"
+)
+
+// Request is used to plan a root at the revision it was last deployed at
+type Request struct {
+	Repo     request.Repo
+	Root     request.Root
+	Revision string
+	Branch   string
+}
+
+type Response struct {
+	Detected bool
+	Summary  terraform.PlanSummary
+}
+
+type checkRunClient interface {
+	CreateOrUpdate(ctx workflow.Context, deploymentID string, request notifier.GithubCheckRunRequest) (int64, error)
+}
+
+type TerraformWorkflow func(ctx workflow.Context, request internalTerraform.Request) (internalTerraform.Response, error)
+
+func Workflow(ctx workflow.Context, request Request, tfWorkflow TerraformWorkflow, notifiers ...plugins.TerraformWorkflowNotifier) (Response, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 5,
+		},
+	})
+
+	var ga *activities.Github
+	runner := &Runner{
+		TerraformWorkflow: tfWorkflow,
+		CheckRunClient:    notifier.NewGithubCheckRunCache(ga),
+		Notifiers:         notifiers,
+		Scope:             metrics.NewScope(ctx, "drift"),
+	}
+
+	return runner.Run(ctx, request)
+}
+
+type Runner struct {
+	TerraformWorkflow TerraformWorkflow
+	CheckRunClient    checkRunClient
+	Notifiers         []plugins.TerraformWorkflowNotifier
+	Scope             metrics.Scope
+}
+
+func (r *Runner) Run(ctx workflow.Context, request Request) (Response, error) {
+	id, err := sideeffect.GenerateUUID(ctx)
+	if err != nil {
+		return Response{}, errors.Wrap(err, "generating drift id")
+	}
+
+	repo := converter.Repo(request.Repo)
+	root := converter.Root(request.Root)
+	info := plugins.TerraformDeploymentInfo{
+		ID: id,
+		Commit: github.Commit{
+			Revision: request.Revision,
+			Branch:   request.Branch,
+		},
+		Root: root,
+		Repo: repo,
+	}
+
+	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID: id.String(),
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+		WaitForCancellation: true,
+		SearchAttributes: map[string]interface{}{
+			"atlantis_repository": repo.GetFullName(),
+			"atlantis_root":       root.Name,
+			"atlantis_revision":   request.Revision,
+		},
+	})
+
+	future := workflow.ExecuteChildWorkflow(ctx, r.TerraformWorkflow, internalTerraform.Request{
+		Repo:         repo,
+		Root:         root,
+		DeploymentID: id.String(),
+		Revision:     request.Revision,
+		WorkflowMode: terraform.Drift,
+	})
+
+	// our child workflow signals us with state changes which we forward to our notifiers,
+	// the latest state is kept around in order to link the plan logs once drift is detected.
+	var latestState *state.Workflow
+	var tfResponse internalTerraform.Response
+	var workflowComplete bool
+	selector := workflow.NewNamedSelector(ctx, "DriftTerraformChildWorkflow")
+	selector.AddReceive(workflow.GetSignalChannel(ctx, state.WorkflowStateChangeSignal), func(c workflow.ReceiveChannel, _ bool) {
+		c.Receive(ctx, &latestState)
+		r.notify(ctx, info, latestState.ToExternalWorkflowState())
+	})
+	selector.AddFuture(future, func(f workflow.Future) {
+		workflowComplete = true
+		err = f.Get(ctx, &tfResponse)
+	})
+
+	for !workflowComplete {
+		selector.Select(ctx)
+	}
+
+	if err != nil {
+		r.Scope.Counter(FailureMetric).Inc(1)
+		return Response{}, errors.Wrap(err, "executing terraform workflow")
+	}
+
+	response := Response{
+		Detected: !tfResponse.PlanSummary.IsEmpty(),
+		Summary:  tfResponse.PlanSummary,
+	}
+
+	finalState := &plugins.TerraformWorkflowState{}
+	if latestState != nil {
+		finalState = latestState.ToExternalWorkflowState()
+	}
+	finalState.Drift = &plugins.DriftState{
+		Detected: response.Detected,
+		Summary:  response.Summary,
+	}
+	r.notify(ctx, info, finalState)
+
+	if !response.Detected {
+		return response, nil
+	}
+
+	r.Scope.Counter(DetectedMetric).Inc(1)
+
+	var planJob *state.Job
+	if latestState != nil {
+		planJob = latestState.Plan
+	}
+
+	_, err = r.CheckRunClient.CreateOrUpdate(ctx, id.String(), notifier.GithubCheckRunRequest{
+		Title:   BuildCheckRunTitle(root.Name),
+		Sha:     request.Revision,
+		Repo:    repo,
+		State:   github.CheckRunActionRequired,
+		Summary: markdown.RenderDriftTmpl(repo, request.Revision, planJob, response.Summary),
+		Mode:    terraform.Drift,
+	})
+	if err != nil {
+		return response, errors.Wrap(err, "creating drift check run")
+	}
+
+	return response, nil
+}
+
+func (r *Runner) notify(ctx workflow.Context, info plugins.TerraformDeploymentInfo, workflowState *plugins.TerraformWorkflowState) {
+	for _, n := range r.Notifiers {
+		if err := n.Notify(ctx, info, workflowState); err != nil {
+			r.Scope.Counter("notifier_plugin_failure").Inc(1)
+			workflow.GetLogger(ctx).Error(errors.Wrap(err, "notifying drift state").Error())
+		}
+	}
+}
+
+func BuildCheckRunTitle(rootName string) string {
+	return fmt.Sprintf("atlantis/drift: %s", rootName)
+}
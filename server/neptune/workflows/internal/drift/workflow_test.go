@@ -0,0 +1,144 @@
+package drift_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/deploy/request"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/drift"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/metrics"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/notifier"
+	terraformWorkflow "github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/plugins"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+var driftedSummary = terraform.PlanSummary{
+	Updates: []terraform.ResourceSummary{{Address: "aws_s3_bucket.bucket"}},
+}
+
+func testTerraformWorkflow(ctx workflow.Context, r terraformWorkflow.Request) (terraformWorkflow.Response, error) {
+	parent := workflow.GetInfo(ctx).ParentWorkflowExecution
+
+	mode := r.WorkflowMode
+	planState := &state.Workflow{
+		Mode: &mode,
+		Plan: &state.Job{
+			ID:     "1234",
+			Status: state.SuccessJobStatus,
+			Output: &state.JobOutput{
+				URL: &url.URL{Scheme: "https", Host: "atlantis.com", Path: "/jobs/1234"},
+			},
+		},
+	}
+	if err := workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, state.WorkflowStateChangeSignal, planState).Get(ctx, nil); err != nil {
+		return terraformWorkflow.Response{}, err
+	}
+
+	if r.Revision == "drifted" {
+		return terraformWorkflow.Response{PlanSummary: driftedSummary}, nil
+	}
+	return terraformWorkflow.Response{}, nil
+}
+
+type recordingCheckRunClient struct {
+	Requests []notifier.GithubCheckRunRequest
+}
+
+func (c *recordingCheckRunClient) CreateOrUpdate(ctx workflow.Context, deploymentID string, request notifier.GithubCheckRunRequest) (int64, error) {
+	c.Requests = append(c.Requests, request)
+	return 1, nil
+}
+
+type recordingNotifier struct {
+	States []*plugins.TerraformWorkflowState
+}
+
+func (n *recordingNotifier) Notify(ctx workflow.Context, info plugins.TerraformDeploymentInfo, s *plugins.TerraformWorkflowState) error {
+	n.States = append(n.States, s)
+	return nil
+}
+
+type testResponse struct {
+	Response  drift.Response
+	CheckRuns []notifier.GithubCheckRunRequest
+	States    []*plugins.TerraformWorkflowState
+}
+
+func testDriftWorkflow(ctx workflow.Context, r drift.Request) (testResponse, error) {
+	checkRunClient := &recordingCheckRunClient{}
+	n := &recordingNotifier{}
+	runner := &drift.Runner{
+		TerraformWorkflow: testTerraformWorkflow,
+		CheckRunClient:    checkRunClient,
+		Notifiers:         []plugins.TerraformWorkflowNotifier{n},
+		Scope:             metrics.NewNullableScope(),
+	}
+
+	resp, err := runner.Run(ctx, r)
+	return testResponse{
+		Response:  resp,
+		CheckRuns: checkRunClient.Requests,
+		States:    n.States,
+	}, err
+}
+
+func buildRequest(revision string) drift.Request {
+	return drift.Request{
+		Repo: request.Repo{
+			FullName: "owner/repo",
+			Owner:    "owner",
+			Name:     "repo",
+		},
+		Root: request.Root{
+			Name: "root",
+		},
+		Revision: revision,
+		Branch:   "main",
+	}
+}
+
+func TestDrift_Detected(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testTerraformWorkflow)
+
+	env.ExecuteWorkflow(testDriftWorkflow, buildRequest("drifted"))
+
+	var resp testResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.Equal(t, drift.Response{Detected: true, Summary: driftedSummary}, resp.Response)
+
+	assert.Len(t, resp.CheckRuns, 1)
+	assert.Equal(t, "atlantis/drift: root", resp.CheckRuns[0].Title)
+	assert.Equal(t, "drifted", resp.CheckRuns[0].Sha)
+	assert.Equal(t, github.CheckRunActionRequired, resp.CheckRuns[0].State)
+	assert.Contains(t, resp.CheckRuns[0].Summary, "aws_s3_bucket.bucket")
+	assert.Contains(t, resp.CheckRuns[0].Summary, "https://atlantis.com/jobs/1234")
+
+	assert.Len(t, resp.States, 2)
+	assert.Nil(t, resp.States[0].Drift)
+	assert.Equal(t, &plugins.DriftState{Detected: true, Summary: driftedSummary}, resp.States[1].Drift)
+	assert.Equal(t, plugins.SuccessJobStatus, resp.States[1].Plan.Status)
+}
+
+func TestDrift_NotDetected(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testTerraformWorkflow)
+
+	env.ExecuteWorkflow(testDriftWorkflow, buildRequest("abc"))
+
+	var resp testResponse
+	assert.NoError(t, env.GetWorkflowResult(&resp))
+	assert.False(t, resp.Response.Detected)
+	assert.Empty(t, resp.CheckRuns)
+
+	assert.Len(t, resp.States, 2)
+	assert.Equal(t, &plugins.DriftState{}, resp.States[1].Drift)
+}
@@ -6,6 +6,9 @@ import (
 	"go.temporal.io/sdk/workflow"
 )
 
+// ContinueAsNewStat counts long lived workflows continuing as new to bound their history
+const ContinueAsNewStat = "continue_as_new"
+
 // Scope is an interface that attempts to wrap temporal's MetricsHandler with additional
 // functionality to build namespaces using NamespacedMetricsHandler
 type Scope interface {
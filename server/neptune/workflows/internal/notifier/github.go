@@ -40,6 +40,25 @@ func NewGithubCheckRunCache(activities checksActivities) *GithubCheckRunCache {
 	}
 }
 
+// NewGithubCheckRunCacheFromSnapshot rebuilds a cache from a prior snapshot, this allows check runs
+// to continue being updated across workflow runs.
+func NewGithubCheckRunCacheFromSnapshot(activities checksActivities, snapshot map[string]int64) *GithubCheckRunCache {
+	cache := NewGithubCheckRunCache(activities)
+	for k, v := range snapshot {
+		cache.deploymentCheckRunCache[k] = v
+	}
+	return cache
+}
+
+// Snapshot returns a copy of the check runs currently being tracked
+func (c *GithubCheckRunCache) Snapshot() map[string]int64 {
+	snapshot := make(map[string]int64, len(c.deploymentCheckRunCache))
+	for k, v := range c.deploymentCheckRunCache {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
 type GithubCheckRunRequest struct {
 	Title   string
 	Sha     string
@@ -173,6 +192,11 @@ func (n *CheckRunNotifier) updateCheckRun(ctx workflow.Context, workflowState *s
 		}
 	}
 
+	// successfully deployed revisions can be redeployed later on to roll back newer ones
+	if n.Mode == terraform.Deploy && workflowState.Result.Status == state.CompleteWorkflowStatus && workflowState.Result.Reason == state.SuccessfulCompletionReason {
+		request.Actions = append(request.Actions, github.CreateRollbackAction())
+	}
+
 	// cap our retries for non-terminal states to allow for at least some progress
 	if checkRunState != github.CheckRunFailure && checkRunState != github.CheckRunSuccess {
 		ctx = workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
@@ -194,6 +218,10 @@ func determineCheckRunState(workflowState *state.Workflow) github.CheckRunState
 	}
 
 	if workflowState.Result.Reason == state.SuccessfulCompletionReason {
+		// policy warnings are advisory so they're surfaced without failing the check
+		if hasPolicyWarnings(workflowState.Validate) {
+			return github.CheckRunNeutral
+		}
 		return github.CheckRunSuccess
 	}
 
@@ -217,6 +245,10 @@ func determineCheckRunState(workflowState *state.Workflow) github.CheckRunState
 	return github.CheckRunFailure
 }
 
+func hasPolicyWarnings(job *state.Job) bool {
+	return job != nil && job.Output != nil && len(job.Output.PolicyWarnings) > 0
+}
+
 func waitingForActionOn(job *state.Job) bool {
 	return job != nil && job.Status == state.WaitingJobStatus && len(job.OnWaitingActions.Actions) > 0
 }
@@ -256,6 +256,7 @@ func TestCheckRunNotifier(t *testing.T) {
 				Mode: &deployMode,
 			},
 			ExpectedCheckRunState: github.CheckRunSuccess,
+			ExpectedActions:       []github.CheckRunAction{github.CreateRollbackAction()},
 		},
 		{
 			State: &state.Workflow{
@@ -361,6 +362,7 @@ func TestCheckRunNotifier(t *testing.T) {
 				Mode: &prMode,
 			},
 			ExpectedCheckRunState: github.CheckRunSuccess,
+			ExpectedActions:       []github.CheckRunAction{github.CreateRollbackAction()},
 		},
 		{
 			State: &state.Workflow{
@@ -380,6 +382,35 @@ func TestCheckRunNotifier(t *testing.T) {
 			},
 			ExpectedCheckRunState: github.CheckRunSkipped,
 		},
+		{
+			State: &state.Workflow{
+				Plan: &state.Job{
+					Output: jobOutput,
+					Status: state.SuccessJobStatus,
+				},
+				Validate: &state.Job{
+					Output: &state.JobOutput{
+						URL: outputURL,
+						PolicyWarnings: []state.PolicyWarning{
+							{
+								PolicySet: "policy1",
+								Warnings:  []string{"instance type is deprecated"},
+							},
+						},
+					},
+					Status:    state.SuccessJobStatus,
+					StartTime: stTime,
+					EndTime:   endTime,
+				},
+				Result: state.WorkflowResult{
+					Status: state.CompleteWorkflowStatus,
+					Reason: state.SuccessfulCompletionReason,
+				},
+				Mode: &prMode,
+			},
+			ExpectedCheckRunState: github.CheckRunNeutral,
+			ExpectedActions:       []github.CheckRunAction{github.CreateRollbackAction()},
+		},
 	}
 
 	for _, c := range cases {
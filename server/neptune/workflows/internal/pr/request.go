@@ -1,7 +1,26 @@
 package pr
 
+import "github.com/runatlantis/atlantis/server/neptune/workflows/internal/pr/revision"
+
 type Request struct {
 	RepoFullName string
 	PRNum        int
 	Organization string
+
+	// ContinueAsNewThreshold is the number of revisions received before the workflow continues as new
+	// in order to bound the size of its history. DefaultContinueAsNewThreshold is used if this isn't set.
+	ContinueAsNewThreshold int
+
+	// State is only set by the workflow itself when continuing as new
+	State *State
+}
+
+// State is the in-memory state of a workflow run which is carried forward when continuing as new
+type State struct {
+	// Revision is the latest revision received by the workflow
+	Revision              revision.Revision
+	LastAttemptedRevision string
+	CheckRuns             map[string]int64
+	// CommentedWarnings is set once policy warnings have been commented on the PR
+	CommentedWarnings bool
 }
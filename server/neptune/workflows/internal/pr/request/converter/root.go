@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/execute"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/pr/request"
@@ -19,6 +20,7 @@ func Root(external request.Root) terraform.Root {
 		},
 		Path:      external.RepoRelPath,
 		TfVersion: external.TfVersion,
+		Engine:    valid.Engine(external.Engine),
 	}
 }
 
@@ -13,7 +13,9 @@ type Root struct {
 	Validate    Job
 	RepoRelPath string
 	TfVersion   string
-	PlanMode    PlanMode
+	// Engine is either terraform or opentofu, empty defaults to terraform
+	Engine   string
+	PlanMode PlanMode
 }
 
 type Job struct {
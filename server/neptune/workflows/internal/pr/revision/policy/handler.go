@@ -5,16 +5,20 @@ import (
 	"github.com/google/go-github/v45/github"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
 	gh "github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github/markdown"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/pr/revision"
 	temporalInternal "github.com/runatlantis/atlantis/server/neptune/workflows/internal/temporal"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
 	"go.temporal.io/sdk/workflow"
+	"sort"
 	"time"
 )
 
 type githubActivities interface {
 	GithubListPRReviews(ctx context.Context, request activities.ListPRReviewsRequest) (activities.ListPRReviewsResponse, error)
 	GithubListTeamMembers(ctx context.Context, request activities.ListTeamMembersRequest) (activities.ListTeamMembersResponse, error)
+	GithubUpsertComment(ctx context.Context, request activities.UpsertCommentRequest) (activities.UpsertCommentResponse, error)
 }
 
 type dismisser interface {
@@ -34,8 +38,15 @@ type FailedPolicyHandler struct {
 	GithubActivities      githubActivities
 	PRNumber              int
 	Org                   string
+
+	// CommentedWarnings is set once policy warnings have been commented on the PR, it's carried
+	// over when the workflow continues as new
+	CommentedWarnings bool
 }
 
+// WarningsCommentMarker identifies the policy warnings comment on a PR
+const WarningsCommentMarker = "atlantis-policy-warnings"
+
 type Action int64
 
 const (
@@ -43,7 +54,11 @@ const (
 	onPollTick
 )
 
+// Handle comments any policy warnings on the PR and then blocks until each failed policy
+// has been approved by its owners.  Warnings are advisory and never require approval.
 func (f *FailedPolicyHandler) Handle(ctx workflow.Context, revision revision.Revision, workflowResponses []terraform.Response) {
+	f.commentWarnings(ctx, revision, workflowResponses)
+
 	failedPolicies := dedup(workflowResponses)
 	if len(failedPolicies) == 0 {
 		return
@@ -119,6 +134,29 @@ func (f *FailedPolicyHandler) handle(ctx workflow.Context, revision revision.Rev
 	return filteredPolicies
 }
 
+func (f *FailedPolicyHandler) commentWarnings(ctx workflow.Context, revision revision.Revision, workflowResponses []terraform.Response) {
+	warnings := dedupWarnings(workflowResponses)
+
+	// once commented, the comment is kept up to date so resolved warnings aren't left behind
+	if len(warnings) == 0 && !f.CommentedWarnings {
+		return
+	}
+
+	err := workflow.ExecuteActivity(ctx, f.GithubActivities.GithubUpsertComment, activities.UpsertCommentRequest{
+		Repo:     revision.Repo,
+		PRNumber: f.PRNumber,
+		Marker:   WarningsCommentMarker,
+		Body:     markdown.RenderPolicyWarningsComment(revision.Repo, revision.Revision, warnings),
+	}).Get(ctx, nil)
+
+	// warnings are advisory so failing to surface them shouldn't block the revision
+	if err != nil {
+		workflow.GetLogger(ctx).Error(err.Error())
+		return
+	}
+	f.CommentedWarnings = true
+}
+
 func (f *FailedPolicyHandler) fetchTeamMembers(ctx workflow.Context, repo gh.Repo, slug string) ([]string, error) {
 	var listTeamMembersResponse activities.ListTeamMembersResponse
 	err := workflow.ExecuteActivity(ctx, f.GithubActivities.GithubListTeamMembers, activities.ListTeamMembersRequest{
@@ -146,6 +184,44 @@ func dedup(workflowResponses []terraform.Response) []activities.PolicySet {
 	return toSlice(uniqueFailedPolicies)
 }
 
+// dedupWarnings merges warnings for each policy set across roots, sorted by policy set name
+// to keep the rendered comment deterministic
+func dedupWarnings(workflowResponses []terraform.Response) []state.PolicyWarning {
+	warningsByPolicy := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	for _, response := range workflowResponses {
+		for _, validationResult := range response.ValidationResults {
+			if validationResult.Status != activities.Warn {
+				continue
+			}
+			name := validationResult.PolicySet.Name
+			if _, ok := seen[name]; !ok {
+				seen[name] = make(map[string]bool)
+				warningsByPolicy[name] = []string{}
+			}
+			for _, warning := range validationResult.Warnings {
+				if seen[name][warning] {
+					continue
+				}
+				seen[name][warning] = true
+				warningsByPolicy[name] = append(warningsByPolicy[name], warning)
+			}
+		}
+	}
+
+	var warnings []state.PolicyWarning
+	for name, w := range warningsByPolicy {
+		warnings = append(warnings, state.PolicyWarning{
+			PolicySet: name,
+			Warnings:  w,
+		})
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].PolicySet < warnings[j].PolicySet
+	})
+	return warnings
+}
+
 func toSlice(policyMap map[string]activities.PolicySet) []activities.PolicySet {
 	var policies []activities.PolicySet
 	for _, policy := range policyMap {
@@ -2,12 +2,15 @@ package policy_test
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/go-github/v45/github"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
 	gh "github.com/runatlantis/atlantis/server/neptune/workflows/activities/github"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/github/markdown"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/pr/revision"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/pr/revision/policy"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
 	"github.com/stretchr/testify/assert"
 	"go.temporal.io/sdk/testsuite"
 	"go.temporal.io/sdk/workflow"
@@ -139,6 +142,161 @@ func TestFailedPolicyHandlerRunner_Handle(t *testing.T) {
 	assert.Equal(t, resp.DismisserReviews[0], testApproval)
 }
 
+func TestFailedPolicyHandlerRunner_Warnings(t *testing.T) {
+	ga := &mockGithubActivities{}
+	repo := gh.Repo{Owner: "owner", Name: "repo"}
+	req := request{
+		T:        t,
+		Revision: revision.Revision{Repo: repo, Revision: "1234"},
+		WorkflowResponses: []terraform.Response{
+			{
+				ValidationResults: []activities.ValidationResult{
+					{
+						Status:    activities.Warn,
+						PolicySet: activities.PolicySet{Name: "policy2"},
+						Warnings:  []string{"missing tags"},
+					},
+					{
+						Status:    activities.Warn,
+						PolicySet: activities.PolicySet{Name: "policy1"},
+						Warnings:  []string{"instance type is deprecated"},
+					},
+				},
+			},
+			{
+				ValidationResults: []activities.ValidationResult{
+					{
+						Status:    activities.Warn,
+						PolicySet: activities.PolicySet{Name: "policy1"},
+						Warnings:  []string{"instance type is deprecated", "public ip"},
+					},
+				},
+			},
+		},
+		GithubActivities: ga,
+	}
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterActivity(ga)
+	env.ExecuteWorkflow(testWorkflow, req)
+	var resp response
+	err := env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+
+	// warnings don't require approval
+	assert.False(t, resp.DismisserCalled)
+	assert.False(t, resp.FilterCalled)
+	assert.False(t, ga.called)
+
+	assert.Equal(t, []activities.UpsertCommentRequest{
+		{
+			Repo:     repo,
+			PRNumber: 1,
+			Marker:   policy.WarningsCommentMarker,
+			Body: markdown.RenderPolicyWarningsComment(repo, "1234", []state.PolicyWarning{
+				{PolicySet: "policy1", Warnings: []string{"instance type is deprecated", "public ip"}},
+				{PolicySet: "policy2", Warnings: []string{"missing tags"}},
+			}),
+		},
+	}, ga.comments)
+}
+
+func testWarningsAcrossRevisionsWorkflow(ctx workflow.Context, ga *mockGithubActivities, commentedWarnings bool, responses [][]terraform.Response) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: time.Minute,
+	})
+	handler := &policy.FailedPolicyHandler{
+		GithubActivities:  ga,
+		PRNumber:          1,
+		CommentedWarnings: commentedWarnings,
+	}
+	for i, r := range responses {
+		handler.Handle(ctx, revision.Revision{Repo: gh.Repo{Owner: "owner", Name: "repo"}, Revision: fmt.Sprintf("%d", i)}, r)
+	}
+	return nil
+}
+
+func TestFailedPolicyHandlerRunner_WarningsAcrossRevisions(t *testing.T) {
+	ga := &mockGithubActivities{}
+	repo := gh.Repo{Owner: "owner", Name: "repo"}
+	warned := []terraform.Response{
+		{
+			ValidationResults: []activities.ValidationResult{
+				{
+					Status:    activities.Warn,
+					PolicySet: activities.PolicySet{Name: "policy1"},
+					Warnings:  []string{"missing tags"},
+				},
+			},
+		},
+	}
+	passed := []terraform.Response{
+		{
+			ValidationResults: []activities.ValidationResult{
+				{
+					Status:    activities.Success,
+					PolicySet: activities.PolicySet{Name: "policy1"},
+				},
+			},
+		},
+	}
+
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterActivity(ga)
+	env.ExecuteWorkflow(testWarningsAcrossRevisionsWorkflow, ga, false, [][]terraform.Response{passed, warned, passed})
+	assert.NoError(t, env.GetWorkflowError())
+
+	// nothing is commented until there are warnings, after which the same comment is updated
+	assert.Equal(t, []activities.UpsertCommentRequest{
+		{
+			Repo:     repo,
+			PRNumber: 1,
+			Marker:   policy.WarningsCommentMarker,
+			Body: markdown.RenderPolicyWarningsComment(repo, "1", []state.PolicyWarning{
+				{PolicySet: "policy1", Warnings: []string{"missing tags"}},
+			}),
+		},
+		{
+			Repo:     repo,
+			PRNumber: 1,
+			Marker:   policy.WarningsCommentMarker,
+			Body:     markdown.RenderPolicyWarningsComment(repo, "2", nil),
+		},
+	}, ga.comments)
+}
+
+func TestFailedPolicyHandlerRunner_WarningsCommentedBeforeContinueAsNew(t *testing.T) {
+	ga := &mockGithubActivities{}
+	repo := gh.Repo{Owner: "owner", Name: "repo"}
+	passed := []terraform.Response{
+		{
+			ValidationResults: []activities.ValidationResult{
+				{
+					Status:    activities.Success,
+					PolicySet: activities.PolicySet{Name: "policy1"},
+				},
+			},
+		},
+	}
+
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterActivity(ga)
+	env.ExecuteWorkflow(testWarningsAcrossRevisionsWorkflow, ga, true, [][]terraform.Response{passed})
+	assert.NoError(t, env.GetWorkflowError())
+
+	// the comment from a previous run is updated once its warnings are resolved
+	assert.Equal(t, []activities.UpsertCommentRequest{
+		{
+			Repo:     repo,
+			PRNumber: 1,
+			Marker:   policy.WarningsCommentMarker,
+			Body:     markdown.RenderPolicyWarningsComment(repo, "0", nil),
+		},
+	}, ga.comments)
+}
+
 type mockDismisser struct {
 	called          bool
 	expectedReviews []*github.PullRequestReview
@@ -164,9 +322,15 @@ func (m *mockFilter) Filter(teams map[string][]string, currentApprovals []*githu
 }
 
 type mockGithubActivities struct {
-	called  bool
-	reviews activities.ListPRReviewsResponse
-	err     error
+	called   bool
+	reviews  activities.ListPRReviewsResponse
+	err      error
+	comments []activities.UpsertCommentRequest
+}
+
+func (g *mockGithubActivities) GithubUpsertComment(ctx context.Context, request activities.UpsertCommentRequest) (activities.UpsertCommentResponse, error) {
+	g.comments = append(g.comments, request)
+	return activities.UpsertCommentResponse{}, nil
 }
 
 func (g *mockGithubActivities) GithubListTeamMembers(ctx context.Context, request activities.ListTeamMembersRequest) (activities.ListTeamMembersResponse, error) {
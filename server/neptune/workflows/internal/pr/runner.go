@@ -21,6 +21,7 @@ const (
 	onShutdown
 	onCancel
 	onTimeout
+	onContinueAsNew
 )
 
 type RunnerState int64
@@ -53,33 +54,55 @@ type Runner struct {
 	InactivityTimeout     time.Duration
 	ShutdownPollTick      time.Duration
 
+	// ContinueAsNewThreshold is the number of revisions received before we continue as new. It's only 0, which
+	// disables this, for workflows started before continue as new was supported since an unset request
+	// threshold is replaced with DefaultContinueAsNewThreshold.
+	ContinueAsNewThreshold int
+	// ContinueAsNewRequest builds the request for the next run from the runner's current state
+	ContinueAsNewRequest func(prRevision revision.Revision, lastAttemptedRevision string) Request
+
 	// mutable state
 	state                 RunnerState
 	lastAttemptedRevision string
+	prRevision            revision.Revision
+	revisionCount         int
+}
+
+// RestoreState initializes the runner with state carried over from a previous run
+func (r *Runner) RestoreState(state State) {
+	r.state = waiting
+	r.lastAttemptedRevision = state.LastAttemptedRevision
+	r.prRevision = state.Revision
 }
 
-func newRunner(ctx workflow.Context, scope workflowMetrics.Scope, org string, tfWorkflow revision.TFWorkflow, prNum int, internalNotifiers []revision.WorkflowNotifier, additionalNotifiers ...plugins.TerraformWorkflowNotifier) *Runner {
+// newPolicyHandler builds the handler for failed policies and policy warnings of each revision
+func newPolicyHandler(ctx workflow.Context, org string, prNum int) *policy.FailedPolicyHandler {
+	var ga *activities.Github
+	dismisser := policy.StaleReviewDismisser{
+		GithubActivities: ga,
+		PRNumber:         prNum,
+	}
+	return &policy.FailedPolicyHandler{
+		ApprovalSignalChannel: workflow.GetSignalChannel(ctx, revision.ApprovalSignalID),
+		GithubActivities:      ga,
+		PRNumber:              prNum,
+		Dismisser:             &dismisser,
+		PolicyFilter:          &policy.Filter{},
+		Org:                   org,
+	}
+}
+
+func newRunner(ctx workflow.Context, scope workflowMetrics.Scope, policyHandler revision.PolicyHandler, tfWorkflow revision.TFWorkflow, prNum int, internalNotifiers []revision.WorkflowNotifier, additionalNotifiers ...plugins.TerraformWorkflowNotifier) *Runner {
 	revisionReceiver := revision.NewRevisionReceiver(ctx, scope)
 	stateReceiver := revision.StateReceiver{
 		InternalNotifiers:   internalNotifiers,
 		AdditionalNotifiers: additionalNotifiers,
 	}
 	var ga *activities.Github
-	dismisser := policy.StaleReviewDismisser{
-		GithubActivities: ga,
-		PRNumber:         prNum,
-	}
 	revisionProcessor := revision.Processor{
 		TFWorkflow:      tfWorkflow,
 		TFStateReceiver: &stateReceiver,
-		PolicyHandler: &policy.FailedPolicyHandler{
-			ApprovalSignalChannel: workflow.GetSignalChannel(ctx, revision.ApprovalSignalID),
-			GithubActivities:      ga,
-			PRNumber:              prNum,
-			Dismisser:             &dismisser,
-			PolicyFilter:          &policy.Filter{},
-			Org:                   org,
-		},
+		PolicyHandler:   policyHandler,
 	}
 	shutdownChecker := ShutdownStateChecker{
 		GithubActivities: ga,
@@ -103,7 +126,7 @@ func newRunner(ctx workflow.Context, scope workflowMetrics.Scope, org string, tf
 // change the current PRAction status
 func (r *Runner) Run(ctx workflow.Context) error {
 	var action Action
-	var prRevision revision.Revision
+	prRevision := r.prRevision
 
 	s := temporalInternal.SelectorWithTimeout{
 		Selector: workflow.NewSelector(ctx),
@@ -120,6 +143,7 @@ func (r *Runner) Run(ctx workflow.Context) error {
 
 	s.AddReceive(r.RevisionSignalChannel, func(c workflow.ReceiveChannel, more bool) {
 		prRevision = r.RevisionReceiver.Receive(c, more)
+		r.revisionCount++
 		action = onNewRevision
 	})
 	s.AddReceive(r.ShutdownSignalChannel, func(c workflow.ReceiveChannel, more bool) {
@@ -138,6 +162,11 @@ func (r *Runner) Run(ctx workflow.Context) error {
 	}
 	shutdownPollCancel, _ := s.AddTimeout(ctx, r.ShutdownPollTick, onShutdownPollTick)
 
+	var continueAsNewPending bool
+	onIdle := func(f workflow.Future) {
+		action = onContinueAsNew
+	}
+
 	_, revisionCancel := workflow.WithCancel(ctx)
 	for {
 		s.Select(ctx)
@@ -146,7 +175,31 @@ func (r *Runner) Run(ctx workflow.Context) error {
 			revisionCancel = r.onNewRevision(ctx, revisionCancel, prRevision)
 			inactivityTimeoutCancel()
 			inactivityTimeoutCancel, _ = s.AddTimeout(ctx, r.InactivityTimeout, onInactivityTimeout)
+
+			if r.shouldContinueAsNew() && !continueAsNewPending {
+				continueAsNewPending = true
+				s.AddFuture(r.awaitIdle(ctx), onIdle)
+			}
 			continue
+		case onContinueAsNew:
+			continueAsNewPending = false
+
+			// a new revision might have started processing before we got here
+			if r.state == working {
+				continueAsNewPending = true
+				s.AddFuture(r.awaitIdle(ctx), onIdle)
+				continue
+			}
+
+			// handle any buffered signals first, we'll try again on the next revision
+			if r.hasPendingSignals(ctx) {
+				continue
+			}
+
+			workflow.GetLogger(ctx).Info("continuing as new", "revisions", r.revisionCount)
+			revisionCancel()
+			r.Scope.Counter(workflowMetrics.ContinueAsNewStat).Inc(1)
+			return workflow.NewContinueAsNewError(ctx, workflow.GetInfo(ctx).WorkflowType.Name, r.ContinueAsNewRequest(prRevision, r.lastAttemptedRevision))
 		case onCancel:
 			continue
 		case onTimeout: // TODO: send message to PR stating atlantis deleted state due to inactivity and to rerun to trigger atlantis workflow
@@ -197,3 +250,29 @@ func (r *Runner) shouldProcessRevision(prRevision revision.Revision) bool {
 	}
 	return true
 }
+
+func (r *Runner) shouldContinueAsNew() bool {
+	return r.ContinueAsNewThreshold > 0 && r.revisionCount >= r.ContinueAsNewThreshold
+}
+
+func (r *Runner) awaitIdle(ctx workflow.Context) workflow.Future {
+	future, settable := workflow.NewFuture(ctx)
+
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		err := workflow.Await(ctx, func() bool {
+			return r.state != working
+		})
+
+		settable.SetError(err)
+	})
+
+	return future
+}
+
+func (r *Runner) hasPendingSignals(ctx workflow.Context) bool {
+	s := workflow.NewSelector(ctx)
+	s.AddReceive(r.RevisionSignalChannel, func(c workflow.ReceiveChannel, more bool) {})
+	s.AddReceive(r.ShutdownSignalChannel, func(c workflow.ReceiveChannel, more bool) {})
+
+	return s.HasPending()
+}
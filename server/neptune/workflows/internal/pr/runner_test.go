@@ -11,12 +11,13 @@ import (
 )
 
 type request struct {
-	mockRevisionProcessor testRevisionProcessor
-	scope                 metrics.Scope
-	InactivityTimeout     time.Duration
-	ShutdownPollTime      time.Duration
-	NumShutdownPollTicks  int
-	T                     *testing.T
+	mockRevisionProcessor  testRevisionProcessor
+	scope                  metrics.Scope
+	InactivityTimeout      time.Duration
+	ShutdownPollTime       time.Duration
+	NumShutdownPollTicks   int
+	ContinueAsNewThreshold int
+	T                      *testing.T
 }
 
 type response struct {
@@ -38,13 +39,23 @@ func testWorkflow(ctx workflow.Context, r request) (response, error) {
 	}
 	revisionReceiver := revision.NewRevisionReceiver(ctx, r.scope)
 	runner := &Runner{
-		RevisionSignalChannel: workflow.GetSignalChannel(ctx, revisionID),
-		RevisionReceiver:      &revisionReceiver,
-		ShutdownSignalChannel: workflow.GetSignalChannel(ctx, shutdownID),
-		RevisionProcessor:     mockRevisionProcessor,
-		ShutdownChecker:       mockShutdownChecker,
-		InactivityTimeout:     r.InactivityTimeout,
-		ShutdownPollTick:      r.ShutdownPollTime,
+		RevisionSignalChannel:  workflow.GetSignalChannel(ctx, revisionID),
+		RevisionReceiver:       &revisionReceiver,
+		ShutdownSignalChannel:  workflow.GetSignalChannel(ctx, shutdownID),
+		RevisionProcessor:      mockRevisionProcessor,
+		ShutdownChecker:        mockShutdownChecker,
+		InactivityTimeout:      r.InactivityTimeout,
+		ShutdownPollTick:       r.ShutdownPollTime,
+		Scope:                  metrics.NewNullableScope(),
+		ContinueAsNewThreshold: r.ContinueAsNewThreshold,
+		ContinueAsNewRequest: func(prRevision revision.Revision, lastAttemptedRevision string) Request {
+			return Request{
+				State: &State{
+					Revision:              prRevision,
+					LastAttemptedRevision: lastAttemptedRevision,
+				},
+			}
+		},
 	}
 	err := runner.Run(ctx)
 	return response{
@@ -122,6 +133,33 @@ func TestWorkflowRunner_Run_ShutdownPoll(t *testing.T) {
 	assert.Equal(t, 0, resp.ProcessCount)
 }
 
+func TestWorkflowRunner_Run_ContinueAsNew(t *testing.T) {
+	req := request{
+		mockRevisionProcessor:  testRevisionProcessor{},
+		InactivityTimeout:      time.Hour,
+		ShutdownPollTime:       time.Hour,
+		ContinueAsNewThreshold: 2,
+	}
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(revisionID, revision.NewTerraformRevisionRequest{
+			Revision: "abc",
+		})
+	}, 2*time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(revisionID, revision.NewTerraformRevisionRequest{
+			Revision: "def",
+		})
+	}, 4*time.Second)
+	env.ExecuteWorkflow(testWorkflow, req)
+
+	err := env.GetWorkflowError()
+	var continueAsNewErr *workflow.ContinueAsNewError
+	assert.ErrorAs(t, err, &continueAsNewErr)
+	assert.Equal(t, "testWorkflow", continueAsNewErr.WorkflowType.Name)
+}
+
 type testRevisionProcessor struct {
 	processCalls int
 }
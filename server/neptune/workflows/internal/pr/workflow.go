@@ -11,7 +11,13 @@ import (
 	"time"
 )
 
-const TaskQueue = "pr"
+const (
+	TaskQueue            = "pr"
+	ContinueAsNewVersion = "continue-as-new"
+
+	// DefaultContinueAsNewThreshold is the number of revisions a workflow receives before continuing as new
+	DefaultContinueAsNewThreshold = 100
+)
 
 type prActivities struct {
 	*activities.Github
@@ -28,13 +34,52 @@ func Workflow(ctx workflow.Context, request Request, tfWorkflow revision.TFWorkf
 		"repo":   request.RepoFullName,
 		"pr-num": strconv.Itoa(request.PRNum),
 	})
-	checkRunCache := notifier.NewGithubCheckRunCache(a)
+	var checkRunCache *notifier.GithubCheckRunCache
+	if request.State != nil {
+		checkRunCache = notifier.NewGithubCheckRunCacheFromSnapshot(a, request.State.CheckRuns)
+	} else {
+		checkRunCache = notifier.NewGithubCheckRunCache(a)
+	}
 	notifiers := []revision.WorkflowNotifier{
 		&notifier.CheckRunNotifier{
 			CheckRunSessionCache: checkRunCache,
 			Mode:                 tfModel.PR,
 		},
 	}
-	runner := newRunner(ctx, scope, request.Organization, tfWorkflow, request.PRNum, notifiers)
+	policyHandler := newPolicyHandler(ctx, request.Organization, request.PRNum)
+	if request.State != nil {
+		policyHandler.CommentedWarnings = request.State.CommentedWarnings
+	}
+	runner := newRunner(ctx, scope, policyHandler, tfWorkflow, request.PRNum, notifiers)
+
+	continueAsNewThreshold := request.ContinueAsNewThreshold
+	if continueAsNewThreshold == 0 {
+		continueAsNewThreshold = DefaultContinueAsNewThreshold
+	}
+
+	// workflows started prior to this change could have received more revisions than our threshold
+	// so we need to guard this to avoid non-determinism errors
+	if v := workflow.GetVersion(ctx, ContinueAsNewVersion, workflow.DefaultVersion, workflow.Version(1)); v > workflow.DefaultVersion {
+		runner.ContinueAsNewThreshold = continueAsNewThreshold
+	}
+	runner.ContinueAsNewRequest = func(prRevision revision.Revision, lastAttemptedRevision string) Request {
+		return Request{
+			RepoFullName:           request.RepoFullName,
+			PRNum:                  request.PRNum,
+			Organization:           request.Organization,
+			ContinueAsNewThreshold: request.ContinueAsNewThreshold,
+			State: &State{
+				Revision:              prRevision,
+				LastAttemptedRevision: lastAttemptedRevision,
+				CheckRuns:             checkRunCache.Snapshot(),
+				CommentedWarnings:     policyHandler.CommentedWarnings,
+			},
+		}
+	}
+
+	if request.State != nil {
+		runner.RestoreState(*request.State)
+	}
+
 	return runner.Run(ctx)
 }
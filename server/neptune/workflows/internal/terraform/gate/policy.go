@@ -0,0 +1,176 @@
+package gate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+)
+
+// maxListedChanges limits the number of changes listed in the approval reason
+const maxListedChanges = 10
+
+// rule is a policy rule whose address globs are compiled once per evaluation
+type rule struct {
+	terraform.PlanApprovalRule
+	addresses []valid.AddressPattern
+}
+
+func compileRules(rules []terraform.PlanApprovalRule) []rule {
+	compiled := make([]rule, 0, len(rules))
+	for _, r := range rules {
+		c := rule{PlanApprovalRule: r}
+		for _, a := range r.Addresses {
+			c.addresses = append(c.addresses, valid.NewAddressPattern(a))
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled
+}
+
+type resourceChange struct {
+	Action  string
+	Address string
+}
+
+// EvaluatePolicy determines the approval required for a plan summary from the given policy.
+func EvaluatePolicy(policy terraform.PlanApprovalPolicy, summary terraform.PlanSummary) terraform.PlanApproval {
+	changes := resourceChanges(summary)
+	rules := compileRules(policy.Rules)
+
+	var reasons []string
+	if policy.MaxChanges > 0 && len(changes) > policy.MaxChanges {
+		reasons = append(reasons, fmt.Sprintf("Plan contains %d resource changes which exceeds the maximum of %d for automatic approval.", len(changes), policy.MaxChanges))
+	}
+
+	var manualChanges []string
+	for _, c := range changes {
+		if approvalFor(rules, policy.Default, c) == terraform.ManualApproval {
+			manualChanges = append(manualChanges, fmt.Sprintf("* %s %s", c.Action, c.Address))
+		}
+	}
+
+	if len(manualChanges) > 0 {
+		reasons = append(reasons, "The following changes require manual approval:")
+		if len(manualChanges) > maxListedChanges {
+			remaining := len(manualChanges) - maxListedChanges
+			manualChanges = append(manualChanges[:maxListedChanges], fmt.Sprintf("* and %d more", remaining))
+		}
+		reasons = append(reasons, manualChanges...)
+	}
+
+	if len(reasons) == 0 {
+		return terraform.PlanApproval{Type: terraform.AutoApproval}
+	}
+
+	return terraform.PlanApproval{
+		Type:   terraform.ManualApproval,
+		Reason: strings.Join(reasons, "\n"),
+	}
+}
+
+// resourceChanges flattens the summary into a single action per resource address,
+// replacements are included in the summary's creations and deletions so they are excluded from both.
+func resourceChanges(summary terraform.PlanSummary) []resourceChange {
+	replaced := map[string]bool{}
+	for _, r := range summary.Replacements {
+		replaced[r.Address] = true
+	}
+
+	var changes []resourceChange
+	add := func(action string, resources []terraform.ResourceSummary, skipReplaced bool) {
+		for _, r := range resources {
+			if skipReplaced && replaced[r.Address] {
+				continue
+			}
+			changes = append(changes, resourceChange{Action: action, Address: r.Address})
+		}
+	}
+
+	add(valid.CreateAction, summary.Creations, true)
+	add(valid.UpdateAction, summary.Updates, false)
+	add(valid.DeleteAction, summary.Deletions, true)
+	add(valid.ReplaceAction, summary.Replacements, false)
+
+	return changes
+}
+
+func approvalFor(rules []rule, defaultApproval terraform.PlanApprovalType, change resourceChange) terraform.PlanApprovalType {
+	for _, rule := range rules {
+		if matches(rule, change) {
+			return rule.Approval
+		}
+	}
+	return defaultApproval
+}
+
+func matches(rule rule, change resourceChange) bool {
+	if len(rule.Actions) > 0 && !contains(rule.Actions, change.Action) {
+		return false
+	}
+
+	if len(rule.ResourceTypes) > 0 && !contains(rule.ResourceTypes, resourceType(change.Address)) {
+		return false
+	}
+
+	if len(rule.addresses) > 0 {
+		for _, pattern := range rule.addresses {
+			if pattern.Match(change.Address) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceType parses the type from a resource address,
+// ie. module.a["key"].aws_instance.b[0] -> aws_instance
+func resourceType(address string) string {
+	segments := splitAddress(address)
+	for i := 0; i < len(segments); i++ {
+		switch segments[i] {
+		case "module":
+			// skip the module name
+			i++
+			continue
+		case "data":
+			continue
+		}
+		return segments[i]
+	}
+	return ""
+}
+
+// splitAddress splits an address on periods which aren't within an index
+func splitAddress(address string) []string {
+	var segments []string
+	var depth int
+	var quoted bool
+	start := 0
+	for i, c := range address {
+		switch {
+		case c == '"' && depth > 0:
+			quoted = !quoted
+		case c == '[' && !quoted:
+			depth++
+		case c == ']' && !quoted:
+			depth--
+		case c == '.' && depth == 0:
+			segments = append(segments, address[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, address[start:])
+}
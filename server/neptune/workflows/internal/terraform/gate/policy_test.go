@@ -0,0 +1,131 @@
+package gate_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/gate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	summary := terraform.PlanSummary{
+		Creations: []terraform.ResourceSummary{
+			{Address: "aws_instance.new"},
+			{Address: `module.db["prod"].aws_db_instance.main`},
+		},
+		Deletions: []terraform.ResourceSummary{
+			{Address: "aws_s3_bucket.old"},
+			{Address: `module.db["prod"].aws_db_instance.main`},
+		},
+		Replacements: []terraform.ResourceSummary{
+			{Address: `module.db["prod"].aws_db_instance.main`},
+		},
+		Updates: []terraform.ResourceSummary{
+			{Address: "aws_instance.existing"},
+		},
+	}
+
+	cases := []struct {
+		description string
+		policy      terraform.PlanApprovalPolicy
+		summary     terraform.PlanSummary
+		expected    terraform.PlanApproval
+	}{
+		{
+			description: "no rules",
+			summary:     summary,
+			expected:    terraform.PlanApproval{Type: terraform.AutoApproval},
+		},
+		{
+			description: "manual default",
+			policy: terraform.PlanApprovalPolicy{
+				Default: terraform.ManualApproval,
+			},
+			summary: terraform.PlanSummary{
+				Updates: []terraform.ResourceSummary{{Address: "aws_instance.existing"}},
+			},
+			expected: terraform.PlanApproval{
+				Type:   terraform.ManualApproval,
+				Reason: "The following changes require manual approval:\n* update aws_instance.existing",
+			},
+		},
+		{
+			description: "pure creations auto approved",
+			policy: terraform.PlanApprovalPolicy{
+				Default: terraform.ManualApproval,
+				Rules: []terraform.PlanApprovalRule{
+					{Actions: []string{valid.CreateAction}, Approval: terraform.AutoApproval},
+				},
+			},
+			summary: terraform.PlanSummary{
+				Creations: []terraform.ResourceSummary{{Address: "aws_instance.new"}},
+			},
+			expected: terraform.PlanApproval{Type: terraform.AutoApproval},
+		},
+		{
+			description: "replacement matches resource type",
+			policy: terraform.PlanApprovalPolicy{
+				Rules: []terraform.PlanApprovalRule{
+					{
+						Actions:       []string{valid.DeleteAction, valid.ReplaceAction},
+						ResourceTypes: []string{"aws_db_instance"},
+						Approval:      terraform.ManualApproval,
+					},
+				},
+			},
+			summary: summary,
+			expected: terraform.PlanApproval{
+				Type:   terraform.ManualApproval,
+				Reason: "The following changes require manual approval:\n* replace module.db[\"prod\"].aws_db_instance.main",
+			},
+		},
+		{
+			description: "address glob",
+			policy: terraform.PlanApprovalPolicy{
+				Rules: []terraform.PlanApprovalRule{
+					{
+						Addresses: []string{"aws_s3_bucket.*"},
+						Approval:  terraform.ManualApproval,
+					},
+				},
+			},
+			summary: summary,
+			expected: terraform.PlanApproval{
+				Type:   terraform.ManualApproval,
+				Reason: "The following changes require manual approval:\n* delete aws_s3_bucket.old",
+			},
+		},
+		{
+			description: "first matching rule wins",
+			policy: terraform.PlanApprovalPolicy{
+				Rules: []terraform.PlanApprovalRule{
+					{Addresses: []string{"aws_s3_bucket.old"}, Approval: terraform.AutoApproval},
+					{Actions: []string{valid.DeleteAction}, Approval: terraform.ManualApproval},
+				},
+			},
+			summary: terraform.PlanSummary{
+				Deletions: []terraform.ResourceSummary{{Address: "aws_s3_bucket.old"}},
+			},
+			expected: terraform.PlanApproval{Type: terraform.AutoApproval},
+		},
+		{
+			description: "max changes",
+			policy: terraform.PlanApprovalPolicy{
+				MaxChanges: 3,
+			},
+			summary: summary,
+			expected: terraform.PlanApproval{
+				Type:   terraform.ManualApproval,
+				Reason: "Plan contains 4 resource changes which exceeds the maximum of 3 for automatic approval.",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			assert.Equal(t, c.expected, gate.EvaluatePolicy(c.policy, c.summary))
+		})
+	}
+}
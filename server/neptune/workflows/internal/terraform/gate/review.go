@@ -19,9 +19,10 @@ const (
 type PlanStatus int
 type PlanReviewSignalRequest struct {
 	Status PlanStatus
+	User   string
 
-	// TODO: Output this info to the checks UI
-	User string
+	// Reason is optional and provided by the reviewer
+	Reason string
 }
 
 const (
@@ -40,9 +41,20 @@ type ActionsClient interface {
 	UpdateApprovalActions(approval terraform.PlanApproval) error
 }
 
-func (r *Review) Await(ctx workflow.Context, root terraform.Root, planSummary terraform.PlanSummary) (PlanStatus, error) {
-	if root.Plan.Approval.Type == terraform.AutoApproval || planSummary.IsEmpty() {
-		return Approved, nil
+// Await returns the review of the plan, automatically approved plans and timeouts are returned without a user.
+func (r *Review) Await(ctx workflow.Context, root terraform.Root, planSummary terraform.PlanSummary) (PlanReviewSignalRequest, error) {
+	if planSummary.IsEmpty() {
+		return PlanReviewSignalRequest{Status: Approved}, nil
+	}
+
+	// forced manual approvals take precedence over the approval policy
+	approval := root.Plan.Approval
+	if approval.Type == terraform.AutoApproval && root.Plan.ApprovalPolicy != nil {
+		approval = EvaluatePolicy(*root.Plan.ApprovalPolicy, planSummary)
+	}
+
+	if approval.Type == terraform.AutoApproval {
+		return PlanReviewSignalRequest{Status: Approved}, nil
 	}
 
 	waitStartTime := time.Now()
@@ -68,16 +80,16 @@ func (r *Review) Await(ctx workflow.Context, root terraform.Root, planSummary te
 		timedOut = true
 	})
 
-	err := r.Client.UpdateApprovalActions(root.Plan.Approval)
+	err := r.Client.UpdateApprovalActions(approval)
 	if err != nil {
-		return Rejected, errors.Wrap(err, "updating approval actions")
+		return PlanReviewSignalRequest{Status: Rejected}, errors.Wrap(err, "updating approval actions")
 	}
 
 	selector.Select(ctx)
 
 	if timedOut {
-		return Rejected, nil
+		return PlanReviewSignalRequest{Status: Rejected}, nil
 	}
 
-	return planReview.Status, nil
+	return planReview, nil
 }
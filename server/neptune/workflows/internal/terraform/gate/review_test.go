@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/gate"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,8 @@ import (
 
 type res struct {
 	Status                        gate.PlanStatus
+	User                          string
+	Reason                        string
 	ActionsClientCalled           bool
 	ActionsClientCapturedApproval terraform.PlanApproval
 }
@@ -21,6 +24,7 @@ type res struct {
 type req struct {
 	PlanSummary      terraform.PlanSummary
 	ApprovalOverride terraform.PlanApproval
+	ApprovalPolicy   *terraform.PlanApprovalPolicy
 }
 
 type testClient struct {
@@ -47,14 +51,17 @@ func testReviewWorkflow(ctx workflow.Context, r req) (res, error) {
 		Client:         c,
 	}
 
-	status, err := review.Await(ctx, terraform.Root{
+	planReview, err := review.Await(ctx, terraform.Root{
 		Plan: terraform.PlanJob{
-			Approval: r.ApprovalOverride,
+			Approval:       r.ApprovalOverride,
+			ApprovalPolicy: r.ApprovalPolicy,
 		},
 	}, r.PlanSummary)
 
 	return res{
-		Status:                        status,
+		Status:                        planReview.Status,
+		User:                          planReview.User,
+		Reason:                        planReview.Reason,
 		ActionsClientCalled:           c.called,
 		ActionsClientCapturedApproval: c.capturedApproval,
 	}, err
@@ -119,3 +126,97 @@ func TestAwait_autoApprove(t *testing.T) {
 		Status: gate.Approved,
 	})
 }
+
+func TestAwait_approvalPolicy(t *testing.T) {
+	policy := &terraform.PlanApprovalPolicy{
+		Rules: []terraform.PlanApprovalRule{
+			{
+				Actions:  []string{valid.DeleteAction},
+				Approval: terraform.ManualApproval,
+			},
+		},
+	}
+
+	t.Run("auto approved", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+
+		env.ExecuteWorkflow(testReviewWorkflow, req{
+			PlanSummary: terraform.PlanSummary{
+				Creations: []terraform.ResourceSummary{{Address: "aws_instance.a"}},
+			},
+			ApprovalPolicy: policy,
+		})
+
+		var r res
+		err := env.GetWorkflowResult(&r)
+		assert.NoError(t, err)
+
+		assert.Equal(t, res{
+			Status: gate.Approved,
+		}, r)
+	})
+
+	t.Run("requires approval", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow(gate.PlanReviewSignalName, gate.PlanReviewSignalRequest{
+				Status: gate.Approved,
+				User:   "nish",
+				Reason: "expected deletion",
+			})
+		}, 5*time.Second)
+
+		env.ExecuteWorkflow(testReviewWorkflow, req{
+			PlanSummary: terraform.PlanSummary{
+				Deletions: []terraform.ResourceSummary{{Address: "aws_instance.a"}},
+			},
+			ApprovalPolicy: policy,
+		})
+
+		var r res
+		err := env.GetWorkflowResult(&r)
+		assert.NoError(t, err)
+
+		assert.Equal(t, res{
+			Status:              gate.Approved,
+			User:                "nish",
+			Reason:              "expected deletion",
+			ActionsClientCalled: true,
+			ActionsClientCapturedApproval: terraform.PlanApproval{
+				Type:   terraform.ManualApproval,
+				Reason: "The following changes require manual approval:\n* delete aws_instance.a",
+			},
+		}, r)
+	})
+
+	t.Run("forced manual approval", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+
+		approvalOverride := terraform.PlanApproval{
+			Type:   terraform.ManualApproval,
+			Reason: "diverged",
+		}
+
+		env.ExecuteWorkflow(testReviewWorkflow, req{
+			PlanSummary: terraform.PlanSummary{
+				Creations: []terraform.ResourceSummary{{Address: "aws_instance.a"}},
+			},
+			ApprovalOverride: approvalOverride,
+			ApprovalPolicy:   policy,
+		})
+
+		var r res
+		err := env.GetWorkflowResult(&r)
+		assert.NoError(t, err)
+
+		assert.Equal(t, res{
+			Status:                        gate.Rejected,
+			ActionsClientCalled:           true,
+			ActionsClientCapturedApproval: approvalOverride,
+		}, r)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	key "github.com/runatlantis/atlantis/server/neptune/context"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/execute"
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
@@ -23,6 +24,7 @@ type ExecutionContext struct {
 	Path      string
 	Envs      []EnvVar
 	TfVersion string
+	Engine    valid.Engine
 	workflow.Context
 	JobID string
 }
@@ -68,10 +70,11 @@ func (r *JobRunner) Plan(ctx workflow.Context, localRoot *terraform.LocalRoot, j
 		Context:   ctx,
 		Path:      localRoot.Path,
 		TfVersion: localRoot.Root.TfVersion,
+		Engine:    localRoot.Root.Engine,
 		JobID:     jobID,
 	}
 
-	defer r.closeTerraformJob(jobCtx)
+	defer r.closeTerraformJob(jobCtx, localRoot)
 
 	var resp activities.TerraformPlanResponse
 
@@ -104,14 +107,14 @@ func (r *JobRunner) Validate(ctx workflow.Context, localRoot *terraform.LocalRoo
 		Path:    localRoot.Path,
 		JobID:   jobID,
 	}
-	defer r.closeTerraformJob(jobCtx)
+	defer r.closeTerraformJob(jobCtx, localRoot)
 
 	var validateResults []activities.ValidationResult
 	for _, step := range localRoot.Root.Validate.GetSteps() {
 		var err error
 		switch step.StepName {
 		case "policy_check":
-			validateResults, err = r.validate(jobCtx, showFile, step)
+			validateResults, err = r.validate(jobCtx, localRoot, showFile, step)
 		}
 
 		if err != nil {
@@ -132,10 +135,55 @@ func (r *JobRunner) Apply(ctx workflow.Context, localRoot *terraform.LocalRoot,
 		Context:   ctx,
 		Path:      localRoot.Path,
 		TfVersion: localRoot.Root.TfVersion,
+		Engine:    localRoot.Root.Engine,
 		JobID:     jobID,
 	}
-	defer r.closeTerraformJob(jobCtx)
+	defer r.closeTerraformJob(jobCtx, localRoot)
 
+	return r.runApplySteps(jobCtx, localRoot, planFile)
+}
+
+// InitAndApply applies a plan in a checkout of the root which wasn't planned, the plan job's steps leading
+// up to the plan are run first to initialize the checkout.
+func (r *JobRunner) InitAndApply(ctx workflow.Context, localRoot *terraform.LocalRoot, jobID string, planFile string) error {
+	ctx = workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
+		NonRetryableErrorTypes: []string{TerraformClientErrorType},
+	})
+	jobCtx := &ExecutionContext{
+		Context:   ctx,
+		Path:      localRoot.Path,
+		TfVersion: localRoot.Root.TfVersion,
+		Engine:    localRoot.Root.Engine,
+		JobID:     jobID,
+	}
+	defer r.closeTerraformJob(jobCtx, localRoot)
+
+	for _, step := range localRoot.Root.Plan.GetSteps() {
+		if step.StepName == "plan" {
+			break
+		}
+
+		var err error
+		if step.StepName == "init" {
+			err = r.init(jobCtx, localRoot, step)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "running step %s", step.StepName)
+		}
+
+		err = r.runOptionalSteps(jobCtx, localRoot, step)
+		if err != nil {
+			return errors.Wrapf(err, "running step %s", step.StepName)
+		}
+	}
+
+	// env vars of the plan job aren't available to the apply job
+	jobCtx.Envs = nil
+
+	return r.runApplySteps(jobCtx, localRoot, planFile)
+}
+
+func (r *JobRunner) runApplySteps(jobCtx *ExecutionContext, localRoot *terraform.LocalRoot, planFile string) error {
 	for _, step := range localRoot.Root.Apply.GetSteps() {
 		var err error
 		switch step.StepName {
@@ -156,7 +204,7 @@ func (r *JobRunner) Apply(ctx workflow.Context, localRoot *terraform.LocalRoot,
 	return nil
 }
 
-func (r *JobRunner) validate(executionCtx *ExecutionContext, showFile string, step execute.Step) ([]activities.ValidationResult, error) {
+func (r *JobRunner) validate(executionCtx *ExecutionContext, localRoot *terraform.LocalRoot, showFile string, step execute.Step) ([]activities.ValidationResult, error) {
 	args, err := command.NewArgumentList(step.ExtraArgs)
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating argument list")
@@ -174,6 +222,8 @@ func (r *JobRunner) validate(executionCtx *ExecutionContext, showFile string, st
 		Path:        executionCtx.Path,
 		JobID:       executionCtx.JobID,
 		ShowFile:    showFile,
+		RepoName:    localRoot.Repo.GetFullName(),
+		RootName:    localRoot.Root.Name,
 	}).Get(executionCtx, &resp)
 	if err != nil {
 		return resp.ValidationResults, errors.Wrap(err, "running conftest activity")
@@ -203,6 +253,7 @@ func (r *JobRunner) apply(executionCtx *ExecutionContext, planFile string, step
 		Args:        args,
 		DynamicEnvs: envs,
 		TfVersion:   executionCtx.TfVersion,
+		Engine:      executionCtx.Engine,
 		Path:        executionCtx.Path,
 		JobID:       executionCtx.JobID,
 		PlanFile:    planFile,
@@ -229,6 +280,7 @@ func (r *JobRunner) plan(ctx *ExecutionContext, mode *terraform.PlanMode, workfl
 		Args:         args,
 		DynamicEnvs:  envs,
 		TfVersion:    ctx.TfVersion,
+		Engine:       ctx.Engine,
 		JobID:        ctx.JobID,
 		Path:         ctx.Path,
 		PlanMode:     mode,
@@ -256,6 +308,7 @@ func (r *JobRunner) init(ctx *ExecutionContext, localRoot *terraform.LocalRoot,
 		Args:                 args,
 		DynamicEnvs:          envs,
 		TfVersion:            ctx.TfVersion,
+		Engine:               ctx.Engine,
 		Path:                 ctx.Path,
 		JobID:                ctx.JobID,
 		GithubInstallationID: localRoot.Repo.Credentials.InstallationToken,
@@ -281,7 +334,7 @@ func (r *JobRunner) runOptionalSteps(ctx *ExecutionContext, localRoot *terraform
 	return nil
 }
 
-func (r *JobRunner) closeTerraformJob(executionCtx *ExecutionContext) {
+func (r *JobRunner) closeTerraformJob(executionCtx *ExecutionContext, localRoot *terraform.LocalRoot) {
 	// create a new disconnected ctx since we want this run even in the event of
 	// cancellation
 	ctx := executionCtx.Context
@@ -292,7 +345,8 @@ func (r *JobRunner) closeTerraformJob(executionCtx *ExecutionContext) {
 	}
 
 	err := workflow.ExecuteActivity(ctx, r.Activity.CloseJob, activities.CloseJobRequest{
-		JobID: executionCtx.JobID,
+		JobID:    executionCtx.JobID,
+		RepoName: localRoot.Repo.GetFullName(),
 	}).Get(ctx, nil)
 
 	if err != nil {
@@ -26,6 +26,11 @@ var repo = github.Repo{
 
 type testTerraformActivity struct {
 	t    *testing.T
+	init struct {
+		req  activities.TerraformInitRequest
+		resp activities.TerraformInitResponse
+		err  error
+	}
 	plan struct {
 		req  activities.TerraformPlanRequest
 		resp activities.TerraformPlanResponse
@@ -48,7 +53,8 @@ type testTerraformActivity struct {
 }
 
 func (t *testTerraformActivity) TerraformInit(ctx context.Context, request activities.TerraformInitRequest) (activities.TerraformInitResponse, error) {
-	return activities.TerraformInitResponse{}, nil
+	assert.Equal(t.t, t.init.req, request)
+	return t.init.resp, t.init.err
 }
 
 func (t *testTerraformActivity) TerraformPlan(ctx context.Context, request activities.TerraformPlanRequest) (activities.TerraformPlanResponse, error) {
@@ -106,6 +112,23 @@ func testJobApplyWorkflow(ctx workflow.Context, r terraform.Request) error {
 	return jobRunner.Apply(ctx, &localRoot, JobID, "")
 }
 
+// test workflow that runs the apply job in a checkout which wasn't planned
+func testJobInitAndApplyWorkflow(ctx workflow.Context, r terraform.Request) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToCloseTimeout: 100 * time.Second,
+	})
+
+	localRoot := terraform_model.LocalRoot{
+		Root: r.Root,
+		Repo: r.Repo,
+		Path: ProjectPath,
+	}
+
+	var a *testTerraformActivity
+	jobRunner := job.NewRunner(&job.CmdStepRunner{}, &job.EnvStepRunner{}, a)
+	return jobRunner.InitAndApply(ctx, &localRoot, JobID, "output.tfplan")
+}
+
 // test workflow that runs validate job
 func testJobValidateWorkflow(ctx workflow.Context, r terraform.Request) error {
 	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
@@ -152,7 +175,8 @@ func TestJobRunner_Plan(t *testing.T) {
 				err error
 			}{
 				req: activities.CloseJobRequest{
-					JobID: JobID,
+					JobID:    JobID,
+					RepoName: repo.GetFullName(),
 				},
 			},
 		}
@@ -190,7 +214,9 @@ func TestJobRunner_Validate(t *testing.T) {
 							Value: "v1",
 						},
 					},
-					Path: ProjectPath,
+					Path:     ProjectPath,
+					RepoName: repo.GetFullName(),
+					RootName: ProjectName,
 				},
 			},
 			close: struct {
@@ -198,7 +224,8 @@ func TestJobRunner_Validate(t *testing.T) {
 				err error
 			}{
 				req: activities.CloseJobRequest{
-					JobID: JobID,
+					JobID:    JobID,
+					RepoName: repo.GetFullName(),
 				},
 			},
 		}
@@ -240,7 +267,8 @@ func TestJobRunner_Apply(t *testing.T) {
 				err error
 			}{
 				req: activities.CloseJobRequest{
-					JobID: JobID,
+					JobID:    JobID,
+					RepoName: repo.GetFullName(),
 				},
 			},
 		}
@@ -254,6 +282,70 @@ func TestJobRunner_Apply(t *testing.T) {
 	})
 }
 
+func TestJobRunner_InitAndApply(t *testing.T) {
+	ts := testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	testTerraformActivity := &testTerraformActivity{t: t}
+	testTerraformActivity.init.req = activities.TerraformInitRequest{
+		JobID: JobID,
+		Args:  []command.Argument{},
+		DynamicEnvs: []activities.EnvVar{
+			{
+				Name:  "env1",
+				Value: "v1",
+			},
+		},
+		Path: ProjectPath,
+	}
+	testTerraformActivity.apply.req = activities.TerraformApplyRequest{
+		JobID:    JobID,
+		Args:     []command.Argument{},
+		Path:     ProjectPath,
+		PlanFile: "output.tfplan",
+	}
+	testTerraformActivity.close.req = activities.CloseJobRequest{
+		JobID:    JobID,
+		RepoName: repo.GetFullName(),
+	}
+	env.RegisterActivity(testTerraformActivity)
+	env.RegisterWorkflow(testJobInitAndApplyWorkflow)
+
+	root := terraform_model.Root{
+		Name: ProjectName,
+		Path: "project",
+		Plan: terraform_model.PlanJob{
+			Job: execute.Job{
+				Steps: []execute.Step{
+					{
+						StepName:    "env",
+						EnvVarName:  "env1",
+						EnvVarValue: "v1",
+					},
+					{
+						StepName: "init",
+					},
+					{
+						StepName: "plan",
+					},
+				},
+			},
+		},
+		Apply: execute.Job{
+			Steps: []execute.Step{
+				{
+					StepName: "apply",
+				},
+			},
+		},
+	}
+
+	env.ExecuteWorkflow(testJobInitAndApplyWorkflow, terraform.Request{
+		Root: root,
+		Repo: repo,
+	})
+	assert.NoError(t, env.GetWorkflowError())
+}
+
 func getTestRootForPlan() terraform_model.Root {
 	return terraform_model.Root{
 		Name: ProjectName,
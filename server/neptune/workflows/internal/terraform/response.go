@@ -2,8 +2,16 @@ package terraform
 
 import (
 	"github.com/runatlantis/atlantis/server/neptune/workflows/activities"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
+	"github.com/runatlantis/atlantis/server/neptune/workflows/internal/terraform/state"
 )
 
 type Response struct {
 	ValidationResults []activities.ValidationResult
+
+	// populated in drift mode
+	PlanSummary terraform.PlanSummary
+
+	// populated in deploy mode when the plan was manually approved
+	PlanReview *state.PlanReview
 }
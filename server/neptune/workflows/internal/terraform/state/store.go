@@ -30,8 +30,12 @@ type WorkflowStore struct {
 type UpdateOptions struct {
 	PlanSummary  terraform.PlanSummary
 	PlanApproval terraform.PlanApproval
+	PlanReview   *PlanReview
 	StartTime    time.Time
 	EndTime      time.Time
+
+	// PolicyWarnings are set on the validate job once it completes
+	PolicyWarnings []PolicyWarning
 }
 
 func NewWorkflowStoreWithGenerator(notifier UpdateNotifier, g urlGenerator, mode terraform.WorkflowMode, id string) *WorkflowStore {
@@ -123,6 +127,7 @@ func (s *WorkflowStore) UpdateValidateJobWithStatus(status JobStatus, options ..
 
 	case FailedJobStatus, SuccessJobStatus:
 		s.state.Validate.EndTime = getEndTimeFromOpts(options...)
+		s.state.Validate.Output.PolicyWarnings = getPolicyWarningsFromOpts(options...)
 	}
 
 	s.state.Validate.Status = status
@@ -164,6 +169,10 @@ func (s *WorkflowStore) UpdateApplyJobWithStatus(status JobStatus, options ...Up
 		s.state.Apply.EndTime = getEndTimeFromOpts(options...)
 	}
 
+	if review := getPlanReviewFromOpts(options...); review != nil {
+		s.state.PlanReview = review
+	}
+
 	s.state.Apply.Status = status
 	return s.notifier(s.state)
 }
@@ -173,6 +182,24 @@ func (s *WorkflowStore) UpdateCompletion(result WorkflowResult) error {
 	return s.notifier(s.state)
 }
 
+func getPlanReviewFromOpts(options ...UpdateOptions) *PlanReview {
+	for _, o := range options {
+		if o.PlanReview != nil {
+			return o.PlanReview
+		}
+	}
+	return nil
+}
+
+func getPolicyWarningsFromOpts(options ...UpdateOptions) []PolicyWarning {
+	for _, o := range options {
+		if len(o.PolicyWarnings) > 0 {
+			return o.PolicyWarnings
+		}
+	}
+	return nil
+}
+
 func getStartTimeFromOpts(options ...UpdateOptions) time.Time {
 	for _, o := range options {
 		if !o.StartTime.IsZero() {
@@ -45,6 +45,15 @@ type JobOutput struct {
 
 	// populated for plan jobs
 	Summary terraform.PlanSummary
+
+	// populated for validate jobs
+	PolicyWarnings []PolicyWarning
+}
+
+// PolicyWarning contains the advisory warnings returned by a single policy set
+type PolicyWarning struct {
+	PolicySet string
+	Warnings  []string
 }
 
 type JobAction struct {
@@ -96,6 +105,32 @@ func (j Job) GetActions() JobActions {
 	return JobActions{}
 }
 
+type PlanReviewStatus string
+
+const (
+	ApprovedPlanReviewStatus PlanReviewStatus = "approved"
+	RejectedPlanReviewStatus PlanReviewStatus = "rejected"
+)
+
+// PlanReview records the user who manually approved or rejected a plan
+type PlanReview struct {
+	Status PlanReviewStatus
+	User   string
+	Reason string
+}
+
+func (r *PlanReview) toExternalPlanReview() *plugins.PlanReviewState {
+	if r == nil {
+		return nil
+	}
+
+	return &plugins.PlanReviewState{
+		Status: plugins.PlanReviewStatus(string(r.Status)),
+		User:   r.User,
+		Reason: r.Reason,
+	}
+}
+
 type WorkflowResult struct {
 	Status WorkflowStatus
 	Reason WorkflowCompletionReason
@@ -111,13 +146,17 @@ type Workflow struct {
 	Apply    *Job
 	Result   WorkflowResult
 	ID       string
+
+	// populated once a plan requiring manual approval has been reviewed
+	PlanReview *PlanReview
 }
 
 func (w *Workflow) ToExternalWorkflowState() *plugins.TerraformWorkflowState {
 	return &plugins.TerraformWorkflowState{
-		Plan:     getExternalJob(w.Plan),
-		Apply:    getExternalJob(w.Apply),
-		Validate: getExternalJob(w.Validate),
+		Plan:       getExternalJob(w.Plan),
+		Apply:      getExternalJob(w.Apply),
+		Validate:   getExternalJob(w.Validate),
+		PlanReview: w.PlanReview.toExternalPlanReview(),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"time"
 
 	key "github.com/runatlantis/atlantis/server/neptune/context"
@@ -28,12 +29,16 @@ type githubActivities interface {
 type terraformActivities interface {
 	Cleanup(ctx context.Context, request activities.CleanupRequest) (activities.CleanupResponse, error)
 	GetWorkerInfo(ctx context.Context) (*activities.GetWorkerInfoResponse, error)
+	StorePlanArtifacts(ctx context.Context, request activities.StorePlanArtifactsRequest) (activities.StorePlanArtifactsResponse, error)
+	RestorePlanArtifacts(ctx context.Context, request activities.RestorePlanArtifactsRequest) (activities.RestorePlanArtifactsResponse, error)
+	DeletePlanArtifacts(ctx context.Context, request activities.DeletePlanArtifactsRequest) (activities.DeletePlanArtifactsResponse, error)
 }
 
 // jobRunner runs a deploy plan/apply job
 type jobRunner interface {
 	Plan(ctx workflow.Context, localRoot *terraform.LocalRoot, jobID string, workflowMode terraform.WorkflowMode) (activities.TerraformPlanResponse, error)
 	Apply(ctx workflow.Context, localRoot *terraform.LocalRoot, jobID string, planFile string) error
+	InitAndApply(ctx workflow.Context, localRoot *terraform.LocalRoot, jobID string, planFile string) error
 	Validate(ctx workflow.Context, localRoot *terraform.LocalRoot, jobID string, showFile string) ([]activities.ValidationResult, error)
 }
 
@@ -61,6 +66,15 @@ const (
 	// we are not running the workflow forever when waiting for confirmation/rejection
 	// of a plan.
 	ReviewGateTimeout = 24 * time.Hour * 7
+
+	// the length of time we will wait to restore plan artifacts on the worker which planned
+	// before assuming it's gone and applying on another worker.
+	RestoreScheduleToStartTimeout = 1 * time.Minute
+
+	// PlanArtifactsVersion persists plan artifacts so applies aren't tied to the planning worker
+	PlanArtifactsVersion = "plan-artifacts"
+
+	PlanArtifactChecksumErrorType = "PlanArtifactChecksumError"
 )
 
 func Workflow(ctx workflow.Context, request Request) (Response, error) {
@@ -191,9 +205,11 @@ func (r *Runner) Validate(ctx workflow.Context, root *terraform.LocalRoot, serve
 		return nil, errors.Wrap(err, "running job")
 	}
 
+	policyWarnings := toPolicyWarnings(validateResults)
 	if containsFailure(validateResults) {
 		if e := r.Store.UpdateValidateJobWithStatus(state.FailedJobStatus, state.UpdateOptions{
-			EndTime: time.Now(),
+			EndTime:        time.Now(),
+			PolicyWarnings: policyWarnings,
 		}); e != nil {
 			return nil, newUpdateJobError(e, "unable to update job with failed status")
 		}
@@ -201,7 +217,8 @@ func (r *Runner) Validate(ctx workflow.Context, root *terraform.LocalRoot, serve
 	}
 
 	if err := r.Store.UpdateValidateJobWithStatus(state.SuccessJobStatus, state.UpdateOptions{
-		EndTime: time.Now(),
+		EndTime:        time.Now(),
+		PolicyWarnings: policyWarnings,
 	}); err != nil {
 		return nil, newUpdateJobError(err, "unable to update job with success status")
 	}
@@ -218,38 +235,58 @@ func containsFailure(results []activities.ValidationResult) bool {
 	return false
 }
 
-func (r *Runner) Apply(ctx workflow.Context, root *terraform.LocalRoot, serverURL fmt.Stringer, planResponse activities.TerraformPlanResponse) error {
+// toPolicyWarnings collects warnings which are surfaced to users without failing the job
+func toPolicyWarnings(results []activities.ValidationResult) []state.PolicyWarning {
+	var warnings []state.PolicyWarning
+	for _, result := range results {
+		if result.Status != activities.Warn {
+			continue
+		}
+		warnings = append(warnings, state.PolicyWarning{
+			PolicySet: result.PolicySet.Name,
+			Warnings:  result.Warnings,
+		})
+	}
+	return warnings
+}
+
+// Apply returns the manual review of the plan if one was required
+func (r *Runner) Apply(ctx workflow.Context, root *terraform.LocalRoot, serverURL fmt.Stringer, planResponse activities.TerraformPlanResponse, artifacts []activities.PlanArtifact) (*state.PlanReview, error) {
 	jobID, err := sideeffect.GenerateUUID(ctx)
 
 	if err != nil {
-		return errors.Wrap(err, "generating job id")
+		return nil, errors.Wrap(err, "generating job id")
 	}
 
 	// fail if we error here since all successive calls to update this will fail otherwise
 	if err := r.Store.InitApplyJob(jobID, serverURL); err != nil {
-		return errors.Wrap(err, "initializing job")
+		return nil, errors.Wrap(err, "initializing job")
 	}
 
-	planStatus, err := r.ReviewGate.Await(ctx, root.Root, planResponse.Summary)
+	review, err := r.ReviewGate.Await(ctx, root.Root, planResponse.Summary)
 	if err != nil {
 		workflow.GetLogger(ctx).Error("error waiting for plan review.", key.ErrKey, err)
-		return newPlanRejectedError()
+		return nil, newPlanRejectedError()
 	}
 
-	if planStatus == gate.Rejected {
-		if err := r.Store.UpdateApplyJobWithStatus(state.RejectedJobStatus); err != nil {
+	planReview := toPlanReview(review)
+	if review.Status == gate.Rejected {
+		if err := r.Store.UpdateApplyJobWithStatus(state.RejectedJobStatus, state.UpdateOptions{
+			PlanReview: planReview,
+		}); err != nil {
 			workflow.GetLogger(ctx).Error("unable to update job with rejected status.", key.ErrKey, err)
 		}
-		return newPlanRejectedError()
+		return planReview, newPlanRejectedError()
 	}
 
 	if err := r.Store.UpdateApplyJobWithStatus(state.InProgressJobStatus, state.UpdateOptions{
-		StartTime: time.Now(),
+		StartTime:  time.Now(),
+		PlanReview: planReview,
 	}); err != nil {
-		return newUpdateJobError(err, "unable to update job with success status")
+		return planReview, newUpdateJobError(err, "unable to update job with success status")
 	}
 
-	err = r.JobRunner.Apply(ctx, root, jobID.String(), planResponse.PlanFile)
+	err = r.runApply(ctx, root, jobID.String(), planResponse.PlanFile, artifacts)
 	if err != nil {
 		if err := r.Store.UpdateApplyJobWithStatus(state.FailedJobStatus, state.UpdateOptions{
 			EndTime: time.Now(),
@@ -257,16 +294,159 @@ func (r *Runner) Apply(ctx workflow.Context, root *terraform.LocalRoot, serverUR
 			// not returning UpdateJobError here since we want to surface the job failure itself
 			workflow.GetLogger(ctx).Error("unable to update job with failed status, job failed with error. ", key.ErrKey, err)
 		}
-		return errors.Wrap(err, "running job")
+		return planReview, errors.Wrap(err, "running job")
 	}
 
 	if err := r.Store.UpdateApplyJobWithStatus(state.SuccessJobStatus, state.UpdateOptions{
 		EndTime: time.Now(),
 	}); err != nil {
-		return newUpdateJobError(err, "unable to update job with success status")
+		return planReview, newUpdateJobError(err, "unable to update job with success status")
+	}
+
+	return planReview, nil
+}
+
+// runApply applies the plan on the planning worker if it's still available, otherwise on a new worker
+func (r *Runner) runApply(ctx workflow.Context, root *terraform.LocalRoot, jobID string, planFile string, artifacts []activities.PlanArtifact) error {
+	target, err := r.restorePlan(ctx, root, planFile, artifacts)
+	defer func() {
+		r.executeCleanup(target.ctx, target.cleanup)
+	}()
+	if err != nil {
+		return err
+	}
+
+	if target.initialize {
+		return r.JobRunner.InitAndApply(target.ctx, target.root, jobID, target.planFile)
+	}
+	return r.JobRunner.Apply(target.ctx, target.root, jobID, target.planFile)
+}
+
+// applyTarget is the checkout of the root a plan is applied in
+type applyTarget struct {
+	ctx      workflow.Context
+	root     *terraform.LocalRoot
+	planFile string
+	// initialize is true if the checkout wasn't planned so it needs to be initialized before applying
+	initialize bool
+	// cleanup removes the checkout if it was created for the apply
+	cleanup func(workflow.Context) error
+}
+
+// storePlanArtifacts persists the plan so it can be applied on another worker, the plan is only applied
+// on this worker if they can't be stored.
+func (r *Runner) storePlanArtifacts(ctx workflow.Context, root *terraform.LocalRoot, planResponse activities.TerraformPlanResponse) []activities.PlanArtifact {
+	ctx = workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
+		MaximumAttempts: 3,
+	})
+
+	var resp activities.StorePlanArtifactsResponse
+	err := workflow.ExecuteActivity(ctx, r.TerraformActivities.StorePlanArtifacts, activities.StorePlanArtifactsRequest{
+		DeploymentID: r.Request.DeploymentID,
+		Path:         root.Path,
+		PlanFile:     planResponse.PlanFile,
+		PlanJSONFile: planResponse.PlanJSONFile,
+	}).Get(ctx, &resp)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("unable to store plan artifacts, apply is limited to this worker", key.ErrKey, err)
+		return nil
+	}
+	return resp.Artifacts
+}
+
+// deletePlanArtifacts removes the stored plan since it's either been applied or the deployment ended without applying it,
+// any worker can delete them so the planning worker isn't required to be available.
+func (r *Runner) deletePlanArtifacts(ctx workflow.Context, artifacts []activities.PlanArtifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	ctx = workflow.WithTaskQueue(ctx, workflow.GetInfo(ctx).TaskQueueName)
+	return workflow.ExecuteActivity(ctx, r.TerraformActivities.DeletePlanArtifacts, activities.DeletePlanArtifactsRequest{
+		Artifacts: artifacts,
+	}).Get(ctx, nil)
+}
+
+// restorePlan returns where the plan should be applied. This is the planning worker unless it's unavailable
+// or restarted, in which case the root is checked out on a new worker and the stored plan artifacts are restored there.
+func (r *Runner) restorePlan(ctx workflow.Context, root *terraform.LocalRoot, planFile string, artifacts []activities.PlanArtifact) (applyTarget, error) {
+	target := applyTarget{
+		ctx:      ctx,
+		root:     root,
+		planFile: planFile,
+		cleanup:  func(_ workflow.Context) error { return nil },
+	}
+	if len(artifacts) == 0 {
+		return target, nil
+	}
+
+	ctx = workflow.WithRetryPolicy(ctx, temporal.RetryPolicy{
+		NonRetryableErrorTypes: []string{PlanArtifactChecksumErrorType},
+	})
+
+	var resp activities.RestorePlanArtifactsResponse
+	err := workflow.ExecuteActivity(workflow.WithScheduleToStartTimeout(ctx, RestoreScheduleToStartTimeout), r.TerraformActivities.RestorePlanArtifacts, activities.RestorePlanArtifactsRequest{
+		Path:      root.Path,
+		Artifacts: artifacts,
+	}).Get(ctx, &resp)
+
+	var timeoutErr *temporal.TimeoutError
+	switch {
+	case errors.As(err, &timeoutErr) && timeoutErr.TimeoutType() == enums.TIMEOUT_TYPE_SCHEDULE_TO_START:
+		workflow.GetLogger(ctx).Warn("planning worker is unavailable, applying on a new worker")
+	case err != nil:
+		return target, errors.Wrap(err, "restoring plan artifacts")
+	case resp.RootMissing:
+		workflow.GetLogger(ctx).Warn("planning worker no longer has the root, applying on a new worker")
+	default:
+		return target, nil
+	}
+
+	var worker *activities.GetWorkerInfoResponse
+	err = workflow.ExecuteActivity(workflow.WithTaskQueue(ctx, workflow.GetInfo(ctx).TaskQueueName), r.TerraformActivities.GetWorkerInfo).Get(ctx, &worker)
+	if err != nil {
+		return target, errors.Wrap(err, "getting worker info")
+	}
+
+	ctx = workflow.WithTaskQueue(ctx, worker.TaskQueue)
+	target.ctx = ctx
+
+	newRoot, cleanup, err := r.RootFetcher.Fetch(ctx)
+	if err != nil {
+		return target, errors.Wrap(err, "fetching root")
+	}
+	target.cleanup = cleanup
+
+	err = workflow.ExecuteActivity(ctx, r.TerraformActivities.RestorePlanArtifacts, activities.RestorePlanArtifactsRequest{
+		Path:      newRoot.Path,
+		Artifacts: artifacts,
+	}).Get(ctx, &resp)
+	if err != nil {
+		return target, errors.Wrap(err, "restoring plan artifacts")
+	}
+
+	target.root = newRoot
+	target.planFile = filepath.Join(newRoot.Path, activities.PlanOutputFile)
+	target.initialize = true
+	return target, nil
+}
+
+// toPlanReview returns nil for reviews without a user since those were either automatically approved or timed out
+func toPlanReview(review gate.PlanReviewSignalRequest) *state.PlanReview {
+	if review.User == "" {
+		return nil
 	}
 
-	return nil
+	status := state.ApprovedPlanReviewStatus
+	if review.Status == gate.Rejected {
+		status = state.RejectedPlanReviewStatus
+	}
+
+	return &state.PlanReview{
+		Status: status,
+		User:   review.User,
+		Reason: review.Reason,
+	}
 }
 
 func (r *Runner) Run(ctx workflow.Context) (Response, error) {
@@ -341,6 +521,10 @@ func (r *Runner) run(ctx workflow.Context) (Response, error) {
 		return Response{}, r.toExternalError(err, "running plan job")
 	}
 
+	if r.Request.WorkflowMode == terraform.Drift {
+		return Response{PlanSummary: planResponse.Summary}, nil
+	}
+
 	if r.Request.WorkflowMode == terraform.PR {
 		validationResults, err := r.Validate(ctx, root, response.ServerURL, planResponse.PlanJSONFile)
 		if err != nil {
@@ -349,10 +533,23 @@ func (r *Runner) run(ctx workflow.Context) (Response, error) {
 		return Response{ValidationResults: validationResults}, nil
 	}
 
-	if err = r.Apply(ctx, root, response.ServerURL, planResponse); err != nil {
+	var artifacts []activities.PlanArtifact
+	if v := workflow.GetVersion(ctx, PlanArtifactsVersion, workflow.DefaultVersion, workflow.Version(1)); v > workflow.DefaultVersion {
+		artifacts = r.storePlanArtifacts(ctx, root, planResponse)
+
+		// artifacts are only needed by the apply so they're deleted once it's done
+		defer func() {
+			r.executeCleanup(ctx, func(ctx workflow.Context) error {
+				return r.deletePlanArtifacts(ctx, artifacts)
+			})
+		}()
+	}
+
+	planReview, err := r.Apply(ctx, root, response.ServerURL, planResponse, artifacts)
+	if err != nil {
 		return Response{}, r.toExternalError(err, "running apply job")
 	}
-	return Response{}, nil
+	return Response{PlanReview: planReview}, nil
 }
 
 func (r *Runner) executeCleanup(ctx workflow.Context, handlers ...func(workflow.Context) error) {
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 
@@ -31,6 +32,7 @@ const (
 	testDeploymentID = "123"
 	testPath         = "rel/path"
 	DeployDir        = "deployments/123"
+	testPlanFile     = "rel/path/output.tfplan"
 )
 
 var testGithubRepo = github.Repo{
@@ -95,12 +97,34 @@ func (a *terraformActivities) GetWorkerInfo(ctx context.Context) (*activities.Ge
 	}, err
 }
 
+func (a *terraformActivities) StorePlanArtifacts(ctx context.Context, request activities.StorePlanArtifactsRequest) (activities.StorePlanArtifactsResponse, error) {
+	return activities.StorePlanArtifactsResponse{}, nil
+}
+
+func (a *terraformActivities) RestorePlanArtifacts(ctx context.Context, request activities.RestorePlanArtifactsRequest) (activities.RestorePlanArtifactsResponse, error) {
+	return activities.RestorePlanArtifactsResponse{}, nil
+}
+
+func (a *terraformActivities) DeletePlanArtifacts(ctx context.Context, request activities.DeletePlanArtifactsRequest) (activities.DeletePlanArtifactsResponse, error) {
+	return activities.DeletePlanArtifactsResponse{}, nil
+}
+
 type jobRunner struct {
 	validateResults []activities.ValidationResult
 	expectedError   error
+
+	appliedPlanFile string
+	initialized     bool
 }
 
 func (r *jobRunner) Apply(ctx workflow.Context, localRoot *terraformModel.LocalRoot, jobID string, planFile string) error {
+	r.appliedPlanFile = planFile
+	return r.expectedError
+}
+
+func (r *jobRunner) InitAndApply(ctx workflow.Context, localRoot *terraformModel.LocalRoot, jobID string, planFile string) error {
+	r.appliedPlanFile = planFile
+	r.initialized = true
 	return r.expectedError
 }
 
@@ -110,6 +134,7 @@ func (r *jobRunner) Validate(ctx workflow.Context, localRoot *terraformModel.Loc
 
 func (r *jobRunner) Plan(ctx workflow.Context, localRoot *terraformModel.LocalRoot, jobID string, workflowMode terraformModel.WorkflowMode) (activities.TerraformPlanResponse, error) {
 	return activities.TerraformPlanResponse{
+		PlanFile: testPlanFile,
 		Summary: terraformModel.PlanSummary{
 			Updates: []terraformModel.ResourceSummary{
 				{
@@ -132,6 +157,9 @@ type response struct {
 	PlanRejected     bool
 	UpdateJobErrored bool
 	ClientErrored    bool
+	PlanSummary      terraformModel.PlanSummary
+	AppliedPlanFile  string
+	Initialized      bool
 }
 
 func testTerraformWorkflow(ctx workflow.Context, req request) (*response, error) {
@@ -187,7 +215,8 @@ func testTerraformWorkflow(ctx workflow.Context, req request) (*response, error)
 
 	var planRejected bool
 	var updateJobErr bool
-	if _, err := subject.Run(ctx); err != nil {
+	runResponse, err := subject.Run(ctx)
+	if err != nil {
 		var appErr *temporal.ApplicationError
 		if errors.As(err, &appErr) {
 			switch appErr.Type() {
@@ -207,6 +236,9 @@ func testTerraformWorkflow(ctx workflow.Context, req request) (*response, error)
 		// doing this so that we can still check states when we get this type of error
 		PlanRejected:     planRejected,
 		UpdateJobErrored: updateJobErr,
+		PlanSummary:      runResponse.PlanSummary,
+		AppliedPlanFile:  runner.appliedPlanFile,
+		Initialized:      runner.initialized,
 	}, nil
 }
 
@@ -240,6 +272,7 @@ func copy(s *state.Workflow) state.Workflow {
 		}
 	}
 	copy.Result = s.Result
+	copy.PlanReview = s.PlanReview
 	return copy
 }
 
@@ -278,6 +311,8 @@ func TestSuccess_DeployMode(t *testing.T) {
 	env.RegisterDelayedCallback(func() {
 		env.SignalWorkflow("planreview", gate.PlanReviewSignalRequest{
 			Status: gate.Approved,
+			User:   "nish",
+			Reason: "expected changes",
 		})
 	}, 5*time.Second)
 
@@ -289,6 +324,12 @@ func TestSuccess_DeployMode(t *testing.T) {
 	err = env.GetWorkflowResult(&resp)
 	assert.NoError(t, err)
 
+	planReview := &state.PlanReview{
+		Status: state.ApprovedPlanReviewStatus,
+		User:   "nish",
+		Reason: "expected changes",
+	}
+
 	// assert results are expected
 	env.AssertExpectations(t)
 	assert.Equal(t, []state.Workflow{
@@ -383,6 +424,7 @@ func TestSuccess_DeployMode(t *testing.T) {
 					Summary: approvalReason,
 				},
 			},
+			PlanReview: planReview,
 		},
 		{
 			Plan: &state.Job{
@@ -410,6 +452,7 @@ func TestSuccess_DeployMode(t *testing.T) {
 					Summary: approvalReason,
 				},
 			},
+			PlanReview: planReview,
 		},
 		{
 			Plan: &state.Job{
@@ -441,6 +484,7 @@ func TestSuccess_DeployMode(t *testing.T) {
 				Reason: state.SuccessfulCompletionReason,
 				Status: state.CompleteWorkflowStatus,
 			},
+			PlanReview: planReview,
 		},
 	}, resp.States)
 }
@@ -589,6 +633,60 @@ func TestSuccess_PRMode(t *testing.T) {
 	}, resp.States)
 }
 
+func TestSuccess_DriftMode(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	ga := &githubActivities{}
+	ta := &terraformActivities{}
+	env.RegisterActivity(ga)
+	env.RegisterActivity(ta)
+
+	outputURL, err := url.Parse("www.test.com/jobs/1235")
+	assert.NoError(t, err)
+
+	env.OnActivity(ga.GithubFetchRoot, mock.Anything, activities.FetchRootRequest{
+		Repo:         testGithubRepo,
+		Root:         testLocalRoot.Root,
+		DeploymentID: testDeploymentID,
+	}).Return(activities.FetchRootResponse{
+		LocalRoot:       testLocalRoot,
+		DeployDirectory: DeployDir,
+	}, nil)
+	env.OnActivity(ta.Cleanup, mock.Anything, activities.CleanupRequest{
+		DeployDirectory: DeployDir,
+	}).Return(activities.CleanupResponse{}, nil)
+
+	env.ExecuteWorkflow(testTerraformWorkflow, request{
+		WorkflowMode: terraformModel.Drift,
+	})
+	assert.True(t, env.IsWorkflowCompleted())
+
+	var resp response
+	err = env.GetWorkflowResult(&resp)
+	assert.NoError(t, err)
+
+	// we should never get to the apply
+	env.AssertExpectations(t)
+	assert.Equal(t, terraformModel.PlanSummary{
+		Updates: []terraformModel.ResourceSummary{{Address: "addr"}},
+	}, resp.PlanSummary)
+	assert.Equal(t, state.Workflow{
+		Plan: &state.Job{
+			Status: state.SuccessJobStatus,
+			Output: &state.JobOutput{
+				URL: outputURL,
+			},
+		},
+		Result: state.WorkflowResult{
+			Reason: state.SuccessfulCompletionReason,
+			Status: state.CompleteWorkflowStatus,
+		},
+	}, resp.States[len(resp.States)-1])
+	for _, s := range resp.States {
+		assert.Nil(t, s.Apply)
+	}
+}
+
 func TestSuccess_PRMode_FailedPolicy(t *testing.T) {
 	var suite testsuite.WorkflowTestSuite
 	env := suite.NewTestWorkflowEnvironment()
@@ -1005,3 +1103,144 @@ func TestCleanupErrorReturnsNoError(t *testing.T) {
 	err := env.GetWorkflowResult(&resp)
 	assert.NoError(t, err)
 }
+
+func TestApply_PlanArtifacts(t *testing.T) {
+	artifacts := []activities.PlanArtifact{
+		{Name: "output.tfplan", Key: "123/output.tfplan", Checksum: "abc"},
+	}
+
+	onTaskQueue := func(taskQueue string) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			return activity.GetInfo(ctx).TaskQueue == taskQueue
+		})
+	}
+
+	approve := func(env *testsuite.TestWorkflowEnvironment) {
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow("planreview", gate.PlanReviewSignalRequest{
+				Status: gate.Approved,
+			})
+		}, 5*time.Second)
+	}
+
+	t.Run("planning worker available", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		ga := &githubActivities{}
+		ta := &terraformActivities{}
+		env.RegisterActivity(ga)
+		env.RegisterActivity(ta)
+
+		env.OnActivity(ta.StorePlanArtifacts, mock.Anything, activities.StorePlanArtifactsRequest{
+			DeploymentID: testDeploymentID,
+			Path:         testPath,
+			PlanFile:     testPlanFile,
+		}).Return(activities.StorePlanArtifactsResponse{Artifacts: artifacts}, nil)
+		env.OnActivity(ta.RestorePlanArtifacts, onTaskQueue("taskqueue"), activities.RestorePlanArtifactsRequest{
+			Path:      testPath,
+			Artifacts: artifacts,
+		}).Return(activities.RestorePlanArtifactsResponse{}, nil)
+		env.OnActivity(ta.DeletePlanArtifacts, mock.Anything, activities.DeletePlanArtifactsRequest{
+			Artifacts: artifacts,
+		}).Return(activities.DeletePlanArtifactsResponse{}, nil).Once()
+
+		approve(env)
+		env.ExecuteWorkflow(testTerraformWorkflow, request{})
+		assert.True(t, env.IsWorkflowCompleted())
+
+		var resp response
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		env.AssertExpectations(t)
+		assert.Equal(t, testPlanFile, resp.AppliedPlanFile)
+		assert.False(t, resp.Initialized)
+	})
+
+	t.Run("plan rejected", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		ga := &githubActivities{}
+		ta := &terraformActivities{}
+		env.RegisterActivity(ga)
+		env.RegisterActivity(ta)
+
+		env.OnActivity(ta.StorePlanArtifacts, mock.Anything, mock.Anything).Return(activities.StorePlanArtifactsResponse{Artifacts: artifacts}, nil)
+		env.OnActivity(ta.DeletePlanArtifacts, mock.Anything, activities.DeletePlanArtifactsRequest{
+			Artifacts: artifacts,
+		}).Return(activities.DeletePlanArtifactsResponse{}, nil).Once()
+
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow("planreview", gate.PlanReviewSignalRequest{
+				Status: gate.Rejected,
+			})
+		}, 5*time.Second)
+		env.ExecuteWorkflow(testTerraformWorkflow, request{})
+		assert.True(t, env.IsWorkflowCompleted())
+
+		var resp response
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		env.AssertExpectations(t)
+		assert.True(t, resp.PlanRejected)
+	})
+
+	t.Run("planning worker unavailable", func(t *testing.T) {
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestWorkflowEnvironment()
+		ga := &githubActivities{}
+		ta := &terraformActivities{}
+		env.RegisterActivity(ga)
+		env.RegisterActivity(ta)
+
+		newRoot := *testLocalRoot
+		newRoot.Path = "new/path"
+
+		u, err := url.Parse("www.test.com")
+		assert.NoError(t, err)
+		env.OnActivity(ta.GetWorkerInfo, mock.Anything).Return(&activities.GetWorkerInfoResponse{
+			ServerURL: u,
+			TaskQueue: "taskqueue",
+		}, nil).Once()
+		env.OnActivity(ta.GetWorkerInfo, mock.Anything).Return(&activities.GetWorkerInfoResponse{
+			ServerURL: u,
+			TaskQueue: "newqueue",
+		}, nil).Once()
+
+		env.OnActivity(ga.GithubFetchRoot, onTaskQueue("taskqueue"), mock.Anything).Return(activities.FetchRootResponse{
+			LocalRoot:       testLocalRoot,
+			DeployDirectory: DeployDir,
+		}, nil)
+		env.OnActivity(ga.GithubFetchRoot, onTaskQueue("newqueue"), mock.Anything).Return(activities.FetchRootResponse{
+			LocalRoot:       &newRoot,
+			DeployDirectory: "new/deployments/123",
+		}, nil)
+
+		env.OnActivity(ta.StorePlanArtifacts, mock.Anything, mock.Anything).Return(activities.StorePlanArtifactsResponse{Artifacts: artifacts}, nil)
+		env.OnActivity(ta.RestorePlanArtifacts, onTaskQueue("taskqueue"), mock.Anything).Return(
+			activities.RestorePlanArtifactsResponse{},
+			temporal.NewTimeoutError(enums.TIMEOUT_TYPE_SCHEDULE_TO_START, nil),
+		)
+		env.OnActivity(ta.RestorePlanArtifacts, onTaskQueue("newqueue"), activities.RestorePlanArtifactsRequest{
+			Path:      "new/path",
+			Artifacts: artifacts,
+		}).Return(activities.RestorePlanArtifactsResponse{}, nil)
+		env.OnActivity(ta.DeletePlanArtifacts, mock.Anything, activities.DeletePlanArtifactsRequest{
+			Artifacts: artifacts,
+		}).Return(activities.DeletePlanArtifactsResponse{}, nil).Once()
+
+		env.OnActivity(ta.Cleanup, onTaskQueue("newqueue"), activities.CleanupRequest{
+			DeployDirectory: "new/deployments/123",
+		}).Return(activities.CleanupResponse{}, nil)
+		env.OnActivity(ta.Cleanup, onTaskQueue("taskqueue"), activities.CleanupRequest{
+			DeployDirectory: DeployDir,
+		}).Return(activities.CleanupResponse{}, nil)
+
+		approve(env)
+		env.ExecuteWorkflow(testTerraformWorkflow, request{})
+		assert.True(t, env.IsWorkflowCompleted())
+
+		var resp response
+		assert.NoError(t, env.GetWorkflowResult(&resp))
+		env.AssertExpectations(t)
+		assert.Equal(t, "new/path/output.tfplan", resp.AppliedPlanFile)
+		assert.True(t, resp.Initialized)
+	})
+}
@@ -2,6 +2,8 @@ package plugins
 
 import (
 	"time"
+
+	"github.com/runatlantis/atlantis/server/neptune/workflows/activities/terraform"
 )
 
 type JobStatus string
@@ -29,4 +31,30 @@ type TerraformWorkflowState struct {
 	Plan     *JobState
 	Validate *JobState
 	Apply    *JobState
+
+	// only populated once a drift detection plan has completed
+	Drift *DriftState
+
+	// only populated once a plan requiring manual approval has been reviewed
+	PlanReview *PlanReviewState
+}
+
+type PlanReviewStatus string
+
+const (
+	ApprovedPlanReviewStatus PlanReviewStatus = "approved"
+	RejectedPlanReviewStatus PlanReviewStatus = "rejected"
+)
+
+// PlanReviewState contains who reviewed a plan and why
+type PlanReviewState struct {
+	Status PlanReviewStatus
+	User   string
+	Reason string
+}
+
+// DriftState contains the result of a drift detection plan against the last deployed revision.
+type DriftState struct {
+	Detected bool
+	Summary  terraform.PlanSummary
 }
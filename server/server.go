@@ -38,6 +38,8 @@ import (
 
 	"github.com/runatlantis/atlantis/server/events/terraform/filter"
 	"github.com/runatlantis/atlantis/server/neptune/storage"
+	internalSync "github.com/runatlantis/atlantis/server/neptune/sync"
+	"github.com/runatlantis/atlantis/server/neptune/sync/crons"
 
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/runatlantis/atlantis/server/instrumentation"
@@ -46,6 +48,9 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/runtime/checksum"
+	"github.com/runatlantis/atlantis/server/core/runtime/mirror"
+	"github.com/runatlantis/atlantis/server/core/runtime/plugincache"
 	"github.com/runatlantis/atlantis/server/core/runtime/policy"
 	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/lyft/aws"
@@ -101,6 +106,9 @@ const (
 	// terraformPluginCacheDir is the name of the dir inside our data dir
 	// where we tell terraform to cache plugins and modules.
 	TerraformPluginCacheDirName = "plugin-cache"
+	// PluginCacheRootsDirName is the name of the dir inside our data dir
+	// where the lock files used to warm the plugin cache are recorded.
+	PluginCacheRootsDirName = "plugin-cache-roots"
 )
 
 // Server runs the Atlantis web server.
@@ -129,9 +137,13 @@ type Server struct {
 	SSLKeyFile                    string
 	Drainer                       *events.Drainer
 	ScheduledExecutorService      *scheduled.ExecutorService
+	CronScheduler                 *internalSync.CronScheduler
+	Crons                         []*internalSync.Cron
 	ProjectCmdOutputHandler       jobs.ProjectCommandOutputHandler
 	LyftMode                      Mode
 	CancelWorker                  context.CancelFunc
+	// PluginCacheWarmer is nil if the plugin cache isn't warmed on startup
+	PluginCacheWarmer func() error
 }
 
 // Config holds config for server that isn't passed in by the user.
@@ -418,6 +430,21 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 
 	jobStore := jobs.NewJobStore(storageBackend, statsScope.SubScope("jobstore"))
 
+	var serverCrons []*internalSync.Cron
+	if globalCfg.PersistenceConfig.JobRetention.Enabled() {
+		jobRetention := &crons.JobRetention{
+			Store:     storageClient,
+			Retention: globalCfg.PersistenceConfig.JobRetention,
+			Logger:    ctxLogger,
+			Scope:     statsScope,
+		}
+
+		serverCrons = append(serverCrons, &internalSync.Cron{
+			Executor:  jobRetention.Run,
+			Frequency: globalCfg.PersistenceConfig.JobRetention.Interval,
+		})
+	}
+
 	var projectCmdOutputHandler jobs.ProjectCommandOutputHandler
 	// When TFE is enabled log streaming is not necessary.
 
@@ -429,13 +456,40 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		logFilter,
 	)
 
+	tfDownloader := &terraform.DefaultDownloader{}
+	getFile := func(dst, src string) error {
+		return tfDownloader.GetFile(dst, src)
+	}
+	tfVerifier, err := checksum.NewVerifierWithDefaultKey(userConfig.BinaryChecksumsFile, userConfig.TFSigningKeyFile, checksum.HashiCorpKey, getFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing terraform verifier")
+	}
+
+	binaryMirror, err := mirror.New(userConfig.BinaryMirrorDir, globalCfg.PersistenceConfig.BinaryMirror)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing binary mirror")
+	}
+
+	tofuVerifier, err := checksum.NewVerifier(userConfig.BinaryChecksumsFile, userConfig.TofuSigningKeyFile, getFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing opentofu verifier")
+	}
+
 	terraformClient, err := terraform.NewClient(
 		binDir,
 		cacheDir,
 		userConfig.DefaultTFVersion,
 		config.DefaultTFVersionFlag,
 		userConfig.TFDownloadURL,
-		&terraform.DefaultDownloader{},
+		tfDownloader,
+		tfVerifier,
+		binaryMirror,
+		terraform.TofuConfig{
+			Enabled:        userConfig.EnableTofu,
+			DefaultVersion: userConfig.DefaultTofuVersion,
+			DownloadURL:    userConfig.TofuDownloadURL,
+			Verifier:       tofuVerifier,
+		},
 		true,
 		projectCmdOutputHandler)
 
@@ -443,6 +497,23 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		return nil, errors.Wrap(err, "initializing terraform")
 	}
 
+	pluginCache, err := plugincache.New(
+		cacheDir,
+		int64(userConfig.PluginCacheMaxSizeMB)*1024*1024,
+		filepath.Join(userConfig.DataDir, PluginCacheRootsDirName),
+		ctxLogger,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing plugin cache")
+	}
+
+	var pluginCacheWarmer func() error
+	if userConfig.PluginCacheWarmUp {
+		pluginCacheWarmer = func() error {
+			return pluginCache.WarmUp(terraformClient.InitProviders)
+		}
+	}
+
 	templateResolver := markdown.TemplateResolver{
 		DisableMarkdownFolding:   userConfig.DisableMarkdownFolding,
 		GitlabSupportsCommonMark: gitlabClient.SupportsCommonMark(),
@@ -456,15 +527,21 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		TemplateResolver:         templateResolver,
 	}
 
-	boltdb, err := db.New(userConfig.DataDir)
+	database, err := newDatabase(userConfig)
 	if err != nil {
 		return nil, err
 	}
 	var lockingClient locking.Locker
 	var applyLockingClient locking.ApplyLocker
 
-	lockingClient = locking.NewClient(boltdb)
-	applyLockingClient = locking.NewApplyClient(boltdb, userConfig.DisableApply)
+	// pulls wait in line for locked projects, OnHandoff is set once the command runner is built
+	queuedLockingClient := &locking.QueuedClient{
+		Locker:   locking.NewExpiringClient(database, time.Duration(userConfig.LockTTLHours)*time.Hour),
+		WaitList: database,
+		Logger:   ctxLogger,
+	}
+	lockingClient = queuedLockingClient
+	applyLockingClient = locking.NewApplyClient(database, userConfig.DisableApply)
 	workingDirLocker := events.NewDefaultWorkingDirLocker()
 
 	var workingDir events.WorkingDir = &events.FileWorkspace{
@@ -493,7 +570,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Logger:           ctxLogger,
 		WorkingDir:       workingDir,
 		WorkingDirLocker: workingDirLocker,
-		DB:               boltdb,
+		DB:               database,
 	}
 
 	pullClosedExecutor := events.NewInstrumentedPullClosedExecutor(
@@ -503,7 +580,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Locker:                   lockingClient,
 			WorkingDir:               workingDir,
 			Logger:                   ctxLogger,
-			DB:                       boltdb,
+			DB:                       database,
 			PullClosedTemplate:       &events.PullClosedEventTemplate{},
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 			VCSClient:                vcsClient,
@@ -581,6 +658,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	initStepRunner := &runtime.InitStepRunner{
 		TerraformExecutor: terraformClient,
 		DefaultTFVersion:  defaultTfVersion,
+		PluginCache:       pluginCache,
 	}
 
 	planStepRunner := &runtime.PlanStepRunner{
@@ -599,7 +677,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		return nil, errors.Wrap(err, "initializing show step runner")
 	}
 
-	conftestEnsurer := policy.NewConfTestVersionEnsurer(ctxLogger, binDir, &terraform.DefaultDownloader{})
+	conftestVerifier, err := checksum.NewVerifier(userConfig.BinaryChecksumsFile, "", getFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing conftest verifier")
+	}
+	conftestEnsurer := policy.NewConfTestVersionEnsurer(ctxLogger, binDir, tfDownloader, conftestVerifier, binaryMirror)
 	conftestExecutor := policy.NewConfTestExecutor(clientCreator, globalCfg.PolicySets, featureAllocator, ctxLogger)
 	policyCheckStepRunner, err := runtime.NewPolicyCheckStepRunner(
 		defaultTfVersion,
@@ -643,7 +725,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	)
 
 	dbUpdater := &events.DBUpdater{
-		DB: boltdb,
+		DB: database,
 	}
 
 	checksOutputUpdater := &events.ChecksOutputUpdater{
@@ -813,13 +895,25 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		DisableAutoplan:               userConfig.DisableAutoplan,
 		Drainer:                       drainer,
 		PreWorkflowHooksCommandRunner: preWorkflowHooksCommandRunner,
-		PullStatusFetcher:             boltdb,
+		PullStatusFetcher:             database,
 		StaleCommandChecker:           staleCommandChecker,
 		VCSStatusUpdater:              vcsStatusUpdater,
 		Logger:                        ctxLogger,
 		PolicyCommandRunner:           prrPolicyCommandRunner,
 	}
 
+	if githubClient != nil {
+		waitingPullAutoplanner := &events.WaitingPullAutoplanner{
+			GithubPullGetter:  githubClient,
+			EventParser:       eventParser,
+			CommandRunner:     commandRunner,
+			PullStatusFetcher: database,
+			Locker:            lockingClient,
+			Logger:            ctxLogger,
+		}
+		queuedLockingClient.OnHandoff = waitingPullAutoplanner.Autoplan
+	}
+
 	forceApplyCommandRunner := &events.ForceApplyCommandRunner{
 		CommandRunner: commandRunner,
 		VCSClient:     vcsClient,
@@ -840,7 +934,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		LockDetailTemplate: templates.LockTemplate,
 		WorkingDir:         workingDir,
 		WorkingDirLocker:   workingDirLocker,
-		DB:                 boltdb,
+		DB:                 database,
 		DeleteLockCommand:  deleteLockCommand,
 	}
 
@@ -859,7 +953,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Logger:                   ctxLogger,
 		ProjectJobsTemplate:      templates.ProjectJobsTemplate,
 		ProjectJobsErrorTemplate: templates.ProjectJobsErrorTemplate,
-		Db:                       boltdb,
+		Db:                       database,
 		WsMux:                    wsMux,
 		StatsScope:               projectJobsScope,
 		KeyGenerator:             controllers.JobIDKeyGenerator{},
@@ -888,7 +982,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Locker:                   lockingClient,
 			WorkingDir:               workingDir,
 			Logger:                   ctxLogger,
-			DB:                       boltdb,
+			DB:                       database,
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 
 			// using a specific template to signal that this is from an async process
@@ -901,7 +995,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Locker:                   lockingClient,
 			WorkingDir:               workingDir,
 			Logger:                   ctxLogger,
-			DB:                       boltdb,
+			DB:                       database,
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 
 			// using a specific template to signal that this is from an async process
@@ -909,6 +1003,10 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		},
 
 		rawGithubClient,
+		lockingClient,
+		deleteLockCommand,
+		vcsClient,
+		time.Duration(userConfig.LockAbandonedDays)*24*time.Hour,
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -992,9 +1090,12 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		SSLCertFile:                   userConfig.SSLCertFile,
 		Drainer:                       drainer,
 		ScheduledExecutorService:      scheduledExecutorService,
+		CronScheduler:                 internalSync.NewCronScheduler(ctxLogger),
+		Crons:                         serverCrons,
 		ProjectCmdOutputHandler:       projectCmdOutputHandler,
 		LyftMode:                      lyftMode,
 		CancelWorker:                  cancel,
+		PluginCacheWarmer:             pluginCacheWarmer,
 	}, nil
 }
 
@@ -1036,6 +1137,18 @@ func (s *Server) Start() error {
 
 	go s.ScheduledExecutorService.Run()
 
+	for _, c := range s.Crons {
+		s.CronScheduler.Schedule(c)
+	}
+
+	if s.PluginCacheWarmer != nil {
+		go func() {
+			if err := s.PluginCacheWarmer(); err != nil {
+				s.CtxLogger.Warn(fmt.Sprintf("warming plugin cache: %s", err))
+			}
+		}()
+	}
+
 	go func() {
 		s.ProjectCmdOutputHandler.Handle()
 	}()
@@ -1065,6 +1178,7 @@ func (s *Server) Start() error {
 
 	s.CtxLogger.Warn("Received interrupt. Waiting for in-progress operations to complete")
 	s.waitForDrain()
+	s.CronScheduler.Shutdown(5 * time.Second)
 
 	// flush stats before shutdown
 	if err := s.StatsCloser.Close(); err != nil {
@@ -1192,3 +1306,11 @@ func ParseAtlantisURL(u string) (*url.URL, error) {
 	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
 	return parsed, nil
 }
+
+// newDatabase returns the database selected by --locking-db-type
+func newDatabase(userConfig UserConfig) (db.Database, error) {
+	if userConfig.LockingDBType == "sql" {
+		return db.NewSQL(userConfig.LockingDBSQLDriver, userConfig.LockingDBSQLDSN)
+	}
+	return db.New(userConfig.DataDir)
+}
@@ -19,6 +19,8 @@ const (
 type UserConfig struct {
 	AtlantisURL                string `mapstructure:"atlantis-url"`
 	AutoplanFileList           string `mapstructure:"autoplan-file-list"`
+	BinaryChecksumsFile        string `mapstructure:"binary-checksums-file"`
+	BinaryMirrorDir            string `mapstructure:"binary-mirror-dir"`
 	AzureDevopsToken           string `mapstructure:"azuredevops-token"`
 	AzureDevopsUser            string `mapstructure:"azuredevops-user"`
 	AzureDevopsWebhookPassword string `mapstructure:"azuredevops-webhook-password"`
@@ -54,9 +56,17 @@ type UserConfig struct {
 	GitlabUser                 string `mapstructure:"gitlab-user"`
 	GitlabWebhookSecret        string `mapstructure:"gitlab-webhook-secret"`
 	HidePrevPlanComments       bool   `mapstructure:"hide-prev-plan-comments"`
+	LockAbandonedDays          int    `mapstructure:"lock-abandoned-days"`
+	LockTTLHours               int    `mapstructure:"lock-ttl-hours"`
+	LockingDBType              string `mapstructure:"locking-db-type"`
+	LockingDBSQLDriver         string `mapstructure:"locking-db-sql-driver"`
+	LockingDBSQLDSN            string `mapstructure:"locking-db-sql-dsn"`
 	LogLevel                   string `mapstructure:"log-level"`
 	ParallelPoolSize           int    `mapstructure:"parallel-pool-size"`
 	MaxProjectsPerPR           int    `mapstructure:"max-projects-per-pr"`
+	PlanEncryptionKeyFile      string `mapstructure:"plan-encryption-key-file"`
+	PluginCacheMaxSizeMB       int    `mapstructure:"plugin-cache-max-size-mb"`
+	PluginCacheWarmUp          bool   `mapstructure:"plugin-cache-warm-up"`
 	StatsNamespace             string `mapstructure:"stats-namespace"`
 	PlanDrafts                 bool   `mapstructure:"allow-draft-prs"`
 	Port                       int    `mapstructure:"port"`
@@ -76,8 +86,13 @@ type UserConfig struct {
 	SSLCertFile              string          `mapstructure:"ssl-cert-file"`
 	SSLKeyFile               string          `mapstructure:"ssl-key-file"`
 	TFDownloadURL            string          `mapstructure:"tf-download-url"`
+	TFSigningKeyFile         string          `mapstructure:"tf-signing-key-file"`
+	EnableTofu               bool            `mapstructure:"enable-tofu"`
+	TofuDownloadURL          string          `mapstructure:"tofu-download-url"`
+	TofuSigningKeyFile       string          `mapstructure:"tofu-signing-key-file"`
 	VCSStatusName            string          `mapstructure:"vcs-status-name"`
 	DefaultTFVersion         string          `mapstructure:"default-tf-version"`
+	DefaultTofuVersion       string          `mapstructure:"default-tofu-version"`
 	Webhooks                 []WebhookConfig `mapstructure:"webhooks"`
 	WriteGitCreds            bool            `mapstructure:"write-git-creds"`
 	LyftAuditJobsSnsTopicArn string          `mapstructure:"lyft-audit-jobs-sns-topic-arn"`
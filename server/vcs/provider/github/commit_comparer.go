@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v45/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+)
+
+type CommitComparer struct {
+	ClientCreator githubapp.ClientCreator
+}
+
+// IsAncestor returns true if revision is the head of branch or one of its ancestors.
+func (c *CommitComparer) IsAncestor(ctx context.Context, installationToken int64, owner, repo, revision, branch string) (bool, error) {
+	client, err := c.ClientCreator.NewInstallationClient(installationToken)
+	if err != nil {
+		return false, errors.Wrap(err, "creating installation client")
+	}
+
+	// only the comparison status is needed so the listed commits are kept to a minimum
+	comparison, _, err := client.Repositories.CompareCommits(ctx, owner, repo, revision, branch, &gh.ListOptions{PerPage: 1})
+	if err != nil {
+		return false, errors.Wrap(err, "comparing commits")
+	}
+
+	// the status is relative to revision, so the branch is ahead of its ancestors
+	switch status := comparison.GetStatus(); status {
+	case "ahead", "identical":
+		return true, nil
+	case "behind", "diverged":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid commit comparison status: %s", status)
+	}
+}